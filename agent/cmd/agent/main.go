@@ -36,6 +36,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/jobs"
 	commonobjects "github.com/stolostron/multicluster-global-hub/pkg/objects"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/controller"
 	"github.com/stolostron/multicluster-global-hub/pkg/utils"
@@ -92,6 +93,17 @@ func doMain(ctx context.Context, restConfig *rest.Config, agentConfig *config.Ag
 		go utils.StartDefaultPprofServer()
 	}
 
+	shutdownTracing, err := tracing.InitTracing(ctx, "multicluster-global-hub-agent", agentConfig.TracingConfig)
+	if err != nil {
+		setupLog.Error(err, "failed to initialize the tracing")
+		return 1
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			setupLog.Error(err, "failed to flush the spans")
+		}
+	}()
+
 	mgr, err := createManager(restConfig, agentConfig)
 	if err != nil {
 		setupLog.Error(err, "failed to create manager")
@@ -109,6 +121,7 @@ func doMain(ctx context.Context, restConfig *rest.Config, agentConfig *config.Ag
 func parseFlags() *config.AgentConfig {
 	agentConfig := &config.AgentConfig{
 		ElectionConfig: &commonobjects.LeaderElectionConfig{},
+		TracingConfig:  &tracing.TracingConfig{},
 		TransportConfig: &transport.TransportInternalConfig{
 			// IsManager specifies the send/receive topics from specTopic and statusTopic
 			// For example, SpecTopic sends and statusTopic receives on the manager; the agent is the opposite
@@ -154,6 +167,14 @@ func parseFlags() *config.AgentConfig {
 		"Enable StackRox integration")
 	pflag.DurationVar(&agentConfig.StackroxPollInterval, "stackrox-poll-interval", 30*time.Minute,
 		"The interval between each StackRox polling")
+	pflag.DurationVar(&agentConfig.SpecDriftCorrectionInterval, "spec-drift-correction-interval", 5*time.Minute,
+		"The interval to revert the changes made on the managed hub to the global resources, 0 disables it")
+	pflag.StringVar(&agentConfig.TracingConfig.Endpoint, "tracing-endpoint", "",
+		"The OTLP/HTTP endpoint of the collector to export the spans, the tracing is disabled if it's empty.")
+	pflag.BoolVar(&agentConfig.TracingConfig.Insecure, "tracing-insecure", false,
+		"Export the spans to the collector without TLS.")
+	pflag.Float64Var(&agentConfig.TracingConfig.SamplingRatio, "tracing-sampling-ratio", 0.1,
+		"The ratio of the traces started by the agent to be sampled, between 0 and 1.")
 	pflag.Parse()
 
 	// set zap logger
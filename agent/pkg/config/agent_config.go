@@ -4,6 +4,7 @@ import (
 	"time"
 
 	commonobjects "github.com/stolostron/multicluster-global-hub/pkg/objects"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
@@ -26,6 +27,8 @@ type AgentConfig struct {
 	Standalone                   bool
 	EnableStackroxIntegration    bool
 	StackroxPollInterval         time.Duration
+	SpecDriftCorrectionInterval  time.Duration
+	TracingConfig                *tracing.TracingConfig
 }
 
 func SetAgentConfig(agentConfig *AgentConfig) {
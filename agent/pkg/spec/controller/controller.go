@@ -8,6 +8,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/controller/drift"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/controller/syncers"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/controller/workers"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
@@ -16,7 +17,9 @@ import (
 
 var specCtrlStarted = false
 
-func AddToManager(context context.Context, mgr ctrl.Manager, consumer transport.Consumer, agentConfig *config.AgentConfig) error {
+func AddToManager(context context.Context, mgr ctrl.Manager, consumer transport.Consumer, producer transport.Producer,
+	agentConfig *config.AgentConfig,
+) error {
 	if specCtrlStarted {
 		return nil
 	}
@@ -40,10 +43,29 @@ func AddToManager(context context.Context, mgr ctrl.Manager, consumer transport.
 
 	// register syncer to the dispatcher
 	if agentConfig.EnableGlobalResource {
+		// the drift corrector is disabled if the interval isn't positive
+		var corrector *drift.Corrector
+		if agentConfig.SpecDriftCorrectionInterval > 0 {
+			corrector = drift.NewCorrector(mgr, producer, agentConfig)
+			if err := mgr.Add(corrector); err != nil {
+				return fmt.Errorf("failed to add drift corrector to runtime manager: %w", err)
+			}
+		}
 		dispatcher.RegisterSyncer(constants.GenericSpecMsgKey,
-			syncers.NewGenericSyncer(workers, agentConfig))
+			syncers.NewGenericSyncer(workers, mgr.GetClient(), corrector, agentConfig))
 		dispatcher.RegisterSyncer(constants.ManagedClustersLabelsMsgKey,
 			syncers.NewManagedClusterLabelSyncer(workers))
+		dispatcher.RegisterSyncer(constants.ManagedClusterOperationMsgKey,
+			syncers.NewManagedClusterOperationSyncer(workers, producer, agentConfig.LeafHubName))
+		// the lifecycle actions are applied as the requester of the GlobalClusterAction
+		dispatcher.RegisterSyncer(constants.ManagedClusterDetachMsgKey,
+			syncers.NewManagedClusterDetachSyncer(workers, producer, agentConfig.LeafHubName))
+		dispatcher.RegisterSyncer(constants.ManagedClusterDenyClientMsgKey,
+			syncers.NewManagedClusterDenyClientSyncer(workers, producer, agentConfig.LeafHubName))
+		dispatcher.RegisterSyncer(constants.ManagedClusterUpgradeMsgKey,
+			syncers.NewManagedClusterUpgradeSyncer(workers, producer, agentConfig.LeafHubName))
+		dispatcher.RegisterSyncer(constants.KlusterletBootstrapRotationMsgKey,
+			syncers.NewKlusterletBootstrapRotationSyncer(workers, producer, agentConfig.LeafHubName))
 	}
 
 	dispatcher.RegisterSyncer(constants.CloudEventTypeMigrationFrom,
@@ -0,0 +1,391 @@
+package drift
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/managedfields"
+	"k8s.io/apimachinery/pkg/util/sets"
+	toolscache "k8s.io/client-go/tools/cache"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/event"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+const (
+	// FieldManager is the field manager of the server-side apply for the global resources
+	FieldManager = "multicluster-global-hub-agent-spec"
+
+	DriftReasonModified = "Modified"
+	DriftReasonDeleted  = "Deleted"
+)
+
+// globalResourceKinds are the kinds of the global resources applied by the agent
+var globalResourceKinds = []schema.GroupVersionKind{
+	{Group: "policy.open-cluster-management.io", Version: "v1", Kind: "Policy"},
+	{Group: "policy.open-cluster-management.io", Version: "v1", Kind: "PlacementBinding"},
+	{Group: "apps.open-cluster-management.io", Version: "v1", Kind: "PlacementRule"},
+	{Group: "cluster.open-cluster-management.io", Version: "v1beta1", Kind: "Placement"},
+	{Group: "cluster.open-cluster-management.io", Version: "v1beta2", Kind: "ManagedClusterSet"},
+	{Group: "cluster.open-cluster-management.io", Version: "v1beta2", Kind: "ManagedClusterSetBinding"},
+	{Group: "app.k8s.io", Version: "v1beta1", Kind: "Application"},
+	{Group: "apps.open-cluster-management.io", Version: "v1", Kind: "Subscription"},
+	{Group: "apps.open-cluster-management.io", Version: "v1", Kind: "Channel"},
+}
+
+type objectKey struct {
+	gvk schema.GroupVersionKind
+	types.NamespacedName
+}
+
+func keyOf(obj *unstructured.Unstructured) objectKey {
+	return objectKey{
+		gvk:            obj.GroupVersionKind(),
+		NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()},
+	}
+}
+
+// Corrector watches the global resources applied by the agent, and reverts the changes made by others on the
+// managed hub on the schedule. The drifts are reported to the global hub as events.
+type Corrector struct {
+	log         logr.Logger
+	client      client.Client
+	cache       cache.Cache
+	producer    transport.Producer
+	leafHubName string
+	interval    time.Duration
+	version     *eventversion.Version
+
+	mutex sync.Mutex
+	ctx   context.Context
+	// desired is the state of the objects applied by the agent
+	desired map[objectKey]*unstructured.Unstructured
+	// suspects are the objects which might be drifted, they're checked by the next correction
+	suspects sets.Set[objectKey]
+	watched  sets.Set[schema.GroupVersionKind]
+}
+
+func NewCorrector(mgr ctrl.Manager, producer transport.Producer, agentConfig *config.AgentConfig) *Corrector {
+	return &Corrector{
+		log:         ctrl.Log.WithName("spec-drift-corrector"),
+		client:      mgr.GetClient(),
+		cache:       mgr.GetCache(),
+		producer:    producer,
+		leafHubName: agentConfig.LeafHubName,
+		interval:    agentConfig.SpecDriftCorrectionInterval,
+		version:     eventversion.NewVersion(),
+		desired:     map[objectKey]*unstructured.Unstructured{},
+		suspects:    sets.New[objectKey](),
+		watched:     sets.New[schema.GroupVersionKind](),
+	}
+}
+
+// Record tracks the desired state of the object applied by the agent. The object recorded the first time is checked
+// by the next correction, since it might be changed while the agent isn't running.
+func (c *Corrector) Record(obj *unstructured.Unstructured) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	key := keyOf(obj)
+	if _, found := c.desired[key]; !found {
+		c.suspects.Insert(key)
+	}
+	c.desired[key] = obj.DeepCopy()
+	if c.ctx != nil && !c.watched.Has(key.gvk) {
+		c.watched.Insert(key.gvk)
+		go c.watch(c.ctx, key.gvk)
+	}
+}
+
+// Forget stops tracking the object deleted by the agent.
+func (c *Corrector) Forget(obj *unstructured.Unstructured) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	key := keyOf(obj)
+	delete(c.desired, key)
+	c.suspects.Delete(key)
+}
+
+func (c *Corrector) Start(ctx context.Context) error {
+	c.log.Info("started drift corrector", "interval", c.interval)
+	c.seed(ctx)
+
+	c.mutex.Lock()
+	c.ctx = ctx
+	for key := range c.desired {
+		if !c.watched.Has(key.gvk) {
+			c.watched.Insert(key.gvk)
+			go c.watch(ctx, key.gvk)
+		}
+	}
+	c.mutex.Unlock()
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			c.log.Info("stopped drift corrector")
+			return nil
+		case <-ticker.C:
+			if err := c.correct(ctx); err != nil {
+				c.log.Error(err, "failed to correct the drifted objects")
+			}
+		}
+	}
+}
+
+// seed tracks the global resources applied before the agent starts, since the bundles which are already applied
+// aren't applied again after the restart. The desired state is the fields applied by the agent on the managed hub.
+func (c *Corrector) seed(ctx context.Context) {
+	for _, gvk := range globalResourceKinds {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := c.client.List(ctx, list); err != nil {
+			// the kind isn't served by the managed hub
+			if !meta.IsNoMatchError(err) {
+				c.log.Error(err, "failed to list the global resources", "gvk", gvk)
+			}
+			continue
+		}
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if _, found := obj.GetAnnotations()[constants.OriginOwnerReferenceAnnotation]; !found {
+				continue
+			}
+			obj.SetGroupVersionKind(gvk)
+			desired, err := appliedState(obj)
+			if err != nil {
+				c.log.Error(err, "failed to get the applied state", "gvk", gvk, "object", client.ObjectKeyFromObject(obj))
+				continue
+			}
+			if desired == nil {
+				continue
+			}
+
+			c.mutex.Lock()
+			// the object recorded by the syncer is newer than the one on the managed hub
+			if key := keyOf(desired); c.desired[key] == nil {
+				c.desired[key] = desired
+			}
+			c.mutex.Unlock()
+		}
+	}
+}
+
+// watch marks the object as a suspect once it's updated or deleted by others than the agent
+func (c *Corrector) watch(ctx context.Context, gvk schema.GroupVersionKind) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	informer, err := c.cache.GetInformer(ctx, obj)
+	if err != nil {
+		c.log.Error(err, "failed to watch the global resources", "gvk", gvk)
+		c.mutex.Lock()
+		c.watched.Delete(gvk)
+		c.mutex.Unlock()
+		return
+	}
+	if _, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(_, newObj interface{}) { c.onChanged(newObj, false) },
+		DeleteFunc: func(obj interface{}) { c.onChanged(obj, true) },
+	}); err != nil {
+		c.log.Error(err, "failed to add the event handler", "gvk", gvk)
+	}
+}
+
+func (c *Corrector) onChanged(obj interface{}, deleted bool) {
+	if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+	if _, found := u.GetAnnotations()[constants.OriginOwnerReferenceAnnotation]; !found {
+		return
+	}
+	// skip the changes applied by the agent itself
+	if !deleted && lastManager(u) == FieldManager {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	key := keyOf(u)
+	if _, found := c.desired[key]; found {
+		c.suspects.Insert(key)
+	}
+}
+
+// correct reverts the drifted objects to the desired state and reports the drifts to the global hub
+func (c *Corrector) correct(ctx context.Context) error {
+	c.mutex.Lock()
+	suspects := c.suspects
+	c.suspects = sets.New[objectKey]()
+	c.mutex.Unlock()
+
+	driftEvents := event.SpecDriftEventBundle{}
+	for key := range suspects {
+		c.mutex.Lock()
+		desired, found := c.desired[key]
+		c.mutex.Unlock()
+		if !found {
+			continue
+		}
+
+		reason, message, err := c.detect(ctx, desired)
+		if err != nil {
+			c.log.Error(err, "failed to detect the drift", "gvk", key.gvk, "object", key.NamespacedName)
+			c.retry(key)
+			continue
+		}
+		if reason == "" {
+			continue
+		}
+
+		driftEvent := models.SpecDriftEvent{
+			ObjectID:        desired.GetAnnotations()[constants.OriginOwnerReferenceAnnotation],
+			ObjectKind:      desired.GetKind(),
+			ObjectNamespace: desired.GetNamespace(),
+			ObjectName:      desired.GetName(),
+			Reason:          reason,
+			Message:         message,
+			CreatedAt:       time.Now(),
+		}
+		if err := utils.ApplyObject(ctx, c.client, desired.DeepCopy(), FieldManager, false); err != nil {
+			c.log.Error(err, "failed to revert the drift", "gvk", key.gvk, "object", key.NamespacedName)
+			driftEvent.Message = fmt.Sprintf("%s, failed to revert it: %v", message, err)
+			c.retry(key)
+		} else {
+			c.log.Info("reverted the drift", "gvk", key.gvk, "object", key.NamespacedName, "reason", reason)
+			driftEvent.Reverted = true
+		}
+		driftEvents = append(driftEvents, driftEvent)
+	}
+
+	if len(driftEvents) == 0 {
+		return nil
+	}
+	return c.report(ctx, driftEvents)
+}
+
+// retry checks the object again by the next correction
+func (c *Corrector) retry(key objectKey) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.suspects.Insert(key)
+}
+
+// detect returns the reason and message if the object on the managed hub is different from the desired state
+func (c *Corrector) detect(ctx context.Context, desired *unstructured.Unstructured) (string, string, error) {
+	live := &unstructured.Unstructured{}
+	live.SetGroupVersionKind(desired.GroupVersionKind())
+	if err := c.client.Get(ctx, client.ObjectKeyFromObject(desired), live); err != nil {
+		if errors.IsNotFound(err) {
+			return DriftReasonDeleted, "the object is deleted on the managed hub", nil
+		}
+		return "", "", err
+	}
+
+	// the dry run returns the object which would be persisted once the desired state is applied
+	applied := desired.DeepCopy()
+	if err := utils.ApplyObject(ctx, c.client, applied, FieldManager, true); err != nil {
+		return "", "", err
+	}
+	if !drifted(live, applied) {
+		return "", "", nil
+	}
+	return DriftReasonModified, fmt.Sprintf("the object is changed by %s on the managed hub", lastManager(live)), nil
+}
+
+func (c *Corrector) report(ctx context.Context, driftEvents event.SpecDriftEventBundle) error {
+	c.version.Incr()
+	evt := cloudevents.NewEvent()
+	evt.SetSource(c.leafHubName)
+	evt.SetType(string(enum.SpecDriftEventType))
+	evt.SetExtension(eventversion.ExtVersion, c.version.String())
+	if err := evt.SetData(cloudevents.ApplicationJSON, driftEvents); err != nil {
+		return fmt.Errorf("failed to set the drift events: %w", err)
+	}
+	if err := c.producer.SendEvent(ctx, evt); err != nil {
+		return fmt.Errorf("failed to send the drift events: %w", err)
+	}
+	c.version.Next()
+	return nil
+}
+
+// drifted returns true if the live object is different from the object with the desired state applied, the
+// metadata maintained by the api server is ignored
+func drifted(live, applied *unstructured.Unstructured) bool {
+	normalize := func(obj *unstructured.Unstructured) map[string]interface{} {
+		normalized := obj.DeepCopy()
+		normalized.SetResourceVersion("")
+		normalized.SetGeneration(0)
+		normalized.SetManagedFields(nil)
+		return normalized.Object
+	}
+	return !equality.Semantic.DeepEqual(normalize(live), normalize(applied))
+}
+
+// appliedState returns the fields of the object applied by the agent, the fields applied by the previous field manager
+// are returned if it isn't applied since the field manager is upgraded. It's nil if the object isn't applied by them.
+func appliedState(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	for _, fieldManager := range []string{FieldManager, utils.UpdateFieldManager} {
+		if !utils.HasFieldManager(obj, fieldManager) {
+			continue
+		}
+		applied := map[string]interface{}{}
+		if err := managedfields.ExtractInto(obj, typed.DeducedParseableType, fieldManager, &applied, ""); err != nil {
+			return nil, err
+		}
+		desired := &unstructured.Unstructured{Object: applied}
+		desired.SetGroupVersionKind(obj.GroupVersionKind())
+		desired.SetNamespace(obj.GetNamespace())
+		desired.SetName(obj.GetName())
+		return desired, nil
+	}
+	return nil, nil
+}
+
+// lastManager returns the field manager which changed the object last, the changes of the subresources are ignored
+func lastManager(obj *unstructured.Unstructured) string {
+	manager := ""
+	var lastTime time.Time
+	for _, entry := range obj.GetManagedFields() {
+		if entry.Subresource != "" || entry.Time == nil {
+			continue
+		}
+		if manager == "" || !entry.Time.Time.Before(lastTime) {
+			manager = entry.Manager
+			lastTime = entry.Time.Time
+		}
+	}
+	return manager
+}
@@ -0,0 +1,144 @@
+package drift
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	toolscache "k8s.io/client-go/tools/cache"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+func newPolicy(name string, remediation string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("policy.open-cluster-management.io/v1")
+	obj.SetKind("Policy")
+	obj.SetNamespace("default")
+	obj.SetName(name)
+	obj.SetAnnotations(map[string]string{
+		constants.OriginOwnerReferenceAnnotation: "2aa5547c-c172-47ed-b70b-db468c84d327",
+	})
+	_ = unstructured.SetNestedField(obj.Object, remediation, "spec", "remediationAction")
+	return obj
+}
+
+func newCorrector() *Corrector {
+	return &Corrector{
+		desired:  map[objectKey]*unstructured.Unstructured{},
+		suspects: sets.New[objectKey](),
+		watched:  sets.New[schema.GroupVersionKind](),
+	}
+}
+
+func TestDrifted(t *testing.T) {
+	applied := newPolicy("policy1", "inform")
+	applied.SetResourceVersion("1")
+	applied.SetGeneration(1)
+
+	live := applied.DeepCopy()
+	live.SetResourceVersion("2")
+	live.SetGeneration(2)
+	live.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "kubectl"}})
+	assert.False(t, drifted(live, applied))
+
+	_ = unstructured.SetNestedField(live.Object, "enforce", "spec", "remediationAction")
+	assert.True(t, drifted(live, applied))
+}
+
+func TestLastManager(t *testing.T) {
+	t0 := metav1.NewTime(time.Now())
+	t1 := metav1.NewTime(t0.Add(time.Minute))
+
+	obj := newPolicy("policy1", "inform")
+	assert.Equal(t, "", lastManager(obj))
+
+	obj.SetManagedFields([]metav1.ManagedFieldsEntry{
+		{Manager: FieldManager, Time: &t0},
+		{Manager: "kubectl-edit", Time: &t1},
+		{Manager: "governance-policy-propagator", Time: &t1, Subresource: "status"},
+	})
+	assert.Equal(t, "kubectl-edit", lastManager(obj))
+}
+
+func TestAppliedState(t *testing.T) {
+	appliedFields := &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:annotations":{` +
+		`"f:global-hub.open-cluster-management.io/origin-ownerreference-uid":{}}},"f:spec":{"f:remediationAction":{}}}`)}
+	live := newPolicy("policy1", "inform")
+	live.SetResourceVersion("2")
+	_ = unstructured.SetNestedField(live.Object, true, "spec", "disabled")
+
+	// the object isn't applied by the agent
+	desired, err := appliedState(live)
+	assert.NoError(t, err)
+	assert.Nil(t, desired)
+
+	for _, fieldManager := range []string{FieldManager, utils.UpdateFieldManager} {
+		live.SetManagedFields([]metav1.ManagedFieldsEntry{
+			{Manager: fieldManager, Operation: metav1.ManagedFieldsOperationApply, FieldsV1: appliedFields},
+			{
+				Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate,
+				FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:disabled":{}}}`)},
+			},
+		})
+		desired, err = appliedState(live)
+		assert.NoError(t, err)
+		// only the fields applied by the agent are desired
+		assert.Equal(t, keyOf(live), keyOf(desired))
+		assert.Equal(t, live.GetAnnotations(), desired.GetAnnotations())
+		assert.Equal(t, "", desired.GetResourceVersion())
+		remediation, _, _ := unstructured.NestedString(desired.Object, "spec", "remediationAction")
+		assert.Equal(t, "inform", remediation)
+		_, found, _ := unstructured.NestedFieldNoCopy(desired.Object, "spec", "disabled")
+		assert.False(t, found)
+	}
+}
+
+func TestCorrectorSuspects(t *testing.T) {
+	// the corrector is disabled
+	var disabled *Corrector
+	disabled.Record(newPolicy("policy1", "inform"))
+	disabled.Forget(newPolicy("policy1", "inform"))
+
+	c := newCorrector()
+	policy1, policy2 := newPolicy("policy1", "inform"), newPolicy("policy2", "inform")
+
+	// the object recorded the first time is checked by the next correction
+	c.Record(policy1)
+	assert.True(t, c.suspects.Has(keyOf(policy1)))
+	c.suspects.Delete(keyOf(policy1))
+	c.Record(policy1)
+	assert.False(t, c.suspects.Has(keyOf(policy1)))
+
+	// the changes applied by the agent are skipped
+	t0 := metav1.NewTime(time.Now())
+	updated := policy1.DeepCopy()
+	updated.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: FieldManager, Time: &t0}})
+	c.onChanged(updated, false)
+	assert.False(t, c.suspects.Has(keyOf(policy1)))
+
+	// the changes applied by others are checked
+	updated.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "kubectl-edit", Time: &t0}})
+	c.onChanged(updated, false)
+	assert.True(t, c.suspects.Has(keyOf(policy1)))
+
+	// the objects without the origin annotation or not recorded are skipped
+	c.onChanged(toolscache.DeletedFinalStateUnknown{Obj: policy2}, true)
+	assert.False(t, c.suspects.Has(keyOf(policy2)))
+	local := policy1.DeepCopy()
+	local.SetName("local-policy")
+	local.SetAnnotations(nil)
+	c.onChanged(local, true)
+	assert.False(t, c.suspects.Has(keyOf(local)))
+
+	// the forgotten object isn't checked anymore
+	c.Forget(policy1)
+	assert.False(t, c.suspects.Has(keyOf(policy1)))
+	_, found := c.desired[keyOf(policy1)]
+	assert.False(t, found)
+}
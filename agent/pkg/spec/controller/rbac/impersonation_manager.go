@@ -8,15 +8,18 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 )
 
 const (
 	// NoIdentity is used to mark no identity is defined on a resource.
 	NoIdentity = ""
 	// UserIdentityAnnotation is the annotation that is used to store the user identity.
-	UserIdentityAnnotation = "open-cluster-management.io/user-identity"
+	UserIdentityAnnotation = constants.UserIdentityAnnotation
 	// UserGroupsAnnotation is the annotation that is used to store the user groups.
-	UserGroupsAnnotation = "open-cluster-management.io/user-group"
+	UserGroupsAnnotation = constants.UserGroupsAnnotation
 )
 
 // NewImpersonationManager creates a new instance of ImpersonationManager.
@@ -40,7 +43,8 @@ func (manager *ImpersonationManager) Impersonate(userIdentity string, userGroups
 		Extra:    nil,
 	}
 
-	userK8sClient, err := client.New(newConfig, client.Options{})
+	// the impersonated client works with the same types as the workers of the agent
+	userK8sClient, err := client.New(newConfig, client.Options{Scheme: config.GetRuntimeScheme()})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create new k8s client for user - %w", err)
 	}
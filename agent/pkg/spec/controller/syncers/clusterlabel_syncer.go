@@ -83,7 +83,7 @@ func (syncer *managedClusterLabelsBundleSyncer) handleBundle() {
 func (syncer *managedClusterLabelsBundleSyncer) updateManagedClusterAsync(
 	labelsSpec *specbundle.ManagedClusterLabelsSpec, lastProcessedTimestampPtr *time.Time,
 ) {
-	syncer.workerPool.Submit(workers.NewJob(labelsSpec, func(ctx context.Context,
+	err := syncer.workerPool.Submit(workers.NewJob(labelsSpec, func(ctx context.Context,
 		k8sClient client.Client, obj interface{},
 	) {
 		defer syncer.bundleProcessingWaitingGroup.Done()
@@ -135,6 +135,10 @@ func (syncer *managedClusterLabelsBundleSyncer) updateManagedClusterAsync(
 		syncer.log.V(2).Info("managed cluster updated", "name", labelsSpec.ClusterName)
 		syncer.managedClusterMarkUpdated(labelsSpec, lastProcessedTimestampPtr)
 	}))
+	if err != nil {
+		syncer.log.Error(err, "failed to submit the labels", "cluster", labelsSpec.ClusterName)
+		syncer.bundleProcessingWaitingGroup.Done()
+	}
 }
 
 func (syncer *managedClusterLabelsBundleSyncer) managedClusterMarkUpdated(
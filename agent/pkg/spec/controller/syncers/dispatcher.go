@@ -8,6 +8,7 @@ import (
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/config"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
@@ -61,8 +62,9 @@ func (d *genericDispatcher) dispatch(ctx context.Context) {
 				d.log.Info("dropping bundle due to invalid cluster name", "clusterName", clusterNameVal)
 				continue
 			}
+			// the manager sends the objects bound to the placements only to the selected hubs
 			if clusterName != transport.Broadcast && clusterName != d.agentConfig.LeafHubName {
-				d.log.Info("dropping bundle due to cluster name mismatch", "clusterName", clusterName)
+				d.log.V(2).Info("dropping bundle due to cluster name mismatch", "clusterName", clusterName)
 				continue
 			}
 			syncer, found := d.syncers[evt.Type()]
@@ -75,7 +77,11 @@ func (d *genericDispatcher) dispatch(ctx context.Context) {
 					"syncer", syncer, "event", evt)
 				continue
 			}
-			if err := syncer.Sync(ctx, evt.Data()); err != nil {
+			// the span continues the one of sending the bundle from the manager
+			syncCtx, span := tracing.Start(ctx, "spec.sync", evt)
+			err = syncer.Sync(syncCtx, evt.Data())
+			tracing.End(span, err)
+			if err != nil {
 				d.log.Error(err, "submit to syncer error", "eventType", evt.Type())
 			}
 		}
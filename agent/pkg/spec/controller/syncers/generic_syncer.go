@@ -11,9 +11,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/controller/drift"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/controller/rbac"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/controller/workers"
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/utils"
 )
 
@@ -23,14 +25,21 @@ type genericBundleSyncer struct {
 	workerPool                   *workers.WorkerPool
 	bundleProcessingWaitingGroup sync.WaitGroup
 	enforceHohRbac               bool
+	watermarks                   *watermarkStore
+	// corrector reverts the drift of the applied objects, it's nil if the drift correction is disabled
+	corrector *drift.Corrector
 }
 
-func NewGenericSyncer(workerPool *workers.WorkerPool, config *config.AgentConfig) *genericBundleSyncer {
+func NewGenericSyncer(workerPool *workers.WorkerPool, runtimeClient client.Client, corrector *drift.Corrector,
+	config *config.AgentConfig,
+) *genericBundleSyncer {
 	return &genericBundleSyncer{
 		log:                          ctrl.Log.WithName("generic-bundle-syncer"),
 		workerPool:                   workerPool,
 		bundleProcessingWaitingGroup: sync.WaitGroup{},
 		enforceHohRbac:               config.SpecEnforceHohRbac,
+		watermarks:                   newWatermarkStore(runtimeClient, config.PodNamespace),
+		corrector:                    corrector,
 	}
 }
 
@@ -40,11 +49,51 @@ func (syncer *genericBundleSyncer) Sync(ctx context.Context, payload []byte) err
 		return err
 	}
 
+	applicable, err := syncer.applicable(ctx, &genericBundle.BundleWatermark)
+	if err != nil {
+		return err
+	}
+	if !applicable {
+		syncer.log.V(2).Info("skip the applied bundle", "key", genericBundle.Key,
+			"watermark", genericBundle.Watermark)
+		return nil
+	}
+
 	syncer.bundleProcessingWaitingGroup.Add(len(genericBundle.Objects) + len(genericBundle.DeletedObjects))
 	syncer.syncObjects(genericBundle.Objects)
 	syncer.syncDeletedObjects(genericBundle.DeletedObjects)
 	syncer.bundleProcessingWaitingGroup.Wait()
-	return nil
+
+	if genericBundle.Key == "" || genericBundle.Watermark == nil {
+		return nil
+	}
+	return syncer.watermarks.set(ctx, genericBundle.Key, *genericBundle.Watermark)
+}
+
+// applicable returns false if the bundle is older than the last applied bundle with the same key. The delta bundle
+// which doesn't follow the applied one is still applied, the missed changes converge on the next full state bundle.
+func (syncer *genericBundleSyncer) applicable(ctx context.Context, watermark *spec.BundleWatermark) (bool, error) {
+	if watermark.Key == "" || watermark.Watermark == nil {
+		return true, nil
+	}
+	applied, found, err := syncer.watermarks.get(ctx, watermark.Key)
+	if err != nil {
+		return false, err
+	}
+	if !found {
+		return true, nil
+	}
+	if watermark.IsDelta() {
+		if !watermark.Watermark.After(applied) {
+			return false, nil
+		}
+		if watermark.BaseWatermark != nil && watermark.BaseWatermark.After(applied) {
+			syncer.log.Info("the delta bundle doesn't follow the applied bundle, waiting for the full state bundle",
+				"key", watermark.Key, "baseWatermark", watermark.BaseWatermark, "appliedWatermark", applied)
+		}
+		return true, nil
+	}
+	return !watermark.Watermark.Before(applied), nil
 }
 
 func (syncer *genericBundleSyncer) syncObjects(bundleObjects []*unstructured.Unstructured) {
@@ -53,22 +102,13 @@ func (syncer *genericBundleSyncer) syncObjects(bundleObjects []*unstructured.Uns
 			bundleObject = syncer.anonymize(bundleObject) // anonymize removes the user identity from the obj if exists
 		}
 
-		syncer.workerPool.Submit(workers.NewJob(bundleObject, func(ctx context.Context,
+		err := syncer.workerPool.Submit(workers.NewJob(bundleObject, func(ctx context.Context,
 			k8sClient client.Client, obj interface{},
 		) {
 			defer syncer.bundleProcessingWaitingGroup.Done()
 
 			unstructuredObject, _ := obj.(*unstructured.Unstructured)
 
-			if !syncer.enforceHohRbac { // if rbac not enforced, create missing namespaces.
-				if err := utils.CreateNamespaceIfNotExist(ctx, k8sClient,
-					unstructuredObject.GetNamespace()); err != nil {
-					syncer.log.Error(err, "failed to create namespace",
-						"namespace", unstructuredObject.GetNamespace())
-					return
-				}
-			}
-
 			// Deprecated: skip the "bindingOverrides" from the placementbinding
 			// Reference: https://github.com/open-cluster-management-io/governance-policy-propagator/pull/110
 			delete(unstructuredObject.Object, "bindingOverrides")
@@ -86,15 +126,46 @@ func (syncer *genericBundleSyncer) syncObjects(bundleObjects []*unstructured.Uns
 			}
 
 			delete(unstructuredObject.Object, "status")
-			err := utils.UpdateObject(ctx, k8sClient, unstructuredObject)
+			desired := unstructuredObject.DeepCopy()
+
+			if unchanged(ctx, k8sClient, unstructuredObject) {
+				syncer.corrector.Record(desired)
+				syncer.log.V(2).Info("object is unchanged", "name", unstructuredObject.GetName(), "namespace",
+					unstructuredObject.GetNamespace(), "kind", unstructuredObject.GetKind())
+				return
+			}
+
+			if !syncer.enforceHohRbac { // if rbac not enforced, create missing namespaces.
+				if err := utils.CreateNamespaceIfNotExist(ctx, k8sClient,
+					unstructuredObject.GetNamespace()); err != nil {
+					syncer.log.Error(err, "failed to create namespace",
+						"namespace", unstructuredObject.GetNamespace())
+					return
+				}
+			}
+
+			err := utils.ApplyObject(ctx, k8sClient, unstructuredObject, drift.FieldManager, false)
 			if err != nil {
 				syncer.log.Error(err, "failed to update object", "name", unstructuredObject.GetName(),
 					"namespace", unstructuredObject.GetNamespace(), "kind", unstructuredObject.GetKind())
 				return
 			}
+			// the fields applied by the previous field manager are owned by the current one after the first apply, it's
+			// retried by the next apply of the object if failed
+			err = utils.UpgradeFieldManager(ctx, k8sClient, unstructuredObject, drift.FieldManager)
+			if err != nil {
+				syncer.log.Error(err, "failed to upgrade the field manager", "name", unstructuredObject.GetName(),
+					"namespace", unstructuredObject.GetNamespace(), "kind", unstructuredObject.GetKind())
+			}
+			syncer.corrector.Record(desired)
 			syncer.log.V(2).Info("object updated", "name", unstructuredObject.GetName(), "namespace",
 				unstructuredObject.GetNamespace(), "kind", unstructuredObject.GetKind())
 		}))
+		if err != nil {
+			syncer.log.Error(err, "failed to submit the object", "name", bundleObject.GetName(),
+				"namespace", bundleObject.GetNamespace(), "kind", bundleObject.GetKind())
+			syncer.bundleProcessingWaitingGroup.Done()
+		}
 	}
 }
 
@@ -104,12 +175,13 @@ func (syncer *genericBundleSyncer) syncDeletedObjects(deletedObjects []*unstruct
 			deletedBundleObj = syncer.anonymize(deletedBundleObj) // anonymize removes the user identity from the obj if exists
 		}
 
-		syncer.workerPool.Submit(workers.NewJob(deletedBundleObj, func(ctx context.Context,
+		err := syncer.workerPool.Submit(workers.NewJob(deletedBundleObj, func(ctx context.Context,
 			k8sClient client.Client, obj interface{},
 		) {
 			defer syncer.bundleProcessingWaitingGroup.Done()
 
 			unstructuredObject, _ := obj.(*unstructured.Unstructured)
+			syncer.corrector.Forget(unstructuredObject)
 
 			// syncer.deleteObject(ctx, k8sClient, obj.(*unstructured.Unstructured))
 			if deleted, err := utils.DeleteObject(ctx, k8sClient, unstructuredObject); err != nil {
@@ -121,7 +193,28 @@ func (syncer *genericBundleSyncer) syncDeletedObjects(deletedObjects []*unstruct
 					"namespace", unstructuredObject.GetNamespace(), "kind", unstructuredObject.GetKind())
 			}
 		}))
+		if err != nil {
+			syncer.log.Error(err, "failed to submit the deleted object", "name", deletedBundleObj.GetName(),
+				"namespace", deletedBundleObj.GetNamespace(), "kind", deletedBundleObj.GetKind())
+			syncer.bundleProcessingWaitingGroup.Done()
+		}
+	}
+}
+
+// unchanged returns true if the object on the managed hub has the same spec version as the received object
+func unchanged(ctx context.Context, k8sClient client.Client, obj *unstructured.Unstructured) bool {
+	version, found := obj.GetAnnotations()[constants.SpecVersionAnnotation]
+	if !found || version == "" {
+		return false
+	}
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(obj.GroupVersionKind())
+	if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), existing); err != nil {
+		return false
 	}
+	// the object applied by the previous field manager is applied again to upgrade the field manager
+	return existing.GetAnnotations()[constants.SpecVersionAnnotation] == version &&
+		!utils.HasFieldManager(existing, utils.UpdateFieldManager)
 }
 
 func (syncer *genericBundleSyncer) anonymize(obj *unstructured.Unstructured) *unstructured.Unstructured {
@@ -0,0 +1,109 @@
+package syncers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/config"
+	specbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+func TestGenericSyncerApplicable(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	syncer := NewGenericSyncer(nil, c, nil, &config.AgentConfig{PodNamespace: "default"})
+
+	t0 := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	t1, t2 := t0.Add(time.Minute), t0.Add(2*time.Minute)
+	watermark := func(bundleType string, base, watermark time.Time) *specbundle.BundleWatermark {
+		return &specbundle.BundleWatermark{
+			Key: "Policies", BundleType: bundleType, BaseWatermark: &base, Watermark: &watermark,
+		}
+	}
+
+	// the bundle from the manager without the watermark is always applied
+	applicable, err := syncer.applicable(ctx, &specbundle.BundleWatermark{})
+	assert.NoError(t, err)
+	assert.True(t, applicable)
+
+	applicable, err = syncer.applicable(ctx, watermark(specbundle.DeltaStateBundle, t0, t1))
+	assert.NoError(t, err)
+	assert.True(t, applicable)
+	assert.NoError(t, syncer.watermarks.set(ctx, "Policies", t1))
+
+	// the watermark is loaded from the configmap after restarting
+	syncer = NewGenericSyncer(nil, c, nil, &config.AgentConfig{PodNamespace: "default"})
+	cases := []struct {
+		name       string
+		watermark  *specbundle.BundleWatermark
+		applicable bool
+	}{
+		{"applied delta bundle", watermark(specbundle.DeltaStateBundle, t0, t1), false},
+		{"newer delta bundle", watermark(specbundle.DeltaStateBundle, t1, t2), true},
+		{"delta bundle after the missed one", watermark(specbundle.DeltaStateBundle, t2, t2.Add(time.Minute)), true},
+		{"stale full state bundle", watermark(specbundle.FullStateBundle, time.Time{}, t0), false},
+		{"current full state bundle", watermark(specbundle.FullStateBundle, time.Time{}, t1), true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			applicable, err := syncer.applicable(ctx, tc.watermark)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.applicable, applicable)
+		})
+	}
+
+	cm := &corev1.ConfigMap{}
+	assert.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "default", Name: SpecStateConfigMapName}, cm))
+	assert.Equal(t, t1.Format(time.RFC3339Nano), cm.Data["Policies"])
+}
+
+func TestUnchangedObject(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cm1", Namespace: "default",
+			Annotations: map[string]string{constants.SpecVersionAnnotation: "v1"},
+		},
+	}, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cm3", Namespace: "default",
+			Annotations: map[string]string{constants.SpecVersionAnnotation: "v1"},
+			ManagedFields: []metav1.ManagedFieldsEntry{{
+				Manager: utils.UpdateFieldManager, Operation: metav1.ManagedFieldsOperationApply,
+			}},
+		},
+	}).Build()
+
+	object := func(name, version string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("ConfigMap")
+		obj.SetName(name)
+		obj.SetNamespace("default")
+		if version != "" {
+			obj.SetAnnotations(map[string]string{constants.SpecVersionAnnotation: version})
+		}
+		return obj
+	}
+	assert.True(t, unchanged(ctx, c, object("cm1", "v1")))
+	assert.False(t, unchanged(ctx, c, object("cm1", "v2")))
+	assert.False(t, unchanged(ctx, c, object("cm1", "")))
+	assert.False(t, unchanged(ctx, c, object("cm2", "v1")))
+	// the object applied by the previous field manager is applied again
+	assert.False(t, unchanged(ctx, c, object("cm3", "v1")))
+}
@@ -0,0 +1,308 @@
+package syncers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/controller/rbac"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/controller/workers"
+	specbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+const (
+	// the manifestwork which updates the ClusterVersion of the managed cluster
+	clusterUpgradeWorkName = "global-hub-cluster-upgrade"
+	// the work agent only accepts the field manager with the work-agent prefix
+	clusterUpgradeFieldManager = "work-agent-global-hub"
+)
+
+// applyActionFunc applies the lifecycle action to the managed cluster with the client of the requester
+type applyActionFunc func(ctx context.Context, k8sClient client.Client,
+	bundle *specbundle.ManagedClusterActionBundle) error
+
+// managedClusterActionSyncer applies a lifecycle action to the managed cluster as the user requesting it on the
+// global hub, and reports the result back to the global hub manager.
+type managedClusterActionSyncer struct {
+	log         logr.Logger
+	leafHubName string
+	workerPool  *workers.WorkerPool
+	producer    transport.Producer
+	apply       applyActionFunc
+	// the results are sent by the workers of the requesters concurrently
+	versionLock sync.Mutex
+	version     *eventversion.Version
+}
+
+// NewManagedClusterDetachSyncer detaches the managed cluster by deleting it from the managed hub
+func NewManagedClusterDetachSyncer(workers *workers.WorkerPool, producer transport.Producer,
+	leafHubName string,
+) *managedClusterActionSyncer {
+	return newManagedClusterActionSyncer("managed-cluster-detach-syncer", workers, producer, leafHubName,
+		detachManagedCluster)
+}
+
+// NewManagedClusterDenyClientSyncer sets the hubAcceptsClient of the managed cluster to false
+func NewManagedClusterDenyClientSyncer(workers *workers.WorkerPool, producer transport.Producer,
+	leafHubName string,
+) *managedClusterActionSyncer {
+	return newManagedClusterActionSyncer("managed-cluster-deny-client-syncer", workers, producer, leafHubName,
+		denyManagedClusterClient)
+}
+
+// NewManagedClusterUpgradeSyncer upgrades the OpenShift managed cluster by the ClusterVersion manifestwork
+func NewManagedClusterUpgradeSyncer(workers *workers.WorkerPool, producer transport.Producer,
+	leafHubName string,
+) *managedClusterActionSyncer {
+	return newManagedClusterActionSyncer("managed-cluster-upgrade-syncer", workers, producer, leafHubName,
+		upgradeManagedCluster)
+}
+
+// NewKlusterletBootstrapRotationSyncer rotates the bootstrap token of the klusterlet
+func NewKlusterletBootstrapRotationSyncer(workers *workers.WorkerPool, producer transport.Producer,
+	leafHubName string,
+) *managedClusterActionSyncer {
+	return newManagedClusterActionSyncer("klusterlet-bootstrap-rotation-syncer", workers, producer, leafHubName,
+		rotateKlusterletBootstrap)
+}
+
+func newManagedClusterActionSyncer(name string, workers *workers.WorkerPool, producer transport.Producer,
+	leafHubName string, apply applyActionFunc,
+) *managedClusterActionSyncer {
+	return &managedClusterActionSyncer{
+		log:         ctrl.Log.WithName(name),
+		leafHubName: leafHubName,
+		workerPool:  workers,
+		producer:    producer,
+		version:     eventversion.NewVersion(),
+		apply:       apply,
+	}
+}
+
+func (syncer *managedClusterActionSyncer) Sync(ctx context.Context, payload []byte) error {
+	bundle := &specbundle.ManagedClusterActionBundle{}
+	if err := json.Unmarshal(payload, bundle); err != nil {
+		return err
+	}
+	syncer.log.Info("apply the managed cluster action", "action", bundle.ActionID, "cluster", bundle.ClusterName)
+
+	// the action is applied by the worker, and the result is reported once it's done, so the spec dispatcher isn't
+	// blocked by the action
+	if err := syncer.applyAsRequesterAsync(bundle); err != nil {
+		return syncer.sendResult(ctx, syncer.actionResult(bundle, err))
+	}
+	return nil
+}
+
+// applyAsRequesterAsync submits the action with the identity of the requester, so that the worker pool impersonates
+// the requester and the action is authorized by the RBAC of the managed hub
+func (syncer *managedClusterActionSyncer) applyAsRequesterAsync(bundle *specbundle.ManagedClusterActionBundle) error {
+	if bundle.UserIdentity == "" {
+		return errors.New("the requester of the action isn't specified")
+	}
+	// verify the identity before submitting, so the error is reported with the cause
+	for _, value := range []string{bundle.UserIdentity, bundle.UserGroups} {
+		if _, err := base64.StdEncoding.DecodeString(value); err != nil {
+			return fmt.Errorf("failed to decode the requester of the action: %w", err)
+		}
+	}
+	requester := &unstructured.Unstructured{}
+	annotations := map[string]string{rbac.UserIdentityAnnotation: bundle.UserIdentity}
+	if bundle.UserGroups != "" {
+		annotations[rbac.UserGroupsAnnotation] = bundle.UserGroups
+	}
+	requester.SetAnnotations(annotations)
+
+	err := syncer.workerPool.Submit(workers.NewJob(requester, func(ctx context.Context,
+		k8sClient client.Client, obj interface{},
+	) {
+		result := syncer.actionResult(bundle, syncer.apply(ctx, k8sClient, bundle))
+		if err := syncer.sendResult(ctx, result); err != nil {
+			syncer.log.Error(err, "failed to report the action", "action", bundle.ActionID, "cluster",
+				bundle.ClusterName)
+		}
+	}))
+	if err != nil {
+		return fmt.Errorf("failed to submit the action: %w", err)
+	}
+	return nil
+}
+
+// actionResult returns the result of the action, which is failed with the message if the error isn't nil
+func (syncer *managedClusterActionSyncer) actionResult(bundle *specbundle.ManagedClusterActionBundle,
+	err error,
+) specbundle.ManagedClusterActionResult {
+	result := specbundle.ManagedClusterActionResult{
+		ActionID:    bundle.ActionID,
+		ClusterName: bundle.ClusterName,
+		Succeeded:   true,
+	}
+	if err != nil {
+		syncer.log.Error(err, "failed to apply the action", "action", bundle.ActionID, "cluster", bundle.ClusterName)
+		result.Succeeded = false
+		result.Message = err.Error()
+	}
+	return result
+}
+
+// sendResult confirms the delivery of the action with the result
+func (syncer *managedClusterActionSyncer) sendResult(ctx context.Context,
+	result specbundle.ManagedClusterActionResult,
+) error {
+	syncer.versionLock.Lock()
+	defer syncer.versionLock.Unlock()
+
+	syncer.version.Incr()
+	evt := cloudevents.NewEvent()
+	evt.SetSource(syncer.leafHubName)
+	evt.SetType(string(enum.ManagedClusterActionResultType))
+	evt.SetExtension(eventversion.ExtVersion, syncer.version.String())
+	if err := evt.SetData(cloudevents.ApplicationJSON, result); err != nil {
+		return fmt.Errorf("failed to set the action result: %w", err)
+	}
+	if err := syncer.producer.SendEvent(ctx, evt); err != nil {
+		return fmt.Errorf("failed to send the action result: %w", err)
+	}
+	syncer.version.Next()
+	return nil
+}
+
+func detachManagedCluster(ctx context.Context, k8sClient client.Client,
+	bundle *specbundle.ManagedClusterActionBundle,
+) error {
+	cluster := &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: bundle.ClusterName}}
+	// the cluster is already detached if it isn't found
+	return client.IgnoreNotFound(k8sClient.Delete(ctx, cluster))
+}
+
+func denyManagedClusterClient(ctx context.Context, k8sClient client.Client,
+	bundle *specbundle.ManagedClusterActionBundle,
+) error {
+	hubAcceptsClient := false
+	return applyOperation(ctx, k8sClient, bundle.ClusterName,
+		&specbundle.ManagedClusterOperation{HubAcceptsClient: &hubAcceptsClient})
+}
+
+func upgradeManagedCluster(ctx context.Context, k8sClient client.Client,
+	bundle *specbundle.ManagedClusterActionBundle,
+) error {
+	if bundle.Upgrade == nil || bundle.Upgrade.Version == "" {
+		return errors.New("the upgrade version isn't specified")
+	}
+	cluster := &clusterv1.ManagedCluster{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Name: bundle.ClusterName}, cluster); err != nil {
+		return err
+	}
+	if cluster.Labels["vendor"] != "OpenShift" {
+		return fmt.Errorf("the managed cluster %s isn't an OpenShift cluster", bundle.ClusterName)
+	}
+
+	manifest, err := clusterVersionManifest(bundle.Upgrade)
+	if err != nil {
+		return err
+	}
+	work := &workv1.ManifestWork{ObjectMeta: metav1.ObjectMeta{
+		Name:      clusterUpgradeWorkName,
+		Namespace: bundle.ClusterName,
+	}}
+	_, err = controllerutil.CreateOrUpdate(ctx, k8sClient, work, func() error {
+		work.Spec.Workload.Manifests = []workv1.Manifest{{RawExtension: runtime.RawExtension{Raw: manifest}}}
+		// only the desired update of the ClusterVersion is owned by the manifestwork, and the ClusterVersion is kept
+		// once the manifestwork is deleted
+		work.Spec.ManifestConfigs = []workv1.ManifestConfigOption{{
+			ResourceIdentifier: workv1.ResourceIdentifier{
+				Group:    "config.openshift.io",
+				Resource: "clusterversions",
+				Name:     "version",
+			},
+			UpdateStrategy: &workv1.UpdateStrategy{
+				Type: workv1.UpdateStrategyTypeServerSideApply,
+				ServerSideApply: &workv1.ServerSideApplyConfig{
+					Force:        true,
+					FieldManager: clusterUpgradeFieldManager,
+				},
+			},
+		}}
+		work.Spec.DeleteOption = &workv1.DeleteOption{PropagationPolicy: workv1.DeletePropagationPolicyTypeOrphan}
+		return nil
+	})
+	return err
+}
+
+// clusterVersionManifest returns the ClusterVersion with the desired update only, the other fields of the spec are
+// left to the cluster by the server side apply
+func clusterVersionManifest(upgrade *specbundle.ClusterUpgrade) ([]byte, error) {
+	desiredUpdate := map[string]interface{}{"version": upgrade.Version}
+	if upgrade.Image != "" {
+		desiredUpdate["image"] = upgrade.Image
+	}
+	if upgrade.Force {
+		desiredUpdate["force"] = true
+	}
+	spec := map[string]interface{}{"desiredUpdate": desiredUpdate}
+	if upgrade.Channel != "" {
+		spec["channel"] = upgrade.Channel
+	}
+	clusterVersion := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "config.openshift.io/v1",
+		"kind":       "ClusterVersion",
+		"metadata":   map[string]interface{}{"name": "version"},
+		"spec":       spec,
+	}}
+	return clusterVersion.MarshalJSON()
+}
+
+// rotateKlusterletBootstrap removes the import secret and the token secrets of the bootstrap service account of the
+// cluster, then the import controller regenerates them with a new bootstrap token
+func rotateKlusterletBootstrap(ctx context.Context, k8sClient client.Client,
+	bundle *specbundle.ManagedClusterActionBundle,
+) error {
+	cluster := &clusterv1.ManagedCluster{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Name: bundle.ClusterName}, cluster); err != nil {
+		return err
+	}
+
+	bootstrapSA := fmt.Sprintf("%s-bootstrap-sa", bundle.ClusterName)
+	secrets := &corev1.SecretList{}
+	if err := k8sClient.List(ctx, secrets, client.InNamespace(bundle.ClusterName)); err != nil {
+		return err
+	}
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if secret.Type != corev1.SecretTypeServiceAccountToken ||
+			secret.Annotations[corev1.ServiceAccountNameKey] != bootstrapSA {
+			continue
+		}
+		if err := k8sClient.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete the bootstrap token %s: %w", secret.Name, err)
+		}
+	}
+
+	importSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Name:      fmt.Sprintf("%s-import", bundle.ClusterName),
+		Namespace: bundle.ClusterName,
+	}}
+	if err := k8sClient.Delete(ctx, importSecret); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete the import secret: %w", err)
+	}
+	return nil
+}
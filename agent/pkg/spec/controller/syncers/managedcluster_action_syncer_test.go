@@ -0,0 +1,112 @@
+package syncers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	specbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+)
+
+func TestApplyManagedClusterAction(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clusterv1.AddToScheme(scheme))
+	assert.NoError(t, workv1.AddToScheme(scheme))
+	assert.NoError(t, corev1.AddToScheme(scheme))
+
+	newCluster := func(vendor string) *clusterv1.ManagedCluster {
+		return &clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster1", Labels: map[string]string{"vendor": vendor}},
+			Spec:       clusterv1.ManagedClusterSpec{HubAcceptsClient: true},
+		}
+	}
+	bundle := &specbundle.ManagedClusterActionBundle{ActionID: "action1", ClusterName: "cluster1"}
+
+	t.Run("detach", func(t *testing.T) {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(newCluster("OpenShift")).Build()
+		assert.NoError(t, detachManagedCluster(ctx, c, bundle))
+		err := c.Get(ctx, client.ObjectKey{Name: "cluster1"}, &clusterv1.ManagedCluster{})
+		assert.True(t, client.IgnoreNotFound(err) == nil && err != nil)
+		// the detached cluster is skipped
+		assert.NoError(t, detachManagedCluster(ctx, c, bundle))
+	})
+
+	t.Run("deny client", func(t *testing.T) {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(newCluster("OpenShift")).Build()
+		assert.NoError(t, denyManagedClusterClient(ctx, c, bundle))
+		cluster := &clusterv1.ManagedCluster{}
+		assert.NoError(t, c.Get(ctx, client.ObjectKey{Name: "cluster1"}, cluster))
+		assert.False(t, cluster.Spec.HubAcceptsClient)
+	})
+
+	t.Run("upgrade", func(t *testing.T) {
+		upgrade := &specbundle.ManagedClusterActionBundle{
+			ActionID:    "action1",
+			ClusterName: "cluster1",
+			Upgrade:     &specbundle.ClusterUpgrade{Version: "4.16.3", Channel: "stable-4.16"},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(newCluster("Kind")).Build()
+		assert.ErrorContains(t, upgradeManagedCluster(ctx, c, upgrade), "isn't an OpenShift cluster")
+
+		c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(newCluster("OpenShift")).Build()
+		assert.ErrorContains(t, upgradeManagedCluster(ctx, c, bundle), "the upgrade version isn't specified")
+		assert.NoError(t, upgradeManagedCluster(ctx, c, upgrade))
+
+		work := &workv1.ManifestWork{}
+		assert.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "cluster1", Name: clusterUpgradeWorkName}, work))
+		assert.Len(t, work.Spec.Workload.Manifests, 1)
+		clusterVersion := &unstructured.Unstructured{}
+		assert.NoError(t, clusterVersion.UnmarshalJSON(work.Spec.Workload.Manifests[0].Raw))
+		assert.Equal(t, "ClusterVersion", clusterVersion.GetKind())
+		version, _, _ := unstructured.NestedString(clusterVersion.Object, "spec", "desiredUpdate", "version")
+		assert.Equal(t, "4.16.3", version)
+		channel, _, _ := unstructured.NestedString(clusterVersion.Object, "spec", "channel")
+		assert.Equal(t, "stable-4.16", channel)
+		_, found, _ := unstructured.NestedFieldNoCopy(clusterVersion.Object, "spec", "clusterID")
+		assert.False(t, found)
+		assert.Equal(t, workv1.UpdateStrategyTypeServerSideApply, work.Spec.ManifestConfigs[0].UpdateStrategy.Type)
+		assert.Equal(t, workv1.DeletePropagationPolicyTypeOrphan, work.Spec.DeleteOption.PropagationPolicy)
+
+		// the manifestwork is updated by the next upgrade
+		upgrade.Upgrade.Version = "4.16.4"
+		assert.NoError(t, upgradeManagedCluster(ctx, c, upgrade))
+		assert.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "cluster1", Name: clusterUpgradeWorkName}, work))
+		assert.Contains(t, string(work.Spec.Workload.Manifests[0].Raw), "4.16.4")
+	})
+
+	t.Run("rotate bootstrap", func(t *testing.T) {
+		token := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster1-bootstrap-sa-token-abcde", Namespace: "cluster1",
+				Annotations: map[string]string{corev1.ServiceAccountNameKey: "cluster1-bootstrap-sa"},
+			},
+			Type: corev1.SecretTypeServiceAccountToken,
+		}
+		otherToken := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "default-token-abcde", Namespace: "cluster1",
+				Annotations: map[string]string{corev1.ServiceAccountNameKey: "default"},
+			},
+			Type: corev1.SecretTypeServiceAccountToken,
+		}
+		importSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "cluster1-import", Namespace: "cluster1"}}
+		c := fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(newCluster("OpenShift"), token, otherToken, importSecret).Build()
+		assert.NoError(t, rotateKlusterletBootstrap(ctx, c, bundle))
+
+		secrets := &corev1.SecretList{}
+		assert.NoError(t, c.List(ctx, secrets, client.InNamespace("cluster1")))
+		assert.Len(t, secrets.Items, 1)
+		assert.Equal(t, "default-token-abcde", secrets.Items[0].Name)
+	})
+}
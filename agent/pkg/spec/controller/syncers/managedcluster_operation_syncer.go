@@ -0,0 +1,183 @@
+package syncers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/controller/workers"
+	specbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// managedClusterOperationSyncer applies the bulk operations to the managed clusters and reports the per-cluster
+// results back to the global hub manager.
+type managedClusterOperationSyncer struct {
+	log         logr.Logger
+	leafHubName string
+	workerPool  *workers.WorkerPool
+	producer    transport.Producer
+	version     *eventversion.Version
+}
+
+func NewManagedClusterOperationSyncer(workers *workers.WorkerPool, producer transport.Producer,
+	leafHubName string,
+) *managedClusterOperationSyncer {
+	return &managedClusterOperationSyncer{
+		log:         ctrl.Log.WithName("managed-clusters-operation-syncer"),
+		leafHubName: leafHubName,
+		workerPool:  workers,
+		producer:    producer,
+		version:     eventversion.NewVersion(),
+	}
+}
+
+func (syncer *managedClusterOperationSyncer) Sync(ctx context.Context, payload []byte) error {
+	bundle := &specbundle.ManagedClusterOperationBundle{}
+	if err := json.Unmarshal(payload, bundle); err != nil {
+		return err
+	}
+	syncer.log.Info("apply the managed cluster operation", "operation", bundle.OperationID,
+		"clusters", len(bundle.Clusters))
+
+	results := make(specbundle.ManagedClusterOperationResultBundle, len(bundle.Clusters))
+	var wg sync.WaitGroup
+	for i, clusterName := range bundle.Clusters {
+		wg.Add(1)
+		err := syncer.workerPool.Submit(workers.NewJob(clusterName, func(ctx context.Context,
+			k8sClient client.Client, obj interface{},
+		) {
+			defer wg.Done()
+			result := specbundle.ManagedClusterOperationResult{
+				OperationID: bundle.OperationID,
+				ClusterName: clusterName,
+				Succeeded:   true,
+			}
+			if err := applyOperation(ctx, k8sClient, clusterName, &bundle.Operation); err != nil {
+				syncer.log.Error(err, "failed to apply the operation", "operation", bundle.OperationID,
+					"cluster", clusterName)
+				result.Succeeded = false
+				result.Message = err.Error()
+			}
+			results[i] = result
+		}))
+		if err != nil {
+			wg.Done()
+			results[i] = specbundle.ManagedClusterOperationResult{
+				OperationID: bundle.OperationID,
+				ClusterName: clusterName,
+				Message:     err.Error(),
+			}
+		}
+	}
+	wg.Wait()
+
+	return syncer.sendResults(ctx, results)
+}
+
+// sendResults confirms the delivery of the operation with the results of the clusters
+func (syncer *managedClusterOperationSyncer) sendResults(ctx context.Context,
+	results specbundle.ManagedClusterOperationResultBundle,
+) error {
+	syncer.version.Incr()
+	evt := cloudevents.NewEvent()
+	evt.SetSource(syncer.leafHubName)
+	evt.SetType(string(enum.ManagedClusterOperationResultType))
+	evt.SetExtension(eventversion.ExtVersion, syncer.version.String())
+	if err := evt.SetData(cloudevents.ApplicationJSON, results); err != nil {
+		return fmt.Errorf("failed to set the operation results: %w", err)
+	}
+	if err := syncer.producer.SendEvent(ctx, evt); err != nil {
+		return fmt.Errorf("failed to send the operation results: %w", err)
+	}
+	syncer.version.Next()
+	return nil
+}
+
+func applyOperation(ctx context.Context, k8sClient client.Client, clusterName string,
+	operation *specbundle.ManagedClusterOperation,
+) error {
+	// retry on conflict, since the managed cluster is also updated by the registration controllers
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cluster := &clusterv1.ManagedCluster{}
+		if err := k8sClient.Get(ctx, client.ObjectKey{Name: clusterName}, cluster); err != nil {
+			return err
+		}
+		if err := mutateManagedCluster(cluster, operation); err != nil {
+			return err
+		}
+		return k8sClient.Update(ctx, cluster, &client.UpdateOptions{FieldManager: hohFieldManager})
+	})
+}
+
+func mutateManagedCluster(cluster *clusterv1.ManagedCluster, operation *specbundle.ManagedClusterOperation) error {
+	labels := mutateMetadata(cluster.GetLabels(), operation.Labels)
+	if operation.ClusterSet != nil {
+		if *operation.ClusterSet == "" {
+			delete(labels, clusterv1beta2.ClusterSetLabel)
+		} else {
+			labels[clusterv1beta2.ClusterSetLabel] = *operation.ClusterSet
+		}
+	}
+	cluster.SetLabels(labels)
+	cluster.SetAnnotations(mutateMetadata(cluster.GetAnnotations(), operation.Annotations))
+
+	if operation.Taints != nil {
+		taints := []clusterv1.Taint{}
+		removed := map[string]struct{}{}
+		for _, key := range operation.Taints.Remove {
+			removed[key] = struct{}{}
+		}
+		added := []clusterv1.Taint{}
+		for _, taint := range operation.Taints.Add {
+			if taint.Key == "" {
+				return errors.New("the taint key must not be empty")
+			}
+			if taint.TimeAdded.IsZero() {
+				taint.TimeAdded = metav1.Now()
+			}
+			added = append(added, taint)
+			removed[taint.Key] = struct{}{} // the added taint overwrites the existing one with the same key
+		}
+		for _, taint := range cluster.Spec.Taints {
+			if _, found := removed[taint.Key]; !found {
+				taints = append(taints, taint)
+			}
+		}
+		cluster.Spec.Taints = append(taints, added...)
+	}
+
+	if operation.HubAcceptsClient != nil {
+		cluster.Spec.HubAcceptsClient = *operation.HubAcceptsClient
+	}
+	return nil
+}
+
+func mutateMetadata(metadata map[string]string, change *specbundle.MetadataChange) map[string]string {
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	if change == nil {
+		return metadata
+	}
+	for _, key := range change.Remove {
+		delete(metadata, key)
+	}
+	for key, value := range change.Add {
+		metadata[key] = value
+	}
+	return metadata
+}
@@ -0,0 +1,106 @@
+package syncers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	specbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+)
+
+func TestApplyManagedClusterOperation(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clusterv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed to add clusterv1 to scheme: %v", err)
+	}
+
+	clusterSet, hubAcceptsClient := "set1", false
+	cases := []struct {
+		name      string
+		cluster   *clusterv1.ManagedCluster
+		operation specbundle.ManagedClusterOperation
+		verify    func(t *testing.T, cluster *clusterv1.ManagedCluster)
+		wantErr   bool
+	}{
+		{
+			name: "change labels, annotations and cluster set",
+			cluster: &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{
+				Name:        "cluster1",
+				Labels:      map[string]string{"env": "dev", "vendor": "OpenShift"},
+				Annotations: map[string]string{"owner": "team1"},
+			}},
+			operation: specbundle.ManagedClusterOperation{
+				Labels:      &specbundle.MetadataChange{Add: map[string]string{"env": "prod"}, Remove: []string{"vendor"}},
+				Annotations: &specbundle.MetadataChange{Remove: []string{"owner"}},
+				ClusterSet:  &clusterSet,
+			},
+			verify: func(t *testing.T, cluster *clusterv1.ManagedCluster) {
+				assert.Equal(t, map[string]string{"env": "prod", clusterv1beta2.ClusterSetLabel: "set1"},
+					cluster.Labels)
+				assert.Empty(t, cluster.Annotations)
+			},
+		},
+		{
+			name: "change taints and hubAcceptsClient",
+			cluster: &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster1"},
+				Spec: clusterv1.ManagedClusterSpec{
+					HubAcceptsClient: true,
+					Taints: []clusterv1.Taint{
+						{Key: "foo", Effect: clusterv1.TaintEffectNoSelect},
+						{Key: "maintenance", Value: "old", Effect: clusterv1.TaintEffectNoSelect},
+						{Key: "bar", Effect: clusterv1.TaintEffectNoSelect},
+					},
+				},
+			},
+			operation: specbundle.ManagedClusterOperation{
+				Taints: &specbundle.TaintChange{
+					Add:    []clusterv1.Taint{{Key: "maintenance", Value: "new", Effect: clusterv1.TaintEffectNoSelect}},
+					Remove: []string{"foo"},
+				},
+				HubAcceptsClient: &hubAcceptsClient,
+			},
+			verify: func(t *testing.T, cluster *clusterv1.ManagedCluster) {
+				assert.False(t, cluster.Spec.HubAcceptsClient)
+				assert.Len(t, cluster.Spec.Taints, 2)
+				assert.Equal(t, "bar", cluster.Spec.Taints[0].Key)
+				assert.Equal(t, "maintenance", cluster.Spec.Taints[1].Key)
+				assert.Equal(t, "new", cluster.Spec.Taints[1].Value)
+				assert.False(t, cluster.Spec.Taints[1].TimeAdded.IsZero())
+			},
+		},
+		{
+			name:    "the taint without key",
+			cluster: &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster1"}},
+			operation: specbundle.ManagedClusterOperation{
+				Taints: &specbundle.TaintChange{Add: []clusterv1.Taint{{Effect: clusterv1.TaintEffectNoSelect}}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.cluster).Build()
+
+			err := applyOperation(ctx, fakeClient, tc.cluster.Name, &tc.operation)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			cluster := &clusterv1.ManagedCluster{}
+			assert.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(tc.cluster), cluster))
+			tc.verify(t, cluster)
+		})
+	}
+}
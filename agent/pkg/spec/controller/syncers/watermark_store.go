@@ -0,0 +1,96 @@
+package syncers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SpecStateConfigMapName is the configmap which persists the watermarks of the applied spec bundles
+const SpecStateConfigMapName = "multicluster-global-hub-agent-spec-state"
+
+// watermarkStore tracks the watermark of the last applied spec bundle by the bundle key. The watermarks are persisted
+// into the configmap, so the agent is still able to skip the applied bundles after it's restarted.
+type watermarkStore struct {
+	client     client.Client
+	namespace  string
+	mutex      sync.Mutex
+	watermarks map[string]time.Time
+}
+
+func newWatermarkStore(c client.Client, namespace string) *watermarkStore {
+	return &watermarkStore{
+		client:    c,
+		namespace: namespace,
+	}
+}
+
+// get returns the watermark of the last applied bundle, false if no bundle with the key has been applied
+func (s *watermarkStore) get(ctx context.Context, key string) (time.Time, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.load(ctx); err != nil {
+		return time.Time{}, false, err
+	}
+	watermark, found := s.watermarks[key]
+	return watermark, found, nil
+}
+
+// set records the watermark of the applied bundle and persists it into the configmap
+func (s *watermarkStore) set(ctx context.Context, key string, watermark time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.load(ctx); err != nil {
+		return err
+	}
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: SpecStateConfigMapName, Namespace: s.namespace}}
+	err := s.client.Get(ctx, client.ObjectKeyFromObject(cm), cm)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	notFound := errors.IsNotFound(err)
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[key] = watermark.Format(time.RFC3339Nano)
+	if notFound {
+		err = s.client.Create(ctx, cm)
+	} else {
+		err = s.client.Update(ctx, cm)
+	}
+	if err != nil {
+		return err
+	}
+	s.watermarks[key] = watermark
+	return nil
+}
+
+// load reads the watermarks from the configmap once
+func (s *watermarkStore) load(ctx context.Context) error {
+	if s.watermarks != nil {
+		return nil
+	}
+	cm := &corev1.ConfigMap{}
+	err := s.client.Get(ctx, client.ObjectKey{Name: SpecStateConfigMapName, Namespace: s.namespace}, cm)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	watermarks := map[string]time.Time{}
+	for key, val := range cm.Data {
+		watermark, err := time.Parse(time.RFC3339Nano, val)
+		if err != nil {
+			continue
+		}
+		watermarks[key] = watermark
+	}
+	s.watermarks = watermarks
+	return nil
+}
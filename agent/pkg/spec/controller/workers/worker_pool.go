@@ -70,24 +70,23 @@ func (pool *WorkerPool) Start(ctx context.Context) error {
 	return nil
 }
 
-func (pool *WorkerPool) Submit(job *Job) {
+// Submit pushes the job to the worker of its user identity, the job isn't run if it returns an error.
+func (pool *WorkerPool) Submit(job *Job) error {
 	pool.initializationWaitingGroup.Wait() // start running jobs only after some initialization steps have finished.
 
 	userIdentity, err := pool.impersonationManager.GetUserIdentity(job.obj)
 	if err != nil {
-		pool.log.Error(err, "failed to get user identity from obj")
-		return
+		return fmt.Errorf("failed to get user identity from obj - %w", err)
 	}
 	// if it doesn't contain impersonation info, let the controller worker pool handle it.
 	if userIdentity == rbac.NoIdentity {
 		pool.jobsQueue <- job
-		return
+		return nil
 	}
 	// otherwise, need to impersonate and use the specific worker to enforce permissions.
 	base64UserGroups, userGroups, err := pool.impersonationManager.GetUserGroups(job.obj)
 	if err != nil {
-		pool.log.Error(err, "failed to get user groups from obj")
-		return
+		return fmt.Errorf("failed to get user groups from obj - %w", err)
 	}
 
 	pool.impersonationWorkersLock.Lock()
@@ -96,8 +95,8 @@ func (pool *WorkerPool) Submit(job *Job) {
 
 	if _, found := pool.impersonationWorkersQueues[workerIdentifier]; !found {
 		if err := pool.createUserWorker(userIdentity, userGroups, workerIdentifier); err != nil {
-			pool.log.Error(err, "failed to create user worker", "user", userIdentity)
-			return
+			pool.impersonationWorkersLock.Unlock()
+			return fmt.Errorf("failed to create user worker for %s - %w", userIdentity, err)
 		}
 	}
 	// push the job to the queue of the specific worker that uses the user identity
@@ -105,6 +104,7 @@ func (pool *WorkerPool) Submit(job *Job) {
 
 	pool.impersonationWorkersLock.Unlock()
 	workerQueue <- job // since this call might get blocking, first Unlock, then try to insert job into queue
+	return nil
 }
 
 func (pool *WorkerPool) createUserWorker(userIdentity string, userGroups []string, workerIdentifier string) error {
@@ -67,6 +67,7 @@ func (c *hubOfHubsConfigController) Reconcile(ctx context.Context, request ctrl.
 
 	c.setAgentConfig(agentConfigMap, AgentAggregationKey)
 	c.setAgentConfig(agentConfigMap, EnableLocalPolicyKey)
+	c.setAgentConfig(agentConfigMap, EnablePolicyViolationKey)
 
 	reqLogger.V(2).Info("Reconciliation complete.")
 	return ctrl.Result{}, nil
@@ -16,6 +16,8 @@ var (
 	agentConfigs = map[AgentConfigKey]AgentConfigValue{
 		AgentAggregationKey:  AggregationFull,
 		EnableLocalPolicyKey: EnableLocalPolicyTrue,
+		// the policy violation details might be large, so it's opt-in
+		EnablePolicyViolationKey: EnablePolicyViolationFalse,
 	}
 )
 
@@ -30,6 +32,8 @@ const (
 
 	AgentAggregationKey  AgentConfigKey = "aggregationLevel"
 	EnableLocalPolicyKey AgentConfigKey = "enableLocalPolicies"
+
+	EnablePolicyViolationKey AgentConfigKey = "enablePolicyViolations"
 )
 
 type AgentConfigValue string
@@ -39,6 +43,9 @@ const (
 	AggregationMinimal     AgentConfigValue = "minimal"
 	EnableLocalPolicyTrue  AgentConfigValue = "true"
 	EnableLocalPolicyFalse AgentConfigValue = "false"
+
+	EnablePolicyViolationTrue  AgentConfigValue = "true"
+	EnablePolicyViolationFalse AgentConfigValue = "false"
 )
 
 // ResolveSyncIntervalFunc is a function for resolving corresponding sync interval from SyncIntervals data structure.
@@ -83,6 +90,10 @@ func GetEnableLocalPolicy() AgentConfigValue {
 	return agentConfigs[EnableLocalPolicyKey]
 }
 
+func GetEnablePolicyViolation() AgentConfigValue {
+	return agentConfigs[EnablePolicyViolationKey]
+}
+
 func SetInterval(key AgentConfigKey, val time.Duration) {
 	syncIntervals[key] = val
 }
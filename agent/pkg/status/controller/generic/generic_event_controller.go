@@ -3,6 +3,7 @@ package generic
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"strconv"
 	"strings"
 	"sync"
@@ -28,7 +29,9 @@ type genericEventController struct {
 	lock            *sync.Mutex
 }
 
-func AddEventController(mgr ctrl.Manager, eventController EventController, emitter Emitter,
+// AddEventController adds the controller to update the event payload, the controller is named with the syncer name
+// and the object type, since the same object might be watched by the controllers of the other syncers
+func AddEventController(mgr ctrl.Manager, syncerName string, eventController EventController, emitter Emitter,
 	lock *sync.Mutex,
 ) error {
 	obj := eventController.Instance()
@@ -40,7 +43,9 @@ func AddEventController(mgr ctrl.Manager, eventController EventController, emitt
 		lock:            lock,
 	}
 
-	controllerBuilder := ctrl.NewControllerManagedBy(mgr).For(obj)
+	controllerName := fmt.Sprintf("%s_%s", strings.ReplaceAll(syncerName, ".", "_"),
+		strings.ToLower(reflect.TypeOf(obj).Elem().Name()))
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).Named(controllerName).For(obj)
 	if eventController.Predicate() != nil {
 		controllerBuilder = controllerBuilder.WithEventFilter(eventController.Predicate())
 	}
@@ -12,6 +12,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
@@ -52,7 +53,7 @@ func LaunchGenericEventSyncer(name string, mgr ctrl.Manager, eventControllers []
 
 	// start all the controllers to update the payload
 	for _, eventController := range eventControllers {
-		err := AddEventController(mgr, eventController, emitter, syncer.lock)
+		err := AddEventController(mgr, name, eventController, emitter, syncer.lock)
 		if err != nil {
 			return err
 		}
@@ -86,20 +87,25 @@ func (s *genericEventSyncer) syncEvent() {
 	defer s.lock.Unlock()
 
 	if s.emitter.ShouldSend() {
+		// the span of building the bundle is continued by the transport and the manager
+		ctx, span := tracing.Start(context.TODO(), "bundle.emit", nil)
 		evt, err := s.emitter.ToCloudEvent()
 		if err != nil {
 			s.log.Error(err, "failed to get CloudEvent instance", "evt", evt)
+			tracing.End(span, err)
 			return
 		}
+		span.SetAttributes(tracing.EventAttributes(evt)...)
 
-		ctx := context.TODO()
 		if s.emitter.Topic() != "" {
 			ctx = cecontext.WithTopic(ctx, s.emitter.Topic())
 		}
 		if err := s.producer.SendEvent(ctx, *evt); err != nil {
 			s.log.Error(err, "failed to send event", "evt", evt)
+			tracing.End(span, err)
 			return
 		}
 		s.emitter.PostSend()
+		span.End()
 	}
 }
@@ -14,6 +14,7 @@ import (
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
@@ -149,21 +150,25 @@ func (c *genericObjectSyncer) syncEvents() {
 		emitter := c.eventEmitters[i]
 
 		if emitter.ShouldSend() {
+			// the span of building the bundle is continued by the transport and the manager
+			ctx, span := tracing.Start(context.TODO(), "bundle.emit", nil)
 			evt, err := emitter.ToCloudEvent()
 			if err != nil {
 				c.log.Error(err, "failed to get CloudEvent instance", "evt", evt)
 			}
 			evt.SetSource(c.leafHubName)
+			span.SetAttributes(tracing.EventAttributes(evt)...)
 
-			ctx := context.TODO()
 			if emitter.Topic() != "" {
 				ctx = cecontext.WithTopic(ctx, emitter.Topic())
 			}
 			if err := c.producer.SendEvent(ctx, *evt); err != nil {
 				c.log.Error(err, "failed to send event", "evt", evt)
+				tracing.End(span, err)
 				continue
 			}
 			emitter.PostSend()
+			span.End()
 		}
 	}
 }
@@ -0,0 +1,58 @@
+package hubcluster
+
+import (
+	"sort"
+
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+)
+
+var _ generic.EventController = &infoAddonController{}
+
+// infoAddonController updates the addons installed on the hub by the ClusterManagementAddOns
+type infoAddonController struct {
+	generic.Controller
+	evtData cluster.HubClusterInfoBundle
+	addons  map[string]struct{}
+}
+
+func NewInfoAddonController(eventData cluster.HubClusterInfoBundle) generic.EventController {
+	instance := func() client.Object { return &addonv1alpha1.ClusterManagementAddOn{} }
+	return &infoAddonController{
+		Controller: generic.NewGenericController(instance, predicate.NewPredicateFuncs(
+			func(object client.Object) bool { return true })),
+		evtData: eventData,
+		addons:  map[string]struct{}{},
+	}
+}
+
+func (p *infoAddonController) Update(obj client.Object) bool {
+	if _, found := p.addons[obj.GetName()]; found {
+		return false
+	}
+	p.addons[obj.GetName()] = struct{}{}
+	p.list()
+	return true
+}
+
+func (p *infoAddonController) Delete(obj client.Object) bool {
+	if _, found := p.addons[obj.GetName()]; !found {
+		return false
+	}
+	delete(p.addons, obj.GetName())
+	p.list()
+	return true
+}
+
+func (p *infoAddonController) list() {
+	addons := make([]string, 0, len(p.addons))
+	for addon := range p.addons {
+		addons = append(addons, addon)
+	}
+	sort.Strings(addons)
+	p.evtData.Addons = addons
+}
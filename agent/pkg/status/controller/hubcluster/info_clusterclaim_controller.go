@@ -7,6 +7,7 @@ import (
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 )
 
 var _ generic.EventController = &infoClusterClaimController{}
@@ -19,7 +20,7 @@ type infoClusterClaimController struct {
 func NewInfoClusterClaimController(eventData cluster.HubClusterInfoBundle) generic.EventController {
 	instance := func() client.Object { return &clustersv1alpha1.ClusterClaim{} }
 	clusterClaimPredicate := predicate.NewPredicateFuncs(func(object client.Object) bool {
-		return object.GetName() == "id.k8s.io"
+		return object.GetName() == "id.k8s.io" || object.GetName() == constants.VersionClusterClaimName
 	})
 	return &infoClusterClaimController{
 		Controller: generic.NewGenericController(instance, clusterClaimPredicate),
@@ -33,20 +34,26 @@ func (p *infoClusterClaimController) Update(obj client.Object) bool {
 		return false
 	}
 
-	oldClusterID := p.evtData.ClusterId
+	oldClusterID, oldACMVersion := p.evtData.ClusterId, p.evtData.ACMVersion
 
-	if clusterClaim.Name == "id.k8s.io" {
+	switch clusterClaim.Name {
+	case "id.k8s.io":
 		p.evtData.ClusterId = clusterClaim.Spec.Value
+	case constants.VersionClusterClaimName:
+		p.evtData.ACMVersion = clusterClaim.Spec.Value
 	}
 	// If no ClusterId, do not send the bundle
 	if p.evtData.ClusterId == "" {
 		return false
 	}
 
-	return oldClusterID != p.evtData.ClusterId
+	return oldClusterID != p.evtData.ClusterId || oldACMVersion != p.evtData.ACMVersion
 }
 
 func (p *infoClusterClaimController) Delete(obj client.Object) bool {
-	// do nothing
+	if obj.GetName() == constants.VersionClusterClaimName && p.evtData.ACMVersion != "" {
+		p.evtData.ACMVersion = ""
+		return p.evtData.ClusterId != ""
+	}
 	return false
 }
@@ -0,0 +1,117 @@
+package hubcluster
+
+import (
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clustersv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+func TestInfoVersionControllers(t *testing.T) {
+	eventData := &cluster.HubClusterInfo{}
+
+	claimController := NewInfoClusterClaimController(eventData)
+	versionClaim := &clustersv1alpha1.ClusterClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: constants.VersionClusterClaimName},
+		Spec:       clustersv1alpha1.ClusterClaimSpec{Value: "2.11.0"},
+	}
+	// the bundle isn't sent until the cluster id is available
+	assert.False(t, claimController.Update(versionClaim))
+	assert.Equal(t, "2.11.0", eventData.ACMVersion)
+	assert.True(t, claimController.Update(&clustersv1alpha1.ClusterClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "id.k8s.io"},
+		Spec:       clustersv1alpha1.ClusterClaimSpec{Value: "00000000-0000-0000-0000-000000000001"},
+	}))
+	assert.False(t, claimController.Update(versionClaim))
+	assert.True(t, claimController.Delete(versionClaim))
+	assert.Empty(t, eventData.ACMVersion)
+
+	versionController := NewInfoVersionController(eventData)
+	clusterVersion := &configv1.ClusterVersion{
+		ObjectMeta: metav1.ObjectMeta{Name: "version"},
+		Status:     configv1.ClusterVersionStatus{Desired: configv1.Release{Version: "4.16.3"}},
+	}
+	assert.True(t, versionController.Update(clusterVersion))
+	assert.False(t, versionController.Update(clusterVersion))
+	assert.Equal(t, "4.16.3", eventData.OpenShiftVersion)
+
+	mceController := NewInfoMCEController(eventData)
+	mce := mceController.Instance().(*unstructured.Unstructured)
+	mce.SetName("multiclusterengine")
+	assert.False(t, mceController.Update(mce))
+	assert.NoError(t, unstructured.SetNestedField(mce.Object, "2.6.0", "status", "currentVersion"))
+	assert.True(t, mceController.Update(mce))
+	assert.Equal(t, "2.6.0", eventData.MCEVersion)
+	assert.True(t, mceController.Delete(mce))
+	assert.Empty(t, eventData.MCEVersion)
+}
+
+func TestInfoManagedClusterController(t *testing.T) {
+	eventData := &cluster.HubClusterInfo{}
+	controller := NewInfoManagedClusterController(eventData)
+
+	newCluster := func(name string, status metav1.ConditionStatus) *clusterv1.ManagedCluster {
+		managedCluster := &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if status != "" {
+			managedCluster.Status.Conditions = []metav1.Condition{
+				{Type: clusterv1.ManagedClusterConditionAvailable, Status: status},
+			}
+		}
+		return managedCluster
+	}
+
+	assert.True(t, controller.Update(newCluster("cluster1", metav1.ConditionTrue)))
+	assert.True(t, controller.Update(newCluster("cluster2", metav1.ConditionFalse)))
+	assert.True(t, controller.Update(newCluster("cluster3", "")))
+	assert.False(t, controller.Update(newCluster("cluster3", metav1.ConditionUnknown)))
+	assert.Equal(t, map[string]int{ClusterAvailable: 1, ClusterUnavailable: 1, ClusterUnknown: 1},
+		eventData.ManagedClusters)
+
+	assert.True(t, controller.Update(newCluster("cluster2", metav1.ConditionTrue)))
+	assert.True(t, controller.Delete(newCluster("cluster3", "")))
+	assert.False(t, controller.Delete(newCluster("cluster4", "")))
+	assert.Equal(t, map[string]int{ClusterAvailable: 2, ClusterUnavailable: 0, ClusterUnknown: 0},
+		eventData.ManagedClusters)
+}
+
+func TestInfoNodeAndAddonControllers(t *testing.T) {
+	eventData := &cluster.HubClusterInfo{}
+
+	nodeController := NewInfoNodeController(eventData)
+	newNode := func(name, cpu, memory string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{Capacity: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+				corev1.ResourcePods:   resource.MustParse("250"),
+			}},
+		}
+	}
+	assert.True(t, nodeController.Update(newNode("node1", "8", "32Gi")))
+	assert.True(t, nodeController.Update(newNode("node2", "4", "16Gi")))
+	assert.False(t, nodeController.Update(newNode("node2", "4", "16Gi")))
+	assert.Equal(t, &cluster.HubCapacity{Nodes: 2, CPU: "12", Memory: "48Gi", Pods: "500"}, eventData.Capacity)
+	assert.True(t, nodeController.Delete(newNode("node1", "8", "32Gi")))
+	assert.Equal(t, &cluster.HubCapacity{Nodes: 1, CPU: "4", Memory: "16Gi", Pods: "250"}, eventData.Capacity)
+
+	addonController := NewInfoAddonController(eventData)
+	for _, name := range []string{"work-manager", "application-manager", "work-manager"} {
+		addonController.Update(&addonv1alpha1.ClusterManagementAddOn{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+	assert.Equal(t, []string{"application-manager", "work-manager"}, eventData.Addons)
+	assert.True(t, addonController.Delete(&addonv1alpha1.ClusterManagementAddOn{
+		ObjectMeta: metav1.ObjectMeta{Name: "work-manager"},
+	}))
+	assert.Equal(t, []string{"application-manager"}, eventData.Addons)
+}
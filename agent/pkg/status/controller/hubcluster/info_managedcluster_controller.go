@@ -0,0 +1,75 @@
+package hubcluster
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+)
+
+const (
+	ClusterAvailable   = "Available"
+	ClusterUnavailable = "Unavailable"
+	ClusterUnknown     = "Unknown"
+)
+
+var _ generic.EventController = &infoManagedClusterController{}
+
+// infoManagedClusterController counts the managed clusters of the hub by the available status
+type infoManagedClusterController struct {
+	generic.Controller
+	evtData cluster.HubClusterInfoBundle
+	// the available status of the managed clusters
+	clusters map[string]string
+}
+
+func NewInfoManagedClusterController(eventData cluster.HubClusterInfoBundle) generic.EventController {
+	instance := func() client.Object { return &clusterv1.ManagedCluster{} }
+	return &infoManagedClusterController{
+		Controller: generic.NewGenericController(instance, predicate.NewPredicateFuncs(
+			func(object client.Object) bool { return true })),
+		evtData:  eventData,
+		clusters: map[string]string{},
+	}
+}
+
+func (p *infoManagedClusterController) Update(obj client.Object) bool {
+	managedCluster, ok := obj.(*clusterv1.ManagedCluster)
+	if !ok {
+		return false
+	}
+	status := ClusterUnknown
+	condition := meta.FindStatusCondition(managedCluster.Status.Conditions, clusterv1.ManagedClusterConditionAvailable)
+	if condition != nil && condition.Status == metav1.ConditionTrue {
+		status = ClusterAvailable
+	} else if condition != nil && condition.Status == metav1.ConditionFalse {
+		status = ClusterUnavailable
+	}
+	if existing, found := p.clusters[obj.GetName()]; found && existing == status {
+		return false
+	}
+	p.clusters[obj.GetName()] = status
+	p.count()
+	return true
+}
+
+func (p *infoManagedClusterController) Delete(obj client.Object) bool {
+	if _, found := p.clusters[obj.GetName()]; !found {
+		return false
+	}
+	delete(p.clusters, obj.GetName())
+	p.count()
+	return true
+}
+
+func (p *infoManagedClusterController) count() {
+	counts := map[string]int{ClusterAvailable: 0, ClusterUnavailable: 0, ClusterUnknown: 0}
+	for _, status := range p.clusters {
+		counts[status]++
+	}
+	p.evtData.ManagedClusters = counts
+}
@@ -0,0 +1,60 @@
+package hubcluster
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+)
+
+// the MultiClusterEngine is watched as unstructured since its api isn't a dependency of the agent
+var mceGVK = schema.GroupVersionKind{
+	Group:   "multicluster.openshift.io",
+	Version: "v1",
+	Kind:    "MultiClusterEngine",
+}
+
+var _ generic.EventController = &infoMCEController{}
+
+// infoMCEController updates the MCE version of the hub from the MultiClusterEngine
+type infoMCEController struct {
+	generic.Controller
+	evtData cluster.HubClusterInfoBundle
+}
+
+func NewInfoMCEController(eventData cluster.HubClusterInfoBundle) generic.EventController {
+	instance := func() client.Object {
+		mce := &unstructured.Unstructured{}
+		mce.SetGroupVersionKind(mceGVK)
+		return mce
+	}
+	return &infoMCEController{
+		Controller: generic.NewGenericController(instance, predicate.NewPredicateFuncs(
+			func(object client.Object) bool { return true })),
+		evtData: eventData,
+	}
+}
+
+func (p *infoMCEController) Update(obj client.Object) bool {
+	mce, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return false
+	}
+	version, _, _ := unstructured.NestedString(mce.Object, "status", "currentVersion")
+	if version == "" || p.evtData.MCEVersion == version {
+		return false
+	}
+	p.evtData.MCEVersion = version
+	return true
+}
+
+func (p *infoMCEController) Delete(obj client.Object) bool {
+	if p.evtData.MCEVersion == "" {
+		return false
+	}
+	p.evtData.MCEVersion = ""
+	return true
+}
@@ -0,0 +1,69 @@
+package hubcluster
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+)
+
+var _ generic.EventController = &infoNodeController{}
+
+// infoNodeController sums the capacity of the nodes on the hub
+type infoNodeController struct {
+	generic.Controller
+	evtData    cluster.HubClusterInfoBundle
+	capacities map[string]corev1.ResourceList
+}
+
+func NewInfoNodeController(eventData cluster.HubClusterInfoBundle) generic.EventController {
+	instance := func() client.Object { return &corev1.Node{} }
+	return &infoNodeController{
+		Controller: generic.NewGenericController(instance, predicate.NewPredicateFuncs(
+			func(object client.Object) bool { return true })),
+		evtData:    eventData,
+		capacities: map[string]corev1.ResourceList{},
+	}
+}
+
+func (p *infoNodeController) Update(obj client.Object) bool {
+	node, ok := obj.(*corev1.Node)
+	if !ok {
+		return false
+	}
+	if existing, found := p.capacities[obj.GetName()]; found && equality.Semantic.DeepEqual(existing,
+		node.Status.Capacity) {
+		return false
+	}
+	p.capacities[obj.GetName()] = node.Status.Capacity
+	p.sum()
+	return true
+}
+
+func (p *infoNodeController) Delete(obj client.Object) bool {
+	if _, found := p.capacities[obj.GetName()]; !found {
+		return false
+	}
+	delete(p.capacities, obj.GetName())
+	p.sum()
+	return true
+}
+
+func (p *infoNodeController) sum() {
+	cpu, memory, pods := resource.Quantity{}, resource.Quantity{}, resource.Quantity{}
+	for _, capacity := range p.capacities {
+		cpu.Add(capacity[corev1.ResourceCPU])
+		memory.Add(capacity[corev1.ResourceMemory])
+		pods.Add(capacity[corev1.ResourcePods])
+	}
+	p.evtData.Capacity = &cluster.HubCapacity{
+		Nodes:  len(p.capacities),
+		CPU:    cpu.String(),
+		Memory: memory.String(),
+		Pods:   pods.String(),
+	}
+}
@@ -1,6 +1,7 @@
 package hubcluster
 
 import (
+	"k8s.io/apimachinery/pkg/api/meta"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
@@ -8,17 +9,31 @@ import (
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
 )
 
 func LaunchHubClusterInfoSyncer(mgr ctrl.Manager, producer transport.Producer) error {
-	eventData := &cluster.HubClusterInfo{}
+	eventData := &cluster.HubClusterInfo{AgentVersion: utils.GetBuildVersion()}
+	eventControllers := []generic.EventController{
+		NewInfoClusterClaimController(eventData),
+		NewInfoRouteController(eventData),
+		NewInfoVersionController(eventData),
+		NewInfoManagedClusterController(eventData),
+		NewInfoNodeController(eventData),
+		NewInfoAddonController(eventData),
+	}
+	// the hub might be installed with the OCM only, then the MultiClusterEngine isn't available
+	_, err := mgr.GetRESTMapper().RESTMapping(mceGVK.GroupKind(), mceGVK.Version)
+	if err == nil {
+		eventControllers = append(eventControllers, NewInfoMCEController(eventData))
+	} else if !meta.IsNoMatchError(err) {
+		return err
+	}
+
 	return generic.LaunchGenericEventSyncer(
 		"status.hub_cluster_info",
 		mgr,
-		[]generic.EventController{
-			NewInfoClusterClaimController(eventData),
-			NewInfoRouteController(eventData),
-		},
+		eventControllers,
 		producer,
 		config.GetHubClusterInfoDuration,
 		generic.NewGenericEmitter(enum.HubClusterInfoType, eventData),
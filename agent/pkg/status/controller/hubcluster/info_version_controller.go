@@ -0,0 +1,46 @@
+package hubcluster
+
+import (
+	configv1 "github.com/openshift/api/config/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+)
+
+var _ generic.EventController = &infoVersionController{}
+
+// infoVersionController updates the OpenShift version of the hub from the ClusterVersion
+type infoVersionController struct {
+	generic.Controller
+	evtData cluster.HubClusterInfoBundle
+}
+
+func NewInfoVersionController(eventData cluster.HubClusterInfoBundle) generic.EventController {
+	instance := func() client.Object { return &configv1.ClusterVersion{} }
+	versionPredicate := predicate.NewPredicateFuncs(func(object client.Object) bool {
+		return object.GetName() == "version"
+	})
+	return &infoVersionController{
+		Controller: generic.NewGenericController(instance, versionPredicate),
+		evtData:    eventData,
+	}
+}
+
+func (p *infoVersionController) Update(obj client.Object) bool {
+	clusterVersion, ok := obj.(*configv1.ClusterVersion)
+	if !ok {
+		return false
+	}
+	version := clusterVersion.Status.Desired.Version
+	if version == "" || p.evtData.OpenShiftVersion == version {
+		return false
+	}
+	p.evtData.OpenShiftVersion = version
+	return true
+}
+
+func (p *infoVersionController) Delete(obj client.Object) bool {
+	return false
+}
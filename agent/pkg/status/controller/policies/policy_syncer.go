@@ -91,6 +91,16 @@ func LaunchPolicySyncer(ctx context.Context, mgr ctrl.Manager, agentConfig *conf
 		compliancePredicate,
 	)
 
+	// 7. policy violation details of the replicated policies, both the local and global policies
+	policyViolationEmitter := PolicyViolationEmitter(ctx, enum.PolicyViolationType,
+		func(obj client.Object) bool {
+			return isPolicyViolationEnabled() &&
+				utils.HasLabel(obj, constants.PolicyEventRootPolicyNameLabelKey) // replicated policy
+		},
+		mgr.GetClient(),
+		agentConfig.TransportConfig.KafkaCredential.StatusTopic,
+	)
+
 	return generic.LaunchGenericObjectSyncer(
 		"status.policy",
 		mgr,
@@ -105,6 +115,8 @@ func LaunchPolicySyncer(ctx context.Context, mgr ctrl.Manager, agentConfig *conf
 			// global compliance
 			complianceEmitter,
 			completeEmitter,
+			// policy violation
+			policyViolationEmitter,
 		})
 }
 
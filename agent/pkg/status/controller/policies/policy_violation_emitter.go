@@ -0,0 +1,262 @@
+package policies
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	policiesv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+const gatekeeperConstraintGroup = "constraints.gatekeeper.sh"
+
+var _ generic.ObjectEmitter = &policyViolationEmitter{}
+
+// policyViolationEmitter sends the details of the non-compliant templates of the replicated policies. Only the
+// changed (policy, cluster) pairs are sent, the pair with empty templates means the violations are resolved. The cache
+// is in memory, so each replicated policy is sent once it's observed after the agent starts even if it's compliant,
+// that resolves the violations which are cleared while the agent is down.
+type policyViolationEmitter struct {
+	ctx             context.Context
+	log             logr.Logger
+	eventType       string
+	runtimeClient   client.Client
+	currentVersion  *eventversion.Version
+	lastSentVersion eventversion.Version
+	topic           string
+	predicate       func(client.Object) bool
+	// the changed violations to be sent, the key is the namespaced name of the replicated policy
+	payload map[types.NamespacedName]grc.PolicyViolation
+	// the last updated violations of all the observed replicated policies, it's used to skip the unchanged ones and
+	// resolve the deleted replicated policy
+	cache map[types.NamespacedName]grc.PolicyViolation
+}
+
+func PolicyViolationEmitter(
+	ctx context.Context,
+	eventType enum.EventType,
+	predicate func(client.Object) bool,
+	c client.Client,
+	topic string,
+) generic.ObjectEmitter {
+	return &policyViolationEmitter{
+		ctx:             ctx,
+		log:             ctrl.Log.WithName("policy-violation-emitter"),
+		eventType:       string(eventType),
+		topic:           topic,
+		runtimeClient:   c,
+		currentVersion:  eventversion.NewVersion(),
+		lastSentVersion: *eventversion.NewVersion(),
+		predicate:       predicate,
+		payload:         map[types.NamespacedName]grc.PolicyViolation{},
+		cache:           map[types.NamespacedName]grc.PolicyViolation{},
+	}
+}
+
+func (h *policyViolationEmitter) ShouldUpdate(obj client.Object) bool {
+	return h.predicate(obj)
+}
+
+func (h *policyViolationEmitter) PostUpdate() {
+	h.currentVersion.Incr()
+}
+
+func (h *policyViolationEmitter) ShouldSend() bool {
+	return len(h.payload) > 0 && h.currentVersion.NewerThan(&h.lastSentVersion)
+}
+
+func (h *policyViolationEmitter) Topic() string {
+	return h.topic
+}
+
+func (h *policyViolationEmitter) Update(obj client.Object) bool {
+	policy, ok := obj.(*policiesv1.Policy)
+	if !ok {
+		return false // do not handle objects other than policy
+	}
+
+	rootPolicy, clusterID, clusterName, err := GetRootPolicyAndClusterInfo(h.ctx, policy, h.runtimeClient)
+	if err != nil {
+		h.log.Error(err, "failed to get get rootPolicy/clusterID by replicatedPolicy")
+		return false
+	}
+
+	violation := grc.PolicyViolation{
+		PolicyID:    extractPolicyIdentity(rootPolicy),
+		ClusterID:   clusterID,
+		ClusterName: clusterName,
+		Templates:   h.templateViolations(policy),
+	}
+	return h.updatePayload(client.ObjectKeyFromObject(policy), violation)
+}
+
+func (h *policyViolationEmitter) Delete(obj client.Object) bool {
+	key := client.ObjectKeyFromObject(obj)
+	violation, found := h.cache[key]
+	if !found {
+		return false
+	}
+	violation.Templates = []grc.TemplateViolation{}
+	updated := h.updatePayload(key, violation)
+	delete(h.cache, key)
+	return updated
+}
+
+func (h *policyViolationEmitter) updatePayload(key types.NamespacedName, violation grc.PolicyViolation) bool {
+	if cached, found := h.cache[key]; found && reflect.DeepEqual(cached, violation) {
+		return false
+	}
+	h.cache[key] = violation
+	h.payload[key] = violation
+	return true
+}
+
+// templateViolations returns the non-compliant templates of the replicated policy, the related objects are only
+// available if the template object can be read from the managed hub, e.g. the templates of the local-cluster.
+func (h *policyViolationEmitter) templateViolations(policy *policiesv1.Policy) []grc.TemplateViolation {
+	templates := map[string]*unstructured.Unstructured{}
+	for _, policyTemplate := range policy.Spec.PolicyTemplates {
+		template := &unstructured.Unstructured{}
+		if err := json.Unmarshal(policyTemplate.ObjectDefinition.Raw, &template.Object); err != nil {
+			h.log.V(2).Info("failed to unmarshal the policy template", "policy", policy.Name, "error", err.Error())
+			continue
+		}
+		templates[template.GetName()] = template
+	}
+
+	violations := []grc.TemplateViolation{}
+	for _, detail := range policy.Status.Details {
+		if detail == nil || detail.ComplianceState == policiesv1.Compliant {
+			continue
+		}
+		violation := grc.TemplateViolation{
+			Name:       detail.TemplateMeta.Name,
+			Compliance: string(detail.ComplianceState),
+		}
+		if len(detail.History) > 0 {
+			violation.Message = detail.History[0].Message
+			violation.Compliance = GetComplianceState(MessageCompliaceStateRegex, violation.Message,
+				violation.Compliance)
+		}
+		if template, found := templates[detail.TemplateMeta.Name]; found {
+			violation.Kind = template.GetKind()
+			violation.APIVersion = template.GetAPIVersion()
+			violation.RelatedObjects = h.relatedObjects(template, policy.Namespace)
+		}
+		violations = append(violations, violation)
+	}
+	return violations
+}
+
+func (h *policyViolationEmitter) relatedObjects(template *unstructured.Unstructured,
+	namespace string,
+) []grc.RelatedObject {
+	gv, err := schema.ParseGroupVersion(template.GetAPIVersion())
+	if err != nil || template.GetKind() == "" {
+		return nil
+	}
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gv.WithKind(template.GetKind()))
+	key := types.NamespacedName{Namespace: namespace, Name: template.GetName()}
+	if gv.Group == gatekeeperConstraintGroup {
+		key.Namespace = "" // the gatekeeper constraints are cluster scoped
+	}
+	if err := h.runtimeClient.Get(h.ctx, key, obj); err != nil {
+		h.log.V(2).Info("the policy template isn't available", "kind", template.GetKind(), "name", key,
+			"error", err.Error())
+		return nil
+	}
+	if gv.Group == gatekeeperConstraintGroup {
+		return constraintViolations(obj)
+	}
+	return templateRelatedObjects(obj)
+}
+
+// templateRelatedObjects returns the non-compliant relatedObjects of the ConfigurationPolicy or OperatorPolicy
+func templateRelatedObjects(obj *unstructured.Unstructured) []grc.RelatedObject {
+	items, _, _ := unstructured.NestedSlice(obj.Object, "status", "relatedObjects")
+	relatedObjects := []grc.RelatedObject{}
+	for _, item := range items {
+		related, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		compliance, _, _ := unstructured.NestedString(related, "compliant")
+		if compliance == string(policiesv1.Compliant) {
+			continue
+		}
+		relatedObject := grc.RelatedObject{Compliance: compliance}
+		relatedObject.Reason, _, _ = unstructured.NestedString(related, "reason")
+		relatedObject.Kind, _, _ = unstructured.NestedString(related, "object", "kind")
+		relatedObject.APIVersion, _, _ = unstructured.NestedString(related, "object", "apiVersion")
+		relatedObject.Name, _, _ = unstructured.NestedString(related, "object", "metadata", "name")
+		relatedObject.Namespace, _, _ = unstructured.NestedString(related, "object", "metadata", "namespace")
+		relatedObjects = append(relatedObjects, relatedObject)
+	}
+	return relatedObjects
+}
+
+// constraintViolations returns the violations of the gatekeeper constraint
+func constraintViolations(obj *unstructured.Unstructured) []grc.RelatedObject {
+	items, _, _ := unstructured.NestedSlice(obj.Object, "status", "violations")
+	relatedObjects := []grc.RelatedObject{}
+	for _, item := range items {
+		violation, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		relatedObject := grc.RelatedObject{Compliance: string(policiesv1.NonCompliant)}
+		relatedObject.Kind, _, _ = unstructured.NestedString(violation, "kind")
+		relatedObject.APIVersion, _, _ = unstructured.NestedString(violation, "version")
+		if group, _, _ := unstructured.NestedString(violation, "group"); group != "" {
+			relatedObject.APIVersion = fmt.Sprintf("%s/%s", group, relatedObject.APIVersion)
+		}
+		relatedObject.Name, _, _ = unstructured.NestedString(violation, "name")
+		relatedObject.Namespace, _, _ = unstructured.NestedString(violation, "namespace")
+		relatedObject.Reason, _, _ = unstructured.NestedString(violation, "message")
+		relatedObjects = append(relatedObjects, relatedObject)
+	}
+	return relatedObjects
+}
+
+func (h *policyViolationEmitter) ToCloudEvent() (*cloudevents.Event, error) {
+	if len(h.payload) < 1 {
+		return nil, fmt.Errorf("the payload shouldn't be nil")
+	}
+	bundle := grc.PolicyViolationBundle{}
+	for _, violation := range h.payload {
+		bundle = append(bundle, violation)
+	}
+	e := cloudevents.NewEvent()
+	e.SetSource(config.GetLeafHubName())
+	e.SetType(h.eventType)
+	e.SetExtension(eventversion.ExtVersion, h.currentVersion.String())
+	err := e.SetData(cloudevents.ApplicationJSON, bundle)
+	return &e, err
+}
+
+func (h *policyViolationEmitter) PostSend() {
+	h.payload = map[types.NamespacedName]grc.PolicyViolation{}
+	h.currentVersion.Next()
+	h.lastSentVersion = *h.currentVersion
+}
+
+// isPolicyViolationEnabled returns true if the policy violation details are enabled by the agent config
+func isPolicyViolationEnabled() bool {
+	return strings.EqualFold(string(config.GetEnablePolicyViolation()), string(config.EnablePolicyViolationTrue))
+}
@@ -27,11 +27,16 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/backup"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/clusteraction"
 	managerconfig "github.com/stolostron/multicluster-global-hub/manager/pkg/config"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/cronjob"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/cronjob/task"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/hubmanagement"
 	migration "github.com/stolostron/multicluster-global-hub/manager/pkg/migration"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/notifier"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/override"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/promotion"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer"
 	statussyncer "github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer"
 	mgrwebhook "github.com/stolostron/multicluster-global-hub/manager/pkg/webhook"
@@ -39,26 +44,32 @@ import (
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	commonobjects "github.com/stolostron/multicluster-global-hub/pkg/objects"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/controller"
 	"github.com/stolostron/multicluster-global-hub/pkg/utils"
 )
 
 const (
-	metricsHost                = "0.0.0.0"
-	metricsPort          int32 = 8384
-	webhookPort                = 9443
-	webhookCertDir             = "/webhook-certs"
-	kafkaTransportType         = "kafka"
-	leaderElectionLockID       = "multicluster-global-hub-manager-lock"
-	launchJobNamesEnv          = "LAUNCH_JOB_NAMES"
-	namespacePath              = "metadata.namespace"
+	metricsHost                       = "0.0.0.0"
+	metricsPort                 int32 = 8384
+	webhookPort                       = 9443
+	webhookCertDir                    = "/webhook-certs"
+	kafkaTransportType                = "kafka"
+	leaderElectionLockID              = "multicluster-global-hub-manager-lock"
+	launchJobNamesEnv                 = "LAUNCH_JOB_NAMES"
+	backupS3AccessKeyIDEnv            = "BACKUP_S3_ACCESS_KEY_ID"
+	backupS3SecretAccessKeyEnv        = "BACKUP_S3_SECRET_ACCESS_KEY"
+	archiveS3AccessKeyIDEnv           = "ARCHIVE_S3_ACCESS_KEY_ID"
+	archiveS3SecretAccessKeyEnv       = "ARCHIVE_S3_SECRET_ACCESS_KEY"
+	namespacePath                     = "metadata.namespace"
 )
 
 var (
 	setupLog              = ctrl.Log.WithName("setup")
 	managerNamespace      = constants.GHDefaultNamespace
 	enableSimulation      = false
+	jobSchedules          = []string{}
 	errFlagParameterEmpty = errors.New("flag parameter empty")
 )
 
@@ -68,17 +79,22 @@ func init() {
 
 func parseFlags() *managerconfig.ManagerConfig {
 	managerConfig := &managerconfig.ManagerConfig{
-		SyncerConfig:   &managerconfig.SyncerConfig{},
-		DatabaseConfig: &managerconfig.DatabaseConfig{},
+		SyncerConfig: &managerconfig.SyncerConfig{},
+		DatabaseConfig: &managerconfig.DatabaseConfig{
+			Backup:  &managerconfig.DatabaseBackupConfig{},
+			Archive: &managerconfig.DatabaseArchiveConfig{},
+		},
 		TransportConfig: &transport.TransportInternalConfig{
 			IsManager:            true,
 			ConsumerGroupId:      "global-hub-manager",
 			EnableDatabaseOffset: true,
 		},
 		StatisticsConfig:      &statistics.StatisticsConfig{},
+		TracingConfig:         &tracing.TracingConfig{},
 		NonK8sAPIServerConfig: &nonk8sapi.NonK8sAPIServerConfig{},
 		ElectionConfig:        &commonobjects.LeaderElectionConfig{},
 		LaunchJobNames:        "",
+		JobConfig:             &managerconfig.JobConfig{Schedules: map[string]string{}},
 	}
 
 	// add zap flags
@@ -94,6 +110,14 @@ func parseFlags() *managerconfig.ManagerConfig {
 	pflag.StringVar(&managerConfig.SchedulerInterval, "scheduler-interval", "day",
 		"The job scheduler interval for moving policy compliance history, "+
 			"can be 'month', 'week', 'day', 'hour', 'minute' or 'second', default value is 'day'.")
+	pflag.StringArrayVar(&jobSchedules, "job-schedule", []string{},
+		"The cron expression of the job overriding the default schedule, e.g. data-retention=0 0 1 * *, "+
+			"the seconds field is supported with 6 fields.")
+	pflag.StringSliceVar(&managerConfig.JobConfig.Disabled, "disabled-jobs", []string{},
+		"The jobs which aren't scheduled, multiple jobs must be splited by comma.")
+	pflag.DurationVar(&managerConfig.JobConfig.StaleHeartbeatThreshold, "stale-heartbeat-threshold",
+		task.DefaultStaleHeartbeatThreshold,
+		"The duration to keep the heartbeat of the removed managed hub after it's inactive.")
 	pflag.DurationVar(&managerConfig.SyncerConfig.SpecSyncInterval, "spec-sync-interval", 5*time.Second,
 		"The synchronization interval of resources in spec.")
 	pflag.DurationVar(&managerConfig.SyncerConfig.StatusSyncInterval, "status-sync-interval", 5*time.Second,
@@ -112,6 +136,12 @@ func parseFlags() *managerconfig.ManagerConfig {
 		"The path of CA certificate for kafka bootstrap server.")
 	pflag.StringVar(&managerConfig.StatisticsConfig.LogInterval, "statistics-log-interval", "1m",
 		"The log interval for statistics.")
+	pflag.StringVar(&managerConfig.TracingConfig.Endpoint, "tracing-endpoint", "",
+		"The OTLP/HTTP endpoint of the collector to export the spans, the tracing is disabled if it's empty.")
+	pflag.BoolVar(&managerConfig.TracingConfig.Insecure, "tracing-insecure", false,
+		"Export the spans to the collector without TLS.")
+	pflag.Float64Var(&managerConfig.TracingConfig.SamplingRatio, "tracing-sampling-ratio", 0.1,
+		"The ratio of the traces started by the manager to be sampled, between 0 and 1.")
 	pflag.StringVar(&managerConfig.NonK8sAPIServerConfig.ClusterAPIURL, "cluster-api-url",
 		"https://kubernetes.default.svc:443", "The cluster API URL for nonK8s API server.")
 	pflag.StringVar(&managerConfig.NonK8sAPIServerConfig.ClusterAPICABundlePath, "cluster-api-cabundle-path",
@@ -123,6 +153,34 @@ func parseFlags() *managerconfig.ManagerConfig {
 	pflag.IntVar(&managerConfig.ElectionConfig.RetryPeriod, "retry-period", 26, "controller leader retry period")
 	pflag.IntVar(&managerConfig.DatabaseConfig.DataRetention, "data-retention", 18,
 		"data retention indicates how many months the expired data will kept in the database")
+	pflag.StringToIntVar(&managerConfig.DatabaseConfig.TableRetentions, "table-retention", map[string]int{},
+		"the months to keep the data of the tables, overriding the data retention, e.g. event.local_policies=3")
+	pflag.StringVar(&managerConfig.DatabaseConfig.Archive.Format, "data-archive-format", "",
+		"the format(json or parquet) to archive the expired partitions, the archive is disabled if it's empty")
+	pflag.StringVar(&managerConfig.DatabaseConfig.Archive.Dir, "data-archive-dir", "/var/lib/global-hub/archive",
+		"the directory to store the archived partitions")
+	pflag.StringVar(&managerConfig.DatabaseConfig.Archive.S3Endpoint, "data-archive-s3-endpoint", "",
+		"the endpoint of the S3-compatible storage to store the archived partitions")
+	pflag.StringVar(&managerConfig.DatabaseConfig.Archive.S3Bucket, "data-archive-s3-bucket", "",
+		"the bucket to store the archived partitions, the archives are stored in the directory if it's empty")
+	pflag.StringVar(&managerConfig.DatabaseConfig.Archive.S3Region, "data-archive-s3-region", "us-east-1",
+		"the region of the archive bucket")
+	pflag.StringVar(&managerConfig.DatabaseConfig.Archive.S3Prefix, "data-archive-s3-prefix", "",
+		"the key prefix of the archived partitions in the bucket")
+	pflag.StringVar(&managerConfig.DatabaseConfig.Backup.Schedule, "database-backup-schedule", "",
+		"the cron expression to run the database backup, the backup is disabled if it's empty")
+	pflag.IntVar(&managerConfig.DatabaseConfig.Backup.Retention, "database-backup-retention", 7,
+		"the number of the latest database backups to keep")
+	pflag.StringVar(&managerConfig.DatabaseConfig.Backup.Dir, "database-backup-dir", "/var/lib/global-hub/backup",
+		"the directory to store the database backups")
+	pflag.StringVar(&managerConfig.DatabaseConfig.Backup.S3Endpoint, "database-backup-s3-endpoint", "",
+		"the endpoint of the S3-compatible storage to store the database backups")
+	pflag.StringVar(&managerConfig.DatabaseConfig.Backup.S3Bucket, "database-backup-s3-bucket", "",
+		"the bucket to store the database backups, the backups are stored in the directory if it's empty")
+	pflag.StringVar(&managerConfig.DatabaseConfig.Backup.S3Region, "database-backup-s3-region", "us-east-1",
+		"the region of the database backup bucket")
+	pflag.StringVar(&managerConfig.DatabaseConfig.Backup.S3Prefix, "database-backup-s3-prefix", "",
+		"the key prefix of the database backups in the bucket")
 	pflag.BoolVar(&managerConfig.EnableGlobalResource, "enable-global-resource", false,
 		"enable the global resource feature")
 	pflag.BoolVar(&managerConfig.WithACM, "with-acm", false,
@@ -153,6 +211,20 @@ func completeConfig(managerConfig *managerconfig.ManagerConfig) error {
 	if ok && val != "" {
 		managerConfig.LaunchJobNames = val
 	}
+	// the schedules of the jobs are specified as name=cron
+	for _, jobSchedule := range jobSchedules {
+		name, schedule, found := strings.Cut(jobSchedule, "=")
+		if !found || strings.TrimSpace(name) == "" || strings.TrimSpace(schedule) == "" {
+			return fmt.Errorf("invalid job schedule %q, expect name=cron", jobSchedule)
+		}
+		managerConfig.JobConfig.Schedules[strings.TrimSpace(name)] = strings.TrimSpace(schedule)
+	}
+	// the credential of the database backup bucket
+	managerConfig.DatabaseConfig.Backup.S3AccessKeyID = os.Getenv(backupS3AccessKeyIDEnv)
+	managerConfig.DatabaseConfig.Backup.S3SecretAccessKey = os.Getenv(backupS3SecretAccessKeyEnv)
+	// the credential of the data archive bucket
+	managerConfig.DatabaseConfig.Archive.S3AccessKeyID = os.Getenv(archiveS3AccessKeyIDEnv)
+	managerConfig.DatabaseConfig.Archive.S3SecretAccessKey = os.Getenv(archiveS3SecretAccessKeyEnv)
 	return nil
 }
 
@@ -160,6 +232,7 @@ func createManager(ctx context.Context,
 	restConfig *rest.Config,
 	managerConfig *managerconfig.ManagerConfig,
 	sqlConn *sql.Conn,
+	restart context.CancelCauseFunc,
 ) (ctrl.Manager, error) {
 	leaseDuration := time.Duration(managerConfig.ElectionConfig.LeaseDuration) * time.Second
 	renewDeadline := time.Duration(managerConfig.ElectionConfig.RenewDeadline) * time.Second
@@ -215,7 +288,7 @@ func createManager(ctx context.Context,
 
 	// TODO: refactor the manager to start the conflation manager so that it can handle the events from restful API
 	err = controller.NewTransportCtrl(managerConfig.ManagerNamespace, constants.GHTransportConfigSecret,
-		transportCallback(mgr, managerConfig),
+		transportCallback(mgr, managerConfig, restart),
 		managerConfig.TransportConfig,
 	).SetupWithManager(mgr)
 	if err != nil {
@@ -243,7 +316,8 @@ func createManager(ctx context.Context,
 	return mgr, nil
 }
 
-func transportCallback(mgr ctrl.Manager, managerConfig *managerconfig.ManagerConfig) controller.TransportCallback {
+func transportCallback(mgr ctrl.Manager, managerConfig *managerconfig.ManagerConfig, restart context.CancelCauseFunc,
+) controller.TransportCallback {
 	return func(producer transport.Producer, consumer transport.Consumer) error {
 		if !managerConfig.WithACM {
 			return nil
@@ -252,6 +326,18 @@ func transportCallback(mgr ctrl.Manager, managerConfig *managerconfig.ManagerCon
 			if err := specsyncer.AddGlobalResourceSpecSyncers(mgr, managerConfig, producer); err != nil {
 				return fmt.Errorf("failed to add global resource spec syncers: %w", err)
 			}
+			// promote the local policies of the managed hubs to the global policies
+			if err := promotion.NewPromotionReconciler(mgr.GetClient()).SetupWithManager(mgr); err != nil {
+				return fmt.Errorf("failed to add promotion controller to manager - %w", err)
+			}
+			// render the global resources for the managed hubs by the overrides
+			if err := override.NewOverrideReconciler(mgr.GetClient()).SetupWithManager(mgr); err != nil {
+				return fmt.Errorf("failed to add override controller to manager - %w", err)
+			}
+			// dispatch the cluster lifecycle actions to the managed hubs
+			if err := clusteraction.NewClusterActionReconciler(mgr.GetClient()).SetupWithManager(mgr); err != nil {
+				return fmt.Errorf("failed to add cluster action controller to manager - %w", err)
+			}
 		}
 
 		if err := statussyncer.AddStatusSyncers(mgr, consumer, managerConfig); err != nil {
@@ -269,6 +355,23 @@ func transportCallback(mgr ctrl.Manager, managerConfig *managerconfig.ManagerCon
 			return fmt.Errorf("failed to add migration controller to manager - %w", err)
 		}
 
+		// start the notifier controller to send the alerts
+		if err := notifier.NewNotifierReconciler(mgr.GetClient(), producer).SetupWithManager(mgr); err != nil {
+			return fmt.Errorf("failed to add notifier controller to manager - %w", err)
+		}
+
+		// start the database restore controller if the backup is enabled
+		if managerConfig.DatabaseConfig.Backup != nil && managerConfig.DatabaseConfig.Backup.Schedule != "" {
+			restoreReconciler, err := backup.NewRestoreReconciler(mgr, producer, managerConfig.DatabaseConfig,
+				restart)
+			if err != nil {
+				return fmt.Errorf("failed to create the restore controller - %w", err)
+			}
+			if err := restoreReconciler.SetupWithManager(mgr); err != nil {
+				return fmt.Errorf("failed to add restore controller to manager - %w", err)
+			}
+		}
+
 		setupLog.Info("add the manager controllers to ctrl.Manager")
 		return nil
 	}
@@ -286,6 +389,17 @@ func doMain(ctx context.Context, restConfig *rest.Config) int {
 		go utils.StartDefaultPprofServer()
 	}
 
+	shutdownTracing, err := tracing.InitTracing(ctx, "multicluster-global-hub-manager", managerConfig.TracingConfig)
+	if err != nil {
+		setupLog.Error(err, "failed to initialize the tracing")
+		return 1
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			setupLog.Error(err, "failed to flush the spans")
+		}
+	}()
+
 	utils.PrintVersion(setupLog)
 	databaseConfig := &database.DatabaseConfig{
 		URL:        managerConfig.DatabaseConfig.ProcessDatabaseURL,
@@ -294,7 +408,7 @@ func doMain(ctx context.Context, restConfig *rest.Config) int {
 		PoolSize:   managerConfig.DatabaseConfig.MaxOpenConns,
 	}
 	// Init the default gorm instance, it's used to sync data to db
-	err := database.InitGormInstance(databaseConfig)
+	err = database.InitGormInstance(databaseConfig)
 	if err != nil {
 		setupLog.Error(err, "failed to initialize GORM instance")
 		return 1
@@ -314,7 +428,11 @@ func doMain(ctx context.Context, restConfig *rest.Config) int {
 		setupLog.Error(err, "failed to get db connection")
 		return 1
 	}
-	mgr, err := createManager(ctx, restConfig, managerConfig, sqlConn)
+	// the manager is restarted by cancelling the context with the cause, e.g. to reload the transport offsets after
+	// the restore, then it exits non-zero to be restarted
+	ctx, restart := context.WithCancelCause(ctx)
+	defer restart(nil)
+	mgr, err := createManager(ctx, restConfig, managerConfig, sqlConn, restart)
 	if err != nil {
 		setupLog.Error(err, "failed to create manager")
 		return 1
@@ -333,6 +451,10 @@ func doMain(ctx context.Context, restConfig *rest.Config) int {
 		setupLog.Error(err, "manager exited non-zero")
 		return 1
 	}
+	if cause := context.Cause(ctx); errors.Is(cause, backup.ErrRestart) {
+		setupLog.Info("the manager is stopped to restart", "reason", cause.Error())
+		return 1
+	}
 
 	return 0
 }
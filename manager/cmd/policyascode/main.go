@@ -0,0 +1,106 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+// The policyascode command exports the global policies, placement bindings and placements to a directory, and
+// imports them from the directory into the global hub.
+//
+//	policyascode export --dir ./policies --namespace default
+//	policyascode import --dir ./policies --dry-run --prune
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/labels"
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	managerconfig "github.com/stolostron/multicluster-global-hub/manager/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/policyascode"
+)
+
+const usage = `Usage: policyascode <export|import> [flags]
+
+Commands:
+  export    write the global policies, placement bindings and placements to the directory
+  import    apply the directory to the global hub, the changes are reported with --dry-run
+
+Flags:
+`
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) == 0 || (args[0] != "export" && args[0] != "import") {
+		fmt.Fprint(os.Stderr, usage)
+		return fmt.Errorf("expect the command export or import")
+	}
+	command := args[0]
+
+	flags := pflag.NewFlagSet("policyascode", pflag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flags.PrintDefaults()
+	}
+	dir := flags.String("dir", ".", "the directory of the policies, usually the root of a git working tree")
+	namespaces := flags.StringSlice("namespace", nil, "the namespaces of the resources, all the namespaces if empty")
+	selector := flags.StringP("selector", "l", "", "the label selector of the resources")
+	dryRun := flags.Bool("dry-run", false, "import: only report the changes without applying them")
+	prune := flags.Bool("prune", false, "import: delete the global resources which don't exist in the directory")
+	verbose := flags.BoolP("verbose", "v", false, "import: print the diff of the changes")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	opts := policyascode.Options{Namespaces: *namespaces}
+	if *selector != "" {
+		labelSelector, err := labels.Parse(*selector)
+		if err != nil {
+			return fmt.Errorf("invalid label selector %q: %w", *selector, err)
+		}
+		opts.LabelSelector = labelSelector
+	}
+
+	restConfig, err := ctrl.GetConfig()
+	if err != nil {
+		return err
+	}
+	c, err := client.New(restConfig, client.Options{Scheme: managerconfig.GetRuntimeScheme()})
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if command == "export" {
+		result, err := policyascode.Export(ctx, c, *dir, opts)
+		if err != nil {
+			return err
+		}
+		for _, path := range result.Written {
+			fmt.Printf("written %s\n", path)
+		}
+		for _, path := range result.Removed {
+			fmt.Printf("removed %s\n", path)
+		}
+		return nil
+	}
+
+	changes, err := policyascode.Plan(ctx, c, *dir, policyascode.ImportOptions{Options: opts, Prune: *prune})
+	if err != nil {
+		return err
+	}
+	policyascode.WriteReport(os.Stdout, changes, *verbose || *dryRun)
+	if *dryRun {
+		return nil
+	}
+	return policyascode.Apply(ctx, c, changes)
+}
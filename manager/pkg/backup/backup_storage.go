@@ -0,0 +1,96 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/config"
+)
+
+const backupFileSuffix = ".tar.gz"
+
+// BackupStorage stores the database backups, the backups are listed in chronological order by name
+type BackupStorage interface {
+	Put(ctx context.Context, name string, body io.Reader, size int64) error
+	Get(ctx context.Context, name string) (io.ReadCloser, error)
+	List(ctx context.Context) ([]string, error)
+	Delete(ctx context.Context, name string) error
+}
+
+// NewBackupStorage returns the S3 storage if the bucket is specified, otherwise returns the directory storage
+func NewBackupStorage(backupConfig *config.DatabaseBackupConfig) (BackupStorage, error) {
+	if backupConfig.S3Bucket != "" {
+		return NewS3Storage(backupConfig.S3Endpoint, backupConfig.S3Region, backupConfig.S3Bucket,
+			backupConfig.S3Prefix, backupConfig.S3AccessKeyID, backupConfig.S3SecretAccessKey)
+	}
+	if backupConfig.Dir == "" {
+		return nil, fmt.Errorf("neither the backup directory nor the bucket is specified")
+	}
+	return &dirStorage{dir: backupConfig.Dir}, nil
+}
+
+// dirStorage stores the backups in the directory, which is usually mounted from a PVC
+type dirStorage struct {
+	dir string
+}
+
+func (s *dirStorage) Put(ctx context.Context, name string, body io.Reader, size int64) error {
+	if err := os.MkdirAll(s.dir, 0o750); err != nil {
+		return err
+	}
+	// write to a temporary file first, so that an interrupted backup isn't listed
+	tmp, err := os.CreateTemp(s.dir, "."+name+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(s.dir, name))
+}
+
+func (s *dirStorage) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(s.dir, filepath.Base(name))) // #nosec G304
+}
+
+func (s *dirStorage) List(ctx context.Context) ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	names := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), backupFileSuffix) ||
+			strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *dirStorage) Delete(ctx context.Context, name string) error {
+	err := os.Remove(filepath.Join(s.dir, filepath.Base(name)))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package backup
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/config"
+)
+
+func TestDirStorage(t *testing.T) {
+	ctx := context.Background()
+	storage, err := NewBackupStorage(&config.DatabaseBackupConfig{Dir: t.TempDir()})
+	assert.NoError(t, err)
+
+	now := time.Date(2024, 5, 1, 2, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		name := NewBackupName(now.AddDate(0, 0, i))
+		assert.NoError(t, storage.Put(ctx, name, strings.NewReader(name), int64(len(name))))
+	}
+
+	names, err := storage.List(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"globalhub-20240501T020000Z.tar.gz",
+		"globalhub-20240502T020000Z.tar.gz",
+		"globalhub-20240503T020000Z.tar.gz",
+	}, names)
+
+	latest, err := LatestBackup(ctx, storage)
+	assert.NoError(t, err)
+	assert.Equal(t, "globalhub-20240503T020000Z.tar.gz", latest)
+
+	reader, err := storage.Get(ctx, latest)
+	assert.NoError(t, err)
+	body, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.NoError(t, reader.Close())
+	assert.Equal(t, latest, string(body))
+
+	assert.NoError(t, PruneBackups(ctx, storage, 1))
+	names, err = storage.List(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{latest}, names)
+
+	// the empty directory doesn't have any backup
+	empty, err := NewBackupStorage(&config.DatabaseBackupConfig{Dir: t.TempDir() + "/empty"})
+	assert.NoError(t, err)
+	_, err = LatestBackup(ctx, empty)
+	assert.Error(t, err)
+}
+
+// fakeS3Server is an in-memory S3 service with the path-style requests
+type fakeS3Server struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	auths   []string
+}
+
+func (s *fakeS3Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.auths = append(s.auths, r.Header.Get("Authorization"))
+
+	key := strings.TrimPrefix(r.URL.Path, "/backups/")
+	switch {
+	case r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2":
+		body := "<ListBucketResult>"
+		for name := range s.objects {
+			if strings.HasPrefix(name, r.URL.Query().Get("prefix")) {
+				body += "<Contents><Key>" + name + "</Key></Contents>"
+			}
+		}
+		body += "<IsTruncated>false</IsTruncated></ListBucketResult>"
+		_, _ = w.Write([]byte(body))
+	case r.Method == http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		s.objects[key] = body
+	case r.Method == http.MethodGet:
+		body, ok := s.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(body)
+	case r.Method == http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3Storage(t *testing.T) {
+	ctx := context.Background()
+	fakeServer := &fakeS3Server{objects: map[string][]byte{}}
+	server := httptest.NewServer(fakeServer)
+	defer server.Close()
+
+	storage, err := NewBackupStorage(&config.DatabaseBackupConfig{
+		S3Endpoint:        server.URL,
+		S3Bucket:          "backups",
+		S3Prefix:          "/globalhub/",
+		S3AccessKeyID:     "AKIDEXAMPLE",
+		S3SecretAccessKey: "secret",
+	})
+	assert.NoError(t, err)
+	storage.(*s3Storage).now = func() time.Time { return time.Date(2024, 5, 1, 2, 0, 0, 0, time.UTC) }
+
+	for _, name := range []string{"globalhub-20240501T020000Z.tar.gz", "globalhub-20240502T020000Z.tar.gz"} {
+		assert.NoError(t, storage.Put(ctx, name, strings.NewReader(name), int64(len(name))))
+	}
+	assert.Contains(t, fakeServer.objects, "globalhub/globalhub-20240501T020000Z.tar.gz")
+
+	names, err := storage.List(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"globalhub-20240501T020000Z.tar.gz", "globalhub-20240502T020000Z.tar.gz"}, names)
+
+	reader, err := storage.Get(ctx, names[1])
+	assert.NoError(t, err)
+	body, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.NoError(t, reader.Close())
+	assert.Equal(t, names[1], string(body))
+
+	assert.NoError(t, PruneBackups(ctx, storage, 1))
+	assert.Len(t, fakeServer.objects, 1)
+
+	_, err = storage.Get(ctx, names[0])
+	assert.Error(t, err)
+
+	for _, auth := range fakeServer.auths {
+		assert.True(t, strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20240501/us-east-1/s3/"+
+			"aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature="), auth)
+	}
+
+	// the credential is required
+	_, err = NewS3Storage(server.URL, "", "backups", "", "", "")
+	assert.Error(t, err)
+}
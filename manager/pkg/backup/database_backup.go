@@ -0,0 +1,514 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	backupManifestFile = "manifest.json"
+	backupTablesDir    = "tables"
+	backupNamePrefix   = "globalhub-"
+	backupTimeFormat   = "20060102T150405Z"
+	// the kafka offsets in the backup are stale, the table is reset on restore instead
+	transportTable = "status.transport"
+)
+
+var (
+	databaseBackupLog = ctrl.Log.WithName("database-backup")
+	// BackupSchemas are the global hub schemas exported by the backup
+	BackupSchemas = []string{"status", "local_spec", "local_status", "spec", "history", "event", "security"}
+)
+
+// BackupManifest describes the tables in the backup, it's used to restore the data to the current schema and to
+// verify the row counts after the restore
+type BackupManifest struct {
+	Name      string          `json:"name"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Tables    []TableManifest `json:"tables"`
+}
+
+type TableManifest struct {
+	// Name is the table name with the schema, e.g. "status.managed_clusters"
+	Name       string           `json:"name"`
+	Columns    []ColumnManifest `json:"columns"`
+	Partitions []Partition      `json:"partitions,omitempty"`
+	Rows       int64            `json:"rows"`
+}
+
+type ColumnManifest struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Partition is a partition of the partitioned table, e.g. the monthly partitions of the event tables
+type Partition struct {
+	Name  string `json:"name"`
+	Bound string `json:"bound"`
+}
+
+// TableVerification compares the row count of the table in the backup with the restored one
+type TableVerification struct {
+	Name         string
+	BackupRows   int64
+	RestoredRows int64
+}
+
+// NewBackupName returns the backup name, which is sorted in chronological order
+func NewBackupName(t time.Time) string {
+	return backupNamePrefix + t.UTC().Format(backupTimeFormat) + backupFileSuffix
+}
+
+// BackupDatabase exports the tables of the global hub schemas into a tar.gz archive. All the tables are exported
+// within a repeatable read transaction, so the backup is a consistent snapshot of the database.
+func BackupDatabase(ctx context.Context, conn *pgx.Conn, storage BackupStorage, name string) (
+	*BackupManifest, error,
+) {
+	archive, err := os.CreateTemp("", "globalhub-backup-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+
+	gzipWriter := gzip.NewWriter(archive)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	tx, err := conn.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	manifest := &BackupManifest{Name: name, CreatedAt: time.Now().UTC()}
+	tables, err := listTables(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	for _, table := range tables {
+		if table == transportTable {
+			continue
+		}
+		tableManifest, err := exportTable(ctx, tx, tarWriter, table)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export the table %s: %w", table, err)
+		}
+		manifest.Tables = append(manifest.Tables, *tableManifest)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeTarFile(tarWriter, backupManifestFile, manifestBytes); err != nil {
+		return nil, err
+	}
+	if err := tarWriter.Close(); err != nil {
+		return nil, err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	size, err := archive.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := archive.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if err := storage.Put(ctx, name, archive, size); err != nil {
+		return nil, fmt.Errorf("failed to store the backup %s: %w", name, err)
+	}
+	databaseBackupLog.Info("backup the database", "name", name, "tables", len(manifest.Tables), "size", size)
+	return manifest, nil
+}
+
+// exportTable copies the table in the csv format into the archive
+func exportTable(ctx context.Context, tx pgx.Tx, tarWriter *tar.Writer, table string) (*TableManifest, error) {
+	columns, err := listColumns(ctx, tx, table)
+	if err != nil {
+		return nil, err
+	}
+	partitions, err := listPartitions(ctx, tx, table)
+	if err != nil {
+		return nil, err
+	}
+
+	// the tar header requires the size of the file, so copy the table into a temporary file first
+	data, err := os.CreateTemp("", "globalhub-table-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(data.Name())
+	defer data.Close()
+
+	columnNames := make([]string, 0, len(columns))
+	for _, column := range columns {
+		columnNames = append(columnNames, column.Name)
+	}
+	tag, err := tx.Conn().PgConn().CopyTo(ctx, data, fmt.Sprintf("COPY (SELECT %s FROM %s) TO STDOUT WITH (FORMAT csv)",
+		quoteColumns(columnNames), quoteTable(table)))
+	if err != nil {
+		return nil, err
+	}
+
+	size, err := data.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := data.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if err := tarWriter.WriteHeader(&tar.Header{
+		Name: tableFile(table), Mode: 0o600, Size: size, ModTime: time.Now(),
+	}); err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(tarWriter, data); err != nil {
+		return nil, err
+	}
+	return &TableManifest{Name: table, Columns: columns, Partitions: partitions, Rows: tag.RowsAffected()}, nil
+}
+
+// RestoreDatabase restores the backup to the current schema of the database. The restore runs in a transaction:
+// the tables are truncated and loaded from the backup, and the row counts are verified against the backup before
+// committing, so the database is left unchanged if the restore or the verification fails. The triggers of the
+// restored tables are disabled during the restore, which only requires the owner of the tables instead of the
+// superuser.
+func RestoreDatabase(ctx context.Context, conn *pgx.Conn, storage BackupStorage, name string) (
+	[]TableVerification, error,
+) {
+	dir, err := os.MkdirTemp("", "globalhub-restore-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	manifest, err := extractBackup(ctx, storage, name, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract the backup %s: %w", name, err)
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	currentTables, err := listTables(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	restoreTables := []TableManifest{}
+	truncateTables := []string{quoteTable(transportTable)}
+	for _, table := range manifest.Tables {
+		if !containsString(currentTables, table.Name) {
+			// the table is dropped by the schema migrations
+			databaseBackupLog.Info("skip restoring the table which doesn't exist", "table", table.Name)
+			continue
+		}
+		restoreTables = append(restoreTables, table)
+		truncateTables = append(truncateTables, quoteTable(table.Name))
+	}
+	if _, err := tx.Exec(ctx, "TRUNCATE "+strings.Join(truncateTables, ", ")); err != nil {
+		return nil, fmt.Errorf("failed to truncate the tables: %w", err)
+	}
+
+	verifications := []TableVerification{}
+	for _, table := range restoreTables {
+		if err := importTable(ctx, tx, dir, table); err != nil {
+			return nil, fmt.Errorf("failed to restore the table %s: %w", table.Name, err)
+		}
+		var rows int64
+		if err := tx.QueryRow(ctx, "SELECT count(*) FROM "+quoteTable(table.Name)).Scan(&rows); err != nil {
+			return nil, err
+		}
+		verifications = append(verifications, TableVerification{
+			Name: table.Name, BackupRows: table.Rows, RestoredRows: rows,
+		})
+	}
+
+	mismatched := []string{}
+	for _, verification := range verifications {
+		if verification.BackupRows != verification.RestoredRows {
+			mismatched = append(mismatched, fmt.Sprintf("%s(backup: %d, restored: %d)", verification.Name,
+				verification.BackupRows, verification.RestoredRows))
+		}
+	}
+	if len(mismatched) > 0 {
+		return verifications, fmt.Errorf("the row counts mismatch: %s", strings.Join(mismatched, ", "))
+	}
+
+	for _, table := range restoreTables {
+		if _, err := tx.Exec(ctx, fmt.Sprintf("ALTER TABLE %s ENABLE TRIGGER USER", quoteTable(table.Name))); err != nil {
+			return nil, fmt.Errorf("failed to enable the triggers of the table %s: %w", table.Name, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	databaseBackupLog.Info("restore the database", "name", name, "tables", len(verifications))
+	return verifications, nil
+}
+
+// importTable loads the table from the backup. The columns are mapped by name, so the backup taken by a previous
+// version can be restored to the migrated schema: the dropped columns are ignored, and the added columns are
+// filled with the default values
+func importTable(ctx context.Context, tx pgx.Tx, dir string, table TableManifest) error {
+	for _, partition := range table.Partitions {
+		if _, err := tx.Exec(ctx, fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s PARTITION OF %s %s",
+			quoteTable(partition.Name), quoteTable(table.Name), partition.Bound)); err != nil {
+			return fmt.Errorf("failed to create the partition %s: %w", partition.Name, err)
+		}
+	}
+	// skip the triggers after the partitions are created, the derived data is restored from the backup as well
+	if _, err := tx.Exec(ctx, fmt.Sprintf("ALTER TABLE %s DISABLE TRIGGER USER", quoteTable(table.Name))); err != nil {
+		return fmt.Errorf("failed to disable the triggers: %w", err)
+	}
+
+	currentColumns, err := listColumns(ctx, tx, table.Name)
+	if err != nil {
+		return err
+	}
+	backupColumns, commonColumns := []string{}, []string{}
+	for _, column := range table.Columns {
+		backupColumns = append(backupColumns, column.Name)
+		for _, current := range currentColumns {
+			if current.Name == column.Name {
+				commonColumns = append(commonColumns, column.Name)
+				break
+			}
+		}
+	}
+
+	data, err := os.Open(filepath.Join(dir, tableFile(table.Name))) // #nosec G304
+	if err != nil {
+		return err
+	}
+	defer data.Close()
+
+	if len(commonColumns) == len(backupColumns) {
+		_, err = tx.Conn().PgConn().CopyFrom(ctx, data, fmt.Sprintf("COPY %s (%s) FROM STDIN WITH (FORMAT csv)",
+			quoteTable(table.Name), quoteColumns(backupColumns)))
+		return err
+	}
+
+	// some columns in the backup are dropped, load the backup into a temporary table first
+	definitions := make([]string, 0, len(table.Columns))
+	for _, column := range table.Columns {
+		definitions = append(definitions, pgx.Identifier{column.Name}.Sanitize()+" "+column.Type)
+	}
+	tmpTable := "globalhub_restore_tmp"
+	if _, err := tx.Exec(ctx, fmt.Sprintf("CREATE TEMP TABLE %s (%s) ON COMMIT DROP", tmpTable,
+		strings.Join(definitions, ", "))); err != nil {
+		return err
+	}
+	if _, err := tx.Conn().PgConn().CopyFrom(ctx, data, fmt.Sprintf("COPY %s FROM STDIN WITH (FORMAT csv)",
+		tmpTable)); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s", quoteTable(table.Name),
+		quoteColumns(commonColumns), quoteColumns(commonColumns), tmpTable)); err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, "DROP TABLE "+tmpTable)
+	return err
+}
+
+// extractBackup extracts the backup archive into the directory, and returns the manifest of the backup
+func extractBackup(ctx context.Context, storage BackupStorage, name, dir string) (*BackupManifest, error) {
+	reader, err := storage.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	gzipReader, err := gzip.NewReader(reader)
+	if err != nil {
+		return nil, err
+	}
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		target := filepath.Join(dir, filepath.Clean("/"+header.Name))
+		if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+			return nil, err
+		}
+		file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600) // #nosec G304
+		if err != nil {
+			return nil, err
+		}
+		_, err = io.Copy(file, tarReader) // #nosec G110
+		_ = file.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	manifestBytes, err := os.ReadFile(filepath.Join(dir, backupManifestFile)) // #nosec G304
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the manifest: %w", err)
+	}
+	manifest := &BackupManifest{}
+	if err := json.Unmarshal(manifestBytes, manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// PruneBackups deletes the oldest backups, and keeps the latest ones
+func PruneBackups(ctx context.Context, storage BackupStorage, retention int) error {
+	names, err := storage.List(ctx)
+	if err != nil {
+		return err
+	}
+	for len(names) > retention {
+		if err := storage.Delete(ctx, names[0]); err != nil {
+			return err
+		}
+		databaseBackupLog.Info("delete the expired backup", "name", names[0])
+		names = names[1:]
+	}
+	return nil
+}
+
+// LatestBackup returns the name of the latest backup
+func LatestBackup(ctx context.Context, storage BackupStorage) (string, error) {
+	names, err := storage.List(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(names) == 0 {
+		return "", fmt.Errorf("no backup is found")
+	}
+	return names[len(names)-1], nil
+}
+
+// listTables returns the tables of the backup schemas, the partitions are exported with their partitioned tables
+func listTables(ctx context.Context, tx pgx.Tx) ([]string, error) {
+	rows, err := tx.Query(ctx, `SELECT n.nspname || '.' || c.relname FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE c.relkind IN ('r', 'p') AND NOT c.relispartition AND n.nspname = ANY($1)
+		ORDER BY 1`, BackupSchemas)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	tables := []string{}
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, rows.Err()
+}
+
+func listColumns(ctx context.Context, tx pgx.Tx, table string) ([]ColumnManifest, error) {
+	rows, err := tx.Query(ctx, `SELECT a.attname, format_type(a.atttypid, a.atttypmod) FROM pg_attribute a
+		WHERE a.attrelid = $1::regclass AND a.attnum > 0 AND NOT a.attisdropped AND a.attgenerated = ''
+		ORDER BY a.attnum`, quoteTable(table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	columns := []ColumnManifest{}
+	for rows.Next() {
+		column := ColumnManifest{}
+		if err := rows.Scan(&column.Name, &column.Type); err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+	}
+	return columns, rows.Err()
+}
+
+func listPartitions(ctx context.Context, tx pgx.Tx, table string) ([]Partition, error) {
+	rows, err := tx.Query(ctx, `SELECT n.nspname || '.' || c.relname, pg_get_expr(c.relpartbound, c.oid)
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE i.inhparent = $1::regclass AND c.relispartition
+		ORDER BY 1`, quoteTable(table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	partitions := []Partition{}
+	for rows.Next() {
+		partition := Partition{}
+		if err := rows.Scan(&partition.Name, &partition.Bound); err != nil {
+			return nil, err
+		}
+		partitions = append(partitions, partition)
+	}
+	return partitions, rows.Err()
+}
+
+func writeTarFile(tarWriter *tar.Writer, name string, data []byte) error {
+	if err := tarWriter.WriteHeader(&tar.Header{
+		Name: name, Mode: 0o600, Size: int64(len(data)), ModTime: time.Now(),
+	}); err != nil {
+		return err
+	}
+	_, err := tarWriter.Write(data)
+	return err
+}
+
+func tableFile(table string) string {
+	return backupTablesDir + "/" + table + ".csv"
+}
+
+// quoteTable quotes the "schema.table" name
+func quoteTable(table string) string {
+	return pgx.Identifier(strings.SplitN(table, ".", 2)).Sanitize()
+}
+
+func quoteColumns(columns []string) string {
+	quoted := make([]string, 0, len(columns))
+	for _, column := range columns {
+		quoted = append(quoted, pgx.Identifier{column}.Sanitize())
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func containsString(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,225 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package backup
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/config"
+)
+
+const (
+	ArchiveFormatJSON    = "json"
+	ArchiveFormatParquet = "parquet"
+	// the key of the parquet metadata recording the postgres types of the columns
+	parquetColumnsMetadata = "globalhub.columns"
+)
+
+// NewArchiveStorage returns the S3 storage if the bucket is specified, otherwise returns the directory storage
+func NewArchiveStorage(archiveConfig *config.DatabaseArchiveConfig) (BackupStorage, error) {
+	if archiveConfig.S3Bucket != "" {
+		return NewS3Storage(archiveConfig.S3Endpoint, archiveConfig.S3Region, archiveConfig.S3Bucket,
+			archiveConfig.S3Prefix, archiveConfig.S3AccessKeyID, archiveConfig.S3SecretAccessKey)
+	}
+	if archiveConfig.Dir == "" {
+		return nil, fmt.Errorf("neither the archive directory nor the bucket is specified")
+	}
+	return &dirStorage{dir: archiveConfig.Dir}, nil
+}
+
+// ArchiveName returns the name of the archived partition, e.g. "event.local_policies_2024_01.json.gz"
+func ArchiveName(partition, format string) (string, error) {
+	switch format {
+	case ArchiveFormatJSON:
+		return partition + ".json.gz", nil
+	case ArchiveFormatParquet:
+		return partition + ".parquet", nil
+	default:
+		return "", fmt.Errorf("unsupported archive format %s", format)
+	}
+}
+
+// ArchivePartition exports the rows of the partition into an archive in the storage, and returns the name of the
+// archive and the number of the archived rows. The JSON archive has a row per line, and the parquet archive keeps
+// the text representation of the columns, whose postgres types are recorded in the metadata of the file.
+func ArchivePartition(ctx context.Context, conn *pgx.Conn, storage BackupStorage, partition, format string) (
+	string, int64, error,
+) {
+	name, err := ArchiveName(partition, format)
+	if err != nil {
+		return "", 0, err
+	}
+
+	file, err := os.CreateTemp("", "globalhub-archive-*")
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	tx, err := conn.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return "", 0, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	var writer archiveWriter
+	var query string
+	switch format {
+	case ArchiveFormatJSON:
+		writer = newJSONArchiveWriter(file)
+		query = fmt.Sprintf("SELECT row_to_json(t)::text FROM %s t", quoteTable(partition))
+	case ArchiveFormatParquet:
+		columns, err := listColumns(ctx, tx, partition)
+		if err != nil {
+			return "", 0, err
+		}
+		writer, err = newParquetArchiveWriter(file, partition, columns)
+		if err != nil {
+			return "", 0, err
+		}
+		selections := make([]string, 0, len(columns))
+		for _, column := range columns {
+			selections = append(selections, pgx.Identifier{column.Name}.Sanitize()+"::text")
+		}
+		query = fmt.Sprintf("SELECT %s FROM %s", strings.Join(selections, ", "), quoteTable(partition))
+	}
+
+	rows, err := tx.Query(ctx, query)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to query the partition %s: %w", partition, err)
+	}
+	defer rows.Close()
+	count := int64(0)
+	for rows.Next() {
+		values := make([]*string, len(rows.FieldDescriptions()))
+		dest := make([]interface{}, len(values))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return "", 0, err
+		}
+		if err := writer.Write(values); err != nil {
+			return "", 0, err
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return "", 0, err
+	}
+	if err := writer.Close(); err != nil {
+		return "", 0, err
+	}
+
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return "", 0, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", 0, err
+	}
+	if err := storage.Put(ctx, name, file, size); err != nil {
+		return "", 0, fmt.Errorf("failed to store the archive %s: %w", name, err)
+	}
+	databaseBackupLog.Info("archive the partition", "partition", partition, "name", name, "rows", count,
+		"size", size)
+	return name, count, nil
+}
+
+// archiveWriter writes the rows of the partition, a nil value is the NULL of the column
+type archiveWriter interface {
+	Write(values []*string) error
+	Close() error
+}
+
+// jsonArchiveWriter writes the rows, which are already encoded by the database, as the gzip compressed JSON lines
+type jsonArchiveWriter struct {
+	gzipWriter *gzip.Writer
+}
+
+func newJSONArchiveWriter(w io.Writer) *jsonArchiveWriter {
+	return &jsonArchiveWriter{gzipWriter: gzip.NewWriter(w)}
+}
+
+func (w *jsonArchiveWriter) Write(values []*string) error {
+	if len(values) != 1 || values[0] == nil {
+		return fmt.Errorf("expect a JSON object per row")
+	}
+	if _, err := io.WriteString(w.gzipWriter, *values[0]); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w.gzipWriter, "\n")
+	return err
+}
+
+func (w *jsonArchiveWriter) Close() error {
+	return w.gzipWriter.Close()
+}
+
+// parquetArchiveWriter writes the rows into the parquet file with an optional string column per table column
+type parquetArchiveWriter struct {
+	writer *parquet.Writer
+	// the indexes of the table columns in the parquet schema, the parquet columns are sorted by name
+	indexes []int
+}
+
+func newParquetArchiveWriter(w io.Writer, table string, columns []ColumnManifest) (*parquetArchiveWriter, error) {
+	group := parquet.Group{}
+	for _, column := range columns {
+		group[column.Name] = parquet.Optional(parquet.String())
+	}
+	schema := parquet.NewSchema(table, group)
+
+	positions := map[string]int{}
+	for i, field := range schema.Fields() {
+		positions[field.Name()] = i
+	}
+	indexes := make([]int, 0, len(columns))
+	for _, column := range columns {
+		indexes = append(indexes, positions[column.Name])
+	}
+
+	columnsBytes, err := json.Marshal(columns)
+	if err != nil {
+		return nil, err
+	}
+	return &parquetArchiveWriter{
+		writer: parquet.NewWriter(w, schema, parquet.Compression(&parquet.Zstd),
+			parquet.KeyValueMetadata(parquetColumnsMetadata, string(columnsBytes))),
+		indexes: indexes,
+	}, nil
+}
+
+func (w *parquetArchiveWriter) Write(values []*string) error {
+	if len(values) != len(w.indexes) {
+		return fmt.Errorf("expect %d columns, but got %d", len(w.indexes), len(values))
+	}
+	row := make(parquet.Row, len(values))
+	for i, value := range values {
+		index := w.indexes[i]
+		if value == nil {
+			row[index] = parquet.NullValue().Level(0, 0, index)
+		} else {
+			row[index] = parquet.ByteArrayValue([]byte(*value)).Level(0, 1, index)
+		}
+	}
+	_, err := w.writer.WriteRows([]parquet.Row{row})
+	return err
+}
+
+func (w *parquetArchiveWriter) Close() error {
+	return w.writer.Close()
+}
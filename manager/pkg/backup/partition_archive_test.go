@@ -0,0 +1,103 @@
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/config"
+)
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func TestArchiveName(t *testing.T) {
+	name, err := ArchiveName("event.local_policies_2024_01", ArchiveFormatJSON)
+	assert.NoError(t, err)
+	assert.Equal(t, "event.local_policies_2024_01.json.gz", name)
+
+	name, err = ArchiveName("event.local_policies_2024_01", ArchiveFormatParquet)
+	assert.NoError(t, err)
+	assert.Equal(t, "event.local_policies_2024_01.parquet", name)
+
+	_, err = ArchiveName("event.local_policies_2024_01", "csv")
+	assert.Error(t, err)
+}
+
+func TestJSONArchiveWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writer := newJSONArchiveWriter(buf)
+	assert.NoError(t, writer.Write([]*string{stringPtr(`{"leaf_hub_name":"hub1","count":1}`)}))
+	assert.NoError(t, writer.Write([]*string{stringPtr(`{"leaf_hub_name":"hub2","count":2}`)}))
+	assert.Error(t, writer.Write([]*string{nil}))
+	assert.NoError(t, writer.Close())
+
+	reader, err := gzip.NewReader(buf)
+	assert.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "{\"leaf_hub_name\":\"hub1\",\"count\":1}\n{\"leaf_hub_name\":\"hub2\",\"count\":2}\n", string(data))
+}
+
+func TestParquetArchiveWriter(t *testing.T) {
+	// the columns aren't sorted by name
+	columns := []ColumnManifest{
+		{Name: "policy_id", Type: "uuid"},
+		{Name: "leaf_hub_name", Type: "character varying(254)"},
+		{Name: "message", Type: "text"},
+	}
+	buf := &bytes.Buffer{}
+	writer, err := newParquetArchiveWriter(buf, "event.local_policies_2024_01", columns)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Write([]*string{
+		stringPtr("a71a6b5c-8361-4f50-9890-3de9e2df0b1c"), stringPtr("hub1"), nil,
+	}))
+	assert.NoError(t, writer.Write([]*string{
+		stringPtr("b71a6b5c-8361-4f50-9890-3de9e2df0b1c"), stringPtr("hub2"), stringPtr("compliant"),
+	}))
+	assert.Error(t, writer.Write([]*string{stringPtr("hub3")}))
+	assert.NoError(t, writer.Close())
+
+	type archivedRow struct {
+		PolicyID    *string `parquet:"policy_id,optional"`
+		LeafHubName *string `parquet:"leaf_hub_name,optional"`
+		Message     *string `parquet:"message,optional"`
+	}
+	rows, err := parquet.Read[archivedRow](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "a71a6b5c-8361-4f50-9890-3de9e2df0b1c", *rows[0].PolicyID)
+	assert.Equal(t, "hub1", *rows[0].LeafHubName)
+	assert.Nil(t, rows[0].Message)
+	assert.Equal(t, "hub2", *rows[1].LeafHubName)
+	assert.Equal(t, "compliant", *rows[1].Message)
+
+	// the postgres types are kept in the metadata
+	file, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	value, ok := file.Lookup(parquetColumnsMetadata)
+	assert.True(t, ok)
+	archivedColumns := []ColumnManifest{}
+	assert.NoError(t, json.Unmarshal([]byte(value), &archivedColumns))
+	assert.Equal(t, columns, archivedColumns)
+}
+
+func TestArchiveStorage(t *testing.T) {
+	ctx := context.Background()
+	storage, err := NewArchiveStorage(&config.DatabaseArchiveConfig{Dir: t.TempDir()})
+	assert.NoError(t, err)
+	assert.NoError(t, storage.Put(ctx, "event.local_policies_2024_01.json.gz", bytes.NewReader([]byte("a")), 1))
+	reader, err := storage.Get(ctx, "event.local_policies_2024_01.json.gz")
+	assert.NoError(t, err)
+	assert.NoError(t, reader.Close())
+
+	_, err = NewArchiveStorage(&config.DatabaseArchiveConfig{})
+	assert.Error(t, err)
+}
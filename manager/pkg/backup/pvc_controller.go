@@ -31,6 +31,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/config"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/utils"
@@ -38,6 +39,9 @@ import (
 
 var backupLog = ctrl.Log.WithName("backup pvc")
 
+// quiesceTimeout is the maximum time to wait for the database writers to release the shared lock
+const quiesceTimeout = 2 * time.Minute
+
 // BackupReconciler reconciles a MulticlusterGlobalHub object
 type BackupPVCReconciler struct {
 	manager.Manager
@@ -99,7 +103,6 @@ func (r *BackupPVCReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		backupLog.Error(err, "failed to get backup enabled", "req", req)
 		return ctrl.Result{}, err
 	}
-	database.IsBackupEnabled = isBackupEnabled
 	if !isBackupEnabled {
 		backupLog.V(2).Info("Backup is not enabled")
 		return ctrl.Result{}, nil
@@ -107,13 +110,17 @@ func (r *BackupPVCReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 
 	backupLog.V(2).Info("Start backup pvc", "req", req)
 
-	err = database.Lock(r.sqlConn)
+	// quiesce the database writers until the volume snapshot is taken
+	err = database.Quiesce(ctx, r.sqlConn, quiesceTimeout)
 	if err != nil {
-		backupLog.Error(err, "failed to get db lock")
+		backupLog.Error(err, "failed to quiesce the database")
 		return ctrl.Result{}, err
 	}
-
-	defer database.Unlock(r.sqlConn)
+	config.GlobalHubDatabaseQuiescedGauge.Set(1)
+	defer func() {
+		database.Unquiesce(r.sqlConn)
+		config.GlobalHubDatabaseQuiescedGauge.Set(0)
+	}()
 
 	triggerTime := time.Now().Format(time.RFC3339)
 	formatTriggerTime := strings.ReplaceAll(triggerTime, ":", ".")
@@ -0,0 +1,244 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/hubmanagement"
+	backupv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/backup/v1alpha1"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+var (
+	restoreLog = ctrl.Log.WithName("database-restore")
+	mghGVK     = schema.GroupVersionKind{
+		Group:   "operator.open-cluster-management.io",
+		Version: "v1alpha4",
+		Kind:    "MulticlusterGlobalHubList",
+	}
+)
+
+// ErrRestart is the cause of stopping the manager after the database is restored, the manager exits non-zero with it
+// to be restarted
+var ErrRestart = errors.New("the manager is restarted to consume the restored database")
+
+// RestoreReconciler restores the database from the backup by the GlobalHubRestore:
+//  1. Restoring: load the backup into the current schema with the other database writers locked out
+//  2. Verifying: compare the row counts of the restored tables with the backup
+//  3. Resyncing: reset the status.transport offsets, ask the operator to re-apply the schema migrations, restart the
+//     manager to drop the consumer offsets and the bundle versions held in memory, then request all the managed hubs
+//     to resync their resources once the manager is started again
+type RestoreReconciler struct {
+	client.Client
+	apiReader   client.Reader
+	producer    transport.Producer
+	storage     BackupStorage
+	databaseURL string
+	caCertPath  string
+	restart     context.CancelCauseFunc
+}
+
+func NewRestoreReconciler(mgr ctrl.Manager, producer transport.Producer, databaseConfig *config.DatabaseConfig,
+	restart context.CancelCauseFunc,
+) (*RestoreReconciler, error) {
+	storage, err := NewBackupStorage(databaseConfig.Backup)
+	if err != nil {
+		return nil, err
+	}
+	return &RestoreReconciler{
+		Client:      mgr.GetClient(),
+		apiReader:   mgr.GetAPIReader(),
+		producer:    producer,
+		storage:     storage,
+		databaseURL: databaseConfig.ProcessDatabaseURL,
+		caCertPath:  databaseConfig.CACertPath,
+		restart:     restart,
+	}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RestoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).Named("restoreController").
+		For(&backupv1alpha1.GlobalHubRestore{}).
+		Complete(r)
+}
+
+func (r *RestoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	restore := &backupv1alpha1.GlobalHubRestore{}
+	if err := r.Get(ctx, req.NamespacedName, restore); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !restore.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	// the restored database is resynced by the restarted manager
+	if restore.Status.Phase == backupv1alpha1.RestorePhaseResyncing {
+		return ctrl.Result{}, r.resync(ctx, restore)
+	}
+	// the restore only runs once
+	if restore.Status.Phase != "" {
+		return ctrl.Result{}, nil
+	}
+
+	backupName := restore.Spec.BackupName
+	if backupName == "" {
+		latest, err := LatestBackup(ctx, r.storage)
+		if err != nil {
+			return ctrl.Result{}, r.failed(ctx, restore, err)
+		}
+		backupName = latest
+	}
+	now := metav1.Now()
+	if err := r.updateStatus(ctx, restore, func(status *backupv1alpha1.GlobalHubRestoreStatus) {
+		status.Phase = backupv1alpha1.RestorePhaseRestoring
+		status.BackupName = backupName
+		status.StartTime = &now
+		status.Message = fmt.Sprintf("Restoring the database from the backup %s", backupName)
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	verifications, err := r.restore(ctx, backupName)
+	if err := r.updateStatus(ctx, restore, func(status *backupv1alpha1.GlobalHubRestoreStatus) {
+		status.Phase = backupv1alpha1.RestorePhaseVerifying
+		status.Tables = nil
+		for _, v := range verifications {
+			status.Tables = append(status.Tables, backupv1alpha1.TableVerification{
+				Name: v.Name, BackupRows: v.BackupRows, RestoredRows: v.RestoredRows,
+			})
+		}
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err != nil {
+		return ctrl.Result{}, r.failed(ctx, restore, err)
+	}
+
+	if err := r.annotateGlobalHub(ctx, string(restore.UID)); err != nil {
+		return ctrl.Result{}, r.failed(ctx, restore, fmt.Errorf("failed to re-apply the schema migrations: %w", err))
+	}
+	if err := r.updateStatus(ctx, restore, func(status *backupv1alpha1.GlobalHubRestoreStatus) {
+		status.Phase = backupv1alpha1.RestorePhaseResyncing
+		status.Message = "Restarting the manager to consume the status from the restored database"
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+	// the running consumer and committer keep the offsets of the database before the restore, which overwrite the
+	// reset status.transport, so the manager is restarted to start them from the restored database
+	restoreLog.Info("restart the manager after the restore", "name", restore.Name)
+	r.restart(ErrRestart)
+	return ctrl.Result{}, nil
+}
+
+// resync requests the managed hubs to resync their resources to the restored database
+func (r *RestoreReconciler) resync(ctx context.Context, restore *backupv1alpha1.GlobalHubRestore) error {
+	if !restore.Spec.SkipResync {
+		if err := hubmanagement.Resync(ctx, r.producer, transport.Broadcast); err != nil {
+			return r.failed(ctx, restore, fmt.Errorf("failed to resync the managed hubs: %w", err))
+		}
+	}
+
+	completed := metav1.Now()
+	return r.updateStatus(ctx, restore, func(status *backupv1alpha1.GlobalHubRestoreStatus) {
+		status.Phase = backupv1alpha1.RestorePhaseCompleted
+		status.CompletionTime = &completed
+		status.Message = fmt.Sprintf("The database is restored from the backup %s", restore.Status.BackupName)
+	})
+}
+
+// restore quiesces the database, so that the other writers, e.g. the status syncers and the cronjobs, don't write
+// the database during the restore. It fails if the writers don't release the shared lock in the quiesceTimeout.
+func (r *RestoreReconciler) restore(ctx context.Context, backupName string) ([]TableVerification, error) {
+	cert, err := os.ReadFile(r.caCertPath) // #nosec G304
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	conn, err := database.PostgresConnection(ctx, r.databaseURL, cert)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := conn.Close(ctx); err != nil {
+			restoreLog.Error(err, "failed to close the database connection")
+		}
+	}()
+
+	// the exclusive lock is held by a connection of the pool, which is released once the restore is done
+	lockConn, err := database.GetSqlDb().Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the connection to lock the database: %w", err)
+	}
+	defer func() {
+		if err := lockConn.Close(); err != nil {
+			restoreLog.Error(err, "failed to close the lock connection")
+		}
+	}()
+	if err := database.Quiesce(ctx, lockConn, quiesceTimeout); err != nil {
+		return nil, err
+	}
+	config.GlobalHubDatabaseQuiescedGauge.Set(1)
+	defer func() {
+		database.Unquiesce(lockConn)
+		config.GlobalHubDatabaseQuiescedGauge.Set(0)
+	}()
+
+	restoreLog.Info("restore the database", "backup", backupName)
+	return RestoreDatabase(ctx, conn, r.storage, backupName)
+}
+
+// annotateGlobalHub asks the operator to re-apply the database schema and migrations to the restored data
+func (r *RestoreReconciler) annotateGlobalHub(ctx context.Context, restoreUID string) error {
+	mghList := &metav1.PartialObjectMetadataList{}
+	mghList.SetGroupVersionKind(mghGVK)
+	if err := r.apiReader.List(ctx, mghList, client.InNamespace(utils.GetDefaultNamespace())); err != nil {
+		return err
+	}
+	for i := range mghList.Items {
+		mgh := &mghList.Items[i]
+		patch := []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, constants.DatabaseRestoreAnnotation,
+			restoreUID))
+		if err := r.Patch(ctx, mgh, client.RawPatch(types.MergePatchType, patch)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *RestoreReconciler) failed(ctx context.Context, restore *backupv1alpha1.GlobalHubRestore, err error) error {
+	restoreLog.Error(err, "failed to restore the database", "name", restore.Name)
+	completed := metav1.Now()
+	return r.updateStatus(ctx, restore, func(status *backupv1alpha1.GlobalHubRestoreStatus) {
+		status.Phase = backupv1alpha1.RestorePhaseFailed
+		status.CompletionTime = &completed
+		status.Message = err.Error()
+	})
+}
+
+func (r *RestoreReconciler) updateStatus(ctx context.Context, restore *backupv1alpha1.GlobalHubRestore,
+	update func(status *backupv1alpha1.GlobalHubRestoreStatus),
+) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		current := &backupv1alpha1.GlobalHubRestore{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(restore), current); err != nil {
+			return err
+		}
+		update(&current.Status)
+		return r.Status().Update(ctx, current)
+	})
+}
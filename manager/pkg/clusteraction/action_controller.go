@@ -0,0 +1,270 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package clusteraction
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	actionv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/action/v1alpha1"
+	specbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+var log = ctrl.Log.WithName("cluster-action")
+
+// the interval to refresh the status of the dispatched action from the result reported by the agent
+var statusRefreshInterval = 10 * time.Second
+
+// actionStore keeps the actions dispatched to the managed hubs and their results
+type actionStore interface {
+	// owningHubs returns the managed hubs which have the living managed cluster
+	owningHubs(ctx context.Context, clusterName string) ([]string, error)
+	// getAction returns the action by the uid of the GlobalClusterAction, or nil if it isn't created yet
+	getAction(ctx context.Context, id string) (*models.ManagedClusterAction, error)
+	createAction(ctx context.Context, action *models.ManagedClusterAction) error
+	// cancelActions removes the actions of the deleted GlobalClusterAction which aren't dispatched yet
+	cancelActions(ctx context.Context, namespace, name string) error
+}
+
+type databaseStore struct{}
+
+func (databaseStore) owningHubs(ctx context.Context, clusterName string) ([]string, error) {
+	hubs := []string{}
+	err := database.GetGorm().WithContext(ctx).Model(&models.ManagedCluster{}).
+		Where("payload->'metadata'->>'name' = ?", clusterName).
+		Distinct().Order("leaf_hub_name").Pluck("leaf_hub_name", &hubs).Error
+	return hubs, err
+}
+
+func (databaseStore) getAction(ctx context.Context, id string) (*models.ManagedClusterAction, error) {
+	action := &models.ManagedClusterAction{}
+	err := database.GetGorm().WithContext(ctx).Where(&models.ManagedClusterAction{ID: id}).First(action).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return action, err
+}
+
+func (databaseStore) createAction(ctx context.Context, action *models.ManagedClusterAction) error {
+	return database.GetGorm().WithContext(ctx).Create(action).Error
+}
+
+func (databaseStore) cancelActions(ctx context.Context, namespace, name string) error {
+	return database.GetGorm().WithContext(ctx).Where(&models.ManagedClusterAction{
+		Namespace: namespace, Name: name, State: database.OperationPending,
+	}).Delete(&models.ManagedClusterAction{}).Error
+}
+
+// ClusterActionReconciler dispatches the lifecycle action of the GlobalClusterAction to the managed hub:
+//  1. resolve the managed hub which owns the cluster from the status.managed_clusters
+//  2. record the action with the requester identity into the spec.managed_cluster_actions, which is sent to the
+//     agent of the managed hub by the spec syncer
+//  3. reflect the result reported by the agent into the status of the GlobalClusterAction
+type ClusterActionReconciler struct {
+	client.Client
+	store actionStore
+}
+
+func NewClusterActionReconciler(c client.Client) *ClusterActionReconciler {
+	return &ClusterActionReconciler{
+		Client: c,
+		store:  databaseStore{},
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterActionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// the action is dispatched only once, so the status and metadata changes don't trigger the reconcile
+	return ctrl.NewControllerManagedBy(mgr).Named("clusterActionController").
+		For(&actionv1alpha1.GlobalClusterAction{},
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
+
+func (r *ClusterActionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	action := &actionv1alpha1.GlobalClusterAction{}
+	if err := r.Get(ctx, req.NamespacedName, action); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			return ctrl.Result{}, r.store.cancelActions(ctx, req.Namespace, req.Name)
+		}
+		return ctrl.Result{}, err
+	}
+	if isCompleted(action.Status.Phase) || !action.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	record, err := r.store.getAction(ctx, string(action.UID))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if record == nil {
+		hubs, err := r.store.owningHubs(ctx, action.Spec.ClusterName)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if record, err = actionRecord(action, hubs); err != nil {
+			return ctrl.Result{}, r.failed(ctx, action, err)
+		}
+		if err := r.store.createAction(ctx, record); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to create the action: %w", err)
+		}
+		log.Info("dispatch the cluster action", "name", req.NamespacedName, "hub", record.LeafHubName,
+			"cluster", record.ClusterName, "action", record.Action)
+	}
+
+	phase := actionPhase(record.State)
+	if phase != action.Status.Phase {
+		err = r.updateStatus(ctx, action, func(status *actionv1alpha1.GlobalClusterActionStatus) {
+			status.Phase = phase
+			status.LeafHubName = record.LeafHubName
+			status.Message = record.Message
+			if isCompleted(phase) {
+				completed := metav1.Now()
+				status.CompletionTime = &completed
+			}
+		})
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if isCompleted(phase) {
+		log.Info("the cluster action is completed", "name", req.NamespacedName, "phase", phase)
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: statusRefreshInterval}, nil
+}
+
+// actionRecord returns the action for the managed hub which owns the cluster, the hubs are the managed hubs which
+// have the cluster
+func actionRecord(action *actionv1alpha1.GlobalClusterAction, hubs []string) (*models.ManagedClusterAction, error) {
+	bundle, err := actionBundle(action)
+	if err != nil {
+		return nil, err
+	}
+	leafHubName, err := owningHub(action, hubs)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(bundle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the action: %w", err)
+	}
+	return &models.ManagedClusterAction{
+		ID:          string(action.UID),
+		Namespace:   action.Namespace,
+		Name:        action.Name,
+		LeafHubName: leafHubName,
+		ClusterName: action.Spec.ClusterName,
+		Action:      string(action.Spec.Action),
+		Payload:     payload,
+		State:       database.OperationPending,
+	}, nil
+}
+
+func owningHub(action *actionv1alpha1.GlobalClusterAction, hubs []string) (string, error) {
+	if action.Spec.LeafHubName != "" {
+		for _, hub := range hubs {
+			if hub == action.Spec.LeafHubName {
+				return hub, nil
+			}
+		}
+		return "", fmt.Errorf("the managed cluster %s isn't found on the hub %s", action.Spec.ClusterName,
+			action.Spec.LeafHubName)
+	}
+	switch len(hubs) {
+	case 0:
+		return "", fmt.Errorf("the managed cluster %s isn't found", action.Spec.ClusterName)
+	case 1:
+		return hubs[0], nil
+	default:
+		return "", fmt.Errorf("the managed cluster %s exists on the hubs %s, specify the leafHubName",
+			action.Spec.ClusterName, strings.Join(hubs, ","))
+	}
+}
+
+// actionBundle validates the action and returns the bundle sent to the managed hub, the action is applied as the
+// requester stamped by the webhook of the manager
+func actionBundle(action *actionv1alpha1.GlobalClusterAction) (*specbundle.ManagedClusterActionBundle, error) {
+	userIdentity := action.Annotations[constants.UserIdentityAnnotation]
+	if userIdentity == "" {
+		return nil, fmt.Errorf("the requester of the action isn't found in the annotation %s",
+			constants.UserIdentityAnnotation)
+	}
+
+	bundle := &specbundle.ManagedClusterActionBundle{
+		ActionID:     string(action.UID),
+		ClusterName:  action.Spec.ClusterName,
+		UserIdentity: userIdentity,
+		UserGroups:   action.Annotations[constants.UserGroupsAnnotation],
+	}
+	if action.Spec.Action == actionv1alpha1.ClusterActionUpgrade {
+		if action.Spec.Upgrade == nil || action.Spec.Upgrade.Version == "" {
+			return nil, errors.New("the upgrade version is required by the Upgrade action")
+		}
+		bundle.Upgrade = &specbundle.ClusterUpgrade{
+			Version: action.Spec.Upgrade.Version,
+			Image:   action.Spec.Upgrade.Image,
+			Channel: action.Spec.Upgrade.Channel,
+			Force:   action.Spec.Upgrade.Force,
+		}
+	}
+	return bundle, nil
+}
+
+func (r *ClusterActionReconciler) failed(ctx context.Context, action *actionv1alpha1.GlobalClusterAction,
+	err error,
+) error {
+	log.Error(err, "failed to dispatch the cluster action", "name", client.ObjectKeyFromObject(action))
+	completed := metav1.Now()
+	return r.updateStatus(ctx, action, func(status *actionv1alpha1.GlobalClusterActionStatus) {
+		status.Phase = actionv1alpha1.ActionPhaseFailed
+		status.CompletionTime = &completed
+		status.Message = err.Error()
+	})
+}
+
+func (r *ClusterActionReconciler) updateStatus(ctx context.Context, action *actionv1alpha1.GlobalClusterAction,
+	update func(status *actionv1alpha1.GlobalClusterActionStatus),
+) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		current := &actionv1alpha1.GlobalClusterAction{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(action), current); err != nil {
+			return err
+		}
+		update(&current.Status)
+		return r.Status().Update(ctx, current)
+	})
+}
+
+func actionPhase(state database.OperationState) actionv1alpha1.ActionPhase {
+	switch state {
+	case database.OperationSent:
+		return actionv1alpha1.ActionPhaseDispatched
+	case database.OperationSucceeded:
+		return actionv1alpha1.ActionPhaseSucceeded
+	case database.OperationFailed:
+		return actionv1alpha1.ActionPhaseFailed
+	default:
+		return actionv1alpha1.ActionPhasePending
+	}
+}
+
+func isCompleted(phase actionv1alpha1.ActionPhase) bool {
+	return phase == actionv1alpha1.ActionPhaseSucceeded || phase == actionv1alpha1.ActionPhaseFailed
+}
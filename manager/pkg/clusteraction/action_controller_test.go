@@ -0,0 +1,185 @@
+package clusteraction
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	actionv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/action/v1alpha1"
+	specbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+type fakeStore struct {
+	clusters map[string][]string
+	actions  map[string]*models.ManagedClusterAction
+}
+
+func (s *fakeStore) owningHubs(ctx context.Context, clusterName string) ([]string, error) {
+	return s.clusters[clusterName], nil
+}
+
+func (s *fakeStore) getAction(ctx context.Context, id string) (*models.ManagedClusterAction, error) {
+	return s.actions[id], nil
+}
+
+func (s *fakeStore) createAction(ctx context.Context, action *models.ManagedClusterAction) error {
+	s.actions[action.ID] = action
+	return nil
+}
+
+func (s *fakeStore) cancelActions(ctx context.Context, namespace, name string) error {
+	for id, action := range s.actions {
+		if action.Namespace == namespace && action.Name == name && action.State == database.OperationPending {
+			delete(s.actions, id)
+		}
+	}
+	return nil
+}
+
+func clusterAction(name string, spec actionv1alpha1.GlobalClusterActionSpec) *actionv1alpha1.GlobalClusterAction {
+	return &actionv1alpha1.GlobalClusterAction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name, Namespace: "default", UID: types.UID("uid-" + name),
+			Annotations: map[string]string{
+				constants.UserIdentityAnnotation: "YWRtaW4=",
+				constants.UserGroupsAnnotation:   "c3lzdGVtOmF1dGhlbnRpY2F0ZWQ=",
+			},
+		},
+		Spec: spec,
+	}
+}
+
+func TestClusterActionReconciler(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	assert.NoError(t, actionv1alpha1.AddToScheme(scheme))
+
+	upgrade := clusterAction("upgrade", actionv1alpha1.GlobalClusterActionSpec{
+		ClusterName: "cluster1",
+		Action:      actionv1alpha1.ClusterActionUpgrade,
+		Upgrade:     &actionv1alpha1.ClusterUpgrade{Version: "4.16.3", Channel: "stable-4.16"},
+	})
+	ambiguous := clusterAction("ambiguous", actionv1alpha1.GlobalClusterActionSpec{
+		ClusterName: "cluster2",
+		Action:      actionv1alpha1.ClusterActionDetach,
+	})
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(upgrade, ambiguous).
+		WithStatusSubresource(upgrade, ambiguous).Build()
+	store := &fakeStore{
+		clusters: map[string][]string{"cluster1": {"hub1"}, "cluster2": {"hub1", "hub2"}},
+		actions:  map[string]*models.ManagedClusterAction{},
+	}
+	r := &ClusterActionReconciler{Client: c, store: store}
+
+	// the action is recorded for the owning hub
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(upgrade)}
+	result, err := r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, statusRefreshInterval, result.RequeueAfter)
+	record := store.actions[string(upgrade.UID)]
+	assert.NotNil(t, record)
+	assert.Equal(t, "hub1", record.LeafHubName)
+	assert.Equal(t, database.OperationPending, record.State)
+	bundle := &specbundle.ManagedClusterActionBundle{}
+	assert.NoError(t, json.Unmarshal(record.Payload, bundle))
+	assert.Equal(t, "YWRtaW4=", bundle.UserIdentity)
+	assert.Equal(t, "4.16.3", bundle.Upgrade.Version)
+	assert.NoError(t, c.Get(ctx, req.NamespacedName, upgrade))
+	assert.Equal(t, actionv1alpha1.ActionPhasePending, upgrade.Status.Phase)
+	assert.Equal(t, "hub1", upgrade.Status.LeafHubName)
+
+	// the result reported by the agent is reflected into the status
+	record.State, record.Message = database.OperationFailed, "forbidden"
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+	assert.NoError(t, c.Get(ctx, req.NamespacedName, upgrade))
+	assert.Equal(t, actionv1alpha1.ActionPhaseFailed, upgrade.Status.Phase)
+	assert.Equal(t, "forbidden", upgrade.Status.Message)
+	assert.NotNil(t, upgrade.Status.CompletionTime)
+
+	// the action fails without dispatching if the cluster exists on multiple hubs
+	req = ctrl.Request{NamespacedName: client.ObjectKeyFromObject(ambiguous)}
+	_, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.NotContains(t, store.actions, string(ambiguous.UID))
+	assert.NoError(t, c.Get(ctx, req.NamespacedName, ambiguous))
+	assert.Equal(t, actionv1alpha1.ActionPhaseFailed, ambiguous.Status.Phase)
+	assert.Contains(t, ambiguous.Status.Message, "specify the leafHubName")
+}
+
+func TestActionRecord(t *testing.T) {
+	cases := []struct {
+		name    string
+		action  *actionv1alpha1.GlobalClusterAction
+		hubs    []string
+		wantHub string
+		wantErr string
+	}{
+		{
+			name: "the specified hub",
+			action: clusterAction("action", actionv1alpha1.GlobalClusterActionSpec{
+				ClusterName: "cluster1", LeafHubName: "hub2", Action: actionv1alpha1.ClusterActionDenyClient,
+			}),
+			hubs:    []string{"hub1", "hub2"},
+			wantHub: "hub2",
+		},
+		{
+			name: "the cluster isn't on the specified hub",
+			action: clusterAction("action", actionv1alpha1.GlobalClusterActionSpec{
+				ClusterName: "cluster1", LeafHubName: "hub3", Action: actionv1alpha1.ClusterActionDenyClient,
+			}),
+			hubs:    []string{"hub1"},
+			wantErr: "isn't found on the hub hub3",
+		},
+		{
+			name: "the cluster isn't found",
+			action: clusterAction("action", actionv1alpha1.GlobalClusterActionSpec{
+				ClusterName: "cluster1", Action: actionv1alpha1.ClusterActionRotateBootstrap,
+			}),
+			wantErr: "the managed cluster cluster1 isn't found",
+		},
+		{
+			name: "the upgrade without version",
+			action: clusterAction("action", actionv1alpha1.GlobalClusterActionSpec{
+				ClusterName: "cluster1", Action: actionv1alpha1.ClusterActionUpgrade,
+			}),
+			hubs:    []string{"hub1"},
+			wantErr: "the upgrade version is required",
+		},
+		{
+			name: "the action without requester",
+			action: &actionv1alpha1.GlobalClusterAction{
+				ObjectMeta: metav1.ObjectMeta{Name: "action", Namespace: "default"},
+				Spec: actionv1alpha1.GlobalClusterActionSpec{
+					ClusterName: "cluster1", Action: actionv1alpha1.ClusterActionDetach,
+				},
+			},
+			hubs:    []string{"hub1"},
+			wantErr: "the requester of the action isn't found",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			record, err := actionRecord(tc.action, tc.hubs)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantHub, record.LeafHubName)
+			assert.Equal(t, string(tc.action.Spec.Action), record.Action)
+		})
+	}
+}
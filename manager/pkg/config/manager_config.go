@@ -9,6 +9,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi"
 	commonobjects "github.com/stolostron/multicluster-global-hub/pkg/objects"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
@@ -20,15 +21,27 @@ type ManagerConfig struct {
 	DatabaseConfig        *DatabaseConfig
 	TransportConfig       *transport.TransportInternalConfig
 	StatisticsConfig      *statistics.StatisticsConfig
+	TracingConfig         *tracing.TracingConfig
 	NonK8sAPIServerConfig *nonk8sapi.NonK8sAPIServerConfig
 	ElectionConfig        *commonobjects.LeaderElectionConfig
 	EnableGlobalResource  bool
 	ImportClusterInHosted bool
 	WithACM               bool
 	LaunchJobNames        string
+	JobConfig             *JobConfig
 	EnablePprof           bool
 }
 
+// JobConfig overrides the schedules of the built-in jobs of the scheduler
+type JobConfig struct {
+	// Schedules are the cron expressions of the jobs, e.g. {"data-retention": "0 0 1 * *"}
+	Schedules map[string]string
+	// Disabled are the names of the jobs which aren't scheduled
+	Disabled []string
+	// StaleHeartbeatThreshold is how long the heartbeat of the removed managed hub is kept after it's inactive
+	StaleHeartbeatThreshold time.Duration
+}
+
 type SyncerConfig struct {
 	SpecSyncInterval              time.Duration
 	StatusSyncInterval            time.Duration
@@ -41,4 +54,35 @@ type DatabaseConfig struct {
 	CACertPath                 string
 	MaxOpenConns               int
 	DataRetention              int
+	// TableRetentions overrides the DataRetention months of the tables, e.g. {"event.local_policies": 3}
+	TableRetentions map[string]int
+	Backup          *DatabaseBackupConfig
+	Archive         *DatabaseArchiveConfig
+}
+
+// DatabaseBackupConfig is the scheduled logical backup of the database. The backups are stored in the Dir if
+// the S3Bucket isn't specified.
+type DatabaseBackupConfig struct {
+	Schedule          string
+	Retention         int
+	Dir               string
+	S3Endpoint        string
+	S3Bucket          string
+	S3Region          string
+	S3Prefix          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+}
+
+// DatabaseArchiveConfig exports the partitions dropped by the data retention into the files of the Format. The files
+// are stored in the Dir if the S3Bucket isn't specified. The archive is disabled if the Format is empty.
+type DatabaseArchiveConfig struct {
+	Format            string
+	Dir               string
+	S3Endpoint        string
+	S3Bucket          string
+	S3Region          string
+	S3Prefix          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
 }
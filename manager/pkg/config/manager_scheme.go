@@ -21,7 +21,12 @@ import (
 	subscriptionv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
 	applicationv1beta1 "sigs.k8s.io/application/api/v1beta1"
 
+	actionv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/action/v1alpha1"
+	backupv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/backup/v1alpha1"
 	migrationv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/migration/v1alpha1"
+	notifierv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/notifier/v1alpha1"
+	overridev1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/override/v1alpha1"
+	promotionv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/promotion/v1alpha1"
 )
 
 func GetRuntimeScheme() *runtime.Scheme {
@@ -39,6 +44,11 @@ func GetRuntimeScheme() *runtime.Scheme {
 	utilruntime.Must(applicationv1beta1.AddToScheme(scheme))
 	utilruntime.Must(mchv1.AddToScheme(scheme))
 	utilruntime.Must(migrationv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(backupv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(notifierv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(promotionv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(overridev1alpha1.AddToScheme(scheme))
+	utilruntime.Must(actionv1alpha1.AddToScheme(scheme))
 	utilruntime.Must(authv1beta1.AddToScheme(scheme))
 	utilruntime.Must(klusterletv1alpha1.AddToScheme(scheme))
 	return scheme
@@ -15,7 +15,26 @@ var GlobalHubCronJobGaugeVec = prometheus.NewGaugeVec(
 	},
 )
 
+var GlobalHubOrphanedSpecRowsGaugeVec = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "multicluster_global_hub_orphaned_spec_rows",
+		Help: "The number of the spec rows referencing the managed hubs or clusters which don't exist.",
+	},
+	[]string{
+		"table", // The spec table of the rows.
+	},
+)
+
+var GlobalHubDatabaseQuiescedGauge = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "multicluster_global_hub_database_quiesced",
+		Help: "Whether the database is quiesced by the backup or the restore. 1 == quiesced, 0 == not quiesced.",
+	},
+)
+
 // RegisterMetrics will register metrics with the global prometheus registry
 func RegisterMetrics() {
 	metrics.Registry.MustRegister(GlobalHubCronJobGaugeVec)
+	metrics.Registry.MustRegister(GlobalHubOrphanedSpecRowsGaugeVec)
+	metrics.Registry.MustRegister(GlobalHubDatabaseQuiescedGauge)
 }
@@ -2,15 +2,21 @@ package cronjob
 
 import (
 	"context"
+	"fmt"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-co-op/gocron"
 	"github.com/go-logr/logr"
+	"github.com/google/uuid"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/config"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/cronjob/task"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
 )
 
 const (
@@ -20,93 +26,303 @@ const (
 	EveryHour   string = "hour"
 	EveryMinute string = "minute"
 	EverySecond string = "second"
+
+	// the interval to pick up the runs triggered by the API
+	pendingRunsInterval = 10 * time.Second
 )
 
+var (
+	// JobNames are the built-in jobs of the scheduler
+	JobNames = []string{
+		task.LocalComplianceTaskName,
+		task.RetentionTaskName,
+		task.DatabaseBackupTaskName,
+		task.ComplianceRollupTaskName,
+		task.StaleHeartbeatTaskName,
+		task.OrphanedSpecTaskName,
+		task.VacuumAnalyzeTaskName,
+	}
+
+	// defaultSchedules are the cron expressions of the jobs if they aren't overridden by the job config
+	defaultSchedules = map[string]string{
+		task.LocalComplianceTaskName:  "0 0 * * *",
+		task.RetentionTaskName:        "0 0 1,15,28 * *",
+		task.ComplianceRollupTaskName: "30 0 * * *",
+		task.StaleHeartbeatTaskName:   "0 1 * * *",
+		task.OrphanedSpecTaskName:     "0 2 * * *",
+		task.VacuumAnalyzeTaskName:    "0 3 * * 0",
+	}
+
+	// intervalSchedules are the cron expressions of the scheduler intervals for the local compliance job
+	intervalSchedules = map[string]string{
+		EveryMonth:  "0 0 1 * *",
+		EveryWeek:   "0 0 * * 0",
+		EveryDay:    "0 0 * * *",
+		EveryHour:   "0 * * * *",
+		EveryMinute: "* * * * *",
+		EverySecond: "* * * * * *",
+	}
+)
+
+// jobTask runs the job, the returned error is recorded as the outcome of the run
+type jobTask func(ctx context.Context, job gocron.Job) error
+
+// scheduledJob is the job added to the scheduler, its runs are serialized by the lock
+type scheduledJob struct {
+	name string
+	task jobTask
+	job  *gocron.Job
+	lock sync.Mutex
+}
+
 type GlobalHubJobScheduler struct {
 	log        logr.Logger
 	scheduler  *gocron.Scheduler
 	launchJobs []string
+	jobs       map[string]*scheduledJob
 }
 
-func NewGlobalHubScheduler(scheduler *gocron.Scheduler, launchJobs []string) *GlobalHubJobScheduler {
-	return &GlobalHubJobScheduler{
-		log:        ctrl.Log.WithName("cronjob-scheduler"),
-		scheduler:  scheduler,
-		launchJobs: launchJobs,
+// NewGlobalHubScheduler adds the enabled jobs to the scheduler with their schedules
+func NewGlobalHubScheduler(ctx context.Context, managerConfig *config.ManagerConfig) (*GlobalHubJobScheduler, error) {
+	jobConfig := managerConfig.JobConfig
+	if jobConfig == nil {
+		jobConfig = &config.JobConfig{}
+	}
+	for name := range jobConfig.Schedules {
+		if !slices.Contains(JobNames, name) {
+			return nil, fmt.Errorf("the schedule of the job %s isn't supported", name)
+		}
+	}
+	for _, name := range jobConfig.Disabled {
+		if !slices.Contains(JobNames, name) {
+			return nil, fmt.Errorf("the disabled job %s isn't supported", name)
+		}
+	}
+	if err := task.ValidateDataRetention(managerConfig.DatabaseConfig); err != nil {
+		return nil, err
+	}
+
+	s := &GlobalHubJobScheduler{
+		log: ctrl.Log.WithName("cronjob-scheduler"),
+		// Scheduler timezone:
+		// The cluster may be in a different timezones, Here we choose to be consistent with the local GH timezone.
+		scheduler: gocron.NewScheduler(time.Local),
+		jobs:      map[string]*scheduledJob{},
+	}
+	for _, name := range strings.Split(managerConfig.LaunchJobNames, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			s.launchJobs = append(s.launchJobs, name)
+		}
 	}
+
+	tasks := jobTasks(managerConfig, jobConfig)
+	for _, name := range JobNames {
+		jobTask, found := tasks[name]
+		if !found || slices.Contains(jobConfig.Disabled, name) {
+			s.log.Info("the job is disabled", "name", name)
+			continue
+		}
+		schedule := jobSchedule(managerConfig, jobConfig, name)
+		var scheduler *gocron.Scheduler
+		// the cron expression with 6 fields starts with the seconds
+		if len(strings.Fields(schedule)) == 6 {
+			scheduler = s.scheduler.CronWithSeconds(schedule)
+		} else {
+			scheduler = s.scheduler.Cron(schedule)
+		}
+		job, err := scheduler.Tag(name).DoWithJobDetails(s.runScheduled, ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to schedule the job %s with %q: %w", name, schedule, err)
+		}
+		s.jobs[name] = &scheduledJob{name: name, task: jobTask, job: job}
+		s.log.Info("set the job", "name", name, "schedule", schedule, "scheduleAt", job.ScheduledAtTime())
+	}
+	return s, nil
 }
 
 func AddSchedulerToManager(ctx context.Context, mgr ctrl.Manager,
 	managerConfig *config.ManagerConfig, enableSimulation bool,
 ) error {
-	log := ctrl.Log.WithName("cronjob-scheduler")
-	// Scheduler timezone:
-	// The cluster may be in a different timezones, Here we choose to be consistent with the local GH timezone.
-	scheduler := gocron.NewScheduler(time.Local)
-
-	switch managerConfig.SchedulerInterval {
-	case EveryMonth:
-		scheduler = scheduler.Every(1).Month(1)
-	case EveryWeek:
-		scheduler = scheduler.Every(1).Week()
-	case EveryHour:
-		scheduler = scheduler.Every(1).Hour()
-	case EveryMinute:
-		scheduler = scheduler.Every(1).Minute()
-	case EverySecond:
-		scheduler = scheduler.Every(1).Second()
-	default:
-		scheduler = scheduler.Every(1).Day().At("00:00")
-	}
-	complianceHistoryJob, err := scheduler.
-		Tag(task.LocalComplianceTaskName).
-		DoWithJobDetails(task.LocalComplianceHistory, ctx)
+	scheduler, err := NewGlobalHubScheduler(ctx, managerConfig)
 	if err != nil {
 		return err
 	}
-	log.Info("set SyncLocalCompliance job", "scheduleAt", complianceHistoryJob.ScheduledAtTime())
+	return mgr.Add(scheduler)
+}
 
-	dataRetentionJob, err := scheduler.
-		Every(1).Month(1, 15, 28).At("00:00").
-		Tag(task.RetentionTaskName).
-		DoWithJobDetails(task.DataRetention, ctx, managerConfig.DatabaseConfig.DataRetention)
-	if err != nil {
-		return err
+// jobTasks returns the tasks of the jobs, the database backup job is only available if the backup is configured
+func jobTasks(managerConfig *config.ManagerConfig, jobConfig *config.JobConfig) map[string]jobTask {
+	databaseConfig := managerConfig.DatabaseConfig
+	staleHeartbeatThreshold := jobConfig.StaleHeartbeatThreshold
+	if staleHeartbeatThreshold == 0 {
+		staleHeartbeatThreshold = task.DefaultStaleHeartbeatThreshold
+	}
+	tasks := map[string]jobTask{
+		task.LocalComplianceTaskName: task.LocalComplianceHistory,
+		task.RetentionTaskName: func(ctx context.Context, job gocron.Job) error {
+			return task.DataRetention(ctx, databaseConfig, job)
+		},
+		task.ComplianceRollupTaskName: func(ctx context.Context, job gocron.Job) error {
+			return task.ComplianceRollup(ctx, databaseConfig, job)
+		},
+		task.StaleHeartbeatTaskName: func(ctx context.Context, job gocron.Job) error {
+			return task.StaleHeartbeatCleanup(ctx, staleHeartbeatThreshold, job)
+		},
+		task.OrphanedSpecTaskName:  task.OrphanedSpecDetection,
+		task.VacuumAnalyzeTaskName: task.VacuumAnalyze,
+	}
+	if databaseConfig.Backup != nil && databaseConfig.Backup.Schedule != "" {
+		tasks[task.DatabaseBackupTaskName] = func(ctx context.Context, job gocron.Job) error {
+			return task.DatabaseBackup(ctx, databaseConfig, job)
+		}
 	}
-	log.Info("set DataRetention job", "scheduleAt", dataRetentionJob.ScheduledAtTime())
+	return tasks
+}
 
-	return mgr.Add(&GlobalHubJobScheduler{
-		log:        log,
-		scheduler:  scheduler,
-		launchJobs: strings.Split(managerConfig.LaunchJobNames, ","),
-	})
+// jobSchedule returns the cron expression of the job, the schedule of the job config takes precedence over the
+// scheduler interval of the local compliance job and the schedule of the database backup
+func jobSchedule(managerConfig *config.ManagerConfig, jobConfig *config.JobConfig, name string) string {
+	if schedule, found := jobConfig.Schedules[name]; found {
+		return schedule
+	}
+	switch name {
+	case task.LocalComplianceTaskName:
+		if schedule, found := intervalSchedules[managerConfig.SchedulerInterval]; found {
+			return schedule
+		}
+	case task.DatabaseBackupTaskName:
+		return managerConfig.DatabaseConfig.Backup.Schedule
+	}
+	return defaultSchedules[name]
 }
 
 func (s *GlobalHubJobScheduler) Start(ctx context.Context) error {
 	s.log.Info("start job scheduler")
 	// Set the status of the job to 0 (success) when the job is started.
-	config.GlobalHubCronJobGaugeVec.WithLabelValues(task.RetentionTaskName).Set(0)
-	config.GlobalHubCronJobGaugeVec.WithLabelValues(task.LocalComplianceTaskName).Set(0)
+	for name := range s.jobs {
+		config.GlobalHubCronJobGaugeVec.WithLabelValues(name).Set(0)
+	}
+	// the runs are interrupted if the previous leader exits while they're running
+	err := database.GetGorm().WithContext(ctx).Model(&models.JobRun{}).
+		Where(&models.JobRun{State: database.JobRunRunning}).
+		Updates(map[string]interface{}{
+			"state":  database.JobRunFailed,
+			"end_at": time.Now(),
+			"error":  "the run is interrupted by the restart of the manager",
+		}).Error
+	if err != nil {
+		s.log.Error(err, "failed to update the interrupted job runs")
+	}
+
 	s.scheduler.StartAsync()
-	if err := s.ExecJobs(); err != nil {
-		return err
+	s.ExecJobs(ctx)
+
+	ticker := time.NewTicker(pendingRunsInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			s.scheduler.Stop()
+			return nil
+		case <-ticker.C:
+			if err := s.runPendingJobs(ctx); err != nil {
+				s.log.Error(err, "failed to run the pending jobs")
+			}
+		}
 	}
-	<-ctx.Done()
-	s.scheduler.Stop()
-	return nil
 }
 
-func (s *GlobalHubJobScheduler) ExecJobs() error {
-	for _, job := range s.launchJobs {
-		switch job {
-		case task.RetentionTaskName:
-			s.log.Info("launch the job", "name", job)
-			if err := s.scheduler.RunByTag(job); err != nil {
-				return err
-			}
-		default:
-			s.log.Info("failed to launch the unknow job immediately", "name", job)
+// ExecJobs launches the jobs once the scheduler is started
+func (s *GlobalHubJobScheduler) ExecJobs(ctx context.Context) {
+	for _, name := range s.launchJobs {
+		if _, found := s.jobs[name]; !found {
+			s.log.Info("failed to launch the unknow job immediately", "name", name)
+			continue
+		}
+		s.log.Info("launch the job", "name", name)
+		go s.launch(ctx, name, database.JobRunLaunched)
+	}
+}
+
+// runScheduled is the function of the gocron job, which runs the job by its schedule
+func (s *GlobalHubJobScheduler) runScheduled(ctx context.Context, name string, job gocron.Job) {
+	s.launch(ctx, name, database.JobRunScheduled)
+}
+
+// launch creates the run of the job and executes it, the run is skipped if the job is still running
+func (s *GlobalHubJobScheduler) launch(ctx context.Context, name string, trigger database.JobRunTrigger) {
+	j := s.jobs[name]
+	run := &models.JobRun{ID: uuid.New().String(), JobName: name, Trigger: trigger}
+	if !j.lock.TryLock() {
+		now := time.Now()
+		run.State = database.JobRunSkipped
+		run.StartAt, run.EndAt = &now, &now
+		run.Error = "the previous run of the job is still running"
+		s.log.Info("skip the job", "name", name, "trigger", trigger)
+		s.saveRun(ctx, run)
+		return
+	}
+	s.execute(ctx, j, run)
+}
+
+// runPendingJobs executes the runs triggered by the API, the run is kept pending until the running one of the job
+// is finished
+func (s *GlobalHubJobScheduler) runPendingJobs(ctx context.Context) error {
+	runs := []models.JobRun{}
+	err := database.GetGorm().WithContext(ctx).Where(&models.JobRun{State: database.JobRunPending}).
+		Order("created_at").Find(&runs).Error
+	if err != nil {
+		return err
+	}
+	for i := range runs {
+		run := &runs[i]
+		j, found := s.jobs[run.JobName]
+		if !found {
+			now := time.Now()
+			run.State = database.JobRunFailed
+			run.EndAt = &now
+			run.Error = fmt.Sprintf("the job %s isn't scheduled", run.JobName)
+			s.saveRun(ctx, run)
+			continue
+		}
+		if !j.lock.TryLock() {
+			continue
 		}
+		go s.execute(ctx, j, run)
 	}
 	return nil
 }
+
+// execute runs the job whose lock is held by the caller, and records the outcome of the run
+func (s *GlobalHubJobScheduler) execute(ctx context.Context, j *scheduledJob, run *models.JobRun) {
+	defer j.lock.Unlock()
+
+	start := time.Now()
+	run.State = database.JobRunRunning
+	run.StartAt = &start
+	s.saveRun(ctx, run)
+
+	err := j.task(ctx, *j.job)
+
+	end := time.Now()
+	run.EndAt = &end
+	run.DurationMs = end.Sub(start).Milliseconds()
+	if err != nil {
+		run.State = database.JobRunFailed
+		run.Error = err.Error()
+		config.GlobalHubCronJobGaugeVec.WithLabelValues(j.name).Set(1)
+	} else {
+		run.State = database.JobRunSucceeded
+		config.GlobalHubCronJobGaugeVec.WithLabelValues(j.name).Set(0)
+	}
+	s.saveRun(ctx, run)
+	s.log.Info("finish the job", "name", j.name, "trigger", run.Trigger, "state", run.State,
+		"duration", end.Sub(start))
+}
+
+// saveRun records the run into the job history, the job isn't failed if the run fails to be recorded
+func (s *GlobalHubJobScheduler) saveRun(ctx context.Context, run *models.JobRun) {
+	if err := database.GetGorm().WithContext(ctx).Save(run).Error; err != nil {
+		s.log.Error(err, "failed to save the job run", "name", run.JobName, "id", run.ID)
+	}
+}
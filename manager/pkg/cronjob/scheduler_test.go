@@ -0,0 +1,91 @@
+package cronjob
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/cronjob/task"
+)
+
+func TestNewGlobalHubScheduler(t *testing.T) {
+	ctx := context.Background()
+	managerConfig := &config.ManagerConfig{
+		SchedulerInterval: EveryHour,
+		DatabaseConfig:    &config.DatabaseConfig{DataRetention: 18},
+		LaunchJobNames:    "data-retention, ,vacuum-analyze",
+		JobConfig: &config.JobConfig{
+			Schedules: map[string]string{
+				task.RetentionTaskName:     "0 0 1 * *",
+				task.VacuumAnalyzeTaskName: "*/30 * * * * *",
+			},
+			Disabled: []string{task.OrphanedSpecTaskName},
+		},
+	}
+	scheduler, err := NewGlobalHubScheduler(ctx, managerConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{task.RetentionTaskName, task.VacuumAnalyzeTaskName}, scheduler.launchJobs)
+
+	// the backup isn't configured and the orphaned spec detection is disabled
+	assert.Len(t, scheduler.jobs, 5)
+	assert.NotContains(t, scheduler.jobs, task.DatabaseBackupTaskName)
+	assert.NotContains(t, scheduler.jobs, task.OrphanedSpecTaskName)
+	for name, job := range scheduler.jobs {
+		assert.Equal(t, []string{name}, job.job.Tags())
+	}
+
+	// the scheduler interval is the schedule of the local compliance job if it isn't overridden
+	assert.Equal(t, "0 * * * *", jobSchedule(managerConfig, managerConfig.JobConfig, task.LocalComplianceTaskName))
+	assert.Equal(t, "0 0 1 * *", jobSchedule(managerConfig, managerConfig.JobConfig, task.RetentionTaskName))
+	assert.Equal(t, "30 0 * * *", jobSchedule(managerConfig, managerConfig.JobConfig, task.ComplianceRollupTaskName))
+
+}
+
+func TestNewGlobalHubSchedulerWithBackup(t *testing.T) {
+	managerConfig := &config.ManagerConfig{
+		DatabaseConfig: &config.DatabaseConfig{
+			DataRetention: 18,
+			Backup:        &config.DatabaseBackupConfig{Schedule: "0 2 * * *"},
+		},
+	}
+	scheduler, err := NewGlobalHubScheduler(context.Background(), managerConfig)
+	assert.NoError(t, err)
+	assert.Len(t, scheduler.jobs, len(JobNames))
+	assert.Equal(t, "0 2 * * *", jobSchedule(managerConfig, &config.JobConfig{}, task.DatabaseBackupTaskName))
+	assert.Equal(t, "0 0 * * *", jobSchedule(managerConfig, &config.JobConfig{}, task.LocalComplianceTaskName))
+}
+
+func TestNewGlobalHubSchedulerWithInvalidConfig(t *testing.T) {
+	cases := []struct {
+		name      string
+		jobConfig *config.JobConfig
+		err       string
+	}{
+		{
+			name:      "unknown schedule",
+			jobConfig: &config.JobConfig{Schedules: map[string]string{"unknown": "0 0 * * *"}},
+			err:       "the schedule of the job unknown isn't supported",
+		},
+		{
+			name:      "unknown disabled job",
+			jobConfig: &config.JobConfig{Disabled: []string{"unknown"}},
+			err:       "the disabled job unknown isn't supported",
+		},
+		{
+			name:      "invalid cron expression",
+			jobConfig: &config.JobConfig{Schedules: map[string]string{task.RetentionTaskName: "0 0 32 * *"}},
+			err:       "failed to schedule the job data-retention",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewGlobalHubScheduler(context.Background(), &config.ManagerConfig{
+				DatabaseConfig: &config.DatabaseConfig{DataRetention: 18},
+				JobConfig:      tc.jobConfig,
+			})
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
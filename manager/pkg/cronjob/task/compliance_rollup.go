@@ -0,0 +1,107 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+var (
+	// The compliance rollup job aggregates the compliance history of the clusters into the daily compliance counts
+	// of the policies per managed hub, and deletes the rollups exceeding the retention of the compliance history
+	ComplianceRollupTaskName = "compliance-rollup"
+	complianceRollupLog      = ctrl.Log.WithName(ComplianceRollupTaskName)
+	complianceHistoryTable   = "history.local_compliance"
+)
+
+const complianceRollupSQL = `
+	INSERT INTO history.local_compliance_rollup (
+		policy_id,
+		leaf_hub_name,
+		compliance_date,
+		compliant,
+		non_compliant,
+		pending,
+		unknown,
+		updated_at
+	)
+	(
+		SELECT
+			policy_id,
+			leaf_hub_name,
+			compliance_date,
+			COUNT(*) FILTER (WHERE compliance = 'compliant'),
+			COUNT(*) FILTER (WHERE compliance = 'non_compliant'),
+			COUNT(*) FILTER (WHERE compliance = 'pending'),
+			COUNT(*) FILTER (WHERE compliance = 'unknown'),
+			now()
+		FROM
+			history.local_compliance
+		WHERE compliance_date >= ?
+		GROUP BY policy_id, leaf_hub_name, compliance_date
+	)
+	ON CONFLICT (
+		policy_id,
+		leaf_hub_name,
+		compliance_date
+	) DO UPDATE SET
+		compliant = EXCLUDED.compliant,
+		non_compliant = EXCLUDED.non_compliant,
+		pending = EXCLUDED.pending,
+		unknown = EXCLUDED.unknown,
+		updated_at = EXCLUDED.updated_at;
+`
+
+func ComplianceRollup(ctx context.Context, databaseConfig *config.DatabaseConfig, job gocron.Job) error {
+	conn := database.GetConn()
+	if err := database.Lock(conn); err != nil {
+		complianceRollupLog.Error(err, "failed to run compliance rollup")
+		return err
+	}
+	defer database.Unlock(conn)
+
+	db := database.GetGorm().WithContext(ctx)
+	since, err := complianceRollupStartDate(ctx)
+	if err != nil {
+		complianceRollupLog.Error(err, "failed to get the latest compliance rollup")
+		return err
+	}
+	ret := db.Exec(complianceRollupSQL, since.Format(DateFormat))
+	if ret.Error != nil {
+		complianceRollupLog.Error(ret.Error, "failed to roll up the compliance history")
+		return ret.Error
+	}
+	rolledUp := ret.RowsAffected
+
+	minDate := time.Now().AddDate(0, -TableRetentionMonth(databaseConfig, complianceHistoryTable), 0)
+	ret = db.Where("compliance_date < ?", minDate.Format(DateFormat)).Delete(&models.LocalComplianceRollup{})
+	if ret.Error != nil {
+		complianceRollupLog.Error(ret.Error, "failed to delete the expired compliance rollups")
+		return ret.Error
+	}
+	complianceRollupLog.Info("finish running", "since", since.Format(DateFormat), "rolledUp", rolledUp,
+		"deleted", ret.RowsAffected, "nextRun", job.NextRun().Format(TimeFormat))
+	return nil
+}
+
+// complianceRollupStartDate returns the latest rolled up date, which is rolled up again since its compliance history
+// may be changed after that. The whole compliance history is rolled up if there isn't any rollup.
+func complianceRollupStartDate(ctx context.Context) (time.Time, error) {
+	latest := sql.NullTime{}
+	err := database.GetGorm().WithContext(ctx).Model(&models.LocalComplianceRollup{}).
+		Select("MAX(compliance_date)").Row().Scan(&latest)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !latest.Valid {
+		return time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), nil
+	}
+	return latest.Time, nil
+}
@@ -3,12 +3,15 @@ package task
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
 	"github.com/go-co-op/gocron"
+	"github.com/jackc/pgx/v4"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/backup"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/config"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/hubmanagement"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
@@ -18,8 +21,9 @@ import (
 var (
 	// The main tasks of this job are:
 	// 1. create partition tables for days in the future, the partition table for the next month is created
-	// 2. delete partition tables that are no longer needed, the partition table for the previous 18 month is deleted
-	// 3. completely delete the soft deleted records from database after retainedMonths
+	// 2. delete partition tables that are no longer needed, the partition tables before the retention months of the
+	// table are archived if the archive is enabled, and then deleted
+	// 3. completely delete the soft deleted records from database after the retention months of the table
 	RetentionTaskName = "data-retention"
 
 	// after the record is marked as deleted, retentionMonth is used to indicate how long it will be retained
@@ -40,67 +44,147 @@ var (
 		"event.local_root_policies",
 		"history.local_compliance",
 		"event.managed_clusters",
+		"event.spec_drifts",
+		"history.managed_clusters",
 	}
 	retentionLog = ctrl.Log.WithName(RetentionTaskName)
 )
 
-func DataRetention(ctx context.Context, retentionMonth int, job gocron.Job) {
+// retentionReport is the result of the data retention of a table, which is recorded in the data retention job log
+type retentionReport struct {
+	retentionMonth    int
+	deletedPartitions []string
+	archives          []string
+	archivedRows      int64
+}
+
+// ValidateDataRetention verifies the tables of the table retentions and the archive format
+func ValidateDataRetention(databaseConfig *config.DatabaseConfig) error {
+	for tableName, months := range databaseConfig.TableRetentions {
+		if !containsTable(PartitionTables, tableName) && !containsTable(RetentionTables, tableName) {
+			return fmt.Errorf("the retention of the table %s isn't supported", tableName)
+		}
+		if months < 1 {
+			return fmt.Errorf("the retention of the table %s should be at least 1 month", tableName)
+		}
+	}
+	if databaseConfig.Archive != nil && databaseConfig.Archive.Format != "" {
+		if _, err := backup.ArchiveName("", databaseConfig.Archive.Format); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TableRetentionMonth returns how many months the data of the table is kept, the data retention is used if the
+// table retention isn't specified
+func TableRetentionMonth(databaseConfig *config.DatabaseConfig, tableName string) int {
+	if months, ok := databaseConfig.TableRetentions[tableName]; ok {
+		return months
+	}
+	return databaseConfig.DataRetention
+}
+
+func DataRetention(ctx context.Context, databaseConfig *config.DatabaseConfig, job gocron.Job) error {
 	now := time.Now()
 	currentMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
 
-	var err error
 	db := database.GetGorm()
 	conn := database.GetConn()
-	err = database.Lock(conn)
-	if err != nil {
+	if err := database.Lock(conn); err != nil {
 		retentionLog.Error(err, "failed to run data retention")
-		return
+		return err
 	}
 	defer database.Unlock(conn)
 
-	defer func() {
-		if err != nil {
-			config.GlobalHubCronJobGaugeVec.WithLabelValues(RetentionTaskName).Set(1)
-		} else {
-			config.GlobalHubCronJobGaugeVec.WithLabelValues(RetentionTaskName).Set(0)
-		}
-	}()
+	archiver, err := newPartitionArchiver(ctx, databaseConfig)
+	if err != nil {
+		retentionLog.Error(err, "failed to initialize the partition archiver")
+		return err
+	}
+	defer archiver.close(ctx)
 
 	createMonth := currentMonth.AddDate(0, 1, 0)
-	deleteMonth := currentMonth.AddDate(0, -(retentionMonth + 1), 0)
 	for _, tableName := range PartitionTables {
-		err = updatePartitionTables(tableName, createMonth, deleteMonth)
-		if e := traceDataRetentionLog(tableName, currentMonth, err, true); e != nil {
+		report := &retentionReport{retentionMonth: TableRetentionMonth(databaseConfig, tableName)}
+		deleteMonth := currentMonth.AddDate(0, -(report.retentionMonth + 1), 0)
+		err = updatePartitionTables(ctx, tableName, createMonth, deleteMonth, archiver, report)
+		if e := traceDataRetentionLog(tableName, currentMonth, report, err, true); e != nil {
 			retentionLog.Error(e, "failed to trace data retention log")
 		}
 		if err != nil {
 			retentionLog.Error(err, "failed to update partition tables")
-			return
+			return err
 		}
 	}
 
 	// delete the soft deleted records from database
-	minTime := currentMonth.AddDate(0, -retentionMonth, 0)
 	for _, tableName := range RetentionTables {
-		err = deleteExpiredRecords(tableName, minTime)
-		if e := traceDataRetentionLog(tableName, currentMonth, err, false); e != nil {
+		report := &retentionReport{retentionMonth: TableRetentionMonth(databaseConfig, tableName)}
+		err = deleteExpiredRecords(tableName, currentMonth.AddDate(0, -report.retentionMonth, 0))
+		if e := traceDataRetentionLog(tableName, currentMonth, report, err, false); e != nil {
 			retentionLog.Error(e, "failed to trace data retention log")
 		}
 		if err != nil {
 			retentionLog.Error(err, "failed to delete soft deleted records")
-			return
+			return err
 		}
 	}
+	minTime := currentMonth.AddDate(0, -databaseConfig.DataRetention, 0)
 	err = db.Where("last_timestamp < ? AND status = ?", minTime, hubmanagement.HubInactive).
 		Delete(&models.LeafHubHeartbeat{}).Error
 	if err != nil {
 		retentionLog.Error(err, "failed to delete the expired leaf hub heartbeat")
-		return
+		return err
 	}
 	retentionLog.Info("finish running", "nextRun", job.NextRun().Format(TimeFormat))
+	return nil
+}
+
+// partitionArchiver exports the partitions into the archive storage before they're deleted, it's nil if the
+// archive is disabled
+type partitionArchiver struct {
+	format  string
+	storage backup.BackupStorage
+	conn    *pgx.Conn
+}
+
+func newPartitionArchiver(ctx context.Context, databaseConfig *config.DatabaseConfig) (*partitionArchiver, error) {
+	archiveConfig := databaseConfig.Archive
+	if archiveConfig == nil || archiveConfig.Format == "" {
+		return nil, nil
+	}
+	storage, err := backup.NewArchiveStorage(archiveConfig)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := os.ReadFile(databaseConfig.CACertPath) // #nosec G304
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	conn, err := database.PostgresConnection(ctx, databaseConfig.ProcessDatabaseURL, cert)
+	if err != nil {
+		return nil, err
+	}
+	return &partitionArchiver{format: archiveConfig.Format, storage: storage, conn: conn}, nil
+}
+
+func (a *partitionArchiver) archive(ctx context.Context, partition string) (string, int64, error) {
+	return backup.ArchivePartition(ctx, a.conn, a.storage, partition, a.format)
 }
 
-func updatePartitionTables(tableName string, createTime, deleteTime time.Time) error {
+func (a *partitionArchiver) close(ctx context.Context) {
+	if a == nil {
+		return
+	}
+	if err := a.conn.Close(ctx); err != nil {
+		retentionLog.Error(err, "failed to close the database connection of the archiver")
+	}
+}
+
+func updatePartitionTables(ctx context.Context, tableName string, createTime, deleteTime time.Time,
+	archiver *partitionArchiver, report *retentionReport,
+) error {
 	db := database.GetGorm()
 
 	// create the partition tables for the next month
@@ -116,16 +200,47 @@ func updatePartitionTables(tableName string, createTime, deleteTime time.Time) e
 	retentionLog.Info("create partition table", "table", createPartitionTableName, "start", startTime.Format(DateFormat),
 		"end", endTime.Format(DateFormat))
 
-	// delete the partition tables that are expired
-	deletePartitionTableName := fmt.Sprintf("%s_%s", tableName, deleteTime.Format(PartitionDateFormat))
-	deletionSql := fmt.Sprintf("DROP TABLE IF EXISTS %s", deletePartitionTableName)
-	if result := db.Exec(deletionSql); result.Error != nil {
-		return fmt.Errorf("failed to delete partition table %s: %w", tableName, result.Error)
+	// delete the partition tables that are expired, the partitions before the deleteTime are deleted as well in case
+	// the retention of the table is reduced
+	partitions, err := getPartitions(tableName)
+	if err != nil {
+		return err
+	}
+	for _, partition := range expiredPartitions(tableName, partitions, deleteTime) {
+		if archiver != nil {
+			name, rows, err := archiver.archive(ctx, partition)
+			if err != nil {
+				return fmt.Errorf("failed to archive partition table %s: %w", partition, err)
+			}
+			report.archives = append(report.archives, name)
+			report.archivedRows += rows
+		}
+		deletionSql := fmt.Sprintf("DROP TABLE IF EXISTS %s", partition)
+		if result := db.Exec(deletionSql); result.Error != nil {
+			return fmt.Errorf("failed to delete partition table %s: %w", partition, result.Error)
+		}
+		report.deletedPartitions = append(report.deletedPartitions, partition)
+		retentionLog.Info("delete partition table", "table", partition)
 	}
-	retentionLog.Info("delete partition table", "table", deletePartitionTableName)
 	return nil
 }
 
+// expiredPartitions returns the monthly partitions of the table, e.g. "event.local_policies_2024_01", which aren't
+// after the deleteTime
+func expiredPartitions(tableName string, partitions []string, deleteTime time.Time) []string {
+	expired := []string{}
+	for _, partition := range partitions {
+		month, err := time.Parse(PartitionDateFormat, strings.TrimPrefix(partition, tableName+"_"))
+		if err != nil {
+			continue
+		}
+		if !month.After(time.Date(deleteTime.Year(), deleteTime.Month(), 1, 0, 0, 0, 0, time.UTC)) {
+			expired = append(expired, partition)
+		}
+	}
+	return expired
+}
+
 func deleteExpiredRecords(tableName string, minDate time.Time) error {
 	sql := fmt.Sprintf("DELETE FROM %s WHERE deleted_at < '%s'", tableName, minDate.Format(DateFormat))
 	db := database.GetGorm()
@@ -137,25 +252,35 @@ func deleteExpiredRecords(tableName string, minDate time.Time) error {
 	return nil
 }
 
-func traceDataRetentionLog(tableName string, startTime time.Time, err error, partition bool) error {
+func traceDataRetentionLog(tableName string, startTime time.Time, report *retentionReport, err error,
+	partition bool,
+) error {
 	db := database.GetGorm()
 	dataRetentionLog := &models.DataRetentionJobLog{
-		Name:    tableName,
-		StartAt: startTime,
-		EndAt:   time.Now(),
-		Error:   "none",
+		Name:              tableName,
+		StartAt:           startTime,
+		EndAt:             time.Now(),
+		Error:             "none",
+		RetentionMonth:    report.retentionMonth,
+		DeletedPartitions: strings.Join(report.deletedPartitions, ","),
+		Archives:          strings.Join(report.archives, ","),
+		ArchivedRows:      report.archivedRows,
 	}
 	if err != nil {
 		dataRetentionLog.Error = err.Error()
 	}
 
 	if partition {
-		minPartition, maxPartition, err := getMinMaxPartitions(tableName)
+		partitions, err := getPartitions(tableName)
 		if err != nil {
 			return err
 		}
-		dataRetentionLog.MinPartition = minPartition
-		dataRetentionLog.MaxPartition = maxPartition
+		if len(partitions) < 1 {
+			retentionLog.Info("no partition table found", "table", tableName)
+		} else {
+			dataRetentionLog.MinPartition = strings.TrimPrefix(partitions[0], getSchema(tableName)+".")
+			dataRetentionLog.MaxPartition = strings.TrimPrefix(partitions[len(partitions)-1], getSchema(tableName)+".")
+		}
 	} else {
 		if minDeletionTime, err := getMinDeletionTime(tableName); err == nil && !minDeletionTime.IsZero() {
 			dataRetentionLog.MinDeletion = minDeletionTime
@@ -164,12 +289,14 @@ func traceDataRetentionLog(tableName string, startTime time.Time, err error, par
 	return db.Create(dataRetentionLog).Error
 }
 
-func getMinMaxPartitions(tableName string) (string, string, error) {
+// getPartitions returns the partitions of the table with the schema in ascending order,
+// e.g. "event.local_policies_2024_01"
+func getPartitions(tableName string) ([]string, error) {
 	db := database.GetGorm()
 
 	schemaTable := strings.Split(tableName, ".")
 	if len(schemaTable) != 2 {
-		return "", "", fmt.Errorf("invalid table name: %s", tableName)
+		return nil, fmt.Errorf("invalid table name: %s", tableName)
 	}
 	sql := fmt.Sprintf(`
 		SELECT
@@ -191,13 +318,26 @@ func getMinMaxPartitions(tableName string) (string, string, error) {
 	var tables []models.Table
 	result := db.Raw(sql).Find(&tables)
 	if result.Error != nil {
-		return "", "", fmt.Errorf("failed to get min/max partition table: %w", result.Error)
+		return nil, fmt.Errorf("failed to get the partition tables: %w", result.Error)
+	}
+	partitions := make([]string, 0, len(tables))
+	for _, table := range tables {
+		partitions = append(partitions, fmt.Sprintf("%s.%s", table.Schema, table.Table))
 	}
-	if len(tables) < 1 {
-		retentionLog.Info("no partition table found", "table", tableName)
-		return "", "", nil
+	return partitions, nil
+}
+
+func getSchema(tableName string) string {
+	return strings.SplitN(tableName, ".", 2)[0]
+}
+
+func containsTable(tables []string, tableName string) bool {
+	for _, table := range tables {
+		if table == tableName {
+			return true
+		}
 	}
-	return tables[0].Table, tables[len(tables)-1].Table, nil
+	return false
 }
 
 func getMinDeletionTime(tableName string) (time.Time, error) {
@@ -0,0 +1,55 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/config"
+)
+
+func TestTableRetention(t *testing.T) {
+	databaseConfig := &config.DatabaseConfig{
+		DataRetention: 18,
+		TableRetentions: map[string]int{
+			"history.local_compliance": 36,
+			"event.managed_clusters":   3,
+		},
+		Archive: &config.DatabaseArchiveConfig{Format: "parquet"},
+	}
+	assert.NoError(t, ValidateDataRetention(databaseConfig))
+	assert.Equal(t, 36, TableRetentionMonth(databaseConfig, "history.local_compliance"))
+	assert.Equal(t, 3, TableRetentionMonth(databaseConfig, "event.managed_clusters"))
+	assert.Equal(t, 18, TableRetentionMonth(databaseConfig, "event.local_policies"))
+
+	databaseConfig.TableRetentions["status.transport"] = 1
+	assert.ErrorContains(t, ValidateDataRetention(databaseConfig), "status.transport")
+
+	delete(databaseConfig.TableRetentions, "status.transport")
+	databaseConfig.TableRetentions["event.local_policies"] = 0
+	assert.ErrorContains(t, ValidateDataRetention(databaseConfig), "at least 1 month")
+
+	delete(databaseConfig.TableRetentions, "event.local_policies")
+	databaseConfig.Archive.Format = "csv"
+	assert.ErrorContains(t, ValidateDataRetention(databaseConfig), "unsupported archive format")
+}
+
+func TestExpiredPartitions(t *testing.T) {
+	partitions := []string{
+		"event.managed_clusters_2023_12",
+		"event.managed_clusters_2024_01",
+		"event.managed_clusters_2024_02",
+		"event.managed_clusters_2024_03",
+		"event.managed_clusters_default",
+	}
+	deleteTime := time.Date(2024, 2, 1, 0, 0, 0, 0, time.Local)
+	assert.Equal(t, []string{
+		"event.managed_clusters_2023_12",
+		"event.managed_clusters_2024_01",
+		"event.managed_clusters_2024_02",
+	}, expiredPartitions("event.managed_clusters", partitions, deleteTime))
+
+	assert.Empty(t, expiredPartitions("event.managed_clusters", partitions,
+		time.Date(2023, 11, 1, 0, 0, 0, 0, time.Local)))
+}
@@ -0,0 +1,56 @@
+package task
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/backup"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+var (
+	// The database backup job exports the tables into an archive in the backup storage with a consistent snapshot,
+	// and then prunes the backups exceeding the retention
+	DatabaseBackupTaskName = "database-backup"
+	databaseBackupLog      = ctrl.Log.WithName(DatabaseBackupTaskName)
+)
+
+func DatabaseBackup(ctx context.Context, databaseConfig *config.DatabaseConfig, job gocron.Job) (err error) {
+	defer func() {
+		if err != nil {
+			databaseBackupLog.Error(err, "failed to backup the database")
+		}
+	}()
+
+	storage, err := backup.NewBackupStorage(databaseConfig.Backup)
+	if err != nil {
+		return err
+	}
+	cert, err := os.ReadFile(databaseConfig.CACertPath) // #nosec G304
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	conn, err := database.PostgresConnection(ctx, databaseConfig.ProcessDatabaseURL, cert)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if e := conn.Close(ctx); e != nil {
+			databaseBackupLog.Error(e, "failed to close the database connection")
+		}
+	}()
+
+	manifest, err := backup.BackupDatabase(ctx, conn, storage, backup.NewBackupName(time.Now()))
+	if err != nil {
+		return err
+	}
+	databaseBackupLog.Info("the database is backed up", "name", manifest.Name, "tables", len(manifest.Tables),
+		"nextRun", job.NextRun())
+
+	return backup.PruneBackups(ctx, storage, databaseConfig.Backup.Retention)
+}
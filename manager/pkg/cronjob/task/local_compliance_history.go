@@ -10,7 +10,6 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	ctrl "sigs.k8s.io/controller-runtime"
 
-	"github.com/stolostron/multicluster-global-hub/manager/pkg/config"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
 )
@@ -31,27 +30,18 @@ var (
 	// sizes and measure the performance of the queries.
 )
 
-func LocalComplianceHistory(ctx context.Context, job gocron.Job) {
+func LocalComplianceHistory(ctx context.Context, job gocron.Job) error {
 	startTime = time.Now()
 	log = ctrl.Log.WithName(LocalComplianceTaskName).WithValues("date", startTime.Format(DateFormat))
 	log.V(2).Info("start running", "currentRun", job.LastRun().Format(TimeFormat))
 
-	var err error
-	defer func() {
-		if err != nil {
-			config.GlobalHubCronJobGaugeVec.WithLabelValues(LocalComplianceTaskName).Set(1)
-		} else {
-			config.GlobalHubCronJobGaugeVec.WithLabelValues(LocalComplianceTaskName).Set(0)
-		}
-	}()
-
-	err = snapshotLocalComplianceToHistory(ctx)
-	if err != nil {
+	if err := snapshotLocalComplianceToHistory(ctx); err != nil {
 		log.Error(err, "sync from local_status.compliance to history.local_compliance failed")
-		return
+		return err
 	}
 
 	log.V(2).Info("finish running", "nextRun", job.NextRun().Format(TimeFormat))
+	return nil
 }
 
 func snapshotLocalComplianceToHistory(ctx context.Context) (err error) {
@@ -0,0 +1,70 @@
+package task
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-co-op/gocron"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+var (
+	// The orphaned spec detection job counts the spec rows referencing the managed hubs or clusters which don't exist
+	// anymore. The rows aren't deleted, the counts are exported by the metric to be reviewed.
+	OrphanedSpecTaskName = "orphaned-spec-detection"
+	orphanedSpecLog      = ctrl.Log.WithName(OrphanedSpecTaskName)
+
+	orphanedSpecQueries = []orphanedSpecQuery{
+		{
+			table: "local_spec.policies",
+			query: `SELECT COUNT(*) FROM local_spec.policies p WHERE p.deleted_at IS NULL AND NOT EXISTS (
+				SELECT 1 FROM status.leaf_hubs h WHERE h.leaf_hub_name = p.leaf_hub_name AND h.deleted_at IS NULL)`,
+		},
+		{
+			table: "spec.managed_clusters_labels",
+			query: `SELECT COUNT(*) FROM spec.managed_clusters_labels l WHERE NOT EXISTS (
+				SELECT 1 FROM status.managed_clusters c WHERE c.leaf_hub_name = l.leaf_hub_name
+				AND c.cluster_name = l.managed_cluster_name AND c.deleted_at IS NULL)`,
+		},
+		{
+			table: "spec.managed_cluster_sets_tracking",
+			query: `SELECT COUNT(*) FROM spec.managed_cluster_sets_tracking t WHERE NOT EXISTS (
+				SELECT 1 FROM status.leaf_hubs h WHERE h.leaf_hub_name = t.leaf_hub_name AND h.deleted_at IS NULL)`,
+		},
+	}
+)
+
+// orphanedSpecQuery counts the orphaned rows of the spec table
+type orphanedSpecQuery struct {
+	table string
+	query string
+}
+
+func OrphanedSpecDetection(ctx context.Context, job gocron.Job) error {
+	db := database.GetGorm().WithContext(ctx)
+	total := int64(0)
+	for _, orphaned := range orphanedSpecQueries {
+		// the spec tables of the global resources only exist when the global resource is enabled
+		exists := false
+		if err := db.Raw("SELECT to_regclass(?) IS NOT NULL", orphaned.table).Row().Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check the table %s: %w", orphaned.table, err)
+		}
+		if !exists {
+			continue
+		}
+		count := int64(0)
+		if err := db.Raw(orphaned.query).Row().Scan(&count); err != nil {
+			return fmt.Errorf("failed to count the orphaned rows of the table %s: %w", orphaned.table, err)
+		}
+		config.GlobalHubOrphanedSpecRowsGaugeVec.WithLabelValues(orphaned.table).Set(float64(count))
+		if count > 0 {
+			orphanedSpecLog.Info("found the orphaned spec rows", "table", orphaned.table, "count", count)
+		}
+		total += count
+	}
+	orphanedSpecLog.Info("finish running", "orphaned", total, "nextRun", job.NextRun().Format(TimeFormat))
+	return nil
+}
@@ -0,0 +1,49 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/hubmanagement"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+var (
+	// The stale heartbeat cleanup job deletes the heartbeats of the managed hubs which have been inactive longer than
+	// the threshold and whose hub info is removed. The heartbeat is created again if the managed hub comes back.
+	StaleHeartbeatTaskName = "stale-heartbeat-cleanup"
+	staleHeartbeatLog      = ctrl.Log.WithName(StaleHeartbeatTaskName)
+	// the heartbeat of the removed managed hub is kept for 30 days after it's inactive by default
+	DefaultStaleHeartbeatThreshold = 30 * 24 * time.Hour
+)
+
+func StaleHeartbeatCleanup(ctx context.Context, threshold time.Duration, job gocron.Job) error {
+	if threshold <= 0 {
+		return fmt.Errorf("the stale heartbeat threshold %s should be positive", threshold)
+	}
+	conn := database.GetConn()
+	if err := database.Lock(conn); err != nil {
+		staleHeartbeatLog.Error(err, "failed to run stale heartbeat cleanup")
+		return err
+	}
+	defer database.Unlock(conn)
+
+	staleTime := time.Now().Add(-threshold)
+	ret := database.GetGorm().WithContext(ctx).
+		Where("status = ? AND last_timestamp < ?", hubmanagement.HubInactive, staleTime).
+		Where(`NOT EXISTS (SELECT 1 FROM status.leaf_hubs WHERE leaf_hubs.leaf_hub_name =
+			leaf_hub_heartbeats.leaf_hub_name AND leaf_hubs.deleted_at IS NULL)`).
+		Delete(&models.LeafHubHeartbeat{})
+	if ret.Error != nil {
+		staleHeartbeatLog.Error(ret.Error, "failed to delete the stale heartbeats")
+		return ret.Error
+	}
+	staleHeartbeatLog.Info("finish running", "before", staleTime.Format(TimeFormat), "deleted", ret.RowsAffected,
+		"nextRun", job.NextRun().Format(TimeFormat))
+	return nil
+}
@@ -0,0 +1,33 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+var (
+	// The vacuum analyze job reclaims the storage of the partitioned tables and refreshes their statistics for the
+	// planner, the partitions of the table are processed together with it
+	VacuumAnalyzeTaskName = "vacuum-analyze"
+	vacuumAnalyzeLog      = ctrl.Log.WithName(VacuumAnalyzeTaskName)
+)
+
+func VacuumAnalyze(ctx context.Context, job gocron.Job) error {
+	db := database.GetGorm().WithContext(ctx)
+	for _, tableName := range PartitionTables {
+		start := time.Now()
+		// the VACUUM can't run inside a transaction block
+		if err := db.Exec(fmt.Sprintf("VACUUM (ANALYZE) %s", tableName)).Error; err != nil {
+			return fmt.Errorf("failed to vacuum the table %s: %w", tableName, err)
+		}
+		vacuumAnalyzeLog.Info("vacuum the table", "table", tableName, "duration", time.Since(start))
+	}
+	vacuumAnalyzeLog.Info("finish running", "nextRun", job.NextRun().Format(TimeFormat))
+	return nil
+}
@@ -190,6 +190,11 @@ func (h *HubManagement) reactive(ctx context.Context, hubs []models.LeafHubHeart
 }
 
 func (h *HubManagement) resync(ctx context.Context, hubName string) error {
+	return Resync(ctx, h.producer, hubName)
+}
+
+// Resync requests the hub to resync its resources to the global hub, the transport.Broadcast requests all the hubs
+func Resync(ctx context.Context, producer transport.Producer, hubName string) error {
 	resyncResources := []string{
 		string(enum.HubClusterInfoType),
 		string(enum.ManagedClusterType),
@@ -206,5 +211,5 @@ func (h *HubManagement) resync(ctx context.Context, hubName string) error {
 	e.SetSource(hubName)
 	_ = e.SetData(cloudevents.ApplicationJSON, payloadBytes)
 
-	return h.producer.SendEvent(ctx, e)
+	return producer.SendEvent(ctx, e)
 }
@@ -0,0 +1,41 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package compliance
+
+const (
+	ServerInternalErrorMsg          = "internal error"
+	QueryComplianceFailureFormatMsg = "error in querying compliance summary: %v\n"
+	QueryHistoryFailureFormatMsg    = "error in querying compliance history: %v\n"
+)
+
+const (
+	groupByHub        = "hub"
+	groupByClusterSet = "clusterset"
+	groupByStandard   = "standard"
+	groupByCategory   = "category"
+	groupByControl    = "control"
+	groupByLabel      = "label"
+
+	intervalDay  = "day"
+	intervalWeek = "week"
+
+	clusterSetLabel    = "cluster.open-cluster-management.io/clusterset"
+	dateFormat         = "2006-01-02"
+	defaultHistoryDays = 30
+	maxHistoryDays     = 366
+)
+
+const complianceCountColumns = `
+	COUNT(*) FILTER (WHERE c.compliance = 'compliant') AS compliant,
+	COUNT(*) FILTER (WHERE c.compliance = 'non_compliant') AS non_compliant,
+	COUNT(*) FILTER (WHERE c.compliance = 'pending') AS pending,
+	COUNT(*) FILTER (WHERE c.compliance = 'unknown') AS unknown`
+
+// the policy annotations are comma-separated, so the policy is counted in each of the values, and the policy
+// without the annotation is counted in ""
+var policyAnnotationColumns = map[string]string{
+	groupByStandard: "p.policy_standard",
+	groupByCategory: "p.policy_category",
+	groupByControl:  "p.policy_control",
+}
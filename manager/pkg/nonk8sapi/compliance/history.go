@@ -0,0 +1,143 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package compliance
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+// HistoryBucket is the compliance summaries of a day or a week, the compliance of a (policy, cluster) pair in the
+// bucket is its latest daily snapshot in the bucket
+type HistoryBucket struct {
+	Date  string    `json:"date"`
+	Items []Summary `json:"items"`
+}
+
+type HistoryList struct {
+	GroupBy  string          `json:"groupBy"`
+	Interval string          `json:"interval"`
+	Start    string          `json:"start"`
+	End      string          `json:"end"`
+	Buckets  []HistoryBucket `json:"buckets"`
+}
+
+type historyRow struct {
+	Bucket time.Time
+	summaryRow
+}
+
+// historyRange parses the date range, which is the last 30 days by default
+func historyRange(ginCtx *gin.Context, now time.Time) (time.Time, time.Time, error) {
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if value := ginCtx.Query("end"); value != "" {
+		var err error
+		if end, err = time.Parse(dateFormat, value); err != nil {
+			return end, end, fmt.Errorf("invalid end date %s, it should be in the format %s", value, dateFormat)
+		}
+	}
+	start := end.AddDate(0, 0, -defaultHistoryDays)
+	if value := ginCtx.Query("start"); value != "" {
+		var err error
+		if start, err = time.Parse(dateFormat, value); err != nil {
+			return start, end, fmt.Errorf("invalid start date %s, it should be in the format %s", value, dateFormat)
+		}
+	}
+	if start.After(end) {
+		return start, end, fmt.Errorf("the start date %s is after the end date %s", start.Format(dateFormat),
+			end.Format(dateFormat))
+	}
+	if end.Sub(start) > maxHistoryDays*24*time.Hour {
+		return start, end, fmt.Errorf("the date range can't exceed %d days", maxHistoryDays)
+	}
+	return start, end, nil
+}
+
+// GetComplianceHistory godoc
+// @summary get compliance history
+// @description get the daily or weekly compliance history of the local policies aggregated by hub, cluster set, policy standard/category/control or cluster label
+// @accept json
+// @produce json
+// @param        groupBy     query     string  false  "hub (default), clusterset, standard, category, control or label"
+// @param        labelKey    query     string  false  "the cluster label key to group by, required if groupBy is label"
+// @param        hub         query     string  false  "only aggregate the compliance of the managed hub"
+// @param        start       query     string  false  "the start date in the format 2006-01-02, the default is 30 days before the end date"
+// @param        end         query     string  false  "the end date in the format 2006-01-02, the default is today"
+// @param        interval    query     string  false  "day (default) or week, the weekly bucket starts on Monday"
+// @success      200  {object}  compliance.HistoryList
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /compliance/history [get]
+func GetComplianceHistory() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		keyExpr, lateral, args, err := groupBy(ginCtx)
+		if err != nil {
+			ginCtx.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		start, end, err := historyRange(ginCtx, time.Now())
+		if err != nil {
+			ginCtx.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		interval := ginCtx.DefaultQuery("interval", intervalDay)
+		if interval != intervalDay && interval != intervalWeek {
+			ginCtx.String(http.StatusBadRequest, fmt.Sprintf("unsupported interval %s, it should be %s or %s",
+				interval, intervalDay, intervalWeek))
+			return
+		}
+		hub := ginCtx.Query("hub")
+
+		// the deleted policies are kept in the history
+		query := fmt.Sprintf(`SELECT c.bucket, %s AS key, %s
+			FROM (
+				SELECT DISTINCT ON (h.policy_id, h.cluster_id, h.leaf_hub_name, bucket)
+					date_trunc('%s', h.compliance_date)::date AS bucket, h.policy_id, h.cluster_id,
+					h.leaf_hub_name, h.compliance
+				FROM history.local_compliance h
+				WHERE h.compliance_date BETWEEN ? AND ? AND (? = '' OR h.leaf_hub_name = ?)
+				ORDER BY h.policy_id, h.cluster_id, h.leaf_hub_name, bucket, h.compliance_date DESC
+			) c
+			JOIN local_spec.policies p ON p.policy_id = c.policy_id
+			LEFT JOIN status.managed_clusters mc ON mc.cluster_id = c.cluster_id
+			%s
+			GROUP BY 1, 2 ORDER BY 1, 2`, keyExpr, complianceCountColumns, interval, lateral)
+		// the arguments of the key expression in the select list come before the ones of the subquery
+		args = append(args, start, end, hub, hub)
+		fmt.Fprintf(gin.DefaultWriter, "compliance history query: %v\n", query)
+
+		rows := []historyRow{}
+		if err := database.GetGorm().Raw(query, args...).Scan(&rows).Error; err != nil {
+			ginCtx.String(http.StatusInternalServerError, ServerInternalErrorMsg)
+			fmt.Fprintf(gin.DefaultWriter, QueryHistoryFailureFormatMsg, err)
+			return
+		}
+
+		historyList := HistoryList{
+			GroupBy:  ginCtx.DefaultQuery("groupBy", groupByHub),
+			Interval: interval,
+			Start:    start.Format(dateFormat),
+			End:      end.Format(dateFormat),
+			Buckets:  []HistoryBucket{},
+		}
+		for _, row := range rows {
+			date := row.Bucket.Format(dateFormat)
+			if len(historyList.Buckets) == 0 || historyList.Buckets[len(historyList.Buckets)-1].Date != date {
+				historyList.Buckets = append(historyList.Buckets, HistoryBucket{Date: date, Items: []Summary{}})
+			}
+			bucket := &historyList.Buckets[len(historyList.Buckets)-1]
+			bucket.Items = append(bucket.Items, row.summary())
+		}
+		ginCtx.JSON(http.StatusOK, historyList)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package compliance
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+// Summary is the compliance of the (policy, cluster) pairs in a group
+type Summary struct {
+	Key            string  `json:"key"`
+	Compliant      int64   `json:"compliant"`
+	NonCompliant   int64   `json:"nonCompliant"`
+	Pending        int64   `json:"pending"`
+	Unknown        int64   `json:"unknown"`
+	Total          int64   `json:"total"`
+	ComplianceRate float64 `json:"complianceRate"`
+}
+
+type SummaryList struct {
+	GroupBy string    `json:"groupBy"`
+	Items   []Summary `json:"items"`
+}
+
+type summaryRow struct {
+	Key          string
+	Compliant    int64
+	NonCompliant int64
+	Pending      int64
+	Unknown      int64
+}
+
+func (r summaryRow) summary() Summary {
+	s := Summary{
+		Key:          r.Key,
+		Compliant:    r.Compliant,
+		NonCompliant: r.NonCompliant,
+		Pending:      r.Pending,
+		Unknown:      r.Unknown,
+		Total:        r.Compliant + r.NonCompliant + r.Pending + r.Unknown,
+	}
+	if s.Total > 0 {
+		s.ComplianceRate = float64(s.Compliant) / float64(s.Total)
+	}
+	return s
+}
+
+// groupBy builds the group key of the compliance row "c", which is joined with the policy "p" and the managed
+// cluster "mc". It returns the key expression, the lateral join for the policy annotations and the arguments.
+func groupBy(ginCtx *gin.Context) (string, string, []interface{}, error) {
+	switch group := ginCtx.DefaultQuery("groupBy", groupByHub); group {
+	case groupByHub:
+		return "c.leaf_hub_name", "", nil, nil
+	case groupByClusterSet:
+		return "COALESCE(mc.payload -> 'metadata' -> 'labels' ->> ?, '')", "", []interface{}{clusterSetLabel}, nil
+	case groupByLabel:
+		labelKey := ginCtx.Query("labelKey")
+		if labelKey == "" {
+			return "", "", nil, fmt.Errorf("the labelKey is required to group by label")
+		}
+		return "COALESCE(mc.payload -> 'metadata' -> 'labels' ->> ?, '')", "", []interface{}{labelKey}, nil
+	case groupByStandard, groupByCategory, groupByControl:
+		lateral := fmt.Sprintf(`CROSS JOIN LATERAL unnest(COALESCE(NULLIF(string_to_array(%s, ','), '{}'),
+			ARRAY[''])) AS g(value)`, policyAnnotationColumns[group])
+		return "TRIM(g.value)", lateral, nil, nil
+	default:
+		return "", "", nil, fmt.Errorf("unsupported groupBy %s, it should be one of %s, %s, %s, %s, %s, %s", group,
+			groupByHub, groupByClusterSet, groupByStandard, groupByCategory, groupByControl, groupByLabel)
+	}
+}
+
+// GetComplianceSummary godoc
+// @summary get compliance summary
+// @description get the compliance of the local policies aggregated by hub, cluster set, policy standard/category/control or cluster label
+// @accept json
+// @produce json
+// @param        groupBy     query     string  false  "hub (default), clusterset, standard, category, control or label"
+// @param        labelKey    query     string  false  "the cluster label key to group by, required if groupBy is label"
+// @param        hub         query     string  false  "only aggregate the compliance of the managed hub"
+// @success      200  {object}  compliance.SummaryList
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /compliance/summary [get]
+func GetComplianceSummary() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		keyExpr, lateral, args, err := groupBy(ginCtx)
+		if err != nil {
+			ginCtx.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		hub := ginCtx.Query("hub")
+
+		query := fmt.Sprintf(`SELECT %s AS key, %s
+			FROM local_status.compliance c
+			JOIN local_spec.policies p ON p.policy_id = c.policy_id AND p.deleted_at IS NULL
+			LEFT JOIN status.managed_clusters mc ON mc.leaf_hub_name = c.leaf_hub_name
+				AND mc.cluster_name = c.cluster_name AND mc.deleted_at IS NULL
+			%s
+			WHERE (? = '' OR c.leaf_hub_name = ?)
+			GROUP BY 1 ORDER BY 1`, keyExpr, complianceCountColumns, lateral)
+		args = append(args, hub, hub)
+		fmt.Fprintf(gin.DefaultWriter, "compliance summary query: %v\n", query)
+
+		rows := []summaryRow{}
+		if err := database.GetGorm().Raw(query, args...).Scan(&rows).Error; err != nil {
+			ginCtx.String(http.StatusInternalServerError, ServerInternalErrorMsg)
+			fmt.Fprintf(gin.DefaultWriter, QueryComplianceFailureFormatMsg, err)
+			return
+		}
+
+		summaryList := SummaryList{GroupBy: ginCtx.DefaultQuery("groupBy", groupByHub), Items: []Summary{}}
+		for _, row := range rows {
+			summaryList.Items = append(summaryList.Items, row.summary())
+		}
+		ginCtx.JSON(http.StatusOK, summaryList)
+	}
+}
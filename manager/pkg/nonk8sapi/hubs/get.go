@@ -0,0 +1,57 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package hubs
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/util"
+)
+
+// GetHub godoc
+// @summary get managed hub
+// @description get the managed hub with the hub info, heartbeat, managed cluster counts, alert counts and the sync
+// @description health of the status events
+// @accept json
+// @produce json
+// @param        name    path    string    true    "Managed hub name"
+// @success      200  {object}  hubs.Hub
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /hub/{name} [get]
+func GetHub() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		name := ginCtx.Param("name")
+		hubs, err := queryHubs(" AND h.leaf_hub_name = ?", name)
+		if err != nil {
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			fmt.Fprintf(gin.DefaultWriter, "error in querying managed hub %s: %v\n", name, err)
+			return
+		}
+		if len(hubs) == 0 {
+			ginCtx.String(http.StatusNotFound, "managed hub %s not found", name)
+			return
+		}
+
+		if util.ShouldReturnAsTable(ginCtx) {
+			table, err := hubsTable(hubs[:1], "")
+			if err != nil {
+				ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+				fmt.Fprintf(gin.DefaultWriter, "error in converting to table: %v\n", err)
+				return
+			}
+			ginCtx.JSON(http.StatusOK, table)
+			return
+		}
+
+		ginCtx.JSON(http.StatusOK, hubs[0])
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package hubs
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+const (
+	serverInternalErrorMsg = "internal error"
+	// hubStatusUnknown is the status of the hub which hasn't sent the heartbeat yet
+	hubStatusUnknown = "unknown"
+)
+
+// hubQuery selects the managed hubs from the inventory together with the heartbeat, the managed cluster counts and
+// the alert counts of all the sources
+const hubQuery = `SELECT h.leaf_hub_name, h.cluster_id, h.payload, hb.status, hb.last_timestamp,
+	(SELECT count(*) FROM status.managed_clusters mc
+		WHERE mc.leaf_hub_name = h.leaf_hub_name AND mc.deleted_at IS NULL) AS managed_clusters,
+	(SELECT count(*) FROM status.managed_clusters mc
+		WHERE mc.leaf_hub_name = h.leaf_hub_name AND mc.deleted_at IS NULL AND mc.payload -> 'status' -> 'conditions'
+		@> '[{"type": "ManagedClusterConditionAvailable", "status": "True"}]') AS available_clusters,
+	a.low, a.medium, a.high, a.critical
+FROM status.leaf_hubs h
+LEFT JOIN status.leaf_hub_heartbeats hb ON hb.leaf_hub_name = h.leaf_hub_name
+LEFT JOIN (SELECT hub_name, sum(low) AS low, sum(medium) AS medium, sum(high) AS high, sum(critical) AS critical
+	FROM security.alert_counts GROUP BY hub_name) a ON a.hub_name = h.leaf_hub_name
+WHERE h.deleted_at IS NULL`
+
+type ManagedClusterCounts struct {
+	Total     int64 `json:"total"`
+	Available int64 `json:"available"`
+}
+
+type AlertCounts struct {
+	Low      int64 `json:"low"`
+	Medium   int64 `json:"medium"`
+	High     int64 `json:"high"`
+	Critical int64 `json:"critical"`
+}
+
+// SyncHealth is the sync health of an event type from the managed hub, the counters are reset when the manager
+// restarts
+type SyncHealth struct {
+	Type                 string     `json:"type"`
+	LastReceivedAt       *time.Time `json:"lastReceivedAt,omitempty"`
+	LastProcessedAt      *time.Time `json:"lastProcessedAt,omitempty"`
+	LastProcessedVersion string     `json:"lastProcessedVersion,omitempty"`
+	ProcessingLatencyMs  int64      `json:"processingLatencyMs"`
+	Received             int64      `json:"received"`
+	Processed            int64      `json:"processed"`
+	Failures             int64      `json:"failures"`
+	LastError            string     `json:"lastError,omitempty"`
+}
+
+type Hub struct {
+	Name             string               `json:"name"`
+	ClusterID        string               `json:"clusterId"`
+	ConsoleURL       string               `json:"consoleURL,omitempty"`
+	GrafanaURL       string               `json:"grafanaURL,omitempty"`
+	AgentVersion     string               `json:"agentVersion,omitempty"`
+	OpenShiftVersion string               `json:"openshiftVersion,omitempty"`
+	ACMVersion       string               `json:"acmVersion,omitempty"`
+	MCEVersion       string               `json:"mceVersion,omitempty"`
+	Addons           []string             `json:"addons,omitempty"`
+	Capacity         *cluster.HubCapacity `json:"capacity,omitempty"`
+	Status           string               `json:"status"`
+	LastHeartbeat    *time.Time           `json:"lastHeartbeat,omitempty"`
+	ManagedClusters  ManagedClusterCounts `json:"managedClusters"`
+	AlertCounts      *AlertCounts         `json:"alertCounts,omitempty"`
+	Topic            string               `json:"topic,omitempty"`
+	ConsumerLag      int64                `json:"consumerLag"`
+	SyncHealth       []SyncHealth         `json:"syncHealth"`
+}
+
+type HubList struct {
+	Items    []Hub  `json:"items"`
+	Continue string `json:"continue,omitempty"`
+}
+
+// queryHubs runs the hub query with the additional condition and fills the sync health of the returned hubs
+func queryHubs(condition string, args ...interface{}) ([]Hub, error) {
+	db := database.GetGorm()
+	rows, err := db.Raw(hubQuery+condition, args...).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hubs := []Hub{}
+	for rows.Next() {
+		hub := Hub{SyncHealth: []SyncHealth{}}
+		var payload []byte
+		var status sql.NullString
+		var heartbeat sql.NullTime
+		var low, medium, high, critical sql.NullInt64
+		if err := rows.Scan(&hub.Name, &hub.ClusterID, &payload, &status, &heartbeat,
+			&hub.ManagedClusters.Total, &hub.ManagedClusters.Available, &low, &medium, &high, &critical); err != nil {
+			return nil, err
+		}
+		// the payload is the hub cluster info reported by the agent
+		info := cluster.HubClusterInfo{}
+		if err := json.Unmarshal(payload, &info); err != nil {
+			return nil, err
+		}
+		hub.ConsoleURL, hub.GrafanaURL, hub.AgentVersion = info.ConsoleURL, info.GrafanaURL, info.AgentVersion
+		hub.OpenShiftVersion, hub.ACMVersion, hub.MCEVersion = info.OpenShiftVersion, info.ACMVersion, info.MCEVersion
+		hub.Addons, hub.Capacity = info.Addons, info.Capacity
+
+		hub.Status = hubStatusUnknown
+		if status.Valid {
+			hub.Status = status.String
+		}
+		if heartbeat.Valid {
+			hub.LastHeartbeat = &heartbeat.Time
+		}
+		if low.Valid {
+			hub.AlertCounts = &AlertCounts{
+				Low: low.Int64, Medium: medium.Int64, High: high.Int64, Critical: critical.Int64,
+			}
+		}
+		hubs = append(hubs, hub)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(hubs) == 0 {
+		return hubs, nil
+	}
+	return hubs, fillSyncHealth(hubs)
+}
+
+func fillSyncHealth(hubs []Hub) error {
+	hubNames := []string{}
+	hubIndexes := map[string][]int{}
+	for i, hub := range hubs {
+		hubNames = append(hubNames, hub.Name)
+		hubIndexes[hub.Name] = append(hubIndexes[hub.Name], i)
+	}
+
+	syncHealthRows := []models.SyncHealth{}
+	err := database.GetGorm().Where("leaf_hub_name IN ?", hubNames).Order("leaf_hub_name, event_type").
+		Find(&syncHealthRows).Error
+	if err != nil {
+		return err
+	}
+	for _, row := range syncHealthRows {
+		for _, i := range hubIndexes[row.LeafHubName] {
+			hub := &hubs[i]
+			if row.EventType == database.SyncHealthTransportType {
+				hub.Topic, hub.ConsumerLag = row.Topic, row.ConsumerLag
+				continue
+			}
+			hub.SyncHealth = append(hub.SyncHealth, SyncHealth{
+				Type:                 row.EventType,
+				LastReceivedAt:       row.LastReceivedAt,
+				LastProcessedAt:      row.LastProcessedAt,
+				LastProcessedVersion: row.LastProcessedVersion,
+				ProcessingLatencyMs:  row.ProcessingLatencyMs,
+				Received:             row.Received,
+				Processed:            row.Processed,
+				Failures:             row.Failures,
+				LastError:            row.LastError,
+			})
+		}
+	}
+	return nil
+}
+
+// hubsTable converts the hubs to the table for the "kubectl get" like clients
+func hubsTable(hubs []Hub, continueToken string) (*metav1.Table, error) {
+	table := &metav1.Table{
+		TypeMeta: metav1.TypeMeta{Kind: "Table", APIVersion: metav1.SchemeGroupVersion.String()},
+		ListMeta: metav1.ListMeta{Continue: continueToken},
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string", Format: "name"},
+			{Name: "Status", Type: "string"},
+			{Name: "Managed Clusters", Type: "integer"},
+			{Name: "Available", Type: "integer"},
+			{Name: "Last Heartbeat", Type: "date"},
+			{Name: "ACM Version", Type: "string"},
+			{Name: "Agent Version", Type: "string"},
+			{Name: "Console URL", Type: "string", Priority: 1},
+		},
+		Rows: []metav1.TableRow{},
+	}
+	for _, hub := range hubs {
+		raw, err := json.Marshal(hub)
+		if err != nil {
+			return nil, err
+		}
+		var heartbeat interface{}
+		if hub.LastHeartbeat != nil {
+			heartbeat = hub.LastHeartbeat.UTC().Format(time.RFC3339)
+		}
+		table.Rows = append(table.Rows, metav1.TableRow{
+			Cells: []interface{}{
+				hub.Name, hub.Status, hub.ManagedClusters.Total, hub.ManagedClusters.Available, heartbeat,
+				hub.ACMVersion, hub.AgentVersion, hub.ConsoleURL,
+			},
+			Object: runtime.RawExtension{Raw: raw},
+		})
+	}
+	return table, nil
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package hubs
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHubsTable(t *testing.T) {
+	heartbeat := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	hubs := []Hub{
+		{
+			Name: "hub1", Status: "active", LastHeartbeat: &heartbeat, AgentVersion: "1.3.0", ACMVersion: "2.11.0",
+			ManagedClusters: ManagedClusterCounts{Total: 3, Available: 2}, ConsoleURL: "https://console.hub1",
+		},
+		{Name: "hub2", Status: hubStatusUnknown},
+	}
+
+	table, err := hubsTable(hubs, "token")
+	assert.NoError(t, err)
+	assert.Equal(t, "Table", table.Kind)
+	assert.Equal(t, "token", table.Continue)
+	assert.Len(t, table.ColumnDefinitions, 8)
+	assert.Len(t, table.Rows, 2)
+	assert.Equal(t, []interface{}{
+		"hub1", "active", int64(3), int64(2), "2024-05-01T08:00:00Z", "2.11.0", "1.3.0", "https://console.hub1",
+	}, table.Rows[0].Cells)
+	assert.Nil(t, table.Rows[1].Cells[4])
+
+	hub := Hub{}
+	assert.NoError(t, json.Unmarshal(table.Rows[1].Object.Raw, &hub))
+	assert.Equal(t, "hub2", hub.Name)
+}
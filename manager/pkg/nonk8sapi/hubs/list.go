@@ -0,0 +1,98 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package hubs
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/util"
+)
+
+// ListHubs godoc
+// @summary list managed hubs
+// @description list the managed hubs with the hub info, heartbeat, managed cluster counts, alert counts and the
+// @description sync health of the status events
+// @accept json
+// @produce json
+// @param        limit            query     int     false  "maximum managed hub number to receive"
+// @param        continue         query     string  false  "continue token to request next request"
+// @success      200  {object}  hubs.HubList
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /hubs [get]
+func ListHubs() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		limit := 0
+		if limitStr := ginCtx.Query("limit"); limitStr != "" {
+			var err error
+			if limit, err = strconv.Atoi(limitStr); err != nil || limit <= 0 {
+				ginCtx.String(http.StatusBadRequest, "invalid limit: %s", limitStr)
+				return
+			}
+		}
+
+		lastHubName, lastClusterID := "", uuid.Nil.String()
+		if continueToken := ginCtx.Query("continue"); continueToken != "" {
+			var err error
+			lastHubName, lastClusterID, err = util.DecodeContinue(continueToken)
+			if err == nil {
+				_, err = uuid.Parse(lastClusterID)
+			}
+			if err != nil {
+				ginCtx.String(http.StatusBadRequest, "invalid continue token")
+				fmt.Fprintf(gin.DefaultWriter, "failed to decode continue token: %v\n", err)
+				return
+			}
+		}
+
+		condition := " AND (h.leaf_hub_name, h.cluster_id) > (?, ?::uuid) ORDER BY h.leaf_hub_name, h.cluster_id"
+		args := []interface{}{lastHubName, lastClusterID}
+		if limit > 0 {
+			// query one more hub to know whether there are remaining hubs
+			condition += " LIMIT ?"
+			args = append(args, limit+1)
+		}
+
+		hubs, err := queryHubs(condition, args...)
+		if err != nil {
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			fmt.Fprintf(gin.DefaultWriter, "error in querying managed hubs: %v\n", err)
+			return
+		}
+
+		hubList := HubList{Items: hubs}
+		if limit > 0 && len(hubs) > limit {
+			hubList.Items = hubs[:limit]
+			last := hubList.Items[limit-1]
+			hubList.Continue, err = util.EncodeContinue(last.Name, last.ClusterID)
+			if err != nil {
+				ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+				fmt.Fprintf(gin.DefaultWriter, "error in encoding the continue token: %v\n", err)
+				return
+			}
+		}
+
+		if util.ShouldReturnAsTable(ginCtx) {
+			table, err := hubsTable(hubList.Items, hubList.Continue)
+			if err != nil {
+				ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+				fmt.Fprintf(gin.DefaultWriter, "error in converting to table: %v\n", err)
+				return
+			}
+			ginCtx.JSON(http.StatusOK, table)
+			return
+		}
+
+		ginCtx.JSON(http.StatusOK, hubList)
+	}
+}
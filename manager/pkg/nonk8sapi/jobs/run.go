@@ -0,0 +1,201 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+const (
+	serverInternalErrorMsg = "internal error"
+	defaultRunsLimit       = 20
+)
+
+// the job names are the names of the built-in jobs of the manager scheduler, e.g. data-retention
+var jobNameRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+type JobRun struct {
+	ID         string     `json:"id"`
+	JobName    string     `json:"jobName"`
+	Trigger    string     `json:"trigger"`
+	State      string     `json:"state"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	StartAt    *time.Time `json:"startAt,omitempty"`
+	EndAt      *time.Time `json:"endAt,omitempty"`
+	DurationMs int64      `json:"durationMs"`
+	Error      string     `json:"error,omitempty"`
+}
+
+type JobRunList struct {
+	Items []JobRun `json:"items"`
+}
+
+// CreateJobRun godoc
+// @summary trigger job
+// @description trigger a run of the manager job, the run is pending until the scheduler of the manager picks it up,
+// @description and it fails if the job isn't enabled
+// @accept json
+// @produce json
+// @param        name    path    string    true    "Job name"
+// @success      202  {object}  jobs.JobRun
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      409
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /jobs/{name}/runs [post]
+func CreateJobRun() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		name := ginCtx.Param("name")
+		if !jobNameRegex.MatchString(name) {
+			ginCtx.String(http.StatusBadRequest, fmt.Sprintf("invalid job name %s", name))
+			return
+		}
+
+		db := database.GetGorm()
+		pending := models.JobRun{}
+		err := db.Where(&models.JobRun{JobName: name, State: database.JobRunPending}).First(&pending).Error
+		if err == nil {
+			ginCtx.String(http.StatusConflict, fmt.Sprintf("the job %s is already triggered by the run %s", name,
+				pending.ID))
+			return
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			fmt.Fprintf(gin.DefaultWriter, "failed to get the pending run of the job %s: %v\n", name, err)
+			return
+		}
+
+		run := &models.JobRun{
+			ID:      uuid.New().String(),
+			JobName: name,
+			Trigger: database.JobRunManual,
+			State:   database.JobRunPending,
+		}
+		if err := db.Create(run).Error; err != nil {
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			fmt.Fprintf(gin.DefaultWriter, "failed to create the run of the job %s: %v\n", name, err)
+			return
+		}
+		fmt.Fprintf(gin.DefaultWriter, "the run %s of the job %s is created\n", run.ID, name)
+
+		ginCtx.JSON(http.StatusAccepted, toJobRun(run))
+	}
+}
+
+// ListJobRuns godoc
+// @summary list job runs
+// @description list the latest runs of the manager job with the trigger, state, duration and error
+// @accept json
+// @produce json
+// @param        name     path     string    true     "Job name"
+// @param        limit    query    int       false    "maximum run number to receive, default is 20"
+// @success      200  {object}  jobs.JobRunList
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /jobs/{name}/runs [get]
+func ListJobRuns() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		name := ginCtx.Param("name")
+		if !jobNameRegex.MatchString(name) {
+			ginCtx.String(http.StatusBadRequest, fmt.Sprintf("invalid job name %s", name))
+			return
+		}
+		limit := defaultRunsLimit
+		if limitStr := ginCtx.Query("limit"); limitStr != "" {
+			var err error
+			if limit, err = strconv.Atoi(limitStr); err != nil || limit <= 0 {
+				ginCtx.String(http.StatusBadRequest, "invalid limit: %s", limitStr)
+				return
+			}
+		}
+
+		runs := []models.JobRun{}
+		err := database.GetGorm().Where(&models.JobRun{JobName: name}).Order("created_at DESC").Limit(limit).
+			Find(&runs).Error
+		if err != nil {
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			fmt.Fprintf(gin.DefaultWriter, "failed to list the runs of the job %s: %v\n", name, err)
+			return
+		}
+
+		runList := JobRunList{Items: []JobRun{}}
+		for i := range runs {
+			runList.Items = append(runList.Items, toJobRun(&runs[i]))
+		}
+		ginCtx.JSON(http.StatusOK, runList)
+	}
+}
+
+// GetJobRun godoc
+// @summary get job run
+// @description get the run of the manager job
+// @accept json
+// @produce json
+// @param        name     path    string    true    "Job name"
+// @param        runID    path    string    true    "Run ID"
+// @success      200  {object}  jobs.JobRun
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /jobs/{name}/runs/{runID} [get]
+func GetJobRun() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		name := ginCtx.Param("name")
+		runID := ginCtx.Param("runID")
+		if _, err := uuid.Parse(runID); err != nil {
+			ginCtx.String(http.StatusBadRequest, fmt.Sprintf("invalid run ID %s", runID))
+			return
+		}
+
+		run := models.JobRun{}
+		err := database.GetGorm().Where(&models.JobRun{ID: runID, JobName: name}).First(&run).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ginCtx.String(http.StatusNotFound, fmt.Sprintf("the run %s of the job %s isn't found", runID, name))
+			return
+		}
+		if err != nil {
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			fmt.Fprintf(gin.DefaultWriter, "failed to get the run %s of the job %s: %v\n", runID, name, err)
+			return
+		}
+		ginCtx.JSON(http.StatusOK, toJobRun(&run))
+	}
+}
+
+func toJobRun(run *models.JobRun) JobRun {
+	return JobRun{
+		ID:         run.ID,
+		JobName:    run.JobName,
+		Trigger:    string(run.Trigger),
+		State:      string(run.State),
+		CreatedAt:  run.CreatedAt,
+		StartAt:    run.StartAt,
+		EndAt:      run.EndAt,
+		DurationMs: run.DurationMs,
+		Error:      run.Error,
+	}
+}
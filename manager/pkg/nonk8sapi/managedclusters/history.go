@@ -0,0 +1,116 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package managedclusters
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+const (
+	defaultHistoryDays  = 30
+	defaultHistoryLimit = 500
+)
+
+var historyFields = map[string]database.ClusterHistoryField{
+	string(database.ClusterHistoryLabel):     database.ClusterHistoryLabel,
+	string(database.ClusterHistoryVersion):   database.ClusterHistoryVersion,
+	string(database.ClusterHistoryCondition): database.ClusterHistoryCondition,
+	string(database.ClusterHistoryClaim):     database.ClusterHistoryClaim,
+	string(database.ClusterHistoryHub):       database.ClusterHistoryHub,
+}
+
+type ClusterHistory struct {
+	ClusterID string                         `json:"clusterID"`
+	Since     time.Time                      `json:"since"`
+	Items     []models.ManagedClusterHistory `json:"items"`
+}
+
+// GetManagedClusterHistory godoc
+// @summary get managed cluster history
+// @description get the field level changes of the labels, version, conditions, claims and the owning hub of the managed cluster in time order
+// @accept json
+// @produce json
+// @param        clusterID    path     string    true     "Managed Cluster ID"
+// @param        since        query    string    false    "the RFC3339 time to get the changes after, the default is 30 days ago"
+// @param        field        query    string    false    "only get the changes of the field: label, version, condition, claim or hub"
+// @param        limit        query    int       false    "maximum change number to receive, default is 500"
+// @success      200  {object}  managedclusters.ClusterHistory
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /managedcluster/{clusterID}/history [get]
+func GetManagedClusterHistory() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		clusterID := ginCtx.Param("clusterID")
+		if _, err := uuid.Parse(clusterID); err != nil {
+			ginCtx.String(http.StatusBadRequest, fmt.Sprintf("invalid cluster ID %s", clusterID))
+			return
+		}
+		since := time.Now().UTC().AddDate(0, 0, -defaultHistoryDays)
+		if sinceStr := ginCtx.Query("since"); sinceStr != "" {
+			var err error
+			if since, err = time.Parse(time.RFC3339, sinceStr); err != nil {
+				ginCtx.String(http.StatusBadRequest, fmt.Sprintf("invalid since %s, it should be in the format %s",
+					sinceStr, time.RFC3339))
+				return
+			}
+		}
+		query := &models.ManagedClusterHistory{ClusterID: clusterID}
+		if fieldStr := ginCtx.Query("field"); fieldStr != "" {
+			field, ok := historyFields[fieldStr]
+			if !ok {
+				ginCtx.String(http.StatusBadRequest, fmt.Sprintf("unsupported field %s", fieldStr))
+				return
+			}
+			query.Field = field
+		}
+		limit := defaultHistoryLimit
+		if limitStr := ginCtx.Query("limit"); limitStr != "" {
+			var err error
+			if limit, err = strconv.Atoi(limitStr); err != nil || limit <= 0 {
+				ginCtx.String(http.StatusBadRequest, fmt.Sprintf("invalid limit: %s", limitStr))
+				return
+			}
+		}
+
+		db := database.GetGorm()
+		history := ClusterHistory{ClusterID: clusterID, Since: since, Items: []models.ManagedClusterHistory{}}
+		err := db.Where(query).Where("created_at > ?", since).Order("created_at").Limit(limit).
+			Find(&history.Items).Error
+		if err != nil {
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			fmt.Fprintf(gin.DefaultWriter, "failed to get the history of the managed cluster %s: %v\n", clusterID, err)
+			return
+		}
+
+		if len(history.Items) == 0 {
+			// the cluster without changes since the time is still found, including the deleted one
+			var count int64
+			err := db.Unscoped().Model(&models.ManagedCluster{}).Where("cluster_id = ?", clusterID).Count(&count).Error
+			if err != nil {
+				ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+				fmt.Fprintf(gin.DefaultWriter, "failed to get the managed cluster %s: %v\n", clusterID, err)
+				return
+			}
+			if count == 0 {
+				ginCtx.String(http.StatusNotFound, fmt.Sprintf("managed cluster %s isn't found", clusterID))
+				return
+			}
+		}
+		ginCtx.JSON(http.StatusOK, history)
+	}
+}
@@ -0,0 +1,263 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package managedclusters
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/util"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+const (
+	operationInProgress = "InProgress"
+	operationCompleted  = "Completed"
+)
+
+// operationRequest selects the target managed clusters either by the label selector or by the cluster IDs
+type operationRequest struct {
+	LabelSelector string                       `json:"labelSelector"`
+	ClusterIDs    []string                     `json:"clusterIDs"`
+	Operation     spec.ManagedClusterOperation `json:"operation"`
+}
+
+type OperationCreated struct {
+	OperationID string `json:"operationID"`
+	Clusters    int    `json:"clusters"`
+}
+
+type ClusterOperationResult struct {
+	ClusterID   string    `json:"clusterID"`
+	ClusterName string    `json:"clusterName"`
+	LeafHubName string    `json:"leafHubName"`
+	State       string    `json:"state"`
+	Message     string    `json:"message,omitempty"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type OperationStatus struct {
+	OperationID string                       `json:"operationID"`
+	Operation   spec.ManagedClusterOperation `json:"operation"`
+	State       string                       `json:"state"`
+	CreatedAt   time.Time                    `json:"createdAt"`
+	Summary     map[string]int               `json:"summary"`
+	Clusters    []ClusterOperationResult     `json:"clusters"`
+}
+
+type operationTarget struct {
+	ClusterID   string
+	ClusterName string
+	LeafHubName string
+}
+
+// CreateManagedClusterOperation godoc
+// @summary create bulk managed cluster operation
+// @description apply the label/annotation changes, ManagedClusterSet membership, taints or hubAcceptsClient to the managed clusters selected by the label selector or the cluster IDs asynchronously
+// @accept json
+// @produce json
+// @param        operation    body    operationRequest    true    "the target managed clusters and the changes"
+// @success      202  {object}  managedclusters.OperationCreated
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /managedclusters/operations [post]
+func CreateManagedClusterOperation() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		request := &operationRequest{}
+		if err := ginCtx.ShouldBindJSON(request); err != nil {
+			ginCtx.String(http.StatusBadRequest, fmt.Sprintf("invalid operation: %s", err.Error()))
+			return
+		}
+		if err := validateOperation(request); err != nil {
+			ginCtx.String(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		targets, err := operationTargets(request)
+		if err != nil {
+			ginCtx.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		if len(targets) == 0 {
+			ginCtx.String(http.StatusNotFound, "no managed cluster is selected by the operation")
+			return
+		}
+
+		operationID := uuid.New().String()
+		if err := saveOperation(operationID, request.Operation, targets); err != nil {
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			fmt.Fprintf(gin.DefaultWriter, "failed to save managed cluster operation: %v\n", err)
+			return
+		}
+		fmt.Fprintf(gin.DefaultWriter, "managed cluster operation %s is created for %d clusters\n", operationID,
+			len(targets))
+
+		ginCtx.JSON(http.StatusAccepted, OperationCreated{OperationID: operationID, Clusters: len(targets)})
+	}
+}
+
+func validateOperation(request *operationRequest) error {
+	if (request.LabelSelector == "") == (len(request.ClusterIDs) == 0) {
+		return errors.New("either labelSelector or clusterIDs must be specified")
+	}
+	for _, clusterID := range request.ClusterIDs {
+		if _, err := uuid.Parse(clusterID); err != nil {
+			return fmt.Errorf("invalid cluster ID %s", clusterID)
+		}
+	}
+	operation := request.Operation
+	if operation.IsEmpty() {
+		return errors.New("the operation doesn't change anything")
+	}
+	if operation.Taints != nil {
+		for _, taint := range operation.Taints.Add {
+			if taint.Key == "" || taint.Effect == "" {
+				return errors.New("the key and effect of the taint are required")
+			}
+		}
+	}
+	return nil
+}
+
+// operationTargets returns the living managed clusters selected by the request
+func operationTargets(request *operationRequest) ([]operationTarget, error) {
+	query := `SELECT cluster_id, payload->'metadata'->>'name' AS cluster_name, leaf_hub_name
+		FROM status.managed_clusters WHERE deleted_at IS NULL`
+	args := []interface{}{}
+	if request.LabelSelector != "" {
+		selectorInSql, err := util.ParseLabelSelector(request.LabelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse label selector: %w", err)
+		}
+		query += selectorInSql
+	} else {
+		query += " AND cluster_id IN ?"
+		args = append(args, request.ClusterIDs)
+	}
+
+	targets := []operationTarget{}
+	if err := database.GetGorm().Raw(query, args...).Scan(&targets).Error; err != nil {
+		return nil, err
+	}
+	return targets, nil
+}
+
+// saveOperation persists the operation with the pending results, which are sent to the managed hubs by the spec
+// syncer later
+func saveOperation(operationID string, operation spec.ManagedClusterOperation, targets []operationTarget) error {
+	payload, err := json.Marshal(operation)
+	if err != nil {
+		return err
+	}
+	results := make([]models.ManagedClusterOperationResult, 0, len(targets))
+	for _, target := range targets {
+		results = append(results, models.ManagedClusterOperationResult{
+			OperationID: operationID,
+			ClusterID:   target.ClusterID,
+			ClusterName: target.ClusterName,
+			LeafHubName: target.LeafHubName,
+			State:       database.OperationPending,
+		})
+	}
+	return database.GetGorm().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&models.ManagedClusterOperation{ID: operationID, Payload: payload}).Error; err != nil {
+			return err
+		}
+		return tx.CreateInBatches(results, 100).Error
+	})
+}
+
+// GetManagedClusterOperation godoc
+// @summary get bulk managed cluster operation
+// @description get the state and the per-cluster results of the bulk managed cluster operation
+// @accept json
+// @produce json
+// @param        operationID    path    string    true    "Operation ID"
+// @success      200  {object}  managedclusters.OperationStatus
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /managedclusters/operations/{operationID} [get]
+func GetManagedClusterOperation() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		operationID := ginCtx.Param("operationID")
+		if _, err := uuid.Parse(operationID); err != nil {
+			ginCtx.String(http.StatusBadRequest, fmt.Sprintf("invalid operation ID %s", operationID))
+			return
+		}
+
+		db := database.GetGorm()
+		operation := models.ManagedClusterOperation{}
+		err := db.Where(&models.ManagedClusterOperation{ID: operationID}).First(&operation).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ginCtx.String(http.StatusNotFound, fmt.Sprintf("operation %s isn't found", operationID))
+			return
+		}
+		if err != nil {
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			fmt.Fprintf(gin.DefaultWriter, "failed to get managed cluster operation: %v\n", err)
+			return
+		}
+
+		results := []models.ManagedClusterOperationResult{}
+		err = db.Where(&models.ManagedClusterOperationResult{OperationID: operationID}).
+			Order("leaf_hub_name, cluster_name").Find(&results).Error
+		if err != nil {
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			fmt.Fprintf(gin.DefaultWriter, "failed to get managed cluster operation results: %v\n", err)
+			return
+		}
+
+		status := OperationStatus{
+			OperationID: operationID,
+			State:       operationCompleted,
+			CreatedAt:   operation.CreatedAt,
+			Summary: map[string]int{
+				string(database.OperationPending):   0,
+				string(database.OperationSent):      0,
+				string(database.OperationSucceeded): 0,
+				string(database.OperationFailed):    0,
+			},
+			Clusters: []ClusterOperationResult{},
+		}
+		if err := json.Unmarshal(operation.Payload, &status.Operation); err != nil {
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			fmt.Fprintf(gin.DefaultWriter, "failed to unmarshal managed cluster operation: %v\n", err)
+			return
+		}
+		for _, result := range results {
+			status.Summary[string(result.State)]++
+			if result.State == database.OperationPending || result.State == database.OperationSent {
+				status.State = operationInProgress
+			}
+			status.Clusters = append(status.Clusters, ClusterOperationResult{
+				ClusterID:   result.ClusterID,
+				ClusterName: result.ClusterName,
+				LeafHubName: result.LeafHubName,
+				State:       string(result.State),
+				Message:     result.Message,
+				UpdatedAt:   result.UpdatedAt,
+			})
+		}
+		ginCtx.JSON(http.StatusOK, status)
+	}
+}
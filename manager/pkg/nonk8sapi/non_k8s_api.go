@@ -14,10 +14,16 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/go-logr/logr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/authentication"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/compliance"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/hubs"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/jobs"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/managedclusters"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/overrides"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/policies"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/search"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/subscriptions"
 )
 
@@ -60,7 +66,7 @@ func readCertificateAuthority(nonK8sAPIServerConfig *NonK8sAPIServerConfig) ([]b
 
 // AddNonK8sApiServer adds the non-k8s-api-server to the Manager.
 func AddNonK8sApiServer(mgr ctrl.Manager, nonK8sAPIServerConfig *NonK8sAPIServerConfig) error {
-	router, err := SetupRouter(nonK8sAPIServerConfig)
+	router, err := SetupRouter(nonK8sAPIServerConfig, mgr.GetClient())
 	if err != nil {
 		return err
 	}
@@ -97,7 +103,7 @@ func AddNonK8sApiServer(mgr ctrl.Manager, nonK8sAPIServerConfig *NonK8sAPIServer
 // @in                          header
 // @name                        Authorization
 // @description					Authorization with user access token
-func SetupRouter(nonK8sAPIServerConfig *NonK8sAPIServerConfig) (*gin.Engine, error) {
+func SetupRouter(nonK8sAPIServerConfig *NonK8sAPIServerConfig, c client.Client) (*gin.Engine, error) {
 	router := gin.Default()
 	// add aythentication eith openshift oauth
 	// skip authentication middleware if ClusterAPIURL is empty for testing
@@ -113,10 +119,29 @@ func SetupRouter(nonK8sAPIServerConfig *NonK8sAPIServerConfig) (*gin.Engine, err
 	routerGroup.GET("/managedclusters", managedclusters.ListManagedClusters())
 	routerGroup.PATCH("/managedcluster/:clusterID",
 		managedclusters.PatchManagedCluster())
+	routerGroup.GET("/managedcluster/:clusterID/history", managedclusters.GetManagedClusterHistory())
+	routerGroup.POST("/managedclusters/operations", managedclusters.CreateManagedClusterOperation())
+	routerGroup.GET("/managedclusters/operations/:operationID", managedclusters.GetManagedClusterOperation())
 	routerGroup.GET("/policies", policies.ListPolicies())
 	routerGroup.GET("/policy/:policyID/status", policies.GetPolicyStatus())
+	routerGroup.GET("/policy/:policyID/violations", policies.GetPolicyViolations())
 	routerGroup.GET("/subscriptions", subscriptions.ListSubscriptions())
 	routerGroup.GET("/subscriptionreport/:subscriptionID", subscriptions.GetSubscriptionReport())
+	routerGroup.GET("/compliance/summary", compliance.GetComplianceSummary())
+	routerGroup.GET("/compliance/history", compliance.GetComplianceHistory())
+	routerGroup.GET("/hubs", hubs.ListHubs())
+	routerGroup.GET("/hub/:name", hubs.GetHub())
+	routerGroup.GET("/search", search.Search())
+	routerGroup.POST("/searches", search.CreateSavedSearch())
+	routerGroup.GET("/searches", search.ListSavedSearches())
+	routerGroup.GET("/searches/:searchID", search.GetSavedSearch())
+	routerGroup.PUT("/searches/:searchID", search.UpdateSavedSearch())
+	routerGroup.DELETE("/searches/:searchID", search.DeleteSavedSearch())
+	routerGroup.GET("/searches/:searchID/results", search.RunSavedSearch())
+	routerGroup.POST("/jobs/:name/runs", jobs.CreateJobRun())
+	routerGroup.GET("/jobs/:name/runs", jobs.ListJobRuns())
+	routerGroup.GET("/jobs/:name/runs/:runID", jobs.GetJobRun())
+	routerGroup.GET("/globalhuboverride/:namespace/:name/rendered/:hub", overrides.GetRenderedResource(c))
 
 	return router, nil
 }
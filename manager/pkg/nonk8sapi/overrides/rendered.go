@@ -0,0 +1,74 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package overrides
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/override"
+	overridev1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/override/v1alpha1"
+)
+
+const serverInternalErrorMsg = "internal error"
+
+// GetRenderedResource godoc
+// @summary get rendered resource
+// @description get the target of the global hub override rendered for the managed hub, which is the resource sent to
+// @description the managed hub, its sha256 hash is listed in the status.renderedHubs of the override
+// @accept json
+// @produce json
+// @param        namespace    path    string    true    "Override namespace"
+// @param        name         path    string    true    "Override name"
+// @param        hub          path    string    true    "Managed hub name"
+// @success      200
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      422
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /globalhuboverride/{namespace}/{name}/rendered/{hub} [get]
+func GetRenderedResource(c client.Client) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		key := types.NamespacedName{Namespace: ginCtx.Param("namespace"), Name: ginCtx.Param("name")}
+		hub := ginCtx.Param("hub")
+
+		globalHubOverride := &overridev1alpha1.GlobalHubOverride{}
+		err := c.Get(ginCtx.Request.Context(), key, globalHubOverride)
+		if apierrors.IsNotFound(err) {
+			ginCtx.String(http.StatusNotFound, "global hub override %s not found", key)
+			return
+		}
+		if err != nil {
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			fmt.Fprintf(gin.DefaultWriter, "error in getting global hub override %s: %v\n", key, err)
+			return
+		}
+
+		rendered, err := override.RenderedTarget(ginCtx.Request.Context(), c, globalHubOverride, hub)
+		switch {
+		case err == nil:
+			ginCtx.Data(http.StatusOK, "application/json", rendered)
+		case apierrors.IsNotFound(err):
+			ginCtx.String(http.StatusNotFound, "the %s %s/%s isn't found", globalHubOverride.Spec.TargetRef.Kind,
+				key.Namespace, globalHubOverride.Spec.TargetRef.Name)
+		case errors.Is(err, override.ErrHubNotSelected):
+			ginCtx.String(http.StatusNotFound, "managed hub %s isn't selected by global hub override %s", hub, key)
+		case errors.Is(err, override.ErrRenderFailed):
+			ginCtx.String(http.StatusUnprocessableEntity, "%s", err.Error())
+		default:
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			fmt.Fprintf(gin.DefaultWriter, "error in rendering global hub override %s for managed hub %s: %v\n", key,
+				hub, err)
+		}
+	}
+}
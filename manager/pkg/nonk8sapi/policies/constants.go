@@ -9,6 +9,7 @@ const (
 	QueryPoliciesFailureFormatMsg         = "error in querying policies: %v\n"
 	QueryPolicyComplianceFailureFormatMsg = "error in querying compliance status of a policy with UID: %v\n"
 	QueryPolicyMappingFailureFormatMsg    = "error in querying policy&placementbinding&placementrule mapping: %v\n"
+	QueryPolicyViolationsFailureFormatMsg = "error in querying violations of a policy with UID: %v\n"
 )
 
 const (
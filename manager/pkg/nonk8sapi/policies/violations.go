@@ -0,0 +1,94 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package policies
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// PolicyViolation is a non-compliant template of the policy on a managed cluster
+type PolicyViolation struct {
+	LeafHubName        string              `json:"leafHubName"`
+	ClusterName        string              `json:"clusterName"`
+	ClusterID          string              `json:"clusterId"`
+	TemplateName       string              `json:"templateName"`
+	TemplateKind       string              `json:"templateKind"`
+	TemplateAPIVersion string              `json:"templateApiVersion"`
+	Compliance         string              `json:"compliance"`
+	Message            string              `json:"message"`
+	RelatedObjects     []grc.RelatedObject `json:"relatedObjects"`
+	UpdatedAt          time.Time           `json:"updatedAt"`
+}
+
+type PolicyViolationList struct {
+	PolicyID string            `json:"policyId"`
+	Items    []PolicyViolation `json:"items"`
+}
+
+// GetPolicyViolations godoc
+// @summary get policy violations
+// @description get the non-compliant templates and their related objects of a given policy on the managed clusters
+// @accept json
+// @produce json
+// @param        policyID    path     string    true     "Policy ID"
+// @param        hub         query    string    false    "only get the violations on the managed hub"
+// @param        cluster     query    string    false    "only get the violations on the managed cluster"
+// @success      200  {object}  policies.PolicyViolationList
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /policy/{policyID}/violations [get]
+func GetPolicyViolations() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		policyID := ginCtx.Param("policyID")
+		fmt.Fprintf(gin.DefaultWriter, "getting violations for policy: %s\n", policyID)
+
+		condition := &models.PolicyViolation{
+			PolicyID:    policyID,
+			LeafHubName: ginCtx.Query("hub"),
+			ClusterName: ginCtx.Query("cluster"),
+		}
+		violations := []models.PolicyViolation{}
+		err := database.GetGorm().Where(condition).Order("leaf_hub_name, cluster_name, template_name").
+			Find(&violations).Error
+		if err != nil {
+			ginCtx.String(http.StatusInternalServerError, ServerInternalErrorMsg)
+			fmt.Fprintf(gin.DefaultWriter, QueryPolicyViolationsFailureFormatMsg, err)
+			return
+		}
+
+		violationList := PolicyViolationList{PolicyID: policyID, Items: []PolicyViolation{}}
+		for _, violation := range violations {
+			relatedObjects := []grc.RelatedObject{}
+			if err := json.Unmarshal(violation.RelatedObjects, &relatedObjects); err != nil {
+				fmt.Fprintf(gin.DefaultWriter, "error in unmarshaling the related objects: %v\n", err)
+			}
+			violationList.Items = append(violationList.Items, PolicyViolation{
+				LeafHubName:        violation.LeafHubName,
+				ClusterName:        violation.ClusterName,
+				ClusterID:          violation.ClusterID,
+				TemplateName:       violation.TemplateName,
+				TemplateKind:       violation.TemplateKind,
+				TemplateAPIVersion: violation.TemplateAPIVersion,
+				Compliance:         string(violation.Compliance),
+				Message:            violation.Message,
+				RelatedObjects:     relatedObjects,
+				UpdatedAt:          violation.UpdatedAt,
+			})
+		}
+		ginCtx.JSON(http.StatusOK, violationList)
+	}
+}
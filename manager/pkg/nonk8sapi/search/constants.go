@@ -0,0 +1,71 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package search
+
+const (
+	serverInternalErrorMsg    = "internal error"
+	searchFailureFormatMsg    = "error in searching resources: %v\n"
+	savedSearchFailureFormMsg = "error in handling saved search: %v\n"
+)
+
+const (
+	KindCluster = "cluster"
+	KindPolicy  = "policy"
+	KindHub     = "hub"
+
+	defaultLimit = 100
+	maxLimit     = 1000
+)
+
+// the predicates of the query language
+const (
+	termKind       = "kind"
+	termHub        = "hub"
+	termName       = "name"
+	termLabel      = "label"
+	termField      = "field"
+	termClaim      = "claim"
+	termCompliance = "compliance"
+)
+
+// kindTable describes how the resources of a kind are stored, the table is always aliased as "r"
+type kindTable struct {
+	table         string
+	idColumn      string
+	nameColumn    string
+	namespaceExpr string
+	hasLabels     bool
+}
+
+var kindTables = map[string]kindTable{
+	KindCluster: {
+		table:         "status.managed_clusters",
+		idColumn:      "r.cluster_id",
+		nameColumn:    "r.cluster_name",
+		namespaceExpr: "''",
+		hasLabels:     true,
+	},
+	KindPolicy: {
+		table:         "local_spec.policies",
+		idColumn:      "r.policy_id",
+		nameColumn:    "r.policy_name",
+		namespaceExpr: "COALESCE(r.payload -> 'metadata' ->> 'namespace', '')",
+		hasLabels:     true,
+	},
+	KindHub: {
+		table:         "status.leaf_hubs",
+		idColumn:      "r.cluster_id",
+		nameColumn:    "r.leaf_hub_name",
+		namespaceExpr: "''",
+	},
+}
+
+// complianceStates maps the accepted compliance values to the local_status.compliance_type
+var complianceStates = map[string]string{
+	"compliant":     "compliant",
+	"noncompliant":  "non_compliant",
+	"non_compliant": "non_compliant",
+	"pending":       "pending",
+	"unknown":       "unknown",
+}
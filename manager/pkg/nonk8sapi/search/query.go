@@ -0,0 +1,265 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// fieldPathPattern limits the field path to the dotted keys, so it can be passed as the text[] path of the payload
+var fieldPathPattern = regexp.MustCompile(`^[A-Za-z0-9_\-/]+(\.[A-Za-z0-9_\-/]+)*$`)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Query is the compiled search query. The query language is a list of whitespace-separated predicates in the
+// form of "<term>:<expression>", the predicates are ANDed except the hub predicates which are ORed, e.g.
+//
+//	kind:cluster hub:hub1 label:vendor=OpenShift claim:version.openshift.io=4.14 compliance:policy1=non_compliant
+//
+// The value can be double-quoted if it contains spaces. The supported predicates are:
+//
+//	kind:cluster|policy|hub         the kind of the resources, cluster by default
+//	hub:<name>                      the resources of the managed hub
+//	name:<name>                     the name of the resources, "*" matches any characters
+//	label:<key>=<value>             also <key>!=<value>, <key> and !<key>, for cluster and policy
+//	field:<path>=<value>            also <path>!=<value> and <path>~<substring>, the path is the dotted keys of
+//	                                the resource, e.g. status.version.kubernetes or spec.remediationAction
+//	claim:<name>=<value>            the cluster claim of the managed cluster
+//	compliance:[<policy>=]<state>   the clusters with the compliance state (for the policy name or ID)
+//	compliance:[<cluster>=]<state>  the policies with the compliance state (on the cluster)
+//
+// All the values are passed to the database as the parameters of the SQL.
+type Query struct {
+	Kind       string
+	hubs       []string
+	conditions []string
+	args       []interface{}
+}
+
+// ParseQuery parses the query language into the conditions of the parameterized SQL
+func ParseQuery(queryStr string) (*Query, error) {
+	terms, err := tokenize(queryStr)
+	if err != nil {
+		return nil, err
+	}
+
+	query := &Query{Kind: KindCluster}
+	kindSet := false
+	predicates := [][2]string{}
+	for _, term := range terms {
+		name, expr, found := strings.Cut(term, ":")
+		if !found || expr == "" {
+			return nil, fmt.Errorf("invalid predicate %q, it should be <term>:<expression>", term)
+		}
+		if name != termKind {
+			predicates = append(predicates, [2]string{name, expr})
+			continue
+		}
+		if kindSet {
+			return nil, fmt.Errorf("only one kind can be specified")
+		}
+		if _, ok := kindTables[expr]; !ok {
+			return nil, fmt.Errorf("unsupported kind %s, it should be one of %s, %s, %s", expr, KindCluster,
+				KindPolicy, KindHub)
+		}
+		query.Kind, kindSet = expr, true
+	}
+
+	for _, predicate := range predicates {
+		if err := query.addPredicate(predicate[0], predicate[1]); err != nil {
+			return nil, err
+		}
+	}
+	return query, nil
+}
+
+func (q *Query) addPredicate(name, expr string) error {
+	table := kindTables[q.Kind]
+	switch name {
+	case termHub:
+		q.hubs = append(q.hubs, expr)
+	case termName:
+		if strings.Contains(expr, "*") {
+			q.addCondition(table.nameColumn+` LIKE ?`,
+				strings.ReplaceAll(likeEscaper.Replace(expr), "*", "%"))
+		} else {
+			q.addCondition(table.nameColumn+" = ?", expr)
+		}
+	case termLabel:
+		if !table.hasLabels {
+			return fmt.Errorf("the label predicate isn't supported for kind %s", q.Kind)
+		}
+		return q.addLabel(expr)
+	case termField:
+		return q.addField(expr)
+	case termClaim:
+		if q.Kind != KindCluster {
+			return fmt.Errorf("the claim predicate is only supported for kind %s", KindCluster)
+		}
+		claimName, claimValue, found := strings.Cut(expr, "=")
+		if !found || claimName == "" {
+			return fmt.Errorf("invalid claim predicate %q, it should be <name>=<value>", expr)
+		}
+		claims, err := json.Marshal([]map[string]string{{"name": claimName, "value": claimValue}})
+		if err != nil {
+			return err
+		}
+		q.addCondition("r.payload -> 'status' -> 'clusterClaims' @> ?::jsonb", string(claims))
+	case termCompliance:
+		return q.addCompliance(expr)
+	default:
+		return fmt.Errorf("unsupported predicate %s", name)
+	}
+	return nil
+}
+
+func (q *Query) addLabel(expr string) error {
+	const labelExpr = "r.payload -> 'metadata' -> 'labels' ->> ?"
+	switch {
+	case strings.Contains(expr, "!="):
+		key, value, _ := strings.Cut(expr, "!=")
+		if key == "" {
+			return fmt.Errorf("invalid label predicate %q", expr)
+		}
+		// the resource without the label also matches, which is the same as the kubernetes label selector
+		q.addCondition(labelExpr+" IS DISTINCT FROM ?", key, value)
+	case strings.Contains(expr, "="):
+		key, value, _ := strings.Cut(strings.Replace(expr, "==", "=", 1), "=")
+		if key == "" {
+			return fmt.Errorf("invalid label predicate %q", expr)
+		}
+		q.addCondition(labelExpr+" = ?", key, value)
+	case strings.HasPrefix(expr, "!"):
+		q.addCondition(labelExpr+" IS NULL", strings.TrimPrefix(expr, "!"))
+	default:
+		q.addCondition(labelExpr+" IS NOT NULL", expr)
+	}
+	return nil
+}
+
+func (q *Query) addField(expr string) error {
+	operator := ""
+	for _, op := range []string{"!=", "~", "="} {
+		if strings.Contains(expr, op) {
+			operator = op
+			break
+		}
+	}
+	if operator == "" {
+		return fmt.Errorf("invalid field predicate %q, the operator should be =, != or ~", expr)
+	}
+	path, value, _ := strings.Cut(expr, operator)
+	if !fieldPathPattern.MatchString(path) {
+		return fmt.Errorf("invalid field path %q, it should be the dotted keys of the resource", path)
+	}
+	pathArg := "{" + strings.ReplaceAll(path, ".", ",") + "}"
+
+	switch operator {
+	case "!=":
+		q.addCondition("r.payload #>> ?::text[] IS DISTINCT FROM ?", pathArg, value)
+	case "~":
+		q.addCondition("r.payload #>> ?::text[] ILIKE ?", pathArg, "%"+likeEscaper.Replace(value)+"%")
+	default:
+		q.addCondition("r.payload #>> ?::text[] = ?", pathArg, value)
+	}
+	return nil
+}
+
+// addCompliance joins the compliance of the local policies with the clusters or the policies
+func (q *Query) addCompliance(expr string) error {
+	target, state, found := strings.Cut(expr, "=")
+	if !found {
+		target, state = "", expr
+	}
+	compliance, ok := complianceStates[strings.ToLower(state)]
+	if !ok {
+		return fmt.Errorf("invalid compliance state %s, it should be compliant, non_compliant, pending or unknown",
+			state)
+	}
+
+	switch q.Kind {
+	case KindCluster:
+		condition := `EXISTS (SELECT 1 FROM local_status.compliance c
+			JOIN local_spec.policies p ON p.policy_id = c.policy_id AND p.deleted_at IS NULL
+			WHERE c.leaf_hub_name = r.leaf_hub_name AND c.cluster_name = r.cluster_name
+			AND c.compliance = ?::local_status.compliance_type`
+		args := []interface{}{compliance}
+		if target != "" {
+			condition += " AND (p.policy_name = ? OR p.policy_id::text = ?)"
+			args = append(args, target, target)
+		}
+		q.addCondition(condition+")", args...)
+	case KindPolicy:
+		condition := `EXISTS (SELECT 1 FROM local_status.compliance c
+			WHERE c.policy_id = r.policy_id AND c.compliance = ?::local_status.compliance_type`
+		args := []interface{}{compliance}
+		if target != "" {
+			condition += " AND c.cluster_name = ?"
+			args = append(args, target)
+		}
+		q.addCondition(condition+")", args...)
+	default:
+		return fmt.Errorf("the compliance predicate isn't supported for kind %s", q.Kind)
+	}
+	return nil
+}
+
+func (q *Query) addCondition(condition string, args ...interface{}) {
+	q.conditions = append(q.conditions, condition)
+	q.args = append(q.args, args...)
+}
+
+// SQL returns the parameterized SQL of the query, which lists the resources ordered by the name and ID after the
+// last returned resource
+func (q *Query) SQL(limit int, lastName, lastID string) (string, []interface{}) {
+	table := kindTables[q.Kind]
+	conditions := append([]string{"r.deleted_at IS NULL"}, q.conditions...)
+	args := append([]interface{}{}, q.args...)
+	if len(q.hubs) > 0 {
+		conditions = append(conditions, "r.leaf_hub_name IN ?")
+		args = append(args, q.hubs)
+	}
+	if lastID != "" {
+		conditions = append(conditions, fmt.Sprintf("(%s, %s) > (?, ?::uuid)", table.nameColumn, table.idColumn))
+		args = append(args, lastName, lastID)
+	}
+	args = append(args, limit)
+
+	return fmt.Sprintf(`SELECT %[1]s::text AS id, %[2]s AS name, %[3]s AS namespace, r.leaf_hub_name AS hub,
+		r.payload AS object FROM %[4]s r WHERE %[5]s ORDER BY %[2]s, %[1]s LIMIT ?`,
+		table.idColumn, table.nameColumn, table.namespaceExpr, table.table,
+		strings.Join(conditions, " AND ")), args
+}
+
+// tokenize splits the query by the whitespaces out of the double quotes, the quotes are removed from the tokens
+func tokenize(queryStr string) ([]string, error) {
+	tokens := []string{}
+	var builder strings.Builder
+	quoted, inToken := false, false
+	for _, c := range queryStr {
+		switch {
+		case c == '"':
+			quoted, inToken = !quoted, true
+		case !quoted && (c == ' ' || c == '\t' || c == '\n'):
+			if inToken {
+				tokens = append(tokens, builder.String())
+				builder.Reset()
+				inToken = false
+			}
+		default:
+			builder.WriteRune(c)
+			inToken = true
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote in the query")
+	}
+	if inToken {
+		tokens = append(tokens, builder.String())
+	}
+	return tokens, nil
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package search
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQuery(t *testing.T) {
+	cases := []struct {
+		name       string
+		query      string
+		kind       string
+		conditions []string
+		args       []interface{}
+		wantErr    string
+	}{
+		{
+			name:  "empty query",
+			query: "",
+			kind:  KindCluster,
+		},
+		{
+			name:  "clusters with labels, claim and compliance",
+			query: `hub:hub1 label:vendor=OpenShift label:!env claim:version.openshift.io=4.14 compliance:plc1=NonCompliant`,
+			kind:  KindCluster,
+			conditions: []string{
+				"r.payload -> 'metadata' -> 'labels' ->> ? = ?",
+				"r.payload -> 'metadata' -> 'labels' ->> ? IS NULL",
+				"r.payload -> 'status' -> 'clusterClaims' @> ?::jsonb",
+				"EXISTS (SELECT 1 FROM local_status.compliance c",
+			},
+			args: []interface{}{
+				"vendor", "OpenShift", "env", `[{"name":"version.openshift.io","value":"4.14"}]`,
+				"non_compliant", "plc1", "plc1",
+			},
+		},
+		{
+			name:       "policies with quoted field and name wildcard",
+			query:      `kind:policy name:plc_* field:spec.remediationAction~"in form"`,
+			kind:       KindPolicy,
+			conditions: []string{"r.policy_name LIKE ?", "r.payload #>> ?::text[] ILIKE ?"},
+			args:       []interface{}{`plc\_%`, "{spec,remediationAction}", "%in form%"},
+		},
+		{
+			name:    "duplicated kind",
+			query:   "kind:policy kind:cluster",
+			wantErr: "only one kind",
+		},
+		{
+			name:    "unsupported kind",
+			query:   "kind:placement",
+			wantErr: "unsupported kind",
+		},
+		{
+			name:    "invalid field path",
+			query:   "field:spec.a'b=1",
+			wantErr: "invalid field path",
+		},
+		{
+			name:    "label of hub",
+			query:   "kind:hub label:env=dev",
+			wantErr: "isn't supported for kind hub",
+		},
+		{
+			name:    "claim of policy",
+			query:   "kind:policy claim:id.k8s.io=1",
+			wantErr: "only supported for kind cluster",
+		},
+		{
+			name:    "invalid compliance state",
+			query:   "compliance:broken",
+			wantErr: "invalid compliance state",
+		},
+		{
+			name:    "unterminated quote",
+			query:   `name:"mc1`,
+			wantErr: "unterminated quote",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			query, err := ParseQuery(tc.query)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.kind, query.Kind)
+			assert.Len(t, query.conditions, len(tc.conditions))
+			for i, condition := range tc.conditions {
+				assert.True(t, strings.HasPrefix(query.conditions[i], condition), query.conditions[i])
+			}
+			assert.Equal(t, tc.args, query.args)
+		})
+	}
+}
+
+func TestQuerySQL(t *testing.T) {
+	query, err := ParseQuery("kind:hub hub:hub1 hub:hub2")
+	assert.NoError(t, err)
+
+	sql, args := query.SQL(10, "", "")
+	assert.Contains(t, sql, "FROM status.leaf_hubs r WHERE r.deleted_at IS NULL AND r.leaf_hub_name IN ?")
+	assert.Contains(t, sql, "ORDER BY r.leaf_hub_name, r.cluster_id LIMIT ?")
+	assert.Equal(t, []interface{}{[]string{"hub1", "hub2"}, 10}, args)
+
+	sql, args = query.SQL(10, "hub1", "2aa5547c-c172-47ed-b70b-db468c84d327")
+	assert.Contains(t, sql, "AND (r.leaf_hub_name, r.cluster_id) > (?, ?::uuid)")
+	assert.Equal(t, []interface{}{
+		[]string{"hub1", "hub2"}, "hub1", "2aa5547c-c172-47ed-b70b-db468c84d327", 10,
+	}, args)
+}
@@ -0,0 +1,304 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package search
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/authentication"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// SavedSearch is a named search query of the user
+type SavedSearch struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Query       string    `json:"query"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type SavedSearchList struct {
+	Items []SavedSearch `json:"items"`
+}
+
+type savedSearchRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	Query       string `json:"query"`
+}
+
+func toSavedSearch(search *models.SavedSearch) SavedSearch {
+	return SavedSearch{
+		ID:          search.ID,
+		Name:        search.Name,
+		Description: search.Description,
+		Query:       search.Query,
+		CreatedAt:   search.CreatedAt,
+		UpdatedAt:   search.UpdatedAt,
+	}
+}
+
+// bindSavedSearch validates the request, the query must be parsed before it's saved
+func bindSavedSearch(ginCtx *gin.Context) (*savedSearchRequest, bool) {
+	request := &savedSearchRequest{}
+	if err := ginCtx.ShouldBindJSON(request); err != nil {
+		ginCtx.String(http.StatusBadRequest, fmt.Sprintf("invalid saved search: %s", err.Error()))
+		return nil, false
+	}
+	if _, err := ParseQuery(request.Query); err != nil {
+		ginCtx.String(http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	return request, true
+}
+
+// getSavedSearch returns the saved search of the current user, the response is written if it isn't found
+func getSavedSearch(ginCtx *gin.Context) (*models.SavedSearch, bool) {
+	searchID := ginCtx.Param("searchID")
+	if _, err := uuid.Parse(searchID); err != nil {
+		ginCtx.String(http.StatusBadRequest, fmt.Sprintf("invalid saved search ID %s", searchID))
+		return nil, false
+	}
+	search := &models.SavedSearch{}
+	err := database.GetGorm().Where(&models.SavedSearch{ID: searchID}).
+		Where("owner = ?", ginCtx.GetString(authentication.UserKey)).First(search).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ginCtx.String(http.StatusNotFound, fmt.Sprintf("saved search %s isn't found", searchID))
+		return nil, false
+	}
+	if err != nil {
+		ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+		fmt.Fprintf(gin.DefaultWriter, savedSearchFailureFormMsg, err)
+		return nil, false
+	}
+	return search, true
+}
+
+// nameConflicts checks whether the user has another saved search with the same name
+func nameConflicts(ginCtx *gin.Context, owner, name, searchID string) bool {
+	var count int64
+	err := database.GetGorm().Model(&models.SavedSearch{}).
+		Where("owner = ? AND name = ? AND id::text <> ?", owner, name, searchID).Count(&count).Error
+	if err != nil {
+		ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+		fmt.Fprintf(gin.DefaultWriter, savedSearchFailureFormMsg, err)
+		return true
+	}
+	if count > 0 {
+		ginCtx.String(http.StatusConflict, fmt.Sprintf("saved search %s already exists", name))
+		return true
+	}
+	return false
+}
+
+// CreateSavedSearch godoc
+// @summary create saved search
+// @description save the search query with a name for the current user
+// @accept json
+// @produce json
+// @param        search    body    savedSearchRequest    true    "the name, description and query of the saved search"
+// @success      201  {object}  search.SavedSearch
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      409
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /searches [post]
+func CreateSavedSearch() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		request, ok := bindSavedSearch(ginCtx)
+		if !ok {
+			return
+		}
+		owner := ginCtx.GetString(authentication.UserKey)
+		if nameConflicts(ginCtx, owner, request.Name, "") {
+			return
+		}
+
+		search := &models.SavedSearch{
+			ID:          uuid.New().String(),
+			Name:        request.Name,
+			Owner:       owner,
+			Description: request.Description,
+			Query:       request.Query,
+		}
+		if err := database.GetGorm().Create(search).Error; err != nil {
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			fmt.Fprintf(gin.DefaultWriter, savedSearchFailureFormMsg, err)
+			return
+		}
+		ginCtx.JSON(http.StatusCreated, toSavedSearch(search))
+	}
+}
+
+// ListSavedSearches godoc
+// @summary list saved searches
+// @description list the saved searches of the current user
+// @accept json
+// @produce json
+// @success      200  {object}  search.SavedSearchList
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /searches [get]
+func ListSavedSearches() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		searches := []models.SavedSearch{}
+		err := database.GetGorm().Where("owner = ?", ginCtx.GetString(authentication.UserKey)).
+			Order("name").Find(&searches).Error
+		if err != nil {
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			fmt.Fprintf(gin.DefaultWriter, savedSearchFailureFormMsg, err)
+			return
+		}
+
+		list := SavedSearchList{Items: []SavedSearch{}}
+		for i := range searches {
+			list.Items = append(list.Items, toSavedSearch(&searches[i]))
+		}
+		ginCtx.JSON(http.StatusOK, list)
+	}
+}
+
+// GetSavedSearch godoc
+// @summary get saved search
+// @description get the saved search of the current user
+// @accept json
+// @produce json
+// @param        searchID    path    string    true    "Saved Search ID"
+// @success      200  {object}  search.SavedSearch
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /searches/{searchID} [get]
+func GetSavedSearch() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		search, ok := getSavedSearch(ginCtx)
+		if !ok {
+			return
+		}
+		ginCtx.JSON(http.StatusOK, toSavedSearch(search))
+	}
+}
+
+// UpdateSavedSearch godoc
+// @summary update saved search
+// @description update the name, description and query of the saved search
+// @accept json
+// @produce json
+// @param        searchID    path    string    true    "Saved Search ID"
+// @param        search      body    savedSearchRequest    true    "the name, description and query of the saved search"
+// @success      200  {object}  search.SavedSearch
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      409
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /searches/{searchID} [put]
+func UpdateSavedSearch() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		search, ok := getSavedSearch(ginCtx)
+		if !ok {
+			return
+		}
+		request, ok := bindSavedSearch(ginCtx)
+		if !ok {
+			return
+		}
+		if nameConflicts(ginCtx, search.Owner, request.Name, search.ID) {
+			return
+		}
+
+		search.Name, search.Description, search.Query = request.Name, request.Description, request.Query
+		if err := database.GetGorm().Save(search).Error; err != nil {
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			fmt.Fprintf(gin.DefaultWriter, savedSearchFailureFormMsg, err)
+			return
+		}
+		ginCtx.JSON(http.StatusOK, toSavedSearch(search))
+	}
+}
+
+// DeleteSavedSearch godoc
+// @summary delete saved search
+// @description delete the saved search of the current user
+// @accept json
+// @produce json
+// @param        searchID    path    string    true    "Saved Search ID"
+// @success      204
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /searches/{searchID} [delete]
+func DeleteSavedSearch() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		search, ok := getSavedSearch(ginCtx)
+		if !ok {
+			return
+		}
+		if err := database.GetGorm().Delete(search).Error; err != nil {
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			fmt.Fprintf(gin.DefaultWriter, savedSearchFailureFormMsg, err)
+			return
+		}
+		ginCtx.Status(http.StatusNoContent)
+	}
+}
+
+// RunSavedSearch godoc
+// @summary run saved search
+// @description search the resources with the query of the saved search
+// @accept json
+// @produce json
+// @param        searchID    path      string  true   "Saved Search ID"
+// @param        limit       query     int     false  "maximum resource number to receive, 100 by default"
+// @param        continue    query     string  false  "continue token to request next request"
+// @success      200  {object}  search.Result
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /searches/{searchID}/results [get]
+func RunSavedSearch() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		search, ok := getSavedSearch(ginCtx)
+		if !ok {
+			return
+		}
+		query, err := ParseQuery(search.Query)
+		if err != nil {
+			ginCtx.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		runQuery(ginCtx, query)
+	}
+}
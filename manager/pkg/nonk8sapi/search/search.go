@@ -0,0 +1,127 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/util"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+// Item is a resource matched by the search, the object is the resource stored in the database
+type Item struct {
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Namespace string          `json:"namespace,omitempty"`
+	Hub       string          `json:"hub"`
+	Object    json.RawMessage `json:"object"`
+}
+
+type Result struct {
+	Kind     string `json:"kind"`
+	Items    []Item `json:"items"`
+	Continue string `json:"continue,omitempty"`
+}
+
+type itemRow struct {
+	ID        string
+	Name      string
+	Namespace string
+	Hub       string
+	Object    datatypes.JSON
+}
+
+// Search godoc
+// @summary search resources
+// @description search the managed clusters, policies or managed hubs across all the managed hubs with the query language, e.g. "kind:cluster label:vendor=OpenShift claim:version.openshift.io=4.14 compliance:policy1=non_compliant"
+// @accept json
+// @produce json
+// @param        q           query     string  false  "the search query"
+// @param        limit       query     int     false  "maximum resource number to receive, 100 by default"
+// @param        continue    query     string  false  "continue token to request next request"
+// @success      200  {object}  search.Result
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /search [get]
+func Search() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		query, err := ParseQuery(ginCtx.Query("q"))
+		if err != nil {
+			ginCtx.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		runQuery(ginCtx, query)
+	}
+}
+
+// runQuery lists a page of the resources matched by the query with the limit and continue parameters
+func runQuery(ginCtx *gin.Context, query *Query) {
+	limit := defaultLimit
+	if limitStr := ginCtx.Query("limit"); limitStr != "" {
+		var err error
+		if limit, err = strconv.Atoi(limitStr); err != nil || limit <= 0 || limit > maxLimit {
+			ginCtx.String(http.StatusBadRequest, fmt.Sprintf("invalid limit %s, it should be in [1, %d]", limitStr,
+				maxLimit))
+			return
+		}
+	}
+
+	lastName, lastID := "", ""
+	if continueToken := ginCtx.Query("continue"); continueToken != "" {
+		var err error
+		lastName, lastID, err = util.DecodeContinue(continueToken)
+		if err == nil {
+			_, err = uuid.Parse(lastID)
+		}
+		if err != nil {
+			ginCtx.String(http.StatusBadRequest, fmt.Sprintf("invalid continue token %s", continueToken))
+			return
+		}
+	}
+
+	// query one more resource to know whether there is a next page
+	sql, args := query.SQL(limit+1, lastName, lastID)
+	fmt.Fprintf(gin.DefaultWriter, "search query: %v\n", sql)
+	rows := []itemRow{}
+	if err := database.GetGorm().Raw(sql, args...).Scan(&rows).Error; err != nil {
+		ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+		fmt.Fprintf(gin.DefaultWriter, searchFailureFormatMsg, err)
+		return
+	}
+
+	result := Result{Kind: query.Kind, Items: []Item{}}
+	if len(rows) > limit {
+		rows = rows[:limit]
+		last := rows[limit-1]
+		continueToken, err := util.EncodeContinue(last.Name, last.ID)
+		if err != nil {
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			fmt.Fprintf(gin.DefaultWriter, searchFailureFormatMsg, err)
+			return
+		}
+		result.Continue = continueToken
+	}
+	for _, row := range rows {
+		result.Items = append(result.Items, Item{
+			ID:        row.ID,
+			Name:      row.Name,
+			Namespace: row.Namespace,
+			Hub:       row.Hub,
+			Object:    json.RawMessage(row.Object),
+		})
+	}
+	ginCtx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package notifier
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	notifierv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/notifier/v1alpha1"
+)
+
+// mergeAlerts merges the firing alerts into the active alerts of the notifier, the resolved alerts are dropped.
+// The pending sinks of the alert are reset to all the sinks if it's neither silenced nor sent within the
+// repeatInterval, otherwise it keeps the sinks which haven't received it.
+func mergeAlerts(active []notifierv1alpha1.ActiveAlert, firing []Alert, silences []notifierv1alpha1.SilenceWindow,
+	sinks []string, repeatInterval time.Duration, now time.Time,
+) []notifierv1alpha1.ActiveAlert {
+	previous := map[string]notifierv1alpha1.ActiveAlert{}
+	for _, alert := range active {
+		previous[alertKey(alert)] = alert
+	}
+
+	merged := []notifierv1alpha1.ActiveAlert{}
+	seen := map[string]bool{}
+	for _, alert := range firing {
+		key := alert.key()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		activeAlert := notifierv1alpha1.ActiveAlert{
+			Rule:    alert.Rule,
+			Hub:     alert.Hub,
+			Subject: alert.Subject,
+			Message: alert.Message,
+			Since:   metav1.NewTime(alert.Since),
+		}
+		if prev, ok := previous[key]; ok {
+			activeAlert.Since = prev.Since
+			activeAlert.LastNotifiedTime = prev.LastNotifiedTime
+			// the removed sinks don't receive the alert any more
+			for _, sink := range prev.PendingSinks {
+				if containsString(sinks, sink) {
+					activeAlert.PendingSinks = append(activeAlert.PendingSinks, sink)
+				}
+			}
+		}
+		activeAlert.Silenced = isSilenced(alert, silences, now)
+		if !activeAlert.Silenced && (activeAlert.LastNotifiedTime == nil ||
+			now.Sub(activeAlert.LastNotifiedTime.Time) >= repeatInterval) {
+			activeAlert.PendingSinks = append([]string{}, sinks...)
+		}
+		merged = append(merged, activeAlert)
+	}
+	return merged
+}
+
+// alertsToSend returns the alerts to send to the sink, which are pending for it and not silenced
+func alertsToSend(active []notifierv1alpha1.ActiveAlert, sink string) []Alert {
+	alerts := []Alert{}
+	for _, alert := range active {
+		if alert.Silenced || !containsString(alert.PendingSinks, sink) {
+			continue
+		}
+		alerts = append(alerts, Alert{
+			Rule:    alert.Rule,
+			Hub:     alert.Hub,
+			Subject: alert.Subject,
+			Message: alert.Message,
+			Since:   alert.Since.Time,
+		})
+	}
+	return alerts
+}
+
+// markNotified records the time when the alerts were sent to the sink, and removes the sink from their pending sinks
+func markNotified(active []notifierv1alpha1.ActiveAlert, sink string, sent []Alert, now time.Time) {
+	sentKeys := map[string]bool{}
+	for _, alert := range sent {
+		sentKeys[alert.key()] = true
+	}
+	notified := metav1.NewTime(now)
+	for i := range active {
+		if !sentKeys[alertKey(active[i])] {
+			continue
+		}
+		active[i].LastNotifiedTime = &notified
+		var pending []string
+		for _, s := range active[i].PendingSinks {
+			if s != sink {
+				pending = append(pending, s)
+			}
+		}
+		active[i].PendingSinks = pending
+	}
+}
+
+func alertKey(alert notifierv1alpha1.ActiveAlert) string {
+	return Alert{Rule: alert.Rule, Hub: alert.Hub, Subject: alert.Subject}.key()
+}
+
+func isSilenced(alert Alert, silences []notifierv1alpha1.SilenceWindow, now time.Time) bool {
+	for _, silence := range silences {
+		if now.Before(silence.StartTime.Time) || !now.Before(silence.EndTime.Time) {
+			continue
+		}
+		if len(silence.Rules) > 0 && !containsRule(silence.Rules, alert.Rule) {
+			continue
+		}
+		if len(silence.Hubs) > 0 && !containsString(silence.Hubs, alert.Hub) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func containsRule(rules []notifierv1alpha1.RuleType, rule notifierv1alpha1.RuleType) bool {
+	for _, r := range rules {
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
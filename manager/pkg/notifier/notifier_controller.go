@@ -0,0 +1,181 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	notifierv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/notifier/v1alpha1"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+const (
+	defaultEvaluationInterval = time.Minute
+	defaultRepeatInterval     = 4 * time.Hour
+
+	ConditionTypeNotified = "Notified"
+)
+
+var log = ctrl.Log.WithName("global-hub-notifier")
+
+// NotifierReconciler evaluates the rules of the GlobalHubNotifier periodically and sends the alerts to the sinks.
+// The active alerts are kept in the status, so that the alerts aren't sent again within the repeat interval even if
+// the manager is restarted. The sinks which fail to receive an alert are kept in it, and only they receive it again
+// in the next evaluation.
+type NotifierReconciler struct {
+	client.Client
+	producer  transport.Producer
+	evaluator *ruleEvaluator
+	newSink   func(ctx context.Context, c client.Client, namespace string, spec notifierv1alpha1.NotificationSink,
+		producer transport.Producer) (Sink, error)
+	now func() time.Time
+}
+
+func NewNotifierReconciler(c client.Client, producer transport.Producer) *NotifierReconciler {
+	return &NotifierReconciler{
+		Client:    c,
+		producer:  producer,
+		evaluator: newRuleEvaluator(&globalHubSource{client: c}),
+		newSink:   newSink,
+		now:       time.Now,
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NotifierReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).Named("notifierController").
+		For(&notifierv1alpha1.GlobalHubNotifier{},
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
+
+func (r *NotifierReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	notifier := &notifierv1alpha1.GlobalHubNotifier{}
+	if err := r.Get(ctx, req.NamespacedName, notifier); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			r.evaluator.forget(req.String())
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !notifier.DeletionTimestamp.IsZero() {
+		r.evaluator.forget(req.String())
+		return ctrl.Result{}, nil
+	}
+
+	interval := notifier.Spec.EvaluationInterval.Duration
+	if interval <= 0 {
+		interval = defaultEvaluationInterval
+	}
+	repeatInterval := notifier.Spec.RepeatInterval.Duration
+	if repeatInterval <= 0 {
+		repeatInterval = defaultRepeatInterval
+	}
+
+	now := r.now()
+	if last := notifier.Status.LastEvaluationTime; last != nil && now.Sub(last.Time) < interval {
+		return ctrl.Result{RequeueAfter: interval - now.Sub(last.Time)}, nil
+	}
+
+	firing, err := r.evaluator.evaluate(ctx, req.String(), notifier.Spec.Rules, now)
+	if err != nil {
+		log.Error(err, "failed to evaluate the rules", "notifier", req.String())
+		return ctrl.Result{RequeueAfter: interval}, r.updateStatus(ctx, notifier,
+			func(status *notifierv1alpha1.GlobalHubNotifierStatus) {
+				meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+					Type:    ConditionTypeNotified,
+					Status:  metav1.ConditionFalse,
+					Reason:  "EvaluationFailed",
+					Message: err.Error(),
+				})
+			})
+	}
+
+	sinks := []string{}
+	for _, sink := range notifier.Spec.Sinks {
+		sinks = append(sinks, sink.Name)
+	}
+	activeAlerts := mergeAlerts(notifier.Status.ActiveAlerts, firing, notifier.Spec.Silences, sinks,
+		repeatInterval, now)
+	sent, sendErr := r.send(ctx, notifier, activeAlerts, now)
+	// the increase is measured from the observation when its alert is received by all the sinks
+	for _, alert := range activeAlerts {
+		if alert.LastNotifiedTime != nil && len(alert.PendingSinks) == 0 {
+			r.evaluator.advance(req.String(), alert.Rule, alert.Hub)
+		}
+	}
+
+	evaluationTime := metav1.NewTime(now)
+	return ctrl.Result{RequeueAfter: interval}, r.updateStatus(ctx, notifier,
+		func(status *notifierv1alpha1.GlobalHubNotifierStatus) {
+			status.LastEvaluationTime = &evaluationTime
+			status.ActiveAlerts = activeAlerts
+			status.ActiveAlertCount = int32(len(activeAlerts))
+			cond := metav1.Condition{
+				Type:    ConditionTypeNotified,
+				Status:  metav1.ConditionTrue,
+				Reason:  "AlertsNotified",
+				Message: fmt.Sprintf("%d alert(s) are firing, %d alert(s) are sent", len(activeAlerts), sent),
+			}
+			if sendErr != nil {
+				cond.Status = metav1.ConditionFalse
+				cond.Reason = "SinkFailed"
+				cond.Message = sendErr.Error()
+			}
+			meta.SetStatusCondition(&status.Conditions, cond)
+		})
+}
+
+// send sends the pending alerts to each sink, the failed sinks don't block the others. It returns the number of the
+// alerts received by any sink.
+func (r *NotifierReconciler) send(ctx context.Context, notifier *notifierv1alpha1.GlobalHubNotifier,
+	activeAlerts []notifierv1alpha1.ActiveAlert, now time.Time,
+) (int, error) {
+	var errs []error
+	sent := map[string]bool{}
+	for _, sinkSpec := range notifier.Spec.Sinks {
+		alerts := alertsToSend(activeAlerts, sinkSpec.Name)
+		if len(alerts) == 0 {
+			continue
+		}
+		sink, err := r.newSink(ctx, r.Client, notifier.Namespace, sinkSpec, r.producer)
+		if err == nil {
+			err = sink.Send(ctx, alerts)
+		}
+		if err != nil {
+			log.Error(err, "failed to send the alerts", "notifier", notifier.Name, "sink", sinkSpec.Name)
+			errs = append(errs, fmt.Errorf("sink %s: %w", sinkSpec.Name, err))
+			continue
+		}
+		markNotified(activeAlerts, sinkSpec.Name, alerts, now)
+		for _, alert := range alerts {
+			sent[alert.key()] = true
+		}
+		log.Info("sent the alerts", "notifier", notifier.Name, "sink", sinkSpec.Name, "count", len(alerts))
+	}
+	return len(sent), errors.Join(errs...)
+}
+
+func (r *NotifierReconciler) updateStatus(ctx context.Context, notifier *notifierv1alpha1.GlobalHubNotifier,
+	update func(status *notifierv1alpha1.GlobalHubNotifierStatus),
+) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		current := &notifierv1alpha1.GlobalHubNotifier{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(notifier), current); err != nil {
+			return err
+		}
+		update(&current.Status)
+		return r.Status().Update(ctx, current)
+	})
+}
@@ -0,0 +1,285 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	notifierv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/notifier/v1alpha1"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+type fakeSource struct {
+	inactive     []string
+	nonCompliant map[string]int64
+	critical     map[string]int64
+	migrations   []failedMigration
+}
+
+func (s *fakeSource) inactiveHubs(ctx context.Context) ([]string, error) { return s.inactive, nil }
+
+func (s *fakeSource) nonCompliantClusters(ctx context.Context) (map[string]int64, error) {
+	return s.nonCompliant, nil
+}
+
+func (s *fakeSource) criticalAlerts(ctx context.Context) (map[string]int64, error) {
+	return s.critical, nil
+}
+
+func (s *fakeSource) failedMigrations(ctx context.Context) ([]failedMigration, error) {
+	return s.migrations, nil
+}
+
+type fakeSink struct {
+	sent [][]Alert
+	err  error
+}
+
+func (s *fakeSink) Send(ctx context.Context, alerts []Alert) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.sent = append(s.sent, alerts)
+	return nil
+}
+
+func TestRuleEvaluator(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	source := &fakeSource{
+		inactive:     []string{"hub1"},
+		nonCompliant: map[string]int64{"hub1": 2, "hub2": 5},
+		critical:     map[string]int64{"hub1": 1},
+		migrations:   []failedMigration{{name: "migration1", hub: "hub2", message: "timeout"}},
+	}
+	evaluator := newRuleEvaluator(source)
+
+	// the first evaluation only records the baselines of the spike rules
+	alerts, err := evaluator.evaluate(ctx, "default/notifier", nil, now)
+	assert.NoError(t, err)
+	assert.Len(t, alerts, 2)
+	assert.Equal(t, notifierv1alpha1.RuleHubInactive, alerts[0].Rule)
+	assert.Equal(t, notifierv1alpha1.RuleMigrationFailed, alerts[1].Rule)
+	assert.Equal(t, "migration1", alerts[1].Subject)
+
+	source.nonCompliant = map[string]int64{"hub1": 3, "hub2": 9}
+	source.critical = map[string]int64{"hub1": 1}
+	alerts, err = evaluator.evaluate(ctx, "default/notifier", []notifierv1alpha1.NotificationRule{
+		{Type: notifierv1alpha1.RuleComplianceSpike, Threshold: 3},
+		{Type: notifierv1alpha1.RuleStackRoxCriticalIncrease, Threshold: 1},
+	}, now)
+	assert.NoError(t, err)
+	assert.Len(t, alerts, 1)
+	assert.Equal(t, "hub2", alerts[0].Hub)
+	assert.Contains(t, alerts[0].Message, "increased by 4 to 9")
+
+	// the increase of hub1 builds up over the evaluations, and the alert of hub2 keeps firing until it's notified
+	spikeRules := []notifierv1alpha1.NotificationRule{{Type: notifierv1alpha1.RuleComplianceSpike, Threshold: 3}}
+	source.nonCompliant = map[string]int64{"hub1": 5, "hub2": 9}
+	alerts, err = evaluator.evaluate(ctx, "default/notifier", spikeRules, now)
+	assert.NoError(t, err)
+	assert.Len(t, alerts, 2)
+	evaluator.advance("default/notifier", notifierv1alpha1.RuleComplianceSpike, "hub1")
+	evaluator.advance("default/notifier", notifierv1alpha1.RuleComplianceSpike, "hub2")
+	alerts, err = evaluator.evaluate(ctx, "default/notifier", spikeRules, now)
+	assert.NoError(t, err)
+	assert.Empty(t, alerts)
+
+	// the increase is measured from the lowest count
+	source.nonCompliant = map[string]int64{"hub1": 1, "hub2": 9}
+	_, err = evaluator.evaluate(ctx, "default/notifier", spikeRules, now)
+	assert.NoError(t, err)
+	source.nonCompliant = map[string]int64{"hub1": 4, "hub2": 9}
+	alerts, err = evaluator.evaluate(ctx, "default/notifier", spikeRules, now)
+	assert.NoError(t, err)
+	assert.Len(t, alerts, 1)
+	assert.Contains(t, alerts[0].Message, "increased by 3 to 4")
+
+	evaluator.forget("default/notifier")
+	assert.Empty(t, evaluator.baselines)
+	assert.Empty(t, evaluator.observations)
+}
+
+func TestMergeAlerts(t *testing.T) {
+	now := time.Now()
+	firing := []Alert{
+		{Rule: notifierv1alpha1.RuleHubInactive, Hub: "hub1", Message: "hub1 is inactive", Since: now},
+		{Rule: notifierv1alpha1.RuleHubInactive, Hub: "hub2", Message: "hub2 is inactive", Since: now},
+	}
+	silences := []notifierv1alpha1.SilenceWindow{{
+		Hubs:      []string{"hub2"},
+		StartTime: metav1.NewTime(now.Add(-time.Hour)),
+		EndTime:   metav1.NewTime(now.Add(time.Hour)),
+	}}
+	sinks := []string{"slack", "email"}
+
+	active := mergeAlerts(nil, firing, silences, sinks, time.Hour, now)
+	assert.Len(t, active, 2)
+	assert.Equal(t, sinks, active[0].PendingSinks)
+	assert.True(t, active[1].Silenced)
+	assert.Len(t, alertsToSend(active, "slack"), 1)
+	assert.Equal(t, "hub1", alertsToSend(active, "slack")[0].Hub)
+
+	// only the email sink fails to receive the alert
+	markNotified(active, "slack", alertsToSend(active, "slack"), now)
+	assert.NotNil(t, active[0].LastNotifiedTime)
+	assert.Equal(t, []string{"email"}, active[0].PendingSinks)
+	assert.Nil(t, active[1].LastNotifiedTime)
+
+	// the alert is sent to the failed sink again, but not to the others within the repeat interval
+	later := now.Add(10 * time.Minute)
+	active = mergeAlerts(active, firing, silences, sinks, time.Hour, later)
+	assert.Empty(t, alertsToSend(active, "slack"))
+	assert.Len(t, alertsToSend(active, "email"), 1)
+	markNotified(active, "email", alertsToSend(active, "email"), later)
+	assert.Empty(t, active[0].PendingSinks)
+
+	// the notified alert isn't sent again within the repeat interval, and the silence window is expired
+	later = now.Add(2 * time.Hour)
+	firing[0].Since = later
+	active = mergeAlerts(active, firing, silences, sinks, 3*time.Hour, later)
+	assert.Len(t, alertsToSend(active, "slack"), 1)
+	assert.Equal(t, "hub2", alertsToSend(active, "slack")[0].Hub)
+	assert.Equal(t, now.Unix(), active[0].Since.Unix())
+
+	// the resolved alerts are dropped
+	active = mergeAlerts(active, nil, silences, sinks, time.Hour, later)
+	assert.Empty(t, active)
+}
+
+func TestSinks(t *testing.T) {
+	ctx := context.Background()
+	alerts := []Alert{{Rule: notifierv1alpha1.RuleHubInactive, Hub: "hub1", Message: "hub1 is inactive"}}
+
+	bodies := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+	}))
+	defer server.Close()
+
+	c := fake.NewClientBuilder().WithObjects(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "webhook", Namespace: "default"},
+			Data:       map[string][]byte{"url": []byte(server.URL + "\n")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "smtp", Namespace: "default"},
+			Data:       map[string][]byte{"username": []byte("user"), "password": []byte("pass")},
+		},
+	).Build()
+	urlRef := notifierv1alpha1.WebhookSink{URLSecretRef: corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "webhook"}, Key: "url",
+	}}
+
+	sink, err := newSink(ctx, c, "default", notifierv1alpha1.NotificationSink{Name: "webhook", Webhook: &urlRef}, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, sink.Send(ctx, alerts))
+	payload := map[string][]Alert{}
+	assert.NoError(t, json.Unmarshal([]byte(bodies[0]), &payload))
+	assert.Equal(t, "hub1", payload["alerts"][0].Hub)
+
+	sink, err = newSink(ctx, c, "default", notifierv1alpha1.NotificationSink{Name: "slack", Slack: &urlRef}, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, sink.Send(ctx, alerts))
+	assert.Contains(t, bodies[1], `"text":"1 global hub alert(s) firing:`)
+
+	emailSpec := &notifierv1alpha1.EmailSink{
+		Host: "smtp.example.com", From: "globalhub@example.com", To: []string{"admin@example.com"},
+		CredentialSecretName: "smtp",
+	}
+	sink, err = newSink(ctx, c, "default", notifierv1alpha1.NotificationSink{Name: "email", Email: emailSpec}, nil)
+	assert.NoError(t, err)
+	var mailAddr, mailMsg string
+	sink.(*emailSink).sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		mailAddr, mailMsg = addr, string(msg)
+		return nil
+	}
+	assert.NoError(t, sink.Send(ctx, alerts))
+	assert.Equal(t, "smtp.example.com:587", mailAddr)
+	assert.True(t, strings.Contains(mailMsg, "Subject: [Global Hub] 1 alert(s) firing"))
+
+	_, err = newSink(ctx, c, "default", notifierv1alpha1.NotificationSink{Name: "empty"}, nil)
+	assert.Error(t, err)
+}
+
+func TestNotifierReconcile(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	assert.NoError(t, notifierv1alpha1.AddToScheme(scheme))
+	notifier := &notifierv1alpha1.GlobalHubNotifier{
+		ObjectMeta: metav1.ObjectMeta{Name: "notifier", Namespace: "default"},
+		Spec: notifierv1alpha1.GlobalHubNotifierSpec{
+			Rules:              []notifierv1alpha1.NotificationRule{{Type: notifierv1alpha1.RuleHubInactive}},
+			Sinks:              []notifierv1alpha1.NotificationSink{{Name: "fake"}, {Name: "failing"}},
+			EvaluationInterval: metav1.Duration{Duration: time.Minute},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(notifier).
+		WithStatusSubresource(notifier).Build()
+
+	sink, failingSink := &fakeSink{}, &fakeSink{err: errors.New("unavailable")}
+	now := time.Now()
+	r := &NotifierReconciler{
+		Client:    c,
+		evaluator: newRuleEvaluator(&fakeSource{inactive: []string{"hub1"}}),
+		newSink: func(ctx context.Context, c client.Client, namespace string,
+			spec notifierv1alpha1.NotificationSink, producer transport.Producer,
+		) (Sink, error) {
+			if spec.Name == "failing" {
+				return failingSink, nil
+			}
+			return sink, nil
+		},
+		now: func() time.Time { return now },
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "notifier"}}
+
+	result, err := r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, result.RequeueAfter)
+	assert.Len(t, sink.sent, 1)
+
+	current := &notifierv1alpha1.GlobalHubNotifier{}
+	assert.NoError(t, c.Get(ctx, req.NamespacedName, current))
+	assert.Equal(t, int32(1), current.Status.ActiveAlertCount)
+	assert.NotNil(t, current.Status.ActiveAlerts[0].LastNotifiedTime)
+	assert.Equal(t, []string{"failing"}, current.Status.ActiveAlerts[0].PendingSinks)
+
+	// the evaluation isn't due
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.True(t, result.RequeueAfter <= time.Minute)
+	assert.Len(t, sink.sent, 1)
+
+	// the alert is only sent to the recovered sink in the next evaluation
+	failingSink.err = nil
+	now = now.Add(2 * time.Minute)
+	_, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Len(t, sink.sent, 1)
+	assert.Len(t, failingSink.sent, 1)
+
+	// the alert is deduplicated in the next evaluation
+	now = now.Add(2 * time.Minute)
+	_, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Len(t, sink.sent, 1)
+	assert.Len(t, failingSink.sent, 1)
+}
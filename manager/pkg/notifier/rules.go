@@ -0,0 +1,278 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/hubmanagement"
+	migrationv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/migration/v1alpha1"
+	notifierv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/notifier/v1alpha1"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// the rules are evaluated in this order if the notifier doesn't specify them
+var defaultRules = []notifierv1alpha1.NotificationRule{
+	{Type: notifierv1alpha1.RuleHubInactive},
+	{Type: notifierv1alpha1.RuleComplianceSpike, Threshold: 1},
+	{Type: notifierv1alpha1.RuleStackRoxCriticalIncrease, Threshold: 1},
+	{Type: notifierv1alpha1.RuleMigrationFailed},
+}
+
+// Alert is a firing alert of the built-in rule
+type Alert struct {
+	Rule    notifierv1alpha1.RuleType `json:"rule"`
+	Hub     string                    `json:"hub,omitempty"`
+	Subject string                    `json:"subject,omitempty"`
+	Message string                    `json:"message"`
+	Since   time.Time                 `json:"since"`
+}
+
+func (a Alert) key() string {
+	return fmt.Sprintf("%s/%s/%s", a.Rule, a.Hub, a.Subject)
+}
+
+// failedMigration is a managed cluster migration which fails
+type failedMigration struct {
+	name    string
+	hub     string
+	message string
+}
+
+// ruleSource reads the observations from the global hub to evaluate the rules
+type ruleSource interface {
+	inactiveHubs(ctx context.Context) ([]string, error)
+	nonCompliantClusters(ctx context.Context) (map[string]int64, error)
+	criticalAlerts(ctx context.Context) (map[string]int64, error)
+	failedMigrations(ctx context.Context) ([]failedMigration, error)
+}
+
+// ruleEvaluator evaluates the rules, the spike rules compare the current observations with the baselines, so the
+// first evaluation of them doesn't fire any alert. The baseline of a hub only moves forward once the alert of it is
+// notified, so the increase which builds up over several evaluations fires too.
+type ruleEvaluator struct {
+	source ruleSource
+	// notifier/rule -> hub -> count
+	baselines map[string]map[string]int64
+	// notifier/rule -> hub -> count of the last evaluation
+	observations map[string]map[string]int64
+}
+
+func newRuleEvaluator(source ruleSource) *ruleEvaluator {
+	return &ruleEvaluator{
+		source:       source,
+		baselines:    map[string]map[string]int64{},
+		observations: map[string]map[string]int64{},
+	}
+}
+
+func (e *ruleEvaluator) evaluate(ctx context.Context, notifierKey string, rules []notifierv1alpha1.NotificationRule,
+	now time.Time,
+) ([]Alert, error) {
+	if len(rules) == 0 {
+		rules = defaultRules
+	}
+	alerts := []Alert{}
+	for _, rule := range rules {
+		var ruleAlerts []Alert
+		var err error
+		switch rule.Type {
+		case notifierv1alpha1.RuleHubInactive:
+			ruleAlerts, err = e.hubInactive(ctx, now)
+		case notifierv1alpha1.RuleComplianceSpike:
+			ruleAlerts, err = e.increase(ctx, notifierKey, rule, now, e.source.nonCompliantClusters,
+				"the non-compliant clusters increased by %d to %d")
+		case notifierv1alpha1.RuleStackRoxCriticalIncrease:
+			ruleAlerts, err = e.increase(ctx, notifierKey, rule, now, e.source.criticalAlerts,
+				"the critical StackRox alerts increased by %d to %d")
+		case notifierv1alpha1.RuleMigrationFailed:
+			ruleAlerts, err = e.migrationFailed(ctx, now)
+		default:
+			err = fmt.Errorf("unknown rule type %s", rule.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate the rule %s: %w", rule.Type, err)
+		}
+		alerts = append(alerts, ruleAlerts...)
+	}
+	return alerts, nil
+}
+
+// forget drops the baselines of the deleted notifier
+func (e *ruleEvaluator) forget(notifierKey string) {
+	for key := range e.baselines {
+		if strings.HasPrefix(key, notifierKey+"/") {
+			delete(e.baselines, key)
+			delete(e.observations, key)
+		}
+	}
+}
+
+// advance moves the baseline of the hub to the last observation once the alert of the spike rule is notified
+func (e *ruleEvaluator) advance(notifierKey string, rule notifierv1alpha1.RuleType, hub string) {
+	baselineKey := fmt.Sprintf("%s/%s", notifierKey, rule)
+	baseline, ok := e.baselines[baselineKey]
+	if !ok {
+		return
+	}
+	if count, ok := e.observations[baselineKey][hub]; ok {
+		baseline[hub] = count
+	}
+}
+
+func (e *ruleEvaluator) hubInactive(ctx context.Context, now time.Time) ([]Alert, error) {
+	hubs, err := e.source.inactiveHubs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	alerts := []Alert{}
+	for _, hub := range hubs {
+		alerts = append(alerts, Alert{
+			Rule:    notifierv1alpha1.RuleHubInactive,
+			Hub:     hub,
+			Message: fmt.Sprintf("the managed hub %s is inactive", hub),
+			Since:   now,
+		})
+	}
+	return alerts, nil
+}
+
+func (e *ruleEvaluator) increase(ctx context.Context, notifierKey string, rule notifierv1alpha1.NotificationRule,
+	now time.Time, observe func(ctx context.Context) (map[string]int64, error), messageFormat string,
+) ([]Alert, error) {
+	current, err := observe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	threshold := int64(rule.Threshold)
+	if threshold < 1 {
+		threshold = 1
+	}
+	baselineKey := fmt.Sprintf("%s/%s", notifierKey, rule.Type)
+	e.observations[baselineKey] = current
+	baseline, ok := e.baselines[baselineKey]
+	if !ok {
+		baseline = map[string]int64{}
+		for hub, count := range current {
+			baseline[hub] = count
+		}
+		e.baselines[baselineKey] = baseline
+		return nil, nil
+	}
+	for hub := range baseline {
+		if _, ok := current[hub]; !ok {
+			delete(baseline, hub)
+		}
+	}
+
+	alerts := []Alert{}
+	for hub, count := range current {
+		// the baseline follows the decrease, so the increase is measured from the lowest count since the last alert
+		if count < baseline[hub] {
+			baseline[hub] = count
+		}
+		if delta := count - baseline[hub]; delta >= threshold {
+			alerts = append(alerts, Alert{
+				Rule:    rule.Type,
+				Hub:     hub,
+				Message: fmt.Sprintf("managed hub %s: "+messageFormat, hub, delta, count),
+				Since:   now,
+			})
+		}
+	}
+	return alerts, nil
+}
+
+func (e *ruleEvaluator) migrationFailed(ctx context.Context, now time.Time) ([]Alert, error) {
+	migrations, err := e.source.failedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	alerts := []Alert{}
+	for _, migration := range migrations {
+		alerts = append(alerts, Alert{
+			Rule:    notifierv1alpha1.RuleMigrationFailed,
+			Hub:     migration.hub,
+			Subject: migration.name,
+			Message: fmt.Sprintf("the migration %s failed: %s", migration.name, migration.message),
+			Since:   now,
+		})
+	}
+	return alerts, nil
+}
+
+// globalHubSource reads the observations from the database and the migrations from the global hub cluster
+type globalHubSource struct {
+	client client.Client
+}
+
+func (s *globalHubSource) inactiveHubs(ctx context.Context) ([]string, error) {
+	hubs := []string{}
+	err := database.GetGorm().WithContext(ctx).Model(&models.LeafHubHeartbeat{}).
+		Where("status = ?", hubmanagement.HubInactive).Pluck("leaf_hub_name", &hubs).Error
+	return hubs, err
+}
+
+func (s *globalHubSource) nonCompliantClusters(ctx context.Context) (map[string]int64, error) {
+	rows := []struct {
+		LeafHubName string
+		Count       int64
+	}{}
+	err := database.GetGorm().WithContext(ctx).Raw(`SELECT leaf_hub_name, COUNT(DISTINCT cluster_name) AS count
+		FROM local_status.compliance WHERE compliance = 'non_compliant' GROUP BY leaf_hub_name`).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := map[string]int64{}
+	for _, row := range rows {
+		counts[row.LeafHubName] = row.Count
+	}
+	return counts, nil
+}
+
+func (s *globalHubSource) criticalAlerts(ctx context.Context) (map[string]int64, error) {
+	rows := []struct {
+		HubName string
+		Count   int64
+	}{}
+	err := database.GetGorm().WithContext(ctx).Model(&models.SecurityAlertCounts{}).
+		Select("hub_name, SUM(critical) AS count").Group("hub_name").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := map[string]int64{}
+	for _, row := range rows {
+		counts[row.HubName] = row.Count
+	}
+	return counts, nil
+}
+
+// failedMigrations returns the migrations with a false condition whose reason ends with "Failed"
+func (s *globalHubSource) failedMigrations(ctx context.Context) ([]failedMigration, error) {
+	migrations := &migrationv1alpha1.ManagedClusterMigrationList{}
+	if err := s.client.List(ctx, migrations); err != nil {
+		return nil, err
+	}
+	failed := []failedMigration{}
+	for _, migration := range migrations.Items {
+		for _, cond := range migration.Status.Conditions {
+			if cond.Status == metav1.ConditionFalse && strings.HasSuffix(cond.Reason, "Failed") {
+				failed = append(failed, failedMigration{
+					name:    migration.Name,
+					hub:     migration.Spec.To,
+					message: cond.Message,
+				})
+				break
+			}
+		}
+	}
+	return failed, nil
+}
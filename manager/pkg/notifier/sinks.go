@@ -0,0 +1,178 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	notifierv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/notifier/v1alpha1"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// Sink sends the alerts to the notification channel
+type Sink interface {
+	Send(ctx context.Context, alerts []Alert) error
+}
+
+// newSink creates the sink by the spec, the credentials are read from the secrets in the notifier namespace
+func newSink(ctx context.Context, c client.Client, namespace string, spec notifierv1alpha1.NotificationSink,
+	producer transport.Producer,
+) (Sink, error) {
+	switch {
+	case spec.Webhook != nil:
+		url, err := secretValue(ctx, c, namespace, spec.Webhook.URLSecretRef)
+		if err != nil {
+			return nil, err
+		}
+		return &webhookSink{url: url, httpClient: &http.Client{Timeout: 30 * time.Second}}, nil
+	case spec.Slack != nil:
+		url, err := secretValue(ctx, c, namespace, spec.Slack.URLSecretRef)
+		if err != nil {
+			return nil, err
+		}
+		return &slackSink{webhookSink{url: url, httpClient: &http.Client{Timeout: 30 * time.Second}}}, nil
+	case spec.Email != nil:
+		port := spec.Email.Port
+		if port == 0 {
+			port = 587
+		}
+		sink := &emailSink{
+			addr:     net.JoinHostPort(spec.Email.Host, strconv.Itoa(int(port))),
+			from:     spec.Email.From,
+			to:       spec.Email.To,
+			sendMail: smtp.SendMail,
+		}
+		if spec.Email.CredentialSecretName != "" {
+			secret := &corev1.Secret{}
+			if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: spec.Email.CredentialSecretName},
+				secret); err != nil {
+				return nil, err
+			}
+			sink.auth = smtp.PlainAuth("", string(secret.Data["username"]), string(secret.Data["password"]),
+				spec.Email.Host)
+		}
+		return sink, nil
+	case spec.Kafka != nil:
+		if producer == nil {
+			return nil, fmt.Errorf("the transport producer isn't ready")
+		}
+		return &kafkaSink{producer: producer, topic: spec.Kafka.Topic}, nil
+	}
+	return nil, fmt.Errorf("the sink %s doesn't specify any channel", spec.Name)
+}
+
+func secretValue(ctx context.Context, c client.Client, namespace string, selector corev1.SecretKeySelector,
+) (string, error) {
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: selector.Name}, secret); err != nil {
+		return "", err
+	}
+	value, ok := secret.Data[selector.Key]
+	if !ok {
+		return "", fmt.Errorf("the key %s isn't found in the secret %s/%s", selector.Key, namespace, selector.Name)
+	}
+	return strings.TrimSpace(string(value)), nil
+}
+
+// webhookSink posts the alerts as the JSON to the generic webhook
+type webhookSink struct {
+	url        string
+	httpClient *http.Client
+}
+
+func (s *webhookSink) Send(ctx context.Context, alerts []Alert) error {
+	return s.post(ctx, map[string]interface{}{"alerts": alerts})
+}
+
+func (s *webhookSink) post(ctx context.Context, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("the webhook responded %s: %s", resp.Status, string(message))
+	}
+	return nil
+}
+
+// slackSink posts the alerts as the text message to the Slack-compatible incoming webhook
+type slackSink struct {
+	webhookSink
+}
+
+func (s *slackSink) Send(ctx context.Context, alerts []Alert) error {
+	return s.post(ctx, map[string]string{"text": formatAlerts(alerts)})
+}
+
+// emailSink sends the alerts as the plain text email
+type emailSink struct {
+	addr     string
+	from     string
+	to       []string
+	auth     smtp.Auth
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func (s *emailSink) Send(ctx context.Context, alerts []Alert) error {
+	msg := &bytes.Buffer{}
+	fmt.Fprintf(msg, "From: %s\r\n", s.from)
+	fmt.Fprintf(msg, "To: %s\r\n", strings.Join(s.to, ", "))
+	fmt.Fprintf(msg, "Subject: [Global Hub] %d alert(s) firing\r\n", len(alerts))
+	fmt.Fprintf(msg, "Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(formatAlerts(alerts), "\n", "\r\n"))
+	return s.sendMail(s.addr, s.auth, s.from, s.to, msg.Bytes())
+}
+
+// kafkaSink sends the alerts as a cloudevent to the topic of the global hub kafka
+type kafkaSink struct {
+	producer transport.Producer
+	topic    string
+}
+
+func (s *kafkaSink) Send(ctx context.Context, alerts []Alert) error {
+	e := cloudevents.NewEvent()
+	e.SetType(string(enum.GlobalHubAlertType))
+	e.SetSource(constants.CloudEventSourceGlobalHub)
+	if err := e.SetData(cloudevents.ApplicationJSON, alerts); err != nil {
+		return err
+	}
+	return s.producer.SendEvent(cecontext.WithTopic(ctx, s.topic), e)
+}
+
+func formatAlerts(alerts []Alert) string {
+	lines := []string{fmt.Sprintf("%d global hub alert(s) firing:", len(alerts))}
+	for _, alert := range alerts {
+		lines = append(lines, fmt.Sprintf("- [%s] %s (since %s)", alert.Rule, alert.Message,
+			alert.Since.UTC().Format(time.RFC3339)))
+	}
+	return strings.Join(lines, "\n")
+}
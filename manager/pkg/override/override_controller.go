@@ -0,0 +1,278 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package override
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	overridev1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/override/v1alpha1"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+var log = ctrl.Log.WithName("global-hub-override")
+
+var (
+	// ErrHubNotSelected is returned if the managed hub isn't selected by the override
+	ErrHubNotSelected = errors.New("the managed hub isn't selected by the override")
+	// ErrRenderFailed is returned if the target fails to be rendered for the managed hub
+	ErrRenderFailed = errors.New("the target fails to be rendered")
+)
+
+// ConditionTypeRendered is false if the target of the override fails to be rendered for any managed hub
+const ConditionTypeRendered = "Rendered"
+
+// the rendered resources are refreshed periodically, since they depend on the managed hubs and their labels
+var renderInterval = 5 * time.Minute
+
+// OverrideReconciler renders the target of the GlobalHubOverride for the managed hubs and records the hashes of the
+// rendered resources into the status, so that the resources sent to the managed hubs are auditable. The resources are
+// rendered by the spec syncers in the same way before they're sent, and the rendered resource of a managed hub is
+// returned by the non-k8s API.
+type OverrideReconciler struct {
+	client.Client
+	leafHubs func(ctx context.Context) ([]string, error)
+}
+
+func NewOverrideReconciler(c client.Client) *OverrideReconciler {
+	return &OverrideReconciler{
+		Client:   c,
+		leafHubs: databaseLeafHubs,
+	}
+}
+
+func databaseLeafHubs(ctx context.Context) ([]string, error) {
+	leafHubs := []string{}
+	err := database.GetGorm().WithContext(ctx).
+		Raw("SELECT DISTINCT leaf_hub_name FROM status.leaf_hubs WHERE deleted_at IS NULL").
+		Scan(&leafHubs).Error
+	return leafHubs, err
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OverrideReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).Named("overrideController").
+		For(&overridev1alpha1.GlobalHubOverride{},
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&policyv1.Policy{}, handler.EnqueueRequestsFromMapFunc(r.overridesOf(policyv1.Kind)),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&clusterv1beta1.Placement{}, handler.EnqueueRequestsFromMapFunc(r.overridesOf(PlacementKind)),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
+
+// overridesOf enqueues the overrides which target the changed global resource
+func (r *OverrideReconciler) overridesOf(kind string) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		overrideList := &overridev1alpha1.GlobalHubOverrideList{}
+		if err := r.List(ctx, overrideList, client.InNamespace(obj.GetNamespace())); err != nil {
+			log.Error(err, "failed to list the overrides", "namespace", obj.GetNamespace())
+			return nil
+		}
+		requests := []reconcile.Request{}
+		for _, override := range overrideList.Items {
+			if override.Spec.TargetRef.Kind == kind && override.Spec.TargetRef.Name == obj.GetName() {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&override)})
+			}
+		}
+		return requests
+	}
+}
+
+func (r *OverrideReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	override := &overridev1alpha1.GlobalHubOverride{}
+	if err := r.Get(ctx, req.NamespacedName, override); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !override.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	status, err := r.render(ctx, override)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	status.ObservedGeneration = override.Generation
+	// keep the transition time of the unchanged condition
+	status.Conditions = override.Status.Conditions
+	meta.SetStatusCondition(&status.Conditions, renderedCondition(override, status))
+	if err := r.updateStatus(ctx, override, status); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: renderInterval}, nil
+}
+
+// render returns the hashes of the target rendered for the managed hubs which are selected by the override, and the
+// errors of the managed hubs which fail to be rendered
+func (r *OverrideReconciler) render(ctx context.Context, override *overridev1alpha1.GlobalHubOverride,
+) (overridev1alpha1.GlobalHubOverrideStatus, error) {
+	status := overridev1alpha1.GlobalHubOverrideStatus{}
+	target, err := newTarget(override.Spec.TargetRef.Kind)
+	if err != nil {
+		status.Message = err.Error()
+		return status, nil
+	}
+	err = getTarget(ctx, r.Client, override, target)
+	if apierrors.IsNotFound(err) {
+		status.Message = fmt.Sprintf("the %s %s/%s isn't found", override.Spec.TargetRef.Kind, override.Namespace,
+			override.Spec.TargetRef.Name)
+		return status, nil
+	}
+	if err != nil {
+		return status, err
+	}
+
+	resolver, err := NewResolver(ctx, r.Client, override.Spec.TargetRef.Kind)
+	if err != nil {
+		return status, err
+	}
+	leafHubs, err := r.leafHubs(ctx)
+	if err != nil {
+		return status, err
+	}
+	sort.Strings(leafHubs)
+
+	for _, hub := range leafHubs {
+		rendered, err := resolver.Render(target, hub)
+		if err != nil {
+			status.RenderedHubs = append(status.RenderedHubs, overridev1alpha1.RenderedHub{
+				LeafHubName: hub, Error: err.Error(),
+			})
+			continue
+		}
+		if !resolver.Selects(override, hub) {
+			continue
+		}
+		renderedBytes, err := cleanup(rendered).MarshalJSON()
+		if err != nil {
+			return status, err
+		}
+		hash := sha256.Sum256(renderedBytes)
+		status.RenderedHubs = append(status.RenderedHubs, overridev1alpha1.RenderedHub{
+			LeafHubName: hub, Hash: hex.EncodeToString(hash[:]),
+		})
+	}
+	return status, nil
+}
+
+// RenderedTarget returns the target of the override rendered for the managed hub, which is the resource sent to the
+// managed hub. The sha256 hash of the returned JSON is the hash of the managed hub in the status.renderedHubs.
+func RenderedTarget(ctx context.Context, c client.Client, override *overridev1alpha1.GlobalHubOverride, hub string,
+) ([]byte, error) {
+	target, err := newTarget(override.Spec.TargetRef.Kind)
+	if err != nil {
+		return nil, err
+	}
+	if err := getTarget(ctx, c, override, target); err != nil {
+		return nil, err
+	}
+	resolver, err := NewResolver(ctx, c, override.Spec.TargetRef.Kind)
+	if err != nil {
+		return nil, err
+	}
+	if !resolver.Selects(override, hub) {
+		return nil, ErrHubNotSelected
+	}
+	rendered, err := resolver.Render(target, hub)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRenderFailed, err)
+	}
+	return cleanup(rendered).MarshalJSON()
+}
+
+// getTarget reads the target of the override into the object of the target kind
+func getTarget(ctx context.Context, c client.Client, override *overridev1alpha1.GlobalHubOverride,
+	target client.Object,
+) error {
+	gvk := target.GetObjectKind().GroupVersionKind()
+	err := c.Get(ctx, types.NamespacedName{Namespace: override.Namespace, Name: override.Spec.TargetRef.Name}, target)
+	if err != nil {
+		return err
+	}
+	// the type meta isn't returned by the client
+	target.GetObjectKind().SetGroupVersionKind(gvk)
+	return nil
+}
+
+// renderedCondition reports the target isn't rendered, or the managed hubs which the target fails to be rendered for,
+// the resource isn't sent to these managed hubs
+func renderedCondition(override *overridev1alpha1.GlobalHubOverride,
+	status overridev1alpha1.GlobalHubOverrideStatus,
+) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               ConditionTypeRendered,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: override.Generation,
+		Reason:             "Rendered",
+		Message:            "The target is rendered for the managed hubs",
+	}
+	if status.Message != "" {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "TargetNotRendered"
+		condition.Message = status.Message
+		return condition
+	}
+	failedHubs := []string{}
+	for _, hub := range status.RenderedHubs {
+		if hub.Error != "" {
+			failedHubs = append(failedHubs, hub.LeafHubName)
+		}
+	}
+	if len(failedHubs) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "RenderFailed"
+		condition.Message = fmt.Sprintf("The target fails to be rendered for the managed hubs: %s",
+			strings.Join(failedHubs, ", "))
+	}
+	return condition
+}
+
+// cleanup removes the status and the metadata maintained by the api server, which aren't sent to the managed hubs
+func cleanup(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	unstructured.RemoveNestedField(obj.Object, "status")
+	obj.SetManagedFields(nil)
+	obj.SetResourceVersion("")
+	obj.SetUID("")
+	obj.SetGeneration(0)
+	unstructured.RemoveNestedField(obj.Object, "metadata", "creationTimestamp")
+	return obj
+}
+
+func (r *OverrideReconciler) updateStatus(ctx context.Context, override *overridev1alpha1.GlobalHubOverride,
+	status overridev1alpha1.GlobalHubOverrideStatus,
+) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		existing := &overridev1alpha1.GlobalHubOverride{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(override), existing); err != nil {
+			return err
+		}
+		if equality.Semantic.DeepEqual(existing.Status, status) {
+			return nil
+		}
+		existing.Status = status
+		return r.Status().Update(ctx, existing)
+	})
+}
@@ -0,0 +1,204 @@
+package override
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	overridev1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/override/v1alpha1"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, overridev1alpha1.AddToScheme(scheme))
+	assert.NoError(t, policyv1.AddToScheme(scheme))
+	assert.NoError(t, clusterv1beta1.AddToScheme(scheme))
+	assert.NoError(t, clusterv1.AddToScheme(scheme))
+	return scheme
+}
+
+func hubCluster(name string, labels map[string]string) *clusterv1.ManagedCluster {
+	return &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func globalPolicy() *policyv1.Policy {
+	policy := &policyv1.Policy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "policy1", Namespace: "default",
+			Annotations: map[string]string{constants.SpecVersionAnnotation: "2024-01-01T00:00:00Z"},
+		},
+		Spec: policyv1.PolicySpec{
+			RemediationAction: policyv1.Inform,
+			PolicyTemplates: []*policyv1.PolicyTemplate{{ObjectDefinition: runtime.RawExtension{
+				Raw: []byte(`{"kind":"ConfigurationPolicy","spec":{"image":` +
+					`"{{globalhub .Parameters.registry globalhub}}/app:{{globalhub .LeafHubName globalhub}}",` +
+					`"hubTemplate":"{{hub .ManagedClusterName hub}}"}}`),
+			}}},
+		},
+	}
+	policy.SetGroupVersionKind(policyv1.GroupVersion.WithKind(policyv1.Kind))
+	return policy
+}
+
+func globalHubOverride() *overridev1alpha1.GlobalHubOverride {
+	return &overridev1alpha1.GlobalHubOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "override1", Namespace: "default", Generation: 1},
+		Spec: overridev1alpha1.GlobalHubOverrideSpec{
+			TargetRef: overridev1alpha1.OverrideTargetReference{Kind: policyv1.Kind, Name: "policy1"},
+			Overrides: []overridev1alpha1.HubOverride{
+				{
+					Parameters: map[string]string{"registry": "quay.io"},
+				},
+				{
+					HubSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"region": "apac"}},
+					Parameters:  map[string]string{"registry": "registry.apac.example.com"},
+					Patches: []overridev1alpha1.JSONPatchOperation{{
+						Op: "replace", Path: "/spec/remediationAction",
+						Value: &apiextensionsv1.JSON{Raw: []byte(`"Enforce"`)},
+					}},
+				},
+			},
+		},
+	}
+}
+
+func TestResolverRender(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(globalHubOverride(),
+		hubCluster("hub1", map[string]string{"region": "apac"}), hubCluster("hub2", nil)).Build()
+
+	resolver, err := NewResolver(ctx, c, policyv1.Kind)
+	assert.NoError(t, err)
+	policy := globalPolicy()
+	assert.True(t, resolver.Overridden(policy))
+	placementResolver, err := NewResolver(ctx, c, PlacementKind)
+	assert.NoError(t, err)
+	assert.False(t, placementResolver.Overridden(policy))
+
+	image := func(rendered *unstructured.Unstructured) string {
+		templates, _, _ := unstructured.NestedSlice(rendered.Object, "spec", "policy-templates")
+		val, _, _ := unstructured.NestedString(templates[0].(map[string]interface{}),
+			"objectDefinition", "spec", "image")
+		return val
+	}
+
+	rendered, err := resolver.Render(policy, "hub1")
+	assert.NoError(t, err)
+	assert.Equal(t, "registry.apac.example.com/app:hub1", image(rendered))
+	remediation, _, _ := unstructured.NestedString(rendered.Object, "spec", "remediationAction")
+	assert.Equal(t, string(policyv1.Enforce), remediation)
+	// the managed cluster templates are kept
+	templates, _, _ := unstructured.NestedSlice(rendered.Object, "spec", "policy-templates")
+	hubTemplate, _, _ := unstructured.NestedString(templates[0].(map[string]interface{}),
+		"objectDefinition", "spec", "hubTemplate")
+	assert.Equal(t, "{{hub .ManagedClusterName hub}}", hubTemplate)
+	hub1Version := rendered.GetAnnotations()[constants.SpecVersionAnnotation]
+	assert.True(t, strings.HasPrefix(hub1Version, "2024-01-01T00:00:00Z+"))
+
+	rendered, err = resolver.Render(policy, "hub2")
+	assert.NoError(t, err)
+	assert.Equal(t, "quay.io/app:hub2", image(rendered))
+	remediation, _, _ = unstructured.NestedString(rendered.Object, "spec", "remediationAction")
+	assert.Equal(t, string(policyv1.Inform), remediation)
+	assert.NotEqual(t, hub1Version, rendered.GetAnnotations()[constants.SpecVersionAnnotation])
+	// the global resource isn't changed by the rendering
+	assert.Equal(t, policyv1.Inform, policy.Spec.RemediationAction)
+
+	// the version is changed once the hub labels are changed
+	cluster := &clusterv1.ManagedCluster{}
+	assert.NoError(t, c.Get(ctx, client.ObjectKey{Name: "hub2"}, cluster))
+	cluster.Labels = map[string]string{"region": "apac"}
+	assert.NoError(t, c.Update(ctx, cluster))
+	reloaded, err := NewResolver(ctx, c, policyv1.Kind)
+	assert.NoError(t, err)
+	assert.NotEqual(t, resolver.Version(), reloaded.Version())
+
+	// the template referencing the missing parameter fails to be rendered
+	override := globalHubOverride()
+	override.Spec.Overrides = override.Spec.Overrides[1:]
+	resolver.overrides = map[client.ObjectKey][]overridev1alpha1.GlobalHubOverride{
+		client.ObjectKeyFromObject(policy): {*override},
+	}
+	_, err = resolver.Render(policy, "hub2")
+	assert.ErrorContains(t, err, "registry")
+}
+
+func TestOverrideReconciler(t *testing.T) {
+	ctx := context.Background()
+	override := globalHubOverride()
+	c := fake.NewClientBuilder().WithScheme(newScheme(t)).WithStatusSubresource(override).WithObjects(override,
+		hubCluster("hub1", map[string]string{"region": "apac"}), hubCluster("hub2", nil)).Build()
+	r := &OverrideReconciler{
+		Client:   c,
+		leafHubs: func(ctx context.Context) ([]string, error) { return []string{"hub3", "hub2", "hub1"}, nil },
+	}
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(override)}
+
+	// the target isn't found
+	_, err := r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.NoError(t, c.Get(ctx, req.NamespacedName, override))
+	assert.Contains(t, override.Status.Message, "isn't found")
+	assert.Empty(t, override.Status.RenderedHubs)
+
+	assert.NoError(t, c.Create(ctx, globalPolicy()))
+	result, err := r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, renderInterval, result.RequeueAfter)
+	assert.NoError(t, c.Get(ctx, req.NamespacedName, override))
+	assert.Empty(t, override.Status.Message)
+	assert.Equal(t, int64(1), override.Status.ObservedGeneration)
+	// the first hub override selects all the managed hubs
+	assert.Len(t, override.Status.RenderedHubs, 3)
+	hub1 := override.Status.RenderedHubs[0]
+	assert.Equal(t, "hub1", hub1.LeafHubName)
+	assert.Empty(t, hub1.Error)
+	// the hashes of the rendered resources are recorded instead of the resources
+	assert.Len(t, hub1.Hash, 64)
+	assert.NotEqual(t, hub1.Hash, override.Status.RenderedHubs[1].Hash)
+	assert.True(t, meta.IsStatusConditionTrue(override.Status.Conditions, ConditionTypeRendered))
+
+	// the rendered resource is returned for the selected managed hub, and it matches the hash in the status
+	rendered, err := RenderedTarget(ctx, c, override, "hub1")
+	assert.NoError(t, err)
+	hash := sha256.Sum256(rendered)
+	assert.Equal(t, hub1.Hash, hex.EncodeToString(hash[:]))
+	assert.Contains(t, string(rendered), "registry.apac.example.com/app:hub1")
+
+	// the managed hubs which the target fails to be rendered for are reported in the condition
+	override.Spec.Overrides = override.Spec.Overrides[1:]
+	override.Generation = 2
+	assert.NoError(t, c.Update(ctx, override))
+	_, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.NoError(t, c.Get(ctx, req.NamespacedName, override))
+	assert.Len(t, override.Status.RenderedHubs, 3)
+	assert.NotEmpty(t, override.Status.RenderedHubs[1].Error)
+	condition := meta.FindStatusCondition(override.Status.Conditions, ConditionTypeRendered)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, "RenderFailed", condition.Reason)
+	assert.Contains(t, condition.Message, "hub2, hub3")
+	_, err = RenderedTarget(ctx, c, override, "hub2")
+	assert.ErrorIs(t, err, ErrHubNotSelected)
+
+	// the requests are mapped from the target
+	requests := r.overridesOf(policyv1.Kind)(ctx, globalPolicy())
+	assert.Equal(t, []ctrl.Request{req}, requests)
+	assert.Empty(t, r.overridesOf(PlacementKind)(ctx, globalPolicy()))
+}
@@ -0,0 +1,246 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package override
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	overridev1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/override/v1alpha1"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+const (
+	PlacementKind = "Placement"
+
+	templateLeftDelim  = "{{globalhub"
+	templateRightDelim = "globalhub}}"
+)
+
+// newTarget returns the global resource of the kind which can be overridden
+func newTarget(kind string) (client.Object, error) {
+	var obj client.Object
+	switch kind {
+	case policyv1.Kind:
+		obj = &policyv1.Policy{}
+		obj.GetObjectKind().SetGroupVersionKind(policyv1.GroupVersion.WithKind(policyv1.Kind))
+	case PlacementKind:
+		obj = &clusterv1beta1.Placement{}
+		obj.GetObjectKind().SetGroupVersionKind(clusterv1beta1.GroupVersion.WithKind(PlacementKind))
+	default:
+		return nil, fmt.Errorf("the kind %s can't be overridden", kind)
+	}
+	return obj, nil
+}
+
+// templateData is referenced by the templates in the string values of the global resource
+type templateData struct {
+	LeafHubName string
+	HubLabels   map[string]string
+	Parameters  map[string]string
+}
+
+// Resolver renders the global resources of a kind for the managed hubs by the GlobalHubOverrides. The managed hubs
+// are selected by the labels of their managed clusters on the global hub.
+type Resolver struct {
+	kind string
+	// overrides of the global resources, they're ordered by the name
+	overrides map[types.NamespacedName][]overridev1alpha1.GlobalHubOverride
+	hubLabels map[string]labels.Set
+	version   string
+}
+
+// NewResolver loads the overrides of the kind and the labels of the managed hubs
+func NewResolver(ctx context.Context, c client.Client, kind string) (*Resolver, error) {
+	overrideList := &overridev1alpha1.GlobalHubOverrideList{}
+	if err := c.List(ctx, overrideList); err != nil {
+		return nil, fmt.Errorf("failed to list the overrides - %w", err)
+	}
+	clusterList := &clusterv1.ManagedClusterList{}
+	if err := c.List(ctx, clusterList); err != nil {
+		return nil, fmt.Errorf("failed to list the managed clusters - %w", err)
+	}
+
+	r := &Resolver{
+		kind:      kind,
+		overrides: map[types.NamespacedName][]overridev1alpha1.GlobalHubOverride{},
+		hubLabels: map[string]labels.Set{},
+	}
+	sort.Slice(overrideList.Items, func(i, j int) bool {
+		return overrideList.Items[i].Name < overrideList.Items[j].Name
+	})
+	hash := sha256.New()
+	for _, override := range overrideList.Items {
+		if override.Spec.TargetRef.Kind != kind || !override.DeletionTimestamp.IsZero() {
+			continue
+		}
+		target := types.NamespacedName{Namespace: override.Namespace, Name: override.Spec.TargetRef.Name}
+		r.overrides[target] = append(r.overrides[target], override)
+		fmt.Fprintf(hash, "%s/%s/%s/%d;", override.Namespace, override.Name, override.UID, override.Generation)
+	}
+	sort.Slice(clusterList.Items, func(i, j int) bool {
+		return clusterList.Items[i].Name < clusterList.Items[j].Name
+	})
+	for _, cluster := range clusterList.Items {
+		r.hubLabels[cluster.Name] = labels.Set(cluster.Labels)
+		fmt.Fprintf(hash, "%s:%s;", cluster.Name, labels.Set(cluster.Labels).String())
+	}
+	r.version = fmt.Sprintf("%x", hash.Sum(nil))
+	return r, nil
+}
+
+// Version changes once the overrides or the labels of the managed hubs are changed
+func (r *Resolver) Version() string {
+	return r.version
+}
+
+// Overridden returns true if the global resource has any override, then it's rendered for each managed hub
+func (r *Resolver) Overridden(object metav1.Object) bool {
+	return len(r.overrides[types.NamespacedName{Namespace: object.GetNamespace(), Name: object.GetName()}]) > 0
+}
+
+// Selects returns true if the managed hub is selected by any of the hub overrides
+func (r *Resolver) Selects(override *overridev1alpha1.GlobalHubOverride, hub string) bool {
+	for i := range override.Spec.Overrides {
+		if r.selects(&override.Spec.Overrides[i], hub) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Resolver) selects(hubOverride *overridev1alpha1.HubOverride, hub string) bool {
+	if len(hubOverride.LeafHubNames) == 0 && hubOverride.HubSelector == nil {
+		return true
+	}
+	for _, name := range hubOverride.LeafHubNames {
+		if name == hub {
+			return true
+		}
+	}
+	if hubOverride.HubSelector == nil {
+		return false
+	}
+	selector, err := metav1.LabelSelectorAsSelector(hubOverride.HubSelector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(r.hubLabels[hub])
+}
+
+// Render applies the patches of the hub overrides which select the managed hub to the global resource, and then
+// renders the templates in its string values. The spec version of the rendered resource is suffixed by its hash, so
+// that the agent applies it once the rendered result is changed.
+func (r *Resolver) Render(object metav1.Object, hub string) (*unstructured.Unstructured, error) {
+	doc, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+
+	data := templateData{LeafHubName: hub, HubLabels: r.hubLabels[hub], Parameters: map[string]string{}}
+	overrides := r.overrides[types.NamespacedName{Namespace: object.GetNamespace(), Name: object.GetName()}]
+	for _, override := range overrides {
+		for i, hubOverride := range override.Spec.Overrides {
+			if !r.selects(&hubOverride, hub) {
+				continue
+			}
+			for key, val := range hubOverride.Parameters {
+				data.Parameters[key] = val
+			}
+			if len(hubOverride.Patches) == 0 {
+				continue
+			}
+			if doc, err = applyPatches(doc, hubOverride.Patches); err != nil {
+				return nil, fmt.Errorf("failed to apply the patches of the override %s[%d] - %w", override.Name, i, err)
+			}
+		}
+	}
+
+	rendered := &unstructured.Unstructured{}
+	if err := rendered.UnmarshalJSON(doc); err != nil {
+		return nil, err
+	}
+	content, err := renderTemplates(rendered.Object, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render the templates - %w", err)
+	}
+	rendered.Object = content.(map[string]interface{})
+
+	annotations := rendered.GetAnnotations()
+	if version, found := annotations[constants.SpecVersionAnnotation]; found {
+		renderedBytes, err := rendered.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		hash := sha256.Sum256(renderedBytes)
+		annotations[constants.SpecVersionAnnotation] = fmt.Sprintf("%s+%x", version, hash[:4])
+		rendered.SetAnnotations(annotations)
+	}
+	return rendered, nil
+}
+
+func applyPatches(doc []byte, operations []overridev1alpha1.JSONPatchOperation) ([]byte, error) {
+	patchBytes, err := json.Marshal(operations)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := jsonpatch.DecodePatch(patchBytes)
+	if err != nil {
+		return nil, err
+	}
+	return patch.Apply(doc)
+}
+
+// renderTemplates renders the templates delimited by "{{globalhub" and "globalhub}}" in the string values
+func renderTemplates(content interface{}, data templateData) (interface{}, error) {
+	switch val := content.(type) {
+	case map[string]interface{}:
+		for key, item := range val {
+			rendered, err := renderTemplates(item, data)
+			if err != nil {
+				return nil, err
+			}
+			val[key] = rendered
+		}
+	case []interface{}:
+		for i, item := range val {
+			rendered, err := renderTemplates(item, data)
+			if err != nil {
+				return nil, err
+			}
+			val[i] = rendered
+		}
+	case string:
+		if !strings.Contains(val, templateLeftDelim) {
+			return val, nil
+		}
+		tmpl, err := template.New("").Delims(templateLeftDelim, templateRightDelim).Option("missingkey=error").
+			Parse(val)
+		if err != nil {
+			return nil, err
+		}
+		buf := &bytes.Buffer{}
+		if err := tmpl.Execute(buf, data); err != nil {
+			return nil, err
+		}
+		return buf.String(), nil
+	}
+	return content, nil
+}
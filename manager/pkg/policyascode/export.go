@@ -0,0 +1,192 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package policyascode
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+// Options selects the global resources to export or import
+type Options struct {
+	// Namespaces limits the resources to the namespaces, all the namespaces if it's empty
+	Namespaces []string
+	// LabelSelector selects the resources by the labels, it's combined with the global resource label
+	LabelSelector labels.Selector
+}
+
+// ExportResult is the files changed by the export, the paths are relative to the directory
+type ExportResult struct {
+	Written []string
+	Removed []string
+}
+
+// Export writes the selected global resources to the directory, one file per object. The files of the resources
+// which don't exist anymore are removed, so the directory is a mirror of the global hub.
+func Export(ctx context.Context, c client.Client, dir string, opts Options) (*ExportResult, error) {
+	objects, err := listGlobalResources(ctx, c, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	result := &ExportResult{}
+	exported := map[string]bool{}
+	for _, obj := range objects {
+		kind, _ := kindByGVK(obj.GroupVersionKind())
+		path := objectPath(kind, obj.GetNamespace(), obj.GetName())
+		data, err := marshal(canonicalize(obj))
+		if err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(path)), 0o755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(filepath.Join(dir, path), data, 0o644); err != nil {
+			return nil, err
+		}
+		exported[path] = true
+		result.Written = append(result.Written, path)
+	}
+
+	existing, err := readLayout(dir, opts.Namespaces)
+	if err != nil {
+		return nil, err
+	}
+	for path := range existing {
+		if exported[path] || !matchLabels(existing[path], opts.LabelSelector) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, path)); err != nil {
+			return nil, err
+		}
+		result.Removed = append(result.Removed, path)
+	}
+	sort.Strings(result.Removed)
+	return result, nil
+}
+
+// listGlobalResources lists the selected global resources ordered by the kind, namespace and name
+func listGlobalResources(ctx context.Context, c client.Client, opts Options) ([]*unstructured.Unstructured, error) {
+	globalResource, err := labels.NewRequirement(constants.GlobalHubGlobalResourceLabel, selection.Exists, nil)
+	if err != nil {
+		return nil, err
+	}
+	selector := labels.NewSelector().Add(*globalResource)
+	if opts.LabelSelector != nil {
+		requirements, _ := opts.LabelSelector.Requirements()
+		selector = selector.Add(requirements...)
+	}
+
+	namespaces := opts.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{""}
+	}
+
+	objects := []*unstructured.Unstructured{}
+	for _, kind := range kinds {
+		kindObjects := []*unstructured.Unstructured{}
+		for _, namespace := range namespaces {
+			list := &unstructured.UnstructuredList{}
+			list.SetGroupVersionKind(kind.gvk.GroupVersion().WithKind(kind.gvk.Kind + "List"))
+			err := c.List(ctx, list, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list %s: %w", kind.dir, err)
+			}
+			for i := range list.Items {
+				obj := &list.Items[i]
+				obj.SetGroupVersionKind(kind.gvk)
+				kindObjects = append(kindObjects, obj)
+			}
+		}
+		sort.Slice(kindObjects, func(i, j int) bool {
+			if kindObjects[i].GetNamespace() != kindObjects[j].GetNamespace() {
+				return kindObjects[i].GetNamespace() < kindObjects[j].GetNamespace()
+			}
+			return kindObjects[i].GetName() < kindObjects[j].GetName()
+		})
+		objects = append(objects, kindObjects...)
+	}
+	return objects, nil
+}
+
+// readLayout reads the objects from the directory layout, the key is the relative path of the file
+func readLayout(dir string, namespaces []string) (map[string]*unstructured.Unstructured, error) {
+	objects := map[string]*unstructured.Unstructured{}
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		parts := splitPath(rel)
+		// skip the hidden directories like ".git"
+		if entry.IsDir() && len(parts) > 0 && parts[len(parts)-1][0] == '.' {
+			return filepath.SkipDir
+		}
+		if entry.IsDir() || len(parts) != 3 || filepath.Ext(rel) != fileExtension {
+			return nil
+		}
+		kind, ok := kindByDir(parts[1])
+		if !ok || (len(namespaces) > 0 && !contains(namespaces, parts[0])) {
+			return nil
+		}
+
+		obj, err := readObject(path)
+		if err != nil {
+			return err
+		}
+		name := parts[2][:len(parts[2])-len(fileExtension)]
+		if obj.GroupVersionKind() != kind.gvk || obj.GetNamespace() != parts[0] || obj.GetName() != name {
+			return fmt.Errorf("%s: the object %s %s/%s doesn't match the path, expect %s %s/%s", rel,
+				obj.GetKind(), obj.GetNamespace(), obj.GetName(), kind.gvk.Kind, parts[0], name)
+		}
+		objects[rel] = obj
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+func splitPath(path string) []string {
+	if path == "." {
+		return nil
+	}
+	parts := []string{}
+	for path != "." && path != string(filepath.Separator) {
+		parts = append([]string{filepath.Base(path)}, parts...)
+		path = filepath.Dir(path)
+	}
+	return parts
+}
+
+func matchLabels(obj *unstructured.Unstructured, selector labels.Selector) bool {
+	return selector == nil || selector.Matches(labels.Set(obj.GetLabels()))
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,246 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package policyascode
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// Action is the operation required to make the global hub match the directory
+type Action string
+
+const (
+	ActionCreate    Action = "create"
+	ActionUpdate    Action = "update"
+	ActionDelete    Action = "delete"
+	ActionUnchanged Action = "unchanged"
+)
+
+// Change is the difference of an object between the directory and the global hub
+type Change struct {
+	Action    Action
+	Kind      string
+	Namespace string
+	Name      string
+	// Path is the relative path of the file in the directory
+	Path string
+	// Diff is the unified diff from the global hub to the directory
+	Diff string
+
+	desired  *unstructured.Unstructured
+	existing *unstructured.Unstructured
+}
+
+// ImportOptions selects the resources to import
+type ImportOptions struct {
+	Options
+	// Prune deletes the global resources which don't exist in the directory
+	Prune bool
+}
+
+// Plan compares the directory with the global hub and returns the changes ordered by the kind, namespace and name.
+// Nothing is changed on the global hub.
+func Plan(ctx context.Context, c client.Client, dir string, opts ImportOptions) ([]Change, error) {
+	desired, err := readLayout(dir, opts.Namespaces)
+	if err != nil {
+		return nil, err
+	}
+	objects, err := listGlobalResources(ctx, c, opts.Options)
+	if err != nil {
+		return nil, err
+	}
+	existing := map[string]*unstructured.Unstructured{}
+	for _, obj := range objects {
+		kind, _ := kindByGVK(obj.GroupVersionKind())
+		existing[objectPath(kind, obj.GetNamespace(), obj.GetName())] = obj
+	}
+
+	changes := []Change{}
+	for path, obj := range desired {
+		if !matchLabels(obj, opts.LabelSelector) {
+			continue
+		}
+		change := Change{
+			Kind:      obj.GetKind(),
+			Namespace: obj.GetNamespace(),
+			Name:      obj.GetName(),
+			Path:      path,
+			desired:   canonicalize(obj),
+		}
+		current, ok := existing[path]
+		if !ok {
+			// the object might exist without the global resource label
+			current = &unstructured.Unstructured{}
+			current.SetGroupVersionKind(obj.GroupVersionKind())
+			err := c.Get(ctx, client.ObjectKeyFromObject(obj), current)
+			if err != nil && client.IgnoreNotFound(err) != nil {
+				return nil, fmt.Errorf("failed to get %s %s/%s: %w", obj.GetKind(), obj.GetNamespace(),
+					obj.GetName(), err)
+			}
+			if err != nil {
+				current = nil
+			}
+		}
+		change.existing = current
+
+		var before *unstructured.Unstructured
+		if current != nil {
+			before = canonicalize(current)
+		}
+		change.Diff, err = diff(path, before, change.desired)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case current == nil:
+			change.Action = ActionCreate
+		case change.Diff == "":
+			change.Action = ActionUnchanged
+		default:
+			change.Action = ActionUpdate
+		}
+		changes = append(changes, change)
+	}
+
+	if opts.Prune {
+		for path, obj := range existing {
+			if _, ok := desired[path]; ok {
+				continue
+			}
+			change := Change{
+				Action:    ActionDelete,
+				Kind:      obj.GetKind(),
+				Namespace: obj.GetNamespace(),
+				Name:      obj.GetName(),
+				Path:      path,
+				existing:  obj,
+			}
+			change.Diff, err = diff(path, canonicalize(obj), nil)
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, change)
+		}
+	}
+
+	sortChanges(changes)
+	return changes, nil
+}
+
+// Apply makes the changes on the global hub. The objects are created and updated in the dependency order, and
+// deleted in the reverse order.
+func Apply(ctx context.Context, c client.Client, changes []Change) error {
+	for _, change := range changes {
+		switch change.Action {
+		case ActionCreate:
+			if err := c.Create(ctx, change.desired.DeepCopy()); err != nil {
+				return fmt.Errorf("failed to create %s: %w", change.Path, err)
+			}
+		case ActionUpdate:
+			obj := change.existing.DeepCopy()
+			for key, value := range change.desired.DeepCopy().Object {
+				if key != "metadata" {
+					obj.Object[key] = value
+				}
+			}
+			obj.SetLabels(change.desired.GetLabels())
+			obj.SetAnnotations(change.desired.GetAnnotations())
+			if err := c.Update(ctx, obj); err != nil {
+				return fmt.Errorf("failed to update %s: %w", change.Path, err)
+			}
+		}
+	}
+	for i := len(changes) - 1; i >= 0; i-- {
+		if changes[i].Action != ActionDelete {
+			continue
+		}
+		if err := client.IgnoreNotFound(c.Delete(ctx, changes[i].existing)); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", changes[i].Path, err)
+		}
+	}
+	return nil
+}
+
+// WriteReport writes the summary of the changes, the diffs are included if verbose is true
+func WriteReport(w io.Writer, changes []Change, verbose bool) {
+	counts := map[Action]int{}
+	for _, change := range changes {
+		counts[change.Action]++
+		if change.Action == ActionUnchanged && !verbose {
+			continue
+		}
+		fmt.Fprintf(w, "%-9s %s\n", change.Action, change.Path)
+		if verbose && change.Diff != "" {
+			fmt.Fprint(w, change.Diff)
+		}
+	}
+	fmt.Fprintf(w, "%d to create, %d to update, %d to delete, %d unchanged\n", counts[ActionCreate],
+		counts[ActionUpdate], counts[ActionDelete], counts[ActionUnchanged])
+}
+
+func sortChanges(changes []Change) {
+	kindOrder := map[string]int{}
+	for i, kind := range kinds {
+		kindOrder[kind.gvk.Kind] = i
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Kind != changes[j].Kind {
+			return kindOrder[changes[i].Kind] < kindOrder[changes[j].Kind]
+		}
+		if changes[i].Namespace != changes[j].Namespace {
+			return changes[i].Namespace < changes[j].Namespace
+		}
+		return changes[i].Name < changes[j].Name
+	})
+}
+
+func diff(path string, before, after *unstructured.Unstructured) (string, error) {
+	toLines := func(obj *unstructured.Unstructured) ([]string, error) {
+		if obj == nil {
+			return nil, nil
+		}
+		data, err := marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		return difflib.SplitLines(string(data)), nil
+	}
+	a, err := toLines(before)
+	if err != nil {
+		return "", err
+	}
+	b, err := toLines(after)
+	if err != nil {
+		return "", err
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        a,
+		B:        b,
+		FromFile: "hub/" + filepath.ToSlash(path),
+		ToFile:   "dir/" + filepath.ToSlash(path),
+		Context:  3,
+	})
+}
+
+func readObject(path string) (*unstructured.Unstructured, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	if err := yaml.Unmarshal(data, &obj.Object); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return obj, nil
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+// Package policyascode exports the global policies, placement bindings and placements to a directory, and imports
+// them from the directory, which is usually a Git working tree. The directory layout is
+//
+//	<namespace>/<kind>/<name>.yaml
+//
+// e.g. "default/policies/policy-config.yaml", one object per file.
+package policyascode
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+const (
+	fileExtension = ".yaml"
+	// the annotation is written by "kubectl apply", it isn't part of the desired state
+	lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+type resourceKind struct {
+	dir string
+	gvk schema.GroupVersionKind
+}
+
+// kinds are ordered by the dependencies: the policies and placements are created before the placement bindings
+// and deleted after them
+var kinds = []resourceKind{
+	{
+		dir: "policies",
+		gvk: schema.GroupVersionKind{Group: "policy.open-cluster-management.io", Version: "v1", Kind: "Policy"},
+	},
+	{
+		dir: "placements",
+		gvk: schema.GroupVersionKind{Group: "cluster.open-cluster-management.io", Version: "v1beta1", Kind: "Placement"},
+	},
+	{
+		dir: "placementbindings",
+		gvk: schema.GroupVersionKind{
+			Group: "policy.open-cluster-management.io", Version: "v1", Kind: "PlacementBinding",
+		},
+	},
+}
+
+func kindByDir(dir string) (resourceKind, bool) {
+	for _, kind := range kinds {
+		if kind.dir == dir {
+			return kind, true
+		}
+	}
+	return resourceKind{}, false
+}
+
+func kindByGVK(gvk schema.GroupVersionKind) (resourceKind, bool) {
+	for _, kind := range kinds {
+		if kind.gvk == gvk {
+			return kind, true
+		}
+	}
+	return resourceKind{}, false
+}
+
+// objectPath returns the relative path of the object in the directory layout
+func objectPath(kind resourceKind, namespace, name string) string {
+	return filepath.Join(namespace, kind.dir, name+fileExtension)
+}
+
+// canonicalize returns a copy of the object which only keeps the desired state: the status, the metadata generated by
+// the server and the empty fields are stripped. The global resource label is always set, so the imported objects are
+// synced to the managed hubs.
+func canonicalize(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	canonical := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	for key, value := range obj.DeepCopy().Object {
+		if key == "metadata" || key == "status" {
+			continue
+		}
+		if value = pruneEmpty(value); value != nil {
+			canonical.Object[key] = value
+		}
+	}
+	canonical.SetName(obj.GetName())
+	canonical.SetNamespace(obj.GetNamespace())
+
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[constants.GlobalHubGlobalResourceLabel] = ""
+	canonical.SetLabels(labels)
+
+	annotations := obj.GetAnnotations()
+	delete(annotations, lastAppliedAnnotation)
+	if len(annotations) > 0 {
+		canonical.SetAnnotations(annotations)
+	}
+	return canonical
+}
+
+// pruneEmpty removes the null, empty map and empty list values, they're usually added by the typed clients and
+// shouldn't be reported as changes. It returns nil if the value is empty.
+func pruneEmpty(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		for key, item := range v {
+			if item = pruneEmpty(item); item == nil {
+				delete(v, key)
+			} else {
+				v[key] = item
+			}
+		}
+		if len(v) == 0 {
+			return nil
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+	}
+	return value
+}
+
+// marshal encodes the object to YAML, the keys are sorted so the output is stable
+func marshal(obj *unstructured.Unstructured) ([]byte, error) {
+	data, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s %s/%s: %w", obj.GetKind(), obj.GetNamespace(), obj.GetName(),
+			err)
+	}
+	return data, nil
+}
@@ -0,0 +1,219 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package policyascode
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	require.NoError(t, policyv1.AddToScheme(scheme))
+	require.NoError(t, clusterv1beta1.AddToScheme(scheme))
+	return scheme
+}
+
+func globalResources() []client.Object {
+	globalLabels := map[string]string{constants.GlobalHubGlobalResourceLabel: "", "env": "dev"}
+	return []client.Object{
+		&policyv1.Policy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "policy-config", Namespace: "default", Labels: globalLabels, ResourceVersion: "1",
+				Annotations: map[string]string{lastAppliedAnnotation: "{}", "policy.open-cluster-management.io/standards": "NIST"},
+			},
+			Spec:   policyv1.PolicySpec{RemediationAction: "inform"},
+			Status: policyv1.PolicyStatus{ComplianceState: policyv1.Compliant},
+		},
+		&clusterv1beta1.Placement{
+			ObjectMeta: metav1.ObjectMeta{Name: "placement-config", Namespace: "default", Labels: globalLabels},
+		},
+		&policyv1.PlacementBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "binding-config", Namespace: "default", Labels: globalLabels},
+			PlacementRef: policyv1.PlacementSubject{
+				APIGroup: "cluster.open-cluster-management.io", Kind: "Placement", Name: "placement-config",
+			},
+			Subjects: []policyv1.Subject{
+				{APIGroup: "policy.open-cluster-management.io", Kind: "Policy", Name: "policy-config"},
+			},
+		},
+		// the local policy isn't exported
+		&policyv1.Policy{ObjectMeta: metav1.ObjectMeta{Name: "policy-local", Namespace: "default"}},
+	}
+}
+
+func TestExport(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(globalResources()...).Build()
+	dir := t.TempDir()
+
+	stale := filepath.Join(dir, "default", "policies", "policy-stale.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(stale), 0o755))
+	require.NoError(t, os.WriteFile(stale, []byte(`apiVersion: policy.open-cluster-management.io/v1
+kind: Policy
+metadata:
+  name: policy-stale
+  namespace: default
+`), 0o644))
+
+	result, err := Export(ctx, c, dir, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"default/policies/policy-config.yaml",
+		"default/placements/placement-config.yaml",
+		"default/placementbindings/binding-config.yaml",
+	}, result.Written)
+	assert.Equal(t, []string{"default/policies/policy-stale.yaml"}, result.Removed)
+
+	data, err := os.ReadFile(filepath.Join(dir, "default", "policies", "policy-config.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, `apiVersion: policy.open-cluster-management.io/v1
+kind: Policy
+metadata:
+  annotations:
+    policy.open-cluster-management.io/standards: NIST
+  labels:
+    env: dev
+    global-hub.open-cluster-management.io/global-resource: ""
+  name: policy-config
+  namespace: default
+spec:
+  disabled: false
+  remediationAction: inform
+`, string(data))
+
+	// the export is stable, and nothing is changed on the global hub
+	_, err = Export(ctx, c, dir, Options{})
+	require.NoError(t, err)
+	again, err := os.ReadFile(filepath.Join(dir, "default", "policies", "policy-config.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, data, again)
+
+	changes, err := Plan(ctx, c, dir, ImportOptions{Prune: true})
+	require.NoError(t, err)
+	for _, change := range changes {
+		assert.Equal(t, ActionUnchanged, change.Action, change.Path)
+	}
+}
+
+func TestExportWithSelector(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(globalResources()...).Build()
+	dir := t.TempDir()
+
+	result, err := Export(context.Background(), c, dir, Options{LabelSelector: labels.SelectorFromSet(
+		labels.Set{"env": "prod"})})
+	require.NoError(t, err)
+	assert.Empty(t, result.Written)
+
+	result, err = Export(context.Background(), c, dir, Options{Namespaces: []string{"default"}})
+	require.NoError(t, err)
+	assert.Len(t, result.Written, 3)
+}
+
+func TestPlanAndApply(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(globalResources()...).Build()
+	dir := t.TempDir()
+	_, err := Export(ctx, c, dir, Options{})
+	require.NoError(t, err)
+
+	// update the policy, remove the binding and add a new placement
+	policyPath := filepath.Join(dir, "default", "policies", "policy-config.yaml")
+	policy, err := readObject(policyPath)
+	require.NoError(t, err)
+	require.NoError(t, unstructured.SetNestedField(policy.Object, "enforce", "spec", "remediationAction"))
+	writeObject(t, policyPath, policy)
+	require.NoError(t, os.Remove(filepath.Join(dir, "default", "placementbindings", "binding-config.yaml")))
+	newPolicy := policy.DeepCopy()
+	newPolicy.SetName("policy-prod")
+	newPolicy.SetAnnotations(nil)
+	writeObject(t, filepath.Join(dir, "default", "policies", "policy-prod.yaml"), newPolicy)
+
+	changes, err := Plan(ctx, c, dir, ImportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Action{
+		"default/policies/policy-config.yaml":      ActionUpdate,
+		"default/policies/policy-prod.yaml":        ActionCreate,
+		"default/placements/placement-config.yaml": ActionUnchanged,
+	}, actions(changes))
+	assert.Contains(t, changes[0].Diff, "-  remediationAction: inform\n+  remediationAction: enforce\n")
+
+	changes, err = Plan(ctx, c, dir, ImportOptions{Prune: true})
+	require.NoError(t, err)
+	assert.Equal(t, ActionDelete, actions(changes)["default/placementbindings/binding-config.yaml"])
+
+	report := &bytes.Buffer{}
+	WriteReport(report, changes, false)
+	assert.Equal(t, `update    default/policies/policy-config.yaml
+create    default/policies/policy-prod.yaml
+delete    default/placementbindings/binding-config.yaml
+1 to create, 1 to update, 1 to delete, 1 unchanged
+`, report.String())
+
+	require.NoError(t, Apply(ctx, c, changes))
+
+	updated := &policyv1.Policy{}
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "policy-config"}, updated))
+	assert.Equal(t, policyv1.RemediationAction("enforce"), updated.Spec.RemediationAction)
+	// the status is kept by the update
+	assert.Equal(t, policyv1.Compliant, updated.Status.ComplianceState)
+
+	created := &policyv1.Policy{}
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "policy-prod"}, created))
+	assert.Contains(t, created.Labels, constants.GlobalHubGlobalResourceLabel)
+
+	err = c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "binding-config"}, &policyv1.PlacementBinding{})
+	assert.True(t, client.IgnoreNotFound(err) == nil && err != nil)
+
+	changes, err = Plan(ctx, c, dir, ImportOptions{Prune: true})
+	require.NoError(t, err)
+	for _, change := range changes {
+		assert.Equal(t, ActionUnchanged, change.Action, change.Diff)
+	}
+}
+
+func TestPlanInvalidPath(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(newScheme(t)).Build()
+	dir := t.TempDir()
+	placement := &unstructured.Unstructured{}
+	placement.SetAPIVersion("cluster.open-cluster-management.io/v1beta1")
+	placement.SetKind("Placement")
+	placement.SetName("placement-prod")
+	placement.SetNamespace("default")
+	writeObject(t, filepath.Join(dir, "default", "policies", "placement-prod.yaml"), placement)
+
+	_, err := Plan(context.Background(), c, dir, ImportOptions{})
+	assert.ErrorContains(t, err, "doesn't match the path")
+}
+
+func writeObject(t *testing.T, path string, obj *unstructured.Unstructured) {
+	data, err := marshal(obj)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+}
+
+func actions(changes []Change) map[string]Action {
+	result := map[string]Action{}
+	for _, change := range changes {
+		result[change.Path] = change.Action
+	}
+	return result
+}
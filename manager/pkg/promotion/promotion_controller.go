@@ -0,0 +1,366 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package promotion
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	promotionv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/promotion/v1alpha1"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+var log = ctrl.Log.WithName("policy-promotion")
+
+// promotionRetryPeriod is the period to retry the promotion which waits for the local policy to be synced from the
+// managed hub, or the placement to be ready on the global hub
+const promotionRetryPeriod = 30 * time.Second
+
+// localPolicySource reads the local policies collected from the managed hubs
+type localPolicySource interface {
+	// localPolicies returns the living local policies with the namespace and name on all the managed hubs
+	localPolicies(ctx context.Context, namespace, name string) ([]models.LocalSpecPolicy, error)
+}
+
+type databaseSource struct{}
+
+func (databaseSource) localPolicies(ctx context.Context, namespace, name string) ([]models.LocalSpecPolicy, error) {
+	policies := []models.LocalSpecPolicy{}
+	err := database.GetGorm().WithContext(ctx).
+		Where("payload->'metadata'->>'namespace' = ? AND payload->'metadata'->>'name' = ?", namespace, name).
+		Order("leaf_hub_name").Find(&policies).Error
+	return policies, err
+}
+
+// PromotionReconciler promotes the local policy of a managed hub to a global policy by the LocalPolicyPromotion:
+//  1. find the local policy in the local_spec.policies
+//  2. detect the conflicts with the local policies of the other managed hubs which have the same namespace and name
+//  3. create the global policy in the target namespace with the global resource label and the provenance
+//     annotations, and bind it to the placement
+//
+// The target namespace must be the namespace of the promotion, so that the policy is only promoted into the namespace
+// where its creator is allowed to create the promotion.
+type PromotionReconciler struct {
+	client.Client
+	source localPolicySource
+}
+
+func NewPromotionReconciler(c client.Client) *PromotionReconciler {
+	return &PromotionReconciler{
+		Client: c,
+		source: databaseSource{},
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *PromotionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// only the spec changes trigger the reconcile, so that the conflicting promotion is retried once it is updated,
+	// the failures which are resolved without updating the spec are retried by requeuing the promotion
+	return ctrl.NewControllerManagedBy(mgr).Named("promotionController").
+		For(&promotionv1alpha1.LocalPolicyPromotion{},
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
+
+func (r *PromotionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	promotion := &promotionv1alpha1.LocalPolicyPromotion{}
+	if err := r.Get(ctx, req.NamespacedName, promotion); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if promotion.Status.Phase == promotionv1alpha1.PromotionPhasePromoted || !promotion.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	spec := promotion.Spec
+	targetNamespace := spec.TargetNamespace
+	if targetNamespace == "" {
+		targetNamespace = promotion.Namespace
+	}
+	if targetNamespace != promotion.Namespace {
+		return r.failed(ctx, promotion, permanentError("the target namespace %s isn't the namespace of the promotion %s",
+			targetNamespace, promotion.Namespace))
+	}
+
+	policies, err := r.source.localPolicies(ctx, spec.PolicyNamespace, spec.PolicyName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	var localPolicy *models.LocalSpecPolicy
+	for i := range policies {
+		if policies[i].LeafHubName == spec.LeafHubName {
+			localPolicy = &policies[i]
+		}
+	}
+	if localPolicy == nil {
+		return r.failed(ctx, promotion, waitingError("the local policy %s/%s isn't found on the hub %s",
+			spec.PolicyNamespace, spec.PolicyName, spec.LeafHubName))
+	}
+
+	// the global policy is propagated to the managed hubs with the target namespace and name
+	if targetNamespace != spec.PolicyNamespace {
+		if policies, err = r.source.localPolicies(ctx, targetNamespace, spec.PolicyName); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	conflicts := []promotionv1alpha1.PolicyConflict{}
+	for _, policy := range policies {
+		if policy.PolicyID == localPolicy.PolicyID {
+			continue
+		}
+		conflicts = append(conflicts, promotionv1alpha1.PolicyConflict{
+			LeafHubName: policy.LeafHubName, PolicyID: policy.PolicyID,
+		})
+	}
+	if len(conflicts) > 0 && !spec.IgnoreConflicts {
+		completed := metav1.Now()
+		return ctrl.Result{}, r.updateStatus(ctx, promotion, func(status *promotionv1alpha1.LocalPolicyPromotionStatus) {
+			status.Phase = promotionv1alpha1.PromotionPhaseConflict
+			status.PolicyID = localPolicy.PolicyID
+			status.Conflicts = conflicts
+			status.CompletionTime = &completed
+			status.Message = fmt.Sprintf("%d managed hubs have the local policy %s/%s, set ignoreConflicts to "+
+				"replace them with the global policy", len(conflicts), targetNamespace, spec.PolicyName)
+		})
+	}
+
+	policy, err := globalPolicy(promotion, localPolicy, targetNamespace)
+	if err != nil {
+		return r.failed(ctx, promotion, err)
+	}
+	if err := r.ensurePlacement(ctx, targetNamespace, spec.PlacementRef); err != nil {
+		return r.failed(ctx, promotion, err)
+	}
+	if err := r.ensurePolicy(ctx, promotion, policy); err != nil {
+		return r.failed(ctx, promotion, err)
+	}
+	if err := r.ensurePlacementBinding(ctx, promotion, policy); err != nil {
+		return r.failed(ctx, promotion, err)
+	}
+
+	log.Info("promoted the local policy", "hub", spec.LeafHubName, "policy", localPolicy.PolicyID,
+		"globalPolicy", client.ObjectKeyFromObject(policy))
+	completed := metav1.Now()
+	return ctrl.Result{}, r.updateStatus(ctx, promotion, func(status *promotionv1alpha1.LocalPolicyPromotionStatus) {
+		status.Phase = promotionv1alpha1.PromotionPhasePromoted
+		status.PolicyID = localPolicy.PolicyID
+		status.GlobalPolicy = client.ObjectKeyFromObject(policy).String()
+		status.Conflicts = conflicts
+		status.CompletionTime = &completed
+		status.Message = fmt.Sprintf("The local policy is promoted to the global policy %s", status.GlobalPolicy)
+	})
+}
+
+// globalPolicy rewrites the local policy into the target namespace, the server generated metadata and the status
+// are dropped
+func globalPolicy(promotion *promotionv1alpha1.LocalPolicyPromotion, localPolicy *models.LocalSpecPolicy,
+	targetNamespace string,
+) (*policyv1.Policy, error) {
+	local := &policyv1.Policy{}
+	if err := json.Unmarshal(localPolicy.Payload, local); err != nil {
+		return nil, permanentError("failed to unmarshal the local policy %s: %v", localPolicy.PolicyID, err)
+	}
+
+	labels := map[string]string{}
+	for key, value := range local.Labels {
+		labels[key] = value
+	}
+	labels[constants.GlobalHubGlobalResourceLabel] = ""
+	annotations := map[string]string{}
+	for key, value := range local.Annotations {
+		if key == "kubectl.kubernetes.io/last-applied-configuration" {
+			continue
+		}
+		annotations[key] = value
+	}
+	annotations[constants.PromotedFromHubAnnotation] = promotion.Spec.LeafHubName
+	annotations[constants.PromotedFromPolicyAnnotation] = types.NamespacedName{
+		Namespace: local.Namespace, Name: local.Name,
+	}.String()
+	annotations[constants.PromotedFromPolicyIDAnnotation] = localPolicy.PolicyID
+	annotations[constants.PromotedByAnnotation] = promotionKey(promotion)
+
+	policy := &policyv1.Policy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        local.Name,
+			Namespace:   targetNamespace,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: *local.Spec.DeepCopy(),
+	}
+	// the dependencies on the policies in the original namespace are moved to the target namespace as well
+	rewriteDependencies(policy.Spec.Dependencies, local.Namespace, targetNamespace)
+	for _, template := range policy.Spec.PolicyTemplates {
+		rewriteDependencies(template.ExtraDependencies, local.Namespace, targetNamespace)
+	}
+	return policy, nil
+}
+
+func rewriteDependencies(dependencies []policyv1.PolicyDependency, namespace, targetNamespace string) {
+	for i := range dependencies {
+		if dependencies[i].Namespace == namespace {
+			dependencies[i].Namespace = targetNamespace
+		}
+	}
+}
+
+// ensurePlacement makes sure the placement is a global resource, otherwise it isn't propagated to the managed hubs
+func (r *PromotionReconciler) ensurePlacement(ctx context.Context, namespace, name string) error {
+	placement := &clusterv1beta1.Placement{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, placement)
+	if apierrors.IsNotFound(err) {
+		return waitingError("the placement %s/%s isn't found", namespace, name)
+	}
+	if err != nil {
+		return err
+	}
+	if _, ok := placement.Labels[constants.GlobalHubGlobalResourceLabel]; !ok {
+		return waitingError("the placement %s/%s doesn't have the label %s", namespace, name,
+			constants.GlobalHubGlobalResourceLabel)
+	}
+	return nil
+}
+
+// ensurePolicy creates the global policy, the existing policy is only updated if it's created by the same promotion
+func (r *PromotionReconciler) ensurePolicy(ctx context.Context, promotion *promotionv1alpha1.LocalPolicyPromotion,
+	policy *policyv1.Policy,
+) error {
+	existing := &policyv1.Policy{}
+	err := r.Get(ctx, client.ObjectKeyFromObject(policy), existing)
+	if apierrors.IsNotFound(err) {
+		return r.Create(ctx, policy)
+	}
+	if err != nil {
+		return err
+	}
+	if existing.Annotations[constants.PromotedByAnnotation] != promotionKey(promotion) {
+		return permanentError("the policy %s already exists on the global hub", client.ObjectKeyFromObject(policy))
+	}
+	existing.Labels = policy.Labels
+	existing.Annotations = policy.Annotations
+	existing.Spec = policy.Spec
+	return r.Update(ctx, existing)
+}
+
+func (r *PromotionReconciler) ensurePlacementBinding(ctx context.Context,
+	promotion *promotionv1alpha1.LocalPolicyPromotion, policy *policyv1.Policy,
+) error {
+	binding := &policyv1.PlacementBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "binding-" + policy.Name,
+			Namespace: policy.Namespace,
+			Labels: map[string]string{
+				constants.GlobalHubGlobalResourceLabel: "",
+			},
+			Annotations: map[string]string{
+				constants.PromotedByAnnotation: promotionKey(promotion),
+			},
+		},
+		PlacementRef: policyv1.PlacementSubject{
+			APIGroup: clusterv1beta1.GroupName,
+			Kind:     "Placement",
+			Name:     promotion.Spec.PlacementRef,
+		},
+		Subjects: []policyv1.Subject{
+			{
+				APIGroup: policyv1.SchemeGroupVersion.Group,
+				Kind:     policyv1.Kind,
+				Name:     policy.Name,
+			},
+		},
+	}
+
+	existing := &policyv1.PlacementBinding{}
+	err := r.Get(ctx, client.ObjectKeyFromObject(binding), existing)
+	if apierrors.IsNotFound(err) {
+		return r.Create(ctx, binding)
+	}
+	if err != nil {
+		return err
+	}
+	if existing.Annotations[constants.PromotedByAnnotation] != promotionKey(promotion) {
+		return permanentError("the placement binding %s already exists on the global hub",
+			client.ObjectKeyFromObject(binding))
+	}
+	existing.Labels = binding.Labels
+	existing.PlacementRef = binding.PlacementRef
+	existing.Subjects = binding.Subjects
+	return r.Update(ctx, existing)
+}
+
+// promotionError is the failure of the promotion rather than of the API requests, it's recorded in the status. The
+// promotion is retried periodically if the failure is resolved without updating the spec, e.g. the local policy isn't
+// synced from the managed hub yet
+type promotionError struct {
+	message string
+	retry   bool
+}
+
+func (e *promotionError) Error() string {
+	return e.message
+}
+
+func permanentError(format string, args ...any) error {
+	return &promotionError{message: fmt.Sprintf(format, args...)}
+}
+
+func waitingError(format string, args ...any) error {
+	return &promotionError{message: fmt.Sprintf(format, args...), retry: true}
+}
+
+// failed records the promotion error in the status, the other errors, e.g. the conflicts of the API requests, are
+// returned to retry the promotion with the backoff
+func (r *PromotionReconciler) failed(ctx context.Context, promotion *promotionv1alpha1.LocalPolicyPromotion,
+	err error,
+) (ctrl.Result, error) {
+	promotionErr := &promotionError{}
+	if !errors.As(err, &promotionErr) {
+		return ctrl.Result{}, err
+	}
+	log.Error(err, "failed to promote the local policy", "name", promotion.Name)
+	completed := metav1.Now()
+	if err := r.updateStatus(ctx, promotion, func(status *promotionv1alpha1.LocalPolicyPromotionStatus) {
+		status.Phase = promotionv1alpha1.PromotionPhaseFailed
+		status.CompletionTime = &completed
+		status.Message = promotionErr.Error()
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+	if promotionErr.retry {
+		return ctrl.Result{RequeueAfter: promotionRetryPeriod}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *PromotionReconciler) updateStatus(ctx context.Context, promotion *promotionv1alpha1.LocalPolicyPromotion,
+	update func(status *promotionv1alpha1.LocalPolicyPromotionStatus),
+) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		current := &promotionv1alpha1.LocalPolicyPromotion{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(promotion), current); err != nil {
+			return err
+		}
+		update(&current.Status)
+		return r.Status().Update(ctx, current)
+	})
+}
+
+func promotionKey(promotion *promotionv1alpha1.LocalPolicyPromotion) string {
+	return client.ObjectKeyFromObject(promotion).String()
+}
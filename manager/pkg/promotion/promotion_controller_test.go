@@ -0,0 +1,252 @@
+package promotion
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	promotionv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/promotion/v1alpha1"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+type fakeSource struct {
+	policies []models.LocalSpecPolicy
+}
+
+func (s *fakeSource) localPolicies(ctx context.Context, namespace, name string) ([]models.LocalSpecPolicy, error) {
+	policies := []models.LocalSpecPolicy{}
+	for _, policy := range s.policies {
+		if policy.PolicyName == namespace+"/"+name {
+			policies = append(policies, policy)
+		}
+	}
+	return policies, nil
+}
+
+func localPolicy(hub, id, namespace string) models.LocalSpecPolicy {
+	return models.LocalSpecPolicy{
+		LeafHubName: hub,
+		PolicyID:    id,
+		PolicyName:  namespace + "/policy1",
+		Payload: []byte(`{"apiVersion":"policy.open-cluster-management.io/v1","kind":"Policy",
+"metadata":{"name":"policy1","namespace":"` + namespace + `","uid":"` + id + `","resourceVersion":"10",
+"labels":{"env":"prod"},"annotations":{"kubectl.kubernetes.io/last-applied-configuration":"{}"}},
+"spec":{"disabled":false,"remediationAction":"inform","policy-templates":[],
+"dependencies":[{"apiVersion":"policy.open-cluster-management.io/v1","kind":"Policy","name":"policy0",
+"namespace":"` + namespace + `","compliance":"Compliant"}]},
+"status":{"compliant":"Compliant"}}`),
+	}
+}
+
+func TestPromotionReconciler(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	assert.NoError(t, promotionv1alpha1.AddToScheme(scheme))
+	assert.NoError(t, policyv1.AddToScheme(scheme))
+	assert.NoError(t, clusterv1beta1.AddToScheme(scheme))
+
+	promotion := &promotionv1alpha1.LocalPolicyPromotion{
+		ObjectMeta: metav1.ObjectMeta{Name: "promotion1", Namespace: "global"},
+		Spec: promotionv1alpha1.LocalPolicyPromotionSpec{
+			LeafHubName:     "hub1",
+			PolicyNamespace: "local",
+			PolicyName:      "policy1",
+			TargetNamespace: "global",
+			PlacementRef:    "placement1",
+		},
+	}
+	placement := &clusterv1beta1.Placement{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "placement1", Namespace: "global",
+			Labels: map[string]string{constants.GlobalHubGlobalResourceLabel: ""},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(promotion, placement).
+		WithStatusSubresource(promotion).Build()
+	source := &fakeSource{policies: []models.LocalSpecPolicy{
+		localPolicy("hub1", "00000000-0000-0000-0000-000000000001", "local"),
+		localPolicy("hub2", "00000000-0000-0000-0000-000000000002", "global"),
+	}}
+	r := &PromotionReconciler{Client: c, source: source}
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(promotion)}
+
+	// hub2 has the local policy with the same namespace and name as the global policy
+	_, err := r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.NoError(t, c.Get(ctx, req.NamespacedName, promotion))
+	assert.Equal(t, promotionv1alpha1.PromotionPhaseConflict, promotion.Status.Phase)
+	assert.Equal(t, []promotionv1alpha1.PolicyConflict{
+		{LeafHubName: "hub2", PolicyID: "00000000-0000-0000-0000-000000000002"},
+	}, promotion.Status.Conflicts)
+	err = c.Get(ctx, types.NamespacedName{Namespace: "global", Name: "policy1"}, &policyv1.Policy{})
+	assert.True(t, client.IgnoreNotFound(err) == nil && err != nil)
+
+	promotion.Spec.IgnoreConflicts = true
+	assert.NoError(t, c.Update(ctx, promotion))
+	_, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.NoError(t, c.Get(ctx, req.NamespacedName, promotion))
+	assert.Equal(t, promotionv1alpha1.PromotionPhasePromoted, promotion.Status.Phase)
+	assert.Equal(t, "global/policy1", promotion.Status.GlobalPolicy)
+	assert.Equal(t, "00000000-0000-0000-0000-000000000001", promotion.Status.PolicyID)
+
+	policy := &policyv1.Policy{}
+	assert.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "global", Name: "policy1"}, policy))
+	assert.Equal(t, map[string]string{"env": "prod", constants.GlobalHubGlobalResourceLabel: ""}, policy.Labels)
+	assert.Equal(t, map[string]string{
+		constants.PromotedFromHubAnnotation:      "hub1",
+		constants.PromotedFromPolicyAnnotation:   "local/policy1",
+		constants.PromotedFromPolicyIDAnnotation: "00000000-0000-0000-0000-000000000001",
+		constants.PromotedByAnnotation:           "global/promotion1",
+	}, policy.Annotations)
+	assert.Equal(t, "global", policy.Spec.Dependencies[0].Namespace)
+	assert.Empty(t, policy.Status.ComplianceState)
+
+	binding := &policyv1.PlacementBinding{}
+	assert.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "global", Name: "binding-policy1"}, binding))
+	assert.Equal(t, "placement1", binding.PlacementRef.Name)
+	assert.Equal(t, "policy1", binding.Subjects[0].Name)
+	assert.Contains(t, binding.Labels, constants.GlobalHubGlobalResourceLabel)
+}
+
+func TestPromotionReconcilerFailed(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	assert.NoError(t, promotionv1alpha1.AddToScheme(scheme))
+	assert.NoError(t, policyv1.AddToScheme(scheme))
+	assert.NoError(t, clusterv1beta1.AddToScheme(scheme))
+
+	cases := []struct {
+		name    string
+		spec    promotionv1alpha1.LocalPolicyPromotionSpec
+		objects []client.Object
+		message string
+		requeue bool
+	}{
+		{
+			name: "target namespace isn't the promotion namespace",
+			spec: promotionv1alpha1.LocalPolicyPromotionSpec{
+				LeafHubName: "hub1", PolicyNamespace: "local", PolicyName: "policy1", TargetNamespace: "global",
+				PlacementRef: "placement1",
+			},
+			message: "the target namespace global isn't the namespace of the promotion local",
+		},
+		{
+			name: "local policy not found",
+			spec: promotionv1alpha1.LocalPolicyPromotionSpec{
+				LeafHubName: "hub2", PolicyNamespace: "local", PolicyName: "policy1", PlacementRef: "placement1",
+			},
+			message: "the local policy local/policy1 isn't found on the hub hub2",
+			requeue: true,
+		},
+		{
+			name: "placement isn't a global resource",
+			spec: promotionv1alpha1.LocalPolicyPromotionSpec{
+				LeafHubName: "hub1", PolicyNamespace: "local", PolicyName: "policy1", PlacementRef: "placement1",
+			},
+			objects: []client.Object{&clusterv1beta1.Placement{
+				ObjectMeta: metav1.ObjectMeta{Name: "placement1", Namespace: "local"},
+			}},
+			message: "the placement local/placement1 doesn't have the label " + constants.GlobalHubGlobalResourceLabel,
+			requeue: true,
+		},
+		{
+			name: "global policy exists",
+			spec: promotionv1alpha1.LocalPolicyPromotionSpec{
+				LeafHubName: "hub1", PolicyNamespace: "local", PolicyName: "policy1", PlacementRef: "placement1",
+			},
+			objects: []client.Object{
+				&clusterv1beta1.Placement{ObjectMeta: metav1.ObjectMeta{
+					Name: "placement1", Namespace: "local",
+					Labels: map[string]string{constants.GlobalHubGlobalResourceLabel: ""},
+				}},
+				&policyv1.Policy{ObjectMeta: metav1.ObjectMeta{Name: "policy1", Namespace: "local"}},
+			},
+			message: "the policy local/policy1 already exists on the global hub",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			promotion := &promotionv1alpha1.LocalPolicyPromotion{
+				ObjectMeta: metav1.ObjectMeta{Name: "promotion1", Namespace: "local"},
+				Spec:       tc.spec,
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(tc.objects, promotion)...).
+				WithStatusSubresource(promotion).Build()
+			r := &PromotionReconciler{Client: c, source: &fakeSource{policies: []models.LocalSpecPolicy{
+				localPolicy("hub1", "00000000-0000-0000-0000-000000000001", "local"),
+			}}}
+			result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(promotion)})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.requeue, result.RequeueAfter > 0)
+			assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(promotion), promotion))
+			assert.Equal(t, promotionv1alpha1.PromotionPhaseFailed, promotion.Status.Phase)
+			assert.Equal(t, tc.message, promotion.Status.Message)
+		})
+	}
+}
+
+func TestPromotionReconcilerRetry(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	assert.NoError(t, promotionv1alpha1.AddToScheme(scheme))
+	assert.NoError(t, policyv1.AddToScheme(scheme))
+	assert.NoError(t, clusterv1beta1.AddToScheme(scheme))
+
+	promotion := &promotionv1alpha1.LocalPolicyPromotion{
+		ObjectMeta: metav1.ObjectMeta{Name: "promotion1", Namespace: "local"},
+		Spec: promotionv1alpha1.LocalPolicyPromotionSpec{
+			LeafHubName: "hub1", PolicyNamespace: "local", PolicyName: "policy1", PlacementRef: "placement1",
+		},
+	}
+	placement := &clusterv1beta1.Placement{ObjectMeta: metav1.ObjectMeta{
+		Name: "placement1", Namespace: "local",
+		Labels: map[string]string{constants.GlobalHubGlobalResourceLabel: ""},
+	}}
+	created := false
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(promotion, placement).
+		WithStatusSubresource(promotion).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, w client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			// the policy is created by the others between the get and the create
+			if _, ok := obj.(*policyv1.Policy); ok && !created {
+				created = true
+				return apierrors.NewAlreadyExists(policyv1.SchemeGroupVersion.WithResource("policies").GroupResource(),
+					obj.GetName())
+			}
+			return w.Create(ctx, obj, opts...)
+		},
+	}).Build()
+	source := &fakeSource{}
+	r := &PromotionReconciler{Client: c, source: source}
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(promotion)}
+
+	// the local policy isn't synced from the managed hub yet
+	result, err := r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, promotionRetryPeriod, result.RequeueAfter)
+	assert.NoError(t, c.Get(ctx, req.NamespacedName, promotion))
+	assert.Equal(t, promotionv1alpha1.PromotionPhaseFailed, promotion.Status.Phase)
+
+	// the API error is returned to retry with the backoff
+	source.policies = []models.LocalSpecPolicy{localPolicy("hub1", "00000000-0000-0000-0000-000000000001", "local")}
+	_, err = r.Reconcile(ctx, req)
+	assert.True(t, apierrors.IsAlreadyExists(err))
+
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+	assert.NoError(t, c.Get(ctx, req.NamespacedName, promotion))
+	assert.Equal(t, promotionv1alpha1.PromotionPhasePromoted, promotion.Status.Phase)
+}
@@ -3,6 +3,7 @@ package bundle
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 )
 
@@ -15,6 +16,7 @@ func NewBaseObjectsBundle() ObjectsBundle {
 }
 
 type baseObjectsBundle struct {
+	spec.BundleWatermark
 	Objects        []metav1.Object `json:"objects"`
 	DeletedObjects []metav1.Object `json:"deletedObjects"`
 }
@@ -30,6 +32,11 @@ func (b *baseObjectsBundle) AddDeletedObject(object metav1.Object) {
 	b.DeletedObjects = append(b.DeletedObjects, object)
 }
 
+// SetWatermark sets the watermark of the spec table carried by the bundle.
+func (b *baseObjectsBundle) SetWatermark(watermark spec.BundleWatermark) {
+	b.BundleWatermark = watermark
+}
+
 // setMetaDataAnnotation sets metadata annotation on the given object.
 func setMetaDataAnnotation(object metav1.Object, key string, value string) {
 	annotations := object.GetAnnotations()
@@ -1,6 +1,10 @@
 package bundle
 
-import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+)
 
 type (
 	// CreateObjectFunction is a function that specifies how to create an object.
@@ -17,4 +21,6 @@ type ObjectsBundle interface {
 	AddObject(object metav1.Object, objectUID string)
 	// AddDeletedObject adds a deleted object to the bundle.
 	AddDeletedObject(object metav1.Object)
+	// SetWatermark sets the watermark of the spec table carried by the bundle.
+	SetWatermark(watermark spec.BundleWatermark)
 }
@@ -11,11 +11,15 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/bundle"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 )
 
 var errQueryTableFailedTemplate = "failed to query table spec.%s - %w"
 
+// timestampFormat is the format of the updated_at column which is the timestamp without time zone
+const timestampFormat = "2006-01-02 15:04:05.999999"
+
 type gormSpecDB struct{}
 
 func NewGormSpecDB() *gormSpecDB {
@@ -116,6 +120,20 @@ func (p *gormSpecDB) DeleteSpecObject(ctx context.Context, tableName, name, name
 // GetObjectsBundle returns a bundle of objects from a specific table.
 func (p *gormSpecDB) GetObjectsBundle(ctx context.Context, tableName string, createObjFunc bundle.CreateObjectFunction,
 	intoBundle bundle.ObjectsBundle,
+) (*time.Time, error) {
+	return p.getObjectsBundle(ctx, tableName, nil, createObjFunc, intoBundle)
+}
+
+// GetUpdatedObjectsBundle returns a bundle of objects which are updated or deleted after the given timestamp from a
+// specific table.
+func (p *gormSpecDB) GetUpdatedObjectsBundle(ctx context.Context, tableName string, since time.Time,
+	createObjFunc bundle.CreateObjectFunction, intoBundle bundle.ObjectsBundle,
+) (*time.Time, error) {
+	return p.getObjectsBundle(ctx, tableName, &since, createObjFunc, intoBundle)
+}
+
+func (p *gormSpecDB) getObjectsBundle(ctx context.Context, tableName string, since *time.Time,
+	createObjFunc bundle.CreateObjectFunction, intoBundle bundle.ObjectsBundle,
 ) (*time.Time, error) {
 	timestamp, err := p.GetLastUpdateTimestamp(ctx, tableName, true)
 	if err != nil {
@@ -124,9 +142,15 @@ func (p *gormSpecDB) GetObjectsBundle(ctx context.Context, tableName string, cre
 
 	db := database.GetGorm()
 
-	rows, err := db.Raw(fmt.Sprintf(`SELECT id,payload,deleted FROM spec.%s WHERE
+	query := fmt.Sprintf(`SELECT id,payload,deleted,updated_at FROM spec.%s WHERE
 		payload->'metadata'->'labels'->'global-hub.open-cluster-management.io/global-resource' IS NOT NULL`,
-		tableName)).Rows()
+		tableName)
+	args := []interface{}{}
+	if since != nil {
+		query += " AND updated_at > ?::timestamp"
+		args = append(args, since.Format(timestampFormat))
+	}
+	rows, err := db.Raw(query, args...).Rows()
 	if err != nil {
 		return nil, fmt.Errorf(errQueryTableFailedTemplate, tableName, err)
 	}
@@ -135,19 +159,28 @@ func (p *gormSpecDB) GetObjectsBundle(ctx context.Context, tableName string, cre
 
 	for rows.Next() {
 		var (
-			objID   string
-			deleted bool
+			objID     string
+			deleted   bool
+			updatedAt time.Time
 		)
 		object := createObjFunc()
 
 		var payload []byte
-		if err := rows.Scan(&objID, &payload, &deleted); err != nil {
+		if err := rows.Scan(&objID, &payload, &deleted, &updatedAt); err != nil {
 			return nil, fmt.Errorf("error reading from table spec.%s - %w", tableName, err)
 		}
 		if err := json.Unmarshal(payload, &object); err != nil {
 			return nil, fmt.Errorf("error reading unmarshal payload from table spec.%s - %w", tableName, err)
 		}
 
+		// the managed hub skips applying the object if the version isn't changed
+		annotations := object.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[constants.SpecVersionAnnotation] = updatedAt.Format(time.RFC3339Nano)
+		object.SetAnnotations(annotations)
+
 		if deleted {
 			intoBundle.AddDeletedObject(object)
 		} else {
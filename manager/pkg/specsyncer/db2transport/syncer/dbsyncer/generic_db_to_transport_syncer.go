@@ -6,12 +6,16 @@ import (
 	"fmt"
 	"time"
 
+	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/bundle"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/db"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/intervalpolicy"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	"github.com/stolostron/multicluster-global-hub/pkg/utils"
 )
@@ -79,28 +83,100 @@ func (syncer *genericDBToTransportSyncer) periodicSync(ctx context.Context) {
 	}
 }
 
+// fullStateSyncInterval is the interval to send all the objects even if nothing is changed, so that the managed hubs
+// converge to the database if any delta bundle is missed or the row is committed behind the watermark
+var fullStateSyncInterval = 10 * time.Minute
+
+// specSyncState tracks the watermarks of the spec table which have been sent to the managed hubs.
+type specSyncState struct {
+	// watermark is the last update timestamp of the table which has been sent
+	watermark time.Time
+	// destinationWatermarks is the watermark of the last bundle sent to each destination
+	destinationWatermarks map[string]time.Time
+	lastFullStateSyncTime time.Time
+}
+
+func newSpecSyncState() *specSyncState {
+	return &specSyncState{destinationWatermarks: map[string]time.Time{}}
+}
+
+// fullStateRequired returns true if the next bundles should carry all the objects of the table
+func (s *specSyncState) fullStateRequired() bool {
+	return s.lastFullStateSyncTime.IsZero() || time.Since(s.lastFullStateSyncTime) >= fullStateSyncInterval
+}
+
+// bundleWatermark returns the watermark of the bundle sent to the destination. The delta bundle is based on the
+// watermark of the last bundle sent to the same destination, so that the agent is able to find the missed bundles.
+func (s *specSyncState) bundleWatermark(eventType, destination string, fullState bool,
+	watermark time.Time,
+) spec.BundleWatermark {
+	bundleWatermark := spec.BundleWatermark{
+		Key:        eventType,
+		BundleType: spec.FullStateBundle,
+		Watermark:  &watermark,
+	}
+	if destination != transport.Broadcast {
+		bundleWatermark.Key = fmt.Sprintf("%s.%s", eventType, destination)
+	}
+	if !fullState {
+		baseWatermark := s.destinationWatermarks[destination]
+		bundleWatermark.BundleType = spec.DeltaStateBundle
+		bundleWatermark.BaseWatermark = &baseWatermark
+	}
+	return bundleWatermark
+}
+
+// synced records the watermark of the bundles sent to the destinations
+func (s *specSyncState) synced(fullState bool, watermark time.Time, destinations ...string) {
+	s.watermark = watermark
+	for _, destination := range destinations {
+		s.destinationWatermarks[destination] = watermark
+	}
+	if fullState {
+		s.lastFullStateSyncTime = time.Now()
+	}
+}
+
+// sendSpecBundle sends the bundle in a span, which is continued by the agents applying the bundle
+func sendSpecBundle(ctx context.Context, producer transport.Producer, evt cloudevents.Event, destination string,
+	fullState bool,
+) error {
+	ctx, span := tracing.Start(ctx, "spec.bundle", &evt, attribute.String("globalhub.destination", destination),
+		attribute.Bool("globalhub.bundle.fullstate", fullState))
+	err := producer.SendEvent(ctx, evt)
+	tracing.End(span, err)
+	return err
+}
+
 // syncObjectsBundle performs the actual sync logic and returns true if bundle was committed to transport,
-// otherwise false.
+// otherwise false. It sends the objects changed since the last sync, and all the objects once the full state sync
+// interval is reached.
 func syncObjectsBundle(ctx context.Context, producer transport.Producer, eventType string,
 	specDB db.SpecDB, dbTableName string, createObjFunc bundle.CreateObjectFunction,
-	createBundleFunc bundle.CreateBundleFunction, lastSyncTimestampPtr *time.Time,
+	createBundleFunc bundle.CreateBundleFunction, state *specSyncState,
 ) (bool, error) {
 	lastUpdateTimestamp, err := specDB.GetLastUpdateTimestamp(ctx, dbTableName, true) // filter local resources
 	if err != nil {
 		return false, fmt.Errorf("unable to sync bundle - %w", err)
 	}
 
-	if !lastUpdateTimestamp.After(*lastSyncTimestampPtr) { // sync only if something has changed
+	fullState := state.fullStateRequired()
+	if !fullState && !lastUpdateTimestamp.After(state.watermark) { // sync only if something has changed
 		return false, nil
 	}
 
-	// if we got here, then the last update timestamp from db is after what we have in memory.
-	// this means something has changed in db, syncing all the objects to transport.
 	bundleResult := createBundleFunc()
-	lastUpdateTimestamp, err = specDB.GetObjectsBundle(ctx, dbTableName, createObjFunc, bundleResult)
+	if fullState {
+		lastUpdateTimestamp, err = specDB.GetObjectsBundle(ctx, dbTableName, createObjFunc, bundleResult)
+	} else {
+		// the last update timestamp from db is after what we have in memory, syncing the changed objects to transport.
+		lastUpdateTimestamp, err = specDB.GetUpdatedObjectsBundle(ctx, dbTableName, state.watermark, createObjFunc,
+			bundleResult)
+	}
 	if err != nil {
 		return false, fmt.Errorf("unable to sync bundle - %w", err)
 	}
+	bundleResult.SetWatermark(state.bundleWatermark(eventType, transport.Broadcast, fullState, *lastUpdateTimestamp))
 
 	// send message to transport
 	payloadBytes, err := json.Marshal(bundleResult)
@@ -109,12 +185,11 @@ func syncObjectsBundle(ctx context.Context, producer transport.Producer, eventTy
 	}
 
 	evt := utils.ToCloudEvent(eventType, constants.CloudEventSourceGlobalHub, transport.Broadcast, payloadBytes)
-	if err := producer.SendEvent(ctx, evt); err != nil {
+	if err := sendSpecBundle(ctx, producer, evt, transport.Broadcast, fullState); err != nil {
 		return false, fmt.Errorf("failed to sync message(%s) from table(%s) to destination(%s) - %w",
 			eventType, dbTableName, transport.Broadcast, err)
 	}
 
-	// updating value to retain same ptr between calls
-	*lastSyncTimestampPtr = *lastUpdateTimestamp
+	state.synced(fullState, *lastUpdateTimestamp, transport.Broadcast)
 	return true, nil
 }
@@ -26,14 +26,14 @@ func AddHoHConfigDBToTransportSyncer(mgr ctrl.Manager, specDB db.SpecDB, produce
 	specSyncInterval time.Duration,
 ) error {
 	createObjFunc := func() metav1.Object { return &corev1.ConfigMap{} }
-	lastSyncTimestampPtr := &time.Time{}
+	state := newSpecSyncState()
 
 	if err := mgr.Add(&genericDBToTransportSyncer{
 		log:            ctrl.Log.WithName("db-to-transport-syncer-configmap"),
 		intervalPolicy: intervalpolicy.NewExponentialBackoffPolicy(specSyncInterval),
 		syncBundleFunc: func(ctx context.Context) (bool, error) {
 			return syncObjectsBundle(ctx, producer, configMsgKey, specDB, configTableName,
-				createObjFunc, bundle.NewBaseObjectsBundle, lastSyncTimestampPtr)
+				createObjFunc, bundle.NewBaseObjectsBundle, state)
 		},
 	}); err != nil {
 		return fmt.Errorf("failed to add config db to transport syncer - %w", err)
@@ -0,0 +1,98 @@
+package dbsyncer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/db"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/intervalpolicy"
+	actionv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/action/v1alpha1"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+// sentResultTimeout is the maximum time to wait for the result of the action or operation sent to the managed hub, it
+// is marked as failed if the agent doesn't report the result in time, e.g. the managed hub is offline
+var sentResultTimeout = 10 * time.Minute
+
+// actionMessageKeys maps the cluster action to the message key of the agent syncer which applies it
+var actionMessageKeys = map[string]string{
+	string(actionv1alpha1.ClusterActionDetach):          constants.ManagedClusterDetachMsgKey,
+	string(actionv1alpha1.ClusterActionDenyClient):      constants.ManagedClusterDenyClientMsgKey,
+	string(actionv1alpha1.ClusterActionUpgrade):         constants.ManagedClusterUpgradeMsgKey,
+	string(actionv1alpha1.ClusterActionRotateBootstrap): constants.KlusterletBootstrapRotationMsgKey,
+}
+
+// AddManagedClusterActionsDBToTransportSyncer adds the syncer which sends the pending managed cluster lifecycle
+// actions to the managed hubs which own the clusters.
+func AddManagedClusterActionsDBToTransportSyncer(mgr ctrl.Manager, specDB db.SpecDB,
+	producer transport.Producer, specSyncInterval time.Duration,
+) error {
+	if err := mgr.Add(&genericDBToTransportSyncer{
+		log:            ctrl.Log.WithName("db-to-transport-syncer-managedclusteraction"),
+		intervalPolicy: intervalpolicy.NewExponentialBackoffPolicy(specSyncInterval),
+		syncBundleFunc: func(ctx context.Context) (bool, error) {
+			return syncManagedClusterActionBundles(ctx, producer)
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to add managed-cluster actions db to transport syncer - %w", err)
+	}
+	return nil
+}
+
+// syncManagedClusterActionBundles sends each pending action with the message key of the action, then marks it as
+// sent. The action is marked as succeeded or failed once the agent reports the result, or as failed if the result
+// isn't reported in the sentResultTimeout.
+func syncManagedClusterActionBundles(ctx context.Context, producer transport.Producer) (bool, error) {
+	db := database.GetGorm()
+	err := db.Model(&models.ManagedClusterAction{}).
+		Where("state = ? AND updated_at < ?", database.OperationSent, time.Now().Add(-sentResultTimeout)).
+		Select("state", "message", "updated_at").
+		Updates(&models.ManagedClusterAction{
+			State:   database.OperationFailed,
+			Message: fmt.Sprintf("the result isn't reported by the managed hub in %s", sentResultTimeout),
+		}).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to mark the timed out managed cluster actions as failed - %w", err)
+	}
+
+	actions := []models.ManagedClusterAction{}
+	err = db.Where(&models.ManagedClusterAction{State: database.OperationPending}).
+		Order("created_at").Find(&actions).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to get the pending managed cluster actions - %w", err)
+	}
+	if len(actions) == 0 {
+		return false, nil
+	}
+
+	for _, action := range actions {
+		state, message := database.OperationSent, ""
+		msgKey, ok := actionMessageKeys[action.Action]
+		if ok {
+			evt := utils.ToCloudEvent(msgKey, constants.CloudEventSourceGlobalHub, action.LeafHubName,
+				action.Payload)
+			if err := producer.SendEvent(ctx, evt); err != nil {
+				return false, fmt.Errorf("failed to sync managed cluster action(%s) to destination(%s) - %w",
+					action.ID, action.LeafHubName, err)
+			}
+		} else {
+			state, message = database.OperationFailed, fmt.Sprintf("the action %s isn't supported", action.Action)
+		}
+		err = db.Model(&models.ManagedClusterAction{}).
+			Where("id = ? AND state = ?", action.ID, database.OperationPending).
+			Select("state", "message", "updated_at").
+			Updates(&models.ManagedClusterAction{State: state, Message: message}).Error
+		if err != nil {
+			return false, fmt.Errorf("failed to mark the managed cluster action(%s) as %s - %w",
+				action.ID, state, err)
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,111 @@
+package dbsyncer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/db"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/intervalpolicy"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+// AddManagedClusterOperationsDBToTransportSyncer adds the syncer which sends the pending bulk managed cluster
+// operations to the managed hubs.
+func AddManagedClusterOperationsDBToTransportSyncer(mgr ctrl.Manager, specDB db.SpecDB,
+	producer transport.Producer, specSyncInterval time.Duration,
+) error {
+	if err := mgr.Add(&genericDBToTransportSyncer{
+		log:            ctrl.Log.WithName("db-to-transport-syncer-managedclusteroperation"),
+		intervalPolicy: intervalpolicy.NewExponentialBackoffPolicy(specSyncInterval),
+		syncBundleFunc: func(ctx context.Context) (bool, error) {
+			return syncManagedClusterOperationBundles(ctx, producer)
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to add managed-cluster operations db to transport syncer - %w", err)
+	}
+	return nil
+}
+
+type pendingOperationRow struct {
+	OperationID string
+	LeafHubName string
+	ClusterName string
+	Payload     []byte
+}
+
+// syncManagedClusterOperationBundles sends a bundle per (operation, leaf hub) for the pending clusters, then marks
+// them as sent. The clusters are marked as succeeded or failed once the agent reports the results, or as failed if
+// the results aren't reported in the sentResultTimeout.
+func syncManagedClusterOperationBundles(ctx context.Context, producer transport.Producer) (bool, error) {
+	db := database.GetGorm()
+	err := db.Model(&models.ManagedClusterOperationResult{}).
+		Where("state = ? AND updated_at < ?", database.OperationSent, time.Now().Add(-sentResultTimeout)).
+		Select("state", "message", "updated_at").
+		Updates(&models.ManagedClusterOperationResult{
+			State:   database.OperationFailed,
+			Message: fmt.Sprintf("the result isn't reported by the managed hub in %s", sentResultTimeout),
+		}).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to mark the timed out managed cluster operations as failed - %w", err)
+	}
+
+	rows := []pendingOperationRow{}
+	err = db.Raw(`SELECT r.operation_id, r.leaf_hub_name, r.cluster_name, o.payload
+		FROM status.managed_cluster_operation_results r
+		JOIN spec.managed_cluster_operations o ON o.id = r.operation_id
+		WHERE r.state = ? ORDER BY o.created_at, o.id, r.leaf_hub_name`, database.OperationPending).Scan(&rows).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to get the pending managed cluster operations - %w", err)
+	}
+	if len(rows) == 0 {
+		return false, nil
+	}
+
+	bundles := []*spec.ManagedClusterOperationBundle{}
+	leafHubs := []string{}
+	for _, row := range rows {
+		last := len(bundles) - 1
+		if last < 0 || bundles[last].OperationID != row.OperationID || leafHubs[last] != row.LeafHubName {
+			bundle := &spec.ManagedClusterOperationBundle{OperationID: row.OperationID, Clusters: []string{}}
+			if err := json.Unmarshal(row.Payload, &bundle.Operation); err != nil {
+				return false, fmt.Errorf("failed to unmarshal the managed cluster operation %s - %w",
+					row.OperationID, err)
+			}
+			bundles = append(bundles, bundle)
+			leafHubs = append(leafHubs, row.LeafHubName)
+			last++
+		}
+		bundles[last].Clusters = append(bundles[last].Clusters, row.ClusterName)
+	}
+
+	for i, bundle := range bundles {
+		payloadBytes, err := json.Marshal(bundle)
+		if err != nil {
+			return false, fmt.Errorf("failed to marshal bundle(%s) - %w", constants.ManagedClusterOperationMsgKey, err)
+		}
+		evt := utils.ToCloudEvent(constants.ManagedClusterOperationMsgKey, constants.CloudEventSourceGlobalHub,
+			leafHubs[i], payloadBytes)
+		if err := producer.SendEvent(ctx, evt); err != nil {
+			return false, fmt.Errorf("failed to sync managed cluster operation(%s) to destination(%s) - %w",
+				bundle.OperationID, leafHubs[i], err)
+		}
+		err = db.Model(&models.ManagedClusterOperationResult{}).
+			Where("operation_id = ? AND leaf_hub_name = ? AND state = ?", bundle.OperationID, leafHubs[i],
+				database.OperationPending).
+			Updates(&models.ManagedClusterOperationResult{State: database.OperationSent}).Error
+		if err != nil {
+			return false, fmt.Errorf("failed to mark the managed cluster operation(%s) as sent - %w",
+				bundle.OperationID, err)
+		}
+	}
+	return true, nil
+}
@@ -28,14 +28,14 @@ func AddManagedClusterSetBindingsDBToTransportSyncer(mgr ctrl.Manager, specDB db
 	createObjFunc := func() metav1.Object {
 		return &clusterv1beta2.ManagedClusterSetBinding{}
 	}
-	lastSyncTimestampPtr := &time.Time{}
+	state := newSpecSyncState()
 
 	if err := mgr.Add(&genericDBToTransportSyncer{
 		log:            ctrl.Log.WithName("db-to-transport-syncer-managedclustersetbinding"),
 		intervalPolicy: intervalpolicy.NewExponentialBackoffPolicy(specSyncInterval),
 		syncBundleFunc: func(ctx context.Context) (bool, error) {
 			return syncObjectsBundle(ctx, producer, managedClusterSetBindingsMsgKey, specDB,
-				managedClusterSetBindingsTableName, createObjFunc, bundle.NewBaseObjectsBundle, lastSyncTimestampPtr)
+				managedClusterSetBindingsTableName, createObjFunc, bundle.NewBaseObjectsBundle, state)
 		},
 	}); err != nil {
 		return fmt.Errorf("failed to add managed-cluster-set-bindings db to transport syncer - %w", err)
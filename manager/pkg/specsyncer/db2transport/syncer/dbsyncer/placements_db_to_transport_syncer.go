@@ -7,9 +7,10 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 
-	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/bundle"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/override"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/db"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/intervalpolicy"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
@@ -25,14 +26,17 @@ func AddPlacementsDBToTransportSyncer(mgr ctrl.Manager, specDB db.SpecDB, produc
 	specSyncInterval time.Duration,
 ) error {
 	createObjFunc := func() metav1.Object { return &clusterv1beta1.Placement{} }
-	lastSyncTimestampPtr := &time.Time{}
+	state := newTargetedSyncState()
+	overridesLoader := func(ctx context.Context) (objectOverrides, error) {
+		return override.NewResolver(ctx, mgr.GetClient(), override.PlacementKind)
+	}
 
 	if err := mgr.Add(&genericDBToTransportSyncer{
 		log:            ctrl.Log.WithName("db-to-transport-syncer-placements"),
 		intervalPolicy: intervalpolicy.NewExponentialBackoffPolicy(specSyncInterval),
 		syncBundleFunc: func(ctx context.Context) (bool, error) {
-			return syncObjectsBundle(ctx, producer, placementsMsgKey, specDB, placementsTableName,
-				createObjFunc, bundle.NewBaseObjectsBundle, lastSyncTimestampPtr)
+			return syncTargetedObjectsBundle(ctx, producer, placementsMsgKey, specDB, placementsTableName,
+				createObjFunc, placementPlacements, overridesLoader, state)
 		},
 	}); err != nil {
 		return fmt.Errorf("failed to add placements db to transport syncer - %w", err)
@@ -40,3 +44,9 @@ func AddPlacementsDBToTransportSyncer(mgr ctrl.Manager, specDB db.SpecDB, produc
 
 	return nil
 }
+
+// placementPlacements returns false since the placement isn't bound to any placement, it's broadcast to all the managed
+// hubs unless it's overridden
+func placementPlacements(object metav1.Object, bindings []*policyv1.PlacementBinding) ([]string, bool) {
+	return nil, false
+}
@@ -6,10 +6,11 @@ import (
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
 	subscriptionv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 
-	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/bundle"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/db"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/intervalpolicy"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
@@ -25,14 +26,14 @@ func AddSubscriptionsDBToTransportSyncer(mgr ctrl.Manager, specDB db.SpecDB, pro
 	specSyncInterval time.Duration,
 ) error {
 	createObjFunc := func() metav1.Object { return &subscriptionv1.Subscription{} }
-	lastSyncTimestampPtr := &time.Time{}
+	state := newTargetedSyncState()
 
 	if err := mgr.Add(&genericDBToTransportSyncer{
 		log:            ctrl.Log.WithName("db-to-transport-syncer-subscriptions"),
 		intervalPolicy: intervalpolicy.NewExponentialBackoffPolicy(specSyncInterval),
 		syncBundleFunc: func(ctx context.Context) (bool, error) {
-			return syncObjectsBundle(ctx, producer, subscriptionMsgKey, specDB, subscriptionsTableName,
-				createObjFunc, bundle.NewBaseObjectsBundle, lastSyncTimestampPtr)
+			return syncTargetedObjectsBundle(ctx, producer, subscriptionMsgKey, specDB, subscriptionsTableName,
+				createObjFunc, subscriptionPlacements, nil, state)
 		},
 	}); err != nil {
 		return fmt.Errorf("failed to add subscriptions db to transport syncer - %w", err)
@@ -40,3 +41,18 @@ func AddSubscriptionsDBToTransportSyncer(mgr ctrl.Manager, specDB db.SpecDB, pro
 
 	return nil
 }
+
+// subscriptionPlacements returns the placement referenced by the subscription, the subscription with the placement
+// rule or the cluster selector is broadcast
+func subscriptionPlacements(object metav1.Object, bindings []*policyv1.PlacementBinding) ([]string, bool) {
+	subscription, ok := object.(*subscriptionv1.Subscription)
+	if !ok || subscription.Spec.Placement == nil || subscription.Spec.Placement.PlacementRef == nil ||
+		subscription.Spec.Placement.PlacementRef.Kind != placementKind {
+		return nil, false
+	}
+	return []string{
+		types.NamespacedName{
+			Namespace: subscription.Namespace, Name: subscription.Spec.Placement.PlacementRef.Name,
+		}.String(),
+	}, true
+}
@@ -0,0 +1,401 @@
+package dbsyncer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/bundle"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/db"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+const placementKind = "Placement"
+
+// objectPlacementsFunction returns the namespaced names of the placements which select the clusters for the object.
+// false means the object isn't bound to any placement, then it's broadcast to all the managed hubs.
+type objectPlacementsFunction func(object metav1.Object, bindings []*policyv1.PlacementBinding) ([]string, bool)
+
+// objectOverrides renders the objects for the managed hubs by the GlobalHubOverrides
+type objectOverrides interface {
+	// Version changes once the overrides or the labels of the managed hubs are changed
+	Version() string
+	// Overridden returns true if the object is rendered for each managed hub
+	Overridden(object metav1.Object) bool
+	Render(object metav1.Object, hub string) (*unstructured.Unstructured, error)
+}
+
+// overridesLoaderFunc loads the overrides of the objects, nil means the objects can't be overridden
+type overridesLoaderFunc func(ctx context.Context) (objectOverrides, error)
+
+// targetedSyncState records what was sent by the last sync, so that the objects are removed from the managed hubs
+// which are no longer selected by the placements
+type targetedSyncState struct {
+	*specSyncState
+	lastBindingsTimestamp time.Time
+	placementTargets      map[string]sets.Set[string]
+	// objectTargets maps the namespaced name of the object to the managed hubs it's sent to, nil means broadcast. The
+	// map is nil until the first sync, since the targets sent before the manager starts are unknown
+	objectTargets map[string]sets.Set[string]
+	// renderedVersions maps the namespaced name of the overridden object to the versions rendered for the managed hubs
+	renderedVersions map[string]map[string]string
+	overridesVersion string
+	hubs             sets.Set[string]
+}
+
+func newTargetedSyncState() *targetedSyncState {
+	return &targetedSyncState{
+		specSyncState:    newSpecSyncState(),
+		placementTargets: map[string]sets.Set[string]{},
+		renderedVersions: map[string]map[string]string{},
+		hubs:             sets.New[string](),
+	}
+}
+
+type specObject struct {
+	id      string
+	object  metav1.Object
+	deleted bool
+	// version is the update time of the object in the database
+	version time.Time
+}
+
+// objectsCollector reads the objects from the spec table, so they can be assigned to the managed hubs before they're
+// added to the bundles
+type objectsCollector struct {
+	objects []specObject
+}
+
+func (c *objectsCollector) AddObject(object metav1.Object, objectUID string) {
+	c.objects = append(c.objects, specObject{id: objectUID, object: object, version: objectVersion(object)})
+}
+
+func (c *objectsCollector) AddDeletedObject(object metav1.Object) {
+	c.objects = append(c.objects, specObject{object: object, deleted: true, version: objectVersion(object)})
+}
+
+// SetWatermark is a no-op, the watermarks are set on the bundles planned from the collected objects
+func (c *objectsCollector) SetWatermark(watermark spec.BundleWatermark) {}
+
+func objectVersion(object metav1.Object) time.Time {
+	version, err := time.Parse(time.RFC3339Nano, object.GetAnnotations()[constants.SpecVersionAnnotation])
+	if err != nil {
+		return time.Time{}
+	}
+	return version
+}
+
+// syncTargetedObjectsBundle sends each managed hub only the objects bound to the placements which select its
+// clusters, the objects which aren't bound to the placements are broadcast. The bundles carry the objects changed
+// since the last sync, and all the objects once the full state sync interval is reached. The overridden objects are
+// rendered for each managed hub. It returns true if any bundle was committed to transport, otherwise false.
+func syncTargetedObjectsBundle(ctx context.Context, producer transport.Producer, eventType string,
+	specDB db.SpecDB, dbTableName string, createObjFunc bundle.CreateObjectFunction,
+	placementsFunc objectPlacementsFunction, overridesLoader overridesLoaderFunc, state *targetedSyncState,
+) (bool, error) {
+	lastUpdateTimestamp, err := specDB.GetLastUpdateTimestamp(ctx, dbTableName, true) // filter local resources
+	if err != nil {
+		return false, fmt.Errorf("unable to sync bundle - %w", err)
+	}
+	bindingsTimestamp, err := specDB.GetLastUpdateTimestamp(ctx, placementBindingsTableName, true)
+	if err != nil {
+		return false, fmt.Errorf("unable to sync bundle - %w", err)
+	}
+	placementTargets, err := getPlacementTargets(ctx)
+	if err != nil {
+		return false, fmt.Errorf("unable to get the placement decisions - %w", err)
+	}
+	hubs, err := getLeafHubs(ctx)
+	if err != nil {
+		return false, fmt.Errorf("unable to get the managed hubs - %w", err)
+	}
+	var overrides objectOverrides
+	overridesVersion := ""
+	if overridesLoader != nil {
+		if overrides, err = overridesLoader(ctx); err != nil {
+			return false, fmt.Errorf("unable to load the overrides - %w", err)
+		}
+		overridesVersion = overrides.Version()
+	}
+
+	// sync only if the objects, the bindings, the managed hubs selected by the placements, the managed hubs or the
+	// overrides are changed
+	fullState := state.fullStateRequired()
+	if !fullState && !lastUpdateTimestamp.After(state.watermark) &&
+		!bindingsTimestamp.After(state.lastBindingsTimestamp) && equalTargets(placementTargets, state.placementTargets) &&
+		hubs.Equal(state.hubs) && overridesVersion == state.overridesVersion {
+		return false, nil
+	}
+
+	collector := &objectsCollector{}
+	lastUpdateTimestamp, err = specDB.GetObjectsBundle(ctx, dbTableName, createObjFunc, collector)
+	if err != nil {
+		return false, fmt.Errorf("unable to sync bundle - %w", err)
+	}
+	bindings, err := getPlacementBindings(ctx, specDB)
+	if err != nil {
+		return false, fmt.Errorf("unable to sync bundle - %w", err)
+	}
+
+	since := state.watermark
+	if fullState {
+		since = time.Time{}
+	}
+	bundles, objectTargets, renderedVersions := planTargetedBundles(collector.objects, bindings, placementsFunc,
+		placementTargets, hubs, overrides, state.objectTargets, state.renderedVersions, since)
+
+	destinations := make([]string, 0, len(bundles))
+	for destination := range bundles {
+		if destination != transport.Broadcast {
+			destinations = append(destinations, destination)
+		}
+	}
+	sort.Strings(destinations)
+	// the broadcast bundle goes first, the objects removed from the managed hubs are never in it
+	destinations = append([]string{transport.Broadcast}, destinations...)
+	for _, destination := range destinations {
+		bundles[destination].SetWatermark(state.bundleWatermark(eventType, destination, fullState,
+			*lastUpdateTimestamp))
+		payloadBytes, err := json.Marshal(bundles[destination])
+		if err != nil {
+			return false, fmt.Errorf("failed to sync marshal bundle(%s)", eventType)
+		}
+		evt := utils.ToCloudEvent(eventType, constants.CloudEventSourceGlobalHub, destination, payloadBytes)
+		if err := sendSpecBundle(ctx, producer, evt, destination, fullState); err != nil {
+			return false, fmt.Errorf("failed to sync message(%s) from table(%s) to destination(%s) - %w",
+				eventType, dbTableName, destination, err)
+		}
+	}
+
+	state.synced(fullState, *lastUpdateTimestamp, destinations...)
+	state.lastBindingsTimestamp = *bindingsTimestamp
+	state.placementTargets = placementTargets
+	state.objectTargets = objectTargets
+	state.renderedVersions = renderedVersions
+	state.overridesVersion = overridesVersion
+	state.hubs = hubs
+	return true, nil
+}
+
+// planTargetedBundles assigns the objects to the managed hubs. The object is broadcast if it isn't bound to any
+// placement, or any of its placements hasn't been decided by the managed hubs yet. The object is deleted from the
+// managed hubs it was sent to by the last sync but are no longer selected, or from all the managed hubs which aren't
+// selected if the last targets are nil, e.g. the first sync after the manager restarts. The object is added only if
+// it's changed since the given time or the destination didn't receive it by the last sync, the zero time means all the
+// objects are added. The overridden object is sent to each of its managed hubs, or all the managed hubs if it's
+// broadcast, with the content rendered for the hub. It's added to the bundle once the rendered version is changed.
+func planTargetedBundles(objects []specObject, bindings []*policyv1.PlacementBinding,
+	placementsFunc objectPlacementsFunction, placementTargets map[string]sets.Set[string], hubs sets.Set[string],
+	overrides objectOverrides, lastObjectTargets map[string]sets.Set[string],
+	lastRenderedVersions map[string]map[string]string, since time.Time,
+) (map[string]bundle.ObjectsBundle, map[string]sets.Set[string], map[string]map[string]string) {
+	bundles := map[string]bundle.ObjectsBundle{transport.Broadcast: bundle.NewBaseObjectsBundle()}
+	bundleOf := func(destination string) bundle.ObjectsBundle {
+		if _, found := bundles[destination]; !found {
+			bundles[destination] = bundle.NewBaseObjectsBundle()
+		}
+		return bundles[destination]
+	}
+
+	objectTargets := map[string]sets.Set[string]{}
+	renderedVersions := map[string]map[string]string{}
+	for _, obj := range objects {
+		changed := since.IsZero() || obj.version.After(since)
+		if obj.deleted {
+			if changed {
+				bundleOf(transport.Broadcast).AddDeletedObject(obj.object)
+			}
+			continue
+		}
+
+		key := types.NamespacedName{Namespace: obj.object.GetNamespace(), Name: obj.object.GetName()}.String()
+		targets := objectHubs(obj.object, bindings, placementsFunc, placementTargets)
+		lastTargets, found := lastObjectTargets[key]
+		if lastObjectTargets == nil {
+			// the object might have been sent to any managed hub before the manager restarts
+			lastTargets, found = nil, true
+		}
+		lastVersions, wasOverridden := lastRenderedVersions[key]
+		if overrides != nil && overrides.Overridden(obj.object) {
+			if targets == nil {
+				targets = hubs.Clone()
+			}
+			versions := map[string]string{}
+			for _, hub := range sets.List(targets) {
+				rendered, err := overrides.Render(obj.object, hub)
+				if err != nil {
+					// the error is reported by the override status, the object is sent once it's rendered
+					continue
+				}
+				versions[hub] = rendered.GetAnnotations()[constants.SpecVersionAnnotation]
+				if since.IsZero() || versions[hub] != lastVersions[hub] {
+					bundleOf(hub).AddObject(rendered, obj.id)
+				}
+			}
+			renderedVersions[key] = versions
+		}
+		objectTargets[key] = targets
+		if targets == nil {
+			if changed || !found || lastTargets != nil {
+				bundleOf(transport.Broadcast).AddObject(obj.object, obj.id)
+			}
+			continue
+		}
+		if _, overridden := renderedVersions[key]; !overridden {
+			for _, hub := range sets.List(targets) {
+				if changed || !found || wasOverridden || (lastTargets != nil && !lastTargets.Has(hub)) {
+					bundleOf(hub).AddObject(obj.object, obj.id)
+				}
+			}
+		}
+
+		if !found {
+			continue
+		}
+		if lastTargets == nil {
+			lastTargets = hubs
+		}
+		for _, hub := range sets.List(lastTargets.Difference(targets)) {
+			bundleOf(hub).AddDeletedObject(obj.object)
+		}
+	}
+	return bundles, objectTargets, renderedVersions
+}
+
+// objectHubs returns the managed hubs selected by the placements of the object, nil means broadcast
+func objectHubs(object metav1.Object, bindings []*policyv1.PlacementBinding, placementsFunc objectPlacementsFunction,
+	placementTargets map[string]sets.Set[string],
+) sets.Set[string] {
+	placements, bound := placementsFunc(object, bindings)
+	if !bound {
+		return nil
+	}
+	targets := sets.New[string]()
+	for _, placement := range placements {
+		hubs, decided := placementTargets[placement]
+		if !decided {
+			return nil
+		}
+		targets = targets.Union(hubs)
+	}
+	return targets
+}
+
+// getPlacementTargets returns the managed hubs which have the clusters selected by each placement. The placement is
+// included with no hubs if the managed hubs report its decisions without any cluster.
+func getPlacementTargets(ctx context.Context) (map[string]sets.Set[string], error) {
+	rows, err := database.GetGorm().WithContext(ctx).Raw(fmt.Sprintf(`SELECT leaf_hub_name,
+		payload->'metadata'->>'namespace',
+		payload->'metadata'->'labels'->>'%s',
+		jsonb_array_length(COALESCE(payload->'status'->'decisions', '[]'::jsonb))
+		FROM status.%s`, clusterv1beta1.PlacementLabel, database.PlacementDecisionsTableName)).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	placementTargets := map[string]sets.Set[string]{}
+	for rows.Next() {
+		var (
+			leafHubName string
+			namespace   string
+			placement   *string
+			decisions   int
+		)
+		if err := rows.Scan(&leafHubName, &namespace, &placement, &decisions); err != nil {
+			return nil, err
+		}
+		if placement == nil {
+			continue
+		}
+		key := types.NamespacedName{Namespace: namespace, Name: *placement}.String()
+		if _, found := placementTargets[key]; !found {
+			placementTargets[key] = sets.New[string]()
+		}
+		if decisions > 0 {
+			placementTargets[key].Insert(leafHubName)
+		}
+	}
+	return placementTargets, nil
+}
+
+func getLeafHubs(ctx context.Context) (sets.Set[string], error) {
+	leafHubs := []string{}
+	err := database.GetGorm().WithContext(ctx).
+		Raw("SELECT DISTINCT leaf_hub_name FROM status.leaf_hubs WHERE deleted_at IS NULL").
+		Scan(&leafHubs).Error
+	return sets.New(leafHubs...), err
+}
+
+func getPlacementBindings(ctx context.Context, specDB db.SpecDB) ([]*policyv1.PlacementBinding, error) {
+	collector := &objectsCollector{}
+	if _, err := specDB.GetObjectsBundle(ctx, placementBindingsTableName,
+		func() metav1.Object { return &policyv1.PlacementBinding{} }, collector); err != nil {
+		return nil, err
+	}
+	bindings := []*policyv1.PlacementBinding{}
+	for _, obj := range collector.objects {
+		if binding, ok := obj.object.(*policyv1.PlacementBinding); ok && !obj.deleted {
+			bindings = append(bindings, binding)
+		}
+	}
+	return bindings, nil
+}
+
+func equalTargets(a, b map[string]sets.Set[string]) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, hubs := range a {
+		if other, found := b[key]; !found || !hubs.Equal(other) {
+			return false
+		}
+	}
+	return true
+}
+
+// bindingPlacements returns the placement of the binding, the placement rule isn't decided by the managed hubs so
+// the binding is broadcast
+func bindingPlacements(object metav1.Object, bindings []*policyv1.PlacementBinding) ([]string, bool) {
+	binding, ok := object.(*policyv1.PlacementBinding)
+	if !ok || binding.PlacementRef.Kind != placementKind {
+		return nil, false
+	}
+	return []string{
+		types.NamespacedName{Namespace: binding.Namespace, Name: binding.PlacementRef.Name}.String(),
+	}, true
+}
+
+// policyPlacements returns the placements of the bindings which have the policy as a subject
+func policyPlacements(object metav1.Object, bindings []*policyv1.PlacementBinding) ([]string, bool) {
+	placements := []string{}
+	for _, binding := range bindings {
+		if binding.Namespace != object.GetNamespace() {
+			continue
+		}
+		for _, subject := range binding.Subjects {
+			if subject.Kind != policyv1.Kind || subject.Name != object.GetName() {
+				continue
+			}
+			bindingPlacement, bound := bindingPlacements(binding, nil)
+			if !bound {
+				return nil, false
+			}
+			placements = append(placements, bindingPlacement...)
+		}
+	}
+	return placements, len(placements) > 0
+}
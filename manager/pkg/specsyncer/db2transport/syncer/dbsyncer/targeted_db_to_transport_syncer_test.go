@@ -0,0 +1,239 @@
+package dbsyncer
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/bundle"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+func policyObject(name string) specObject {
+	return specObject{
+		id:     name + "-id",
+		object: &policyv1.Policy{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}},
+	}
+}
+
+func bundleNames(t *testing.T, objectsBundle bundle.ObjectsBundle) ([]string, []string) {
+	payload, err := json.Marshal(objectsBundle)
+	assert.NoError(t, err)
+	decoded := struct {
+		Objects        []policyv1.Policy `json:"objects"`
+		DeletedObjects []policyv1.Policy `json:"deletedObjects"`
+	}{}
+	assert.NoError(t, json.Unmarshal(payload, &decoded))
+	objects, deletedObjects := []string{}, []string{}
+	for _, obj := range decoded.Objects {
+		objects = append(objects, obj.Name)
+	}
+	for _, obj := range decoded.DeletedObjects {
+		deletedObjects = append(deletedObjects, obj.Name)
+	}
+	return objects, deletedObjects
+}
+
+func TestPlanTargetedBundles(t *testing.T) {
+	binding := func(name, placementKind, placement string, policies ...string) *policyv1.PlacementBinding {
+		b := &policyv1.PlacementBinding{
+			ObjectMeta:   metav1.ObjectMeta{Name: name, Namespace: "default"},
+			PlacementRef: policyv1.PlacementSubject{Kind: placementKind, Name: placement},
+		}
+		for _, policy := range policies {
+			b.Subjects = append(b.Subjects, policyv1.Subject{Kind: policyv1.Kind, Name: policy})
+		}
+		return b
+	}
+	bindings := []*policyv1.PlacementBinding{
+		binding("binding1", placementKind, "placement1", "policy1"),
+		binding("binding2", placementKind, "placement2", "policy2"),
+		binding("binding3", "PlacementRule", "placementrule1", "policy3"),
+		binding("binding4", placementKind, "placement-undecided", "policy4"),
+	}
+	deleted := policyObject("policy-deleted")
+	deleted.deleted = true
+	objects := []specObject{
+		policyObject("policy1"), policyObject("policy2"), policyObject("policy3"), policyObject("policy4"),
+		policyObject("policy5"), deleted,
+	}
+	placementTargets := map[string]sets.Set[string]{
+		"default/placement1": sets.New("hub1", "hub2"),
+		"default/placement2": sets.New[string](),
+	}
+	hubs := sets.New("hub1", "hub2", "hub3")
+
+	bundles, objectTargets, _ := planTargetedBundles(objects, bindings, policyPlacements, placementTargets, hubs,
+		nil, map[string]sets.Set[string]{}, nil, time.Time{})
+	assert.Len(t, bundles, 3)
+	objs, deletedObjs := bundleNames(t, bundles[transport.Broadcast])
+	// policy3 is bound to the placement rule, policy4's placement isn't decided and policy5 isn't bound
+	assert.Equal(t, []string{"policy3", "policy4", "policy5"}, objs)
+	assert.Equal(t, []string{"policy-deleted"}, deletedObjs)
+	for _, hub := range []string{"hub1", "hub2"} {
+		objs, deletedObjs = bundleNames(t, bundles[hub])
+		assert.Equal(t, []string{"policy1"}, objs)
+		assert.Empty(t, deletedObjs)
+	}
+	assert.Equal(t, sets.New("hub1", "hub2"), objectTargets["default/policy1"])
+	assert.Equal(t, sets.New[string](), objectTargets["default/policy2"])
+	assert.Nil(t, objectTargets["default/policy4"])
+
+	// placement1 doesn't select the clusters of hub2 and placement-undecided is decided to hub3
+	placementTargets["default/placement1"] = sets.New("hub1")
+	placementTargets["default/placement-undecided"] = sets.New("hub3")
+	bundles, objectTargets, _ = planTargetedBundles(objects, bindings, policyPlacements, placementTargets, hubs,
+		nil, objectTargets, nil, time.Time{})
+	objs, _ = bundleNames(t, bundles[transport.Broadcast])
+	assert.Equal(t, []string{"policy3", "policy5"}, objs)
+	objs, deletedObjs = bundleNames(t, bundles["hub1"])
+	assert.Equal(t, []string{"policy1"}, objs)
+	// policy4 was broadcast, it's removed from the hubs which aren't selected
+	assert.Equal(t, []string{"policy4"}, deletedObjs)
+	objs, deletedObjs = bundleNames(t, bundles["hub2"])
+	assert.Empty(t, objs)
+	assert.Equal(t, []string{"policy1", "policy4"}, deletedObjs)
+	objs, deletedObjs = bundleNames(t, bundles["hub3"])
+	assert.Equal(t, []string{"policy4"}, objs)
+	assert.Empty(t, deletedObjs)
+
+	// the last targets are unknown after the manager restarts, the objects are removed from the hubs not selected
+	bundles, _, _ = planTargetedBundles(objects, bindings, policyPlacements, placementTargets, hubs,
+		nil, nil, nil, time.Time{})
+	objs, deletedObjs = bundleNames(t, bundles["hub1"])
+	assert.Equal(t, []string{"policy1"}, objs)
+	assert.Equal(t, []string{"policy2", "policy4"}, deletedObjs)
+	objs, deletedObjs = bundleNames(t, bundles["hub2"])
+	assert.Empty(t, objs)
+	assert.Equal(t, []string{"policy1", "policy2", "policy4"}, deletedObjs)
+	objs, deletedObjs = bundleNames(t, bundles["hub3"])
+	assert.Equal(t, []string{"policy4"}, objs)
+	assert.Equal(t, []string{"policy1", "policy2"}, deletedObjs)
+}
+
+func TestPlanTargetedDeltaBundles(t *testing.T) {
+	watermark := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	versionedObject := func(name string, version time.Time) specObject {
+		obj := policyObject(name)
+		obj.version = version
+		return obj
+	}
+	bindings := []*policyv1.PlacementBinding{{
+		ObjectMeta:   metav1.ObjectMeta{Name: "binding1", Namespace: "default"},
+		PlacementRef: policyv1.PlacementSubject{Kind: placementKind, Name: "placement1"},
+		Subjects: []policyv1.Subject{
+			{Kind: policyv1.Kind, Name: "policy1"},
+			{Kind: policyv1.Kind, Name: "policy2"},
+		},
+	}}
+	deleted := versionedObject("policy-deleted", watermark.Add(time.Second))
+	deleted.deleted = true
+	objects := []specObject{
+		versionedObject("policy1", watermark.Add(time.Second)),
+		versionedObject("policy2", watermark.Add(-time.Second)),
+		versionedObject("policy3", watermark.Add(-time.Second)),
+		versionedObject("policy4", watermark.Add(time.Second)),
+		versionedObject("policy-deleted-before", watermark.Add(-time.Second)),
+		deleted,
+	}
+	objects[4].deleted = true
+	placementTargets := map[string]sets.Set[string]{"default/placement1": sets.New("hub1", "hub2")}
+	lastObjectTargets := map[string]sets.Set[string]{
+		"default/policy1": sets.New("hub1"),
+		"default/policy2": sets.New("hub1"),
+		"default/policy3": nil,
+		"default/policy4": nil,
+	}
+
+	bundles, _, _ := planTargetedBundles(objects, bindings, policyPlacements, placementTargets,
+		sets.New("hub1", "hub2"), nil, lastObjectTargets, nil, watermark)
+	objs, deletedObjs := bundleNames(t, bundles[transport.Broadcast])
+	// policy3 isn't changed, and policy-deleted-before was deleted before the watermark
+	assert.Equal(t, []string{"policy4"}, objs)
+	assert.Equal(t, []string{"policy-deleted"}, deletedObjs)
+	objs, _ = bundleNames(t, bundles["hub1"])
+	assert.Equal(t, []string{"policy1"}, objs)
+	// policy2 isn't changed, but hub2 is newly selected by its placement
+	objs, _ = bundleNames(t, bundles["hub2"])
+	assert.Equal(t, []string{"policy1", "policy2"}, objs)
+}
+
+// fakeOverrides renders the overridden objects by suffixing their names with the managed hubs
+type fakeOverrides struct {
+	overridden sets.Set[string]
+	// revision is added to the rendered versions
+	revision string
+}
+
+func (o *fakeOverrides) Version() string { return o.revision }
+
+func (o *fakeOverrides) Overridden(object metav1.Object) bool {
+	return o.overridden.Has(object.GetName())
+}
+
+func (o *fakeOverrides) Render(object metav1.Object, hub string) (*unstructured.Unstructured, error) {
+	if hub == "hub-invalid" {
+		return nil, fmt.Errorf("failed to render the object for %s", hub)
+	}
+	rendered := &unstructured.Unstructured{}
+	rendered.SetName(object.GetName() + "-" + hub)
+	rendered.SetAnnotations(map[string]string{constants.SpecVersionAnnotation: o.revision})
+	return rendered, nil
+}
+
+func TestPlanOverriddenBundles(t *testing.T) {
+	watermark := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	bindings := []*policyv1.PlacementBinding{{
+		ObjectMeta:   metav1.ObjectMeta{Name: "binding1", Namespace: "default"},
+		PlacementRef: policyv1.PlacementSubject{Kind: placementKind, Name: "placement1"},
+		Subjects:     []policyv1.Subject{{Kind: policyv1.Kind, Name: "policy1"}},
+	}}
+	objects := []specObject{policyObject("policy1"), policyObject("policy2")}
+	placementTargets := map[string]sets.Set[string]{"default/placement1": sets.New("hub1")}
+	hubs := sets.New("hub1", "hub2", "hub-invalid")
+	overrides := &fakeOverrides{overridden: sets.New("policy1", "policy2"), revision: "1"}
+
+	bundles, objectTargets, renderedVersions := planTargetedBundles(objects, bindings, policyPlacements,
+		placementTargets, hubs, overrides, map[string]sets.Set[string]{}, nil, time.Time{})
+	objs, _ := bundleNames(t, bundles[transport.Broadcast])
+	assert.Empty(t, objs)
+	objs, _ = bundleNames(t, bundles["hub1"])
+	assert.Equal(t, []string{"policy1-hub1", "policy2-hub1"}, objs)
+	// the broadcast policy2 is rendered for each managed hub
+	objs, _ = bundleNames(t, bundles["hub2"])
+	assert.Equal(t, []string{"policy2-hub2"}, objs)
+	objs, _ = bundleNames(t, bundles["hub-invalid"])
+	assert.Empty(t, objs)
+	assert.Equal(t, hubs, objectTargets["default/policy2"])
+	assert.Equal(t, map[string]string{"hub1": "1", "hub2": "1"}, renderedVersions["default/policy2"])
+
+	// the unchanged objects are sent again once the rendered versions are changed
+	bundles, _, _ = planTargetedBundles(objects, bindings, policyPlacements, placementTargets, hubs, overrides,
+		objectTargets, renderedVersions, watermark)
+	assert.Len(t, bundles, 1)
+	overrides.revision = "2"
+	bundles, _, _ = planTargetedBundles(objects, bindings, policyPlacements, placementTargets, hubs, overrides,
+		objectTargets, renderedVersions, watermark)
+	objs, _ = bundleNames(t, bundles["hub1"])
+	assert.Equal(t, []string{"policy1-hub1", "policy2-hub1"}, objs)
+
+	// the original objects are sent once the overrides are removed
+	bundles, objectTargets, renderedVersions = planTargetedBundles(objects, bindings, policyPlacements,
+		placementTargets, hubs, &fakeOverrides{overridden: sets.New[string]()}, objectTargets, renderedVersions,
+		watermark)
+	objs, deletedObjs := bundleNames(t, bundles[transport.Broadcast])
+	assert.Equal(t, []string{"policy2"}, objs)
+	assert.Empty(t, deletedObjs)
+	objs, _ = bundleNames(t, bundles["hub1"])
+	assert.Equal(t, []string{"policy1"}, objs)
+	assert.Nil(t, objectTargets["default/policy2"])
+	assert.Empty(t, renderedVersions)
+}
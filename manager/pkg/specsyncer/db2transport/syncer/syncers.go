@@ -26,6 +26,8 @@ func AddDB2TransportSyncers(mgr ctrl.Manager, managerConfig *config.ManagerConfi
 		dbsyncer.AddSubscriptionsDBToTransportSyncer,
 		dbsyncer.AddChannelsDBToTransportSyncer,
 		dbsyncer.AddManagedClusterLabelsDBToTransportSyncer,
+		dbsyncer.AddManagedClusterOperationsDBToTransportSyncer,
+		dbsyncer.AddManagedClusterActionsDBToTransportSyncer,
 		dbsyncer.AddPlacementsDBToTransportSyncer,
 		dbsyncer.AddManagedClusterSetsDBToTransportSyncer,
 		dbsyncer.AddManagedClusterSetBindingsDBToTransportSyncer,
@@ -95,10 +95,10 @@ func (k *ConflationCommitter) commit() error {
 	conn := database.GetConn()
 
 	err := database.Lock(conn)
-	defer database.Unlock(conn)
 	if err != nil {
 		return err
 	}
+	defer database.Unlock(conn)
 	if len(databaseTransports) > 0 {
 		err := db.Clauses(clause.OnConflict{
 			UpdateAll: true,
@@ -1,14 +1,17 @@
 package conflator
 
 import (
+	"context"
 	"sync"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator/metadata"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/consumer"
 )
 
@@ -58,7 +61,12 @@ func (cm *ConflationManager) Insert(evt *cloudevents.Event) {
 		return
 	}
 
-	cm.getConflationUnit(evt.Source()).insert(evt, conflationMetadata)
+	// the job carries the span of the insertion, which is continued by the database worker
+	ctx, span := tracing.Start(context.Background(), "conflation.insert", evt)
+	tracing.Inject(ctx, evt)
+	inserted := cm.getConflationUnit(evt.Source()).insert(evt, conflationMetadata)
+	span.SetAttributes(attribute.Bool("globalhub.conflation.stale", !inserted))
+	span.End()
 }
 
 // GetTransportMetadatas provides collections of the CU's bundle transport-metadata.
@@ -16,6 +16,7 @@ const (
 	LocalReplicatedPolicyEventPriority ConflationPriority = iota
 	LocalPlacementRulesSpecPriority    ConflationPriority = iota
 	SecurityAlertCountsPriority        ConflationPriority = iota
+	PolicyViolationPriority            ConflationPriority = iota
 
 	// enable global resource
 	CompliancePriority         ConflationPriority = iota
@@ -29,4 +30,8 @@ const (
 
 	SubscriptionStatusPriority ConflationPriority = iota
 	SubscriptionReportPriority ConflationPriority = iota
+
+	ManagedClusterOperationResultPriority ConflationPriority = iota
+	SpecDriftEventPriority                ConflationPriority = iota
+	ManagedClusterActionResultPriority    ConflationPriority = iota
 )
@@ -52,8 +52,9 @@ func newConflationUnit(name string, readyQueue *ConflationReadyQueue,
 	return conflationUnit
 }
 
-// insert is an internal function, new bundles are inserted only via conflation manager.
-func (cu *ConflationUnit) insert(event *cloudevents.Event, eventMetadata ConflationMetadata) {
+// insert is an internal function, new bundles are inserted only via conflation manager. It returns false if the
+// bundle isn't inserted, e.g. it's stale.
+func (cu *ConflationUnit) insert(event *cloudevents.Event, eventMetadata ConflationMetadata) bool {
 	cu.lock.Lock()
 	defer cu.lock.Unlock()
 
@@ -61,11 +62,12 @@ func (cu *ConflationUnit) insert(event *cloudevents.Event, eventMetadata Conflat
 	conflationElement := cu.ElementPriorityQueue[priority]
 	if conflationElement == nil {
 		cu.log.Info("the conflationElement hasn't been registered to conflation unit", "eventType", event.Type())
-		return
+		return false
 	}
 
 	if !conflationElement.Predicate(eventMetadata.Version()) {
-		return
+		cu.statistics.StaleEvent(event)
+		return false
 	}
 
 	// for the delta element, insert the ready queue directly and process one by one
@@ -76,6 +78,7 @@ func (cu *ConflationUnit) insert(event *cloudevents.Event, eventMetadata Conflat
 	// if we got here, we got bundle with newer version
 	// update the bundle in the priority queue.
 	conflationElement.AddToReadyQueue(event, eventMetadata, cu)
+	return true
 }
 
 // GetNext returns the next ready to be processed bundle and its transport metadata.
@@ -124,6 +127,7 @@ func (cu *ConflationUnit) addCUToReadyQueueIfNeeded() {
 	if element != nil { // there is a ready to be processed bundle
 		cu.readyQueue.ConflationUnitChan <- cu // let the dispatcher know this CU has a ready to be processed bundle
 		cu.isInReadyQueue = true
+		cu.readyQueue.ReportSize()
 	}
 }
 
@@ -20,3 +20,8 @@ type ConflationReadyQueue struct {
 	DeltaEventJobChan  chan *ConflationJob
 	ConflationUnitChan chan *ConflationUnit
 }
+
+// ReportSize reports the number of the conflation units and the delta event jobs waiting in the queue.
+func (rq *ConflationReadyQueue) ReportSize() {
+	rq.statistics.SetConflationReadyQueueSize(len(rq.DeltaEventJobChan) + len(rq.ConflationUnitChan))
+}
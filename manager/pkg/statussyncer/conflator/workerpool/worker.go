@@ -5,11 +5,13 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
 	"k8s.io/apimachinery/pkg/util/wait"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 )
 
 // NewWorker creates a new instance of DBWorker.
@@ -64,21 +66,24 @@ func (worker *Worker) start(ctx context.Context) {
 
 func (worker *Worker) handleJob(ctx context.Context, job *conflator.ConflationJob) {
 	startTime := time.Now()
+	ctx, span := tracing.Start(ctx, "status.worker", job.Event,
+		attribute.Int("globalhub.worker.id", int(worker.workerID)))
 	conn := database.GetConn()
 
 	err := database.Lock(conn)
-
-	defer database.Unlock(conn)
-
 	if err != nil {
 		worker.log.Error(err, "failed to get db lock")
+		tracing.End(span, err)
 		return
 	}
+	defer database.Unlock(conn)
 
 	// handle the event until it's metadata is marked as processed
 	err = wait.PollUntilContextTimeout(ctx, 2*time.Second, 5*time.Minute, true,
 		func(ctx context.Context) (bool, error) {
-			err = job.Handle(ctx, job.Event) // db connection released to pool when done
+			handleCtx, handleSpan := tracing.Start(ctx, "status.handle", job.Event)
+			err = job.Handle(handleCtx, job.Event) // db connection released to pool when done
+			tracing.End(handleSpan, err)
 			if err != nil {
 				job.Metadata.MarkAsUnprocessed()
 				worker.log.Error(err, "failed to handle event", "type", job.Event.Type())
@@ -94,6 +99,7 @@ func (worker *Worker) handleJob(ctx context.Context, job *conflator.ConflationJo
 		})
 
 	worker.statistics.AddDatabaseMetrics(job.Event, time.Since(startTime), err)
+	tracing.End(span, err)
 
 	job.Reporter.ReportResult(job.Metadata, err)
 
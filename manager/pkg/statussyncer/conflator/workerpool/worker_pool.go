@@ -44,6 +44,7 @@ func (pool *DBWorkerPool) Start(ctx context.Context) error {
 
 	// initialize workers pool
 	pool.workers = make(chan *Worker, workSize)
+	pool.statistics.SetNumberOfDBWorkers(workSize)
 
 	// start workers and register them within the workers pool
 	var i int32
@@ -69,6 +70,7 @@ func (pool *DBWorkerPool) Acquire() (*Worker, error) {
 	for i := 0; i < 6; i += 1 {
 		select {
 		case res := <-pool.workers:
+			pool.statistics.SetNumberOfAvailableDBWorkers(len(pool.workers))
 			return res, nil
 		case <-ticker.C:
 			pool.log.V(2).Info("the db workers are not available, retrying", "seconds", i*10+10)
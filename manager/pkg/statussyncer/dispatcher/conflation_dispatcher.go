@@ -77,9 +77,11 @@ func (dispatcher *ConflationDispatcher) dispatch(ctx context.Context) {
 			return
 
 		case deltaEventJob := <-dispatcher.conflationReadyQueue.DeltaEventJobChan:
+			dispatcher.conflationReadyQueue.ReportSize()
 			worker := dispatcher.getBlockingWorker(ctx)
 			worker.RunAsync(deltaEventJob)
 		case conflationUnit := <-dispatcher.conflationReadyQueue.ConflationUnitChan:
+			dispatcher.conflationReadyQueue.ReportSize()
 			eventJob, err := conflationUnit.GetNext()
 			if err != nil {
 				dispatcher.log.Info(err.Error()) // don't need to throw the error when bundle is not ready
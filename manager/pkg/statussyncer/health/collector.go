@@ -0,0 +1,72 @@
+package health
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
+)
+
+var (
+	receivedDesc = prometheus.NewDesc("multicluster_global_hub_status_received_total",
+		"The number of the status events received from the managed hub.", []string{"hub", "type"}, nil)
+	processedDesc = prometheus.NewDesc("multicluster_global_hub_status_processed_total",
+		"The number of the status events persisted into the database.", []string{"hub", "type"}, nil)
+	failuresDesc = prometheus.NewDesc("multicluster_global_hub_status_failures_total",
+		"The number of the status events failed to be persisted into the database.", []string{"hub", "type"}, nil)
+	lastReceivedDesc = prometheus.NewDesc("multicluster_global_hub_status_last_received_timestamp_seconds",
+		"The time when the last status event is received from the managed hub.", []string{"hub", "type"}, nil)
+	lastProcessedDesc = prometheus.NewDesc("multicluster_global_hub_status_last_processed_timestamp_seconds",
+		"The time when the last status event is persisted into the database.", []string{"hub", "type"}, nil)
+	latencyDesc = prometheus.NewDesc("multicluster_global_hub_status_processing_latency_seconds",
+		"The duration from the event time to the database commit of the last processed status event.",
+		[]string{"hub", "type"}, nil)
+	consumerLagDesc = prometheus.NewDesc("multicluster_global_hub_status_consumer_lag",
+		"The number of the messages in the status topic which aren't committed by the manager.",
+		[]string{"hub", "topic"}, nil)
+)
+
+// syncHealthCollector exports the sync health of the managed hubs when the metrics are scraped
+type syncHealthCollector struct {
+	stats    *statistics.Statistics
+	reporter *SyncHealthReporter
+}
+
+func NewSyncHealthCollector(stats *statistics.Statistics, reporter *SyncHealthReporter) prometheus.Collector {
+	return &syncHealthCollector{stats: stats, reporter: reporter}
+}
+
+func (c *syncHealthCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- receivedDesc
+	ch <- processedDesc
+	ch <- failuresDesc
+	ch <- lastReceivedDesc
+	ch <- lastProcessedDesc
+	ch <- latencyDesc
+	ch <- consumerLagDesc
+}
+
+func (c *syncHealthCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, health := range c.stats.HubSyncHealth() {
+		labels := []string{health.LeafHubName, health.EventType}
+		ch <- prometheus.MustNewConstMetric(receivedDesc, prometheus.CounterValue, float64(health.Received),
+			labels...)
+		ch <- prometheus.MustNewConstMetric(processedDesc, prometheus.CounterValue, float64(health.Processed),
+			labels...)
+		ch <- prometheus.MustNewConstMetric(failuresDesc, prometheus.CounterValue, float64(health.Failures),
+			labels...)
+		if !health.LastReceivedAt.IsZero() {
+			ch <- prometheus.MustNewConstMetric(lastReceivedDesc, prometheus.GaugeValue,
+				float64(health.LastReceivedAt.Unix()), labels...)
+		}
+		if !health.LastProcessedAt.IsZero() {
+			ch <- prometheus.MustNewConstMetric(lastProcessedDesc, prometheus.GaugeValue,
+				float64(health.LastProcessedAt.Unix()), labels...)
+			ch <- prometheus.MustNewConstMetric(latencyDesc, prometheus.GaugeValue,
+				health.ProcessingLatency.Seconds(), labels...)
+		}
+	}
+	for _, lag := range c.reporter.Lags() {
+		ch <- prometheus.MustNewConstMetric(consumerLagDesc, prometheus.GaugeValue, float64(lag.Lag),
+			lag.LeafHubName, lag.Topic)
+	}
+}
@@ -0,0 +1,45 @@
+package health
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+func TestSyncHealthCollector(t *testing.T) {
+	stats := statistics.NewStatistics(&statistics.StatisticsConfig{})
+	stats.Register("policy")
+
+	evt := cloudevents.NewEvent()
+	evt.SetSource("hub1")
+	evt.SetType("policy")
+	stats.ReceivedEvent(&evt)
+	stats.ReceivedEvent(&evt)
+	stats.AddDatabaseMetrics(&evt, time.Millisecond, nil)
+
+	reporter := NewSyncHealthReporter(stats, &transport.TransportInternalConfig{})
+	reporter.lags = []TopicLag{{Topic: "gh-status.hub1", LeafHubName: "hub1", Lag: 3}}
+
+	expected := `
+# HELP multicluster_global_hub_status_consumer_lag The number of the messages in the status topic which aren't committed by the manager.
+# TYPE multicluster_global_hub_status_consumer_lag gauge
+multicluster_global_hub_status_consumer_lag{hub="hub1",topic="gh-status.hub1"} 3
+# HELP multicluster_global_hub_status_processed_total The number of the status events persisted into the database.
+# TYPE multicluster_global_hub_status_processed_total counter
+multicluster_global_hub_status_processed_total{hub="hub1",type="policy"} 1
+# HELP multicluster_global_hub_status_received_total The number of the status events received from the managed hub.
+# TYPE multicluster_global_hub_status_received_total counter
+multicluster_global_hub_status_received_total{hub="hub1",type="policy"} 2
+`
+	err := testutil.CollectAndCompare(NewSyncHealthCollector(stats, reporter), strings.NewReader(expected),
+		"multicluster_global_hub_status_consumer_lag", "multicluster_global_hub_status_processed_total",
+		"multicluster_global_hub_status_received_total")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,80 @@
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	transportconfig "github.com/stolostron/multicluster-global-hub/pkg/transport/config"
+)
+
+const watermarkTimeoutMs = 5000
+
+// watermarkQuerier queries the high watermark of the topic partitions, it's implemented by the kafka consumer
+type watermarkQuerier interface {
+	QueryWatermarkOffsets(topic string, partition int32, timeoutMs int) (low, high int64, err error)
+	Close() error
+}
+
+// TopicLag is the number of the messages in the status topic which aren't committed by the manager
+type TopicLag struct {
+	Topic       string
+	LeafHubName string
+	Lag         int64
+}
+
+func newWatermarkQuerier(kafkaConfig *transport.KafkaConfig) (watermarkQuerier, error) {
+	configMap, err := transportconfig.GetConfluentConfigMapByKafkaCredential(kafkaConfig, lagConsumerGroupID)
+	if err != nil {
+		return nil, err
+	}
+	return kafka.NewConsumer(configMap)
+}
+
+// consumerLags compares the committed offsets of the status topics with the high watermarks, the committed offset
+// is the next offset to consume
+func consumerLags(querier watermarkQuerier, kafkaConfig *transport.KafkaConfig) ([]TopicLag, error) {
+	positions := []models.Transport{}
+	err := database.GetGorm().Where("payload->>'ownerIdentity' = ?", kafkaConfig.ClusterID).
+		Find(&positions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the committed offsets - %w", err)
+	}
+
+	lags := []TopicLag{}
+	for _, position := range positions {
+		eventPosition := transport.EventPosition{}
+		if err := json.Unmarshal(position.Payload, &eventPosition); err != nil {
+			return nil, err
+		}
+		_, high, err := querier.QueryWatermarkOffsets(position.Name, eventPosition.Partition, watermarkTimeoutMs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query the watermark of the topic %s - %w", position.Name, err)
+		}
+		lag := high - eventPosition.Offset
+		if lag < 0 {
+			lag = 0
+		}
+		lags = append(lags, TopicLag{
+			Topic:       position.Name,
+			LeafHubName: topicHub(kafkaConfig.StatusTopic, position.Name),
+			Lag:         lag,
+		})
+	}
+	return lags, nil
+}
+
+// topicHub returns the managed hub of the status topic, the manager subscribes the pattern like "^gh-status.*" and
+// the hub "hub1" sends to the topic "gh-status.hub1". It returns empty if the topic is shared by the hubs.
+func topicHub(statusTopic, topic string) string {
+	prefix, found := strings.CutSuffix(strings.TrimPrefix(statusTopic, "^"), "*")
+	if !found || !strings.HasPrefix(topic, prefix) {
+		return ""
+	}
+	return strings.TrimPrefix(topic, prefix)
+}
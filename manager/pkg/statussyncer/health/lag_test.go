@@ -0,0 +1,23 @@
+package health
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopicHub(t *testing.T) {
+	cases := []struct {
+		statusTopic string
+		topic       string
+		hub         string
+	}{
+		{"^gh-status.*", "gh-status.hub1", "hub1"},
+		{"gh-status.*", "gh-status.hub2", "hub2"},
+		{"^gh-status.*", "gh-event.hub1", ""},
+		{"gh-status", "gh-status", ""},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.hub, topicHub(tc.statusTopic, tc.topic), tc.topic)
+	}
+}
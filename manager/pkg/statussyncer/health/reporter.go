@@ -0,0 +1,135 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"gorm.io/gorm/clause"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+const (
+	reportInterval     = 30 * time.Second
+	lagConsumerGroupID = "global-hub-manager-lag"
+)
+
+// SyncHealthReporter persists the per-hub sync health from the statistics into the status.sync_health table
+// periodically, together with the consumer lag of the status topics if the transport is kafka.
+type SyncHealthReporter struct {
+	log             logr.Logger
+	stats           *statistics.Statistics
+	transportConfig *transport.TransportInternalConfig
+	querier         watermarkQuerier
+
+	mutex sync.Mutex
+	lags  []TopicLag
+}
+
+func NewSyncHealthReporter(stats *statistics.Statistics,
+	transportConfig *transport.TransportInternalConfig,
+) *SyncHealthReporter {
+	return &SyncHealthReporter{
+		log:             ctrl.Log.WithName("sync-health-reporter"),
+		stats:           stats,
+		transportConfig: transportConfig,
+	}
+}
+
+func (r *SyncHealthReporter) Start(ctx context.Context) error {
+	ticker := time.NewTicker(reportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if r.querier != nil {
+				_ = r.querier.Close()
+			}
+			return nil
+		case <-ticker.C:
+			r.updateLags()
+			if err := r.persist(); err != nil {
+				r.log.Error(err, "failed to persist the sync health")
+			}
+		}
+	}
+}
+
+// Lags returns the latest consumer lags of the status topics
+func (r *SyncHealthReporter) Lags() []TopicLag {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]TopicLag{}, r.lags...)
+}
+
+func (r *SyncHealthReporter) updateLags() {
+	kafkaConfig := r.transportConfig.KafkaCredential
+	if r.transportConfig.TransportType != string(transport.Kafka) || kafkaConfig == nil {
+		return
+	}
+	if r.querier == nil {
+		querier, err := newWatermarkQuerier(kafkaConfig)
+		if err != nil {
+			r.log.Error(err, "failed to create the kafka client to query the watermarks")
+			return
+		}
+		r.querier = querier
+	}
+
+	lags, err := consumerLags(r.querier, kafkaConfig)
+	if err != nil {
+		// recreate the client in the next round, since the kafka credential might be rotated
+		r.log.Error(err, "failed to get the consumer lags")
+		_ = r.querier.Close()
+		r.querier = nil
+		return
+	}
+	r.mutex.Lock()
+	r.lags = lags
+	r.mutex.Unlock()
+}
+
+func (r *SyncHealthReporter) persist() error {
+	rows := []models.SyncHealth{}
+	for _, health := range r.stats.HubSyncHealth() {
+		row := models.SyncHealth{
+			LeafHubName:          health.LeafHubName,
+			EventType:            health.EventType,
+			LastProcessedVersion: health.LastProcessedVersion,
+			ProcessingLatencyMs:  health.ProcessingLatency.Milliseconds(),
+			Received:             health.Received,
+			Processed:            health.Processed,
+			Failures:             health.Failures,
+			LastError:            health.LastError,
+		}
+		if !health.LastReceivedAt.IsZero() {
+			row.LastReceivedAt = &health.LastReceivedAt
+		}
+		if !health.LastProcessedAt.IsZero() {
+			row.LastProcessedAt = &health.LastProcessedAt
+		}
+		rows = append(rows, row)
+	}
+	for _, lag := range r.Lags() {
+		// the lag of the shared topic can't be attributed to a managed hub
+		if lag.LeafHubName == "" {
+			continue
+		}
+		rows = append(rows, models.SyncHealth{
+			LeafHubName: lag.LeafHubName,
+			EventType:   database.SyncHealthTransportType,
+			Topic:       lag.Topic,
+			ConsumerLag: lag.Lag,
+		})
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return database.GetGorm().Clauses(clause.OnConflict{UpdateAll: true}).CreateInBatches(rows, 100).Error
+}
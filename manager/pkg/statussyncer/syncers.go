@@ -4,10 +4,12 @@ import (
 	"fmt"
 
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/config"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/dispatcher"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/health"
 	dbsyncer "github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/syncers"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
@@ -46,6 +48,18 @@ func AddStatusSyncers(mgr ctrl.Manager, consumer transport.Consumer, managerConf
 	if err := mgr.Add(committer); err != nil {
 		return fmt.Errorf("failed to start the offset committer: %w", err)
 	}
+
+	// persist and export the sync health of the managed hubs
+	reporter := health.NewSyncHealthReporter(stats, managerConfig.TransportConfig)
+	if err := mgr.Add(reporter); err != nil {
+		return fmt.Errorf("failed to start the sync health reporter: %w", err)
+	}
+	if err := metrics.Registry.Register(health.NewSyncHealthCollector(stats, reporter)); err != nil {
+		return fmt.Errorf("failed to register the sync health metrics: %w", err)
+	}
+	if err := statistics.RegisterMetrics(); err != nil {
+		return fmt.Errorf("failed to register the status pipeline metrics: %w", err)
+	}
 	statusCtrlStarted = true
 	return nil
 }
@@ -62,6 +76,7 @@ func registerHandler(cmr *conflator.ConflationManager, enableGlobalResource bool
 	dbsyncer.NewLocalReplicatedPolicyEventHandler().RegisterHandler(cmr)
 	dbsyncer.NewLocalPlacementRuleSpecHandler().RegisterHandler(cmr)
 	dbsyncer.NewSecurityAlertCountsHandler().RegisterHandler(cmr)
+	dbsyncer.NewPolicyViolationHandler().RegisterHandler(cmr)
 	if enableGlobalResource {
 		dbsyncer.NewPolicyComplianceHandler().RegisterHandler(cmr)
 		dbsyncer.NewPolicyCompleteHandler().RegisterHandler(cmr)
@@ -74,5 +89,9 @@ func registerHandler(cmr *conflator.ConflationManager, enableGlobalResource bool
 
 		dbsyncer.NewSubscriptionReportHandler().RegisterHandler(cmr)
 		dbsyncer.NewSubscriptionStatusHandler().RegisterHandler(cmr)
+
+		dbsyncer.NewManagedClusterOperationResultHandler().RegisterHandler(cmr)
+		dbsyncer.NewSpecDriftEventHandler().RegisterHandler(cmr)
+		dbsyncer.NewManagedClusterActionResultHandler().RegisterHandler(cmr)
 	}
 }
@@ -0,0 +1,72 @@
+package dbsyncer
+
+import (
+	"context"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+type managedClusterActionResultHandler struct {
+	log           logr.Logger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+func NewManagedClusterActionResultHandler() conflator.Handler {
+	eventType := string(enum.ManagedClusterActionResultType)
+	logName := strings.Replace(eventType, enum.EventTypePrefix, "", -1)
+	return &managedClusterActionResultHandler{
+		log:           ctrl.Log.WithName(logName),
+		eventType:     eventType,
+		eventSyncMode: enum.DeltaStateMode,
+		eventPriority: conflator.ManagedClusterActionResultPriority,
+	}
+}
+
+func (h *managedClusterActionResultHandler) RegisterHandler(conflationManager *conflator.ConflationManager) {
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+// handleEvent updates the result of the cluster lifecycle action applied by the agent of the managed hub
+func (h *managedClusterActionResultHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.V(2).Info(startMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+
+	result := spec.ManagedClusterActionResult{}
+	if err := evt.DataAs(&result); err != nil {
+		return err
+	}
+
+	state := database.OperationSucceeded
+	if !result.Succeeded {
+		state = database.OperationFailed
+	}
+	// the message of the succeeded result is reset by the select
+	err := database.GetGorm().Model(&models.ManagedClusterAction{}).
+		Where("id = ? AND leaf_hub_name = ?", result.ActionID, leafHubName).
+		Select("state", "message", "updated_at").
+		Updates(&models.ManagedClusterAction{State: state, Message: result.Message}).Error
+	if err != nil {
+		return err
+	}
+
+	h.log.V(2).Info(finishMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+	return nil
+}
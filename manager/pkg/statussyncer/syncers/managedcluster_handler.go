@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
@@ -65,6 +66,7 @@ func (h *managedClusterHandler) handleEvent(ctx context.Context, evt *cloudevent
 
 	// batch update/insert managed clusters
 	batchManagedClusters := []models.ManagedCluster{}
+	changedClusters := map[string]*clusterv1.ManagedCluster{}
 	for _, object := range data {
 		cluster := object
 
@@ -93,6 +95,7 @@ func (h *managedClusterHandler) handleEvent(ctx context.Context, evt *cloudevent
 				Payload:     payload,
 				Error:       database.ErrorNone,
 			})
+			changedClusters[clusterId] = &cluster
 			continue
 		}
 
@@ -109,30 +112,44 @@ func (h *managedClusterHandler) handleEvent(ctx context.Context, evt *cloudevent
 			Payload:     payload,
 			Error:       database.ErrorNone,
 		})
+		changedClusters[clusterId] = &cluster
 	}
-	err = db.Clauses(clause.OnConflict{
-		UpdateAll: true,
-	}).CreateInBatches(batchManagedClusters, 100).Error
+
+	// the history is generated from the clusters in the db before they're updated or deleted
+	history, err := getClusterHistory(db, leafHubName, changedClusters, clusterIdToVersionMapFromDB)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed generating managed clusters history - %w", err)
 	}
 
-	// delete objects that in the db but were not sent in the bundle (leaf hub sends only living resources).
-	// https://gorm.io/docs/delete.html#Soft-Delete
+	// the clusters and their history are committed together, otherwise the history is lost once the clusters are
+	// updated, since the retry doesn't find the changes from the db
 	err = db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			UpdateAll: true,
+		}).CreateInBatches(batchManagedClusters, 100).Error
+		if err != nil {
+			return err
+		}
+
+		// delete objects that in the db but were not sent in the bundle (leaf hub sends only living resources).
+		// https://gorm.io/docs/delete.html#Soft-Delete
 		for clusterId := range clusterIdToVersionMapFromDB {
 			e := tx.Where(&models.ManagedCluster{
 				LeafHubName: leafHubName,
 				ClusterID:   clusterId,
 			}).Delete(&models.ManagedCluster{}).Error
 			if e != nil {
-				return e
+				return fmt.Errorf("failed deleting managed clusters - %w", e)
 			}
 		}
+
+		if err := tx.CreateInBatches(history, 100).Error; err != nil {
+			return fmt.Errorf("failed inserting managed clusters history - %w", err)
+		}
 		return nil
 	})
 	if err != nil {
-		return fmt.Errorf("failed deleting managed clusters - %w", err)
+		return err
 	}
 
 	h.log.V(2).Info(finishMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
@@ -155,3 +172,60 @@ func getClusterIdToVersionMap(db *gorm.DB, leafHubName string) (map[string]strin
 	}
 	return nameToVersionMap, nil
 }
+
+// getClusterHistory compares the changed and deleted clusters of the leaf hub with the ones in the db, including the
+// deleted ones and the ones owned by other hubs, to generate the field level changes of them
+func getClusterHistory(db *gorm.DB, leafHubName string, changedClusters map[string]*clusterv1.ManagedCluster,
+	deletedClusters map[string]string,
+) ([]models.ManagedClusterHistory, error) {
+	history := []models.ManagedClusterHistory{}
+	clusterIds := make([]string, 0, len(changedClusters)+len(deletedClusters))
+	for clusterId := range changedClusters {
+		clusterIds = append(clusterIds, clusterId)
+	}
+	for clusterId := range deletedClusters {
+		clusterIds = append(clusterIds, clusterId)
+	}
+	if len(clusterIds) == 0 {
+		return history, nil
+	}
+	sort.Strings(clusterIds)
+
+	var previousClusters []models.ManagedCluster
+	if err := db.Unscoped().Where("cluster_id IN ?", clusterIds).Find(&previousClusters).Error; err != nil {
+		return nil, err
+	}
+	previousClusterMap := make(map[string]models.ManagedCluster, len(previousClusters))
+	for _, previousCluster := range previousClusters {
+		previousClusterMap[previousCluster.ClusterID] = previousCluster
+	}
+
+	for _, clusterId := range clusterIds {
+		cluster, changed := changedClusters[clusterId]
+		record, exist := previousClusterMap[clusterId]
+		if !exist {
+			if changed {
+				history = append(history, hubHistory(clusterId, cluster.Name, "", leafHubName))
+			}
+			continue
+		}
+
+		previous := &clusterv1.ManagedCluster{}
+		if err := json.Unmarshal(record.Payload, previous); err != nil {
+			return nil, err
+		}
+		previousHub := record.LeafHubName
+		if record.DeletedAt.Valid {
+			previousHub = ""
+		}
+		if !changed {
+			history = append(history, hubHistory(clusterId, previous.Name, previousHub, ""))
+			continue
+		}
+		if previousHub != leafHubName {
+			history = append(history, hubHistory(clusterId, cluster.Name, previousHub, leafHubName))
+		}
+		history = append(history, clusterHistory(clusterId, leafHubName, previous, cluster)...)
+	}
+	return history, nil
+}
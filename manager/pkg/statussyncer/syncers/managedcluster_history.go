@@ -0,0 +1,106 @@
+package dbsyncer
+
+import (
+	"sort"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// clusterHistory returns the field level changes of the labels, version, conditions and claims from the previous
+// cluster to the current one
+func clusterHistory(clusterID, leafHubName string, previous, current *clusterv1.ManagedCluster,
+) []models.ManagedClusterHistory {
+	changes := []models.ManagedClusterHistory{}
+	appendChanges := func(field database.ClusterHistoryField, oldValues, newValues map[string]string) {
+		for _, key := range unionKeys(oldValues, newValues) {
+			oldValue, oldFound := oldValues[key]
+			newValue, newFound := newValues[key]
+			if oldFound == newFound && oldValue == newValue {
+				continue
+			}
+			change := models.ManagedClusterHistory{
+				ClusterID:   clusterID,
+				ClusterName: current.Name,
+				LeafHubName: leafHubName,
+				Field:       field,
+				Key:         key,
+			}
+			if oldFound {
+				change.OldValue = &oldValue
+			}
+			if newFound {
+				change.NewValue = &newValue
+			}
+			changes = append(changes, change)
+		}
+	}
+
+	appendChanges(database.ClusterHistoryLabel, previous.Labels, current.Labels)
+	appendChanges(database.ClusterHistoryVersion, versionValues(previous), versionValues(current))
+	appendChanges(database.ClusterHistoryCondition, conditionValues(previous), conditionValues(current))
+	appendChanges(database.ClusterHistoryClaim, claimValues(previous), claimValues(current))
+	return changes
+}
+
+// hubHistory returns the change of the managed hub which owns the cluster, the previous hub is empty if the cluster is
+// new or has been deleted, and the current hub is empty if the cluster is deleted
+func hubHistory(clusterID, clusterName, previousHub, currentHub string) models.ManagedClusterHistory {
+	change := models.ManagedClusterHistory{
+		ClusterID:   clusterID,
+		ClusterName: clusterName,
+		LeafHubName: currentHub,
+		Field:       database.ClusterHistoryHub,
+	}
+	if previousHub != "" {
+		change.OldValue = &previousHub
+	}
+	if currentHub != "" {
+		change.NewValue = &currentHub
+	} else {
+		// the deleted cluster is recorded for the hub which owned it
+		change.LeafHubName = previousHub
+	}
+	return change
+}
+
+func versionValues(cluster *clusterv1.ManagedCluster) map[string]string {
+	if cluster.Status.Version.Kubernetes == "" {
+		return nil
+	}
+	return map[string]string{"": cluster.Status.Version.Kubernetes}
+}
+
+// conditionValues returns the status of the conditions, the changes of the reason and the message aren't recorded
+func conditionValues(cluster *clusterv1.ManagedCluster) map[string]string {
+	values := map[string]string{}
+	for _, condition := range cluster.Status.Conditions {
+		values[condition.Type] = string(condition.Status)
+	}
+	return values
+}
+
+func claimValues(cluster *clusterv1.ManagedCluster) map[string]string {
+	values := map[string]string{}
+	for _, claim := range cluster.Status.ClusterClaims {
+		values[claim.Name] = claim.Value
+	}
+	return values
+}
+
+func unionKeys(maps ...map[string]string) []string {
+	keys := []string{}
+	found := map[string]struct{}{}
+	for _, m := range maps {
+		for key := range m {
+			if _, ok := found[key]; !ok {
+				found[key] = struct{}{}
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
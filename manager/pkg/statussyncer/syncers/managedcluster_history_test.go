@@ -0,0 +1,94 @@
+package dbsyncer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+func TestClusterHistory(t *testing.T) {
+	previous := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster1", Labels: map[string]string{"env": "dev", "vendor": "OpenShift"}},
+		Status: clusterv1.ManagedClusterStatus{
+			Version: clusterv1.ManagedClusterVersion{Kubernetes: "v1.28.6"},
+			Conditions: []metav1.Condition{
+				{Type: clusterv1.ManagedClusterConditionAvailable, Status: metav1.ConditionTrue, Reason: "Available"},
+				{Type: clusterv1.ManagedClusterConditionJoined, Status: metav1.ConditionTrue},
+			},
+			ClusterClaims: []clusterv1.ManagedClusterClaim{{Name: "version.openshift.io", Value: "4.15.10"}},
+		},
+	}
+	current := previous.DeepCopy()
+	current.Labels = map[string]string{"env": "prod", "region": "apac"}
+	current.Status.Version.Kubernetes = "v1.29.5"
+	// the change of the reason isn't recorded
+	current.Status.Conditions = []metav1.Condition{
+		{Type: clusterv1.ManagedClusterConditionAvailable, Status: metav1.ConditionUnknown, Reason: "Unreachable"},
+		{Type: clusterv1.ManagedClusterConditionJoined, Status: metav1.ConditionTrue, Reason: "Joined"},
+	}
+	current.Status.ClusterClaims = []clusterv1.ManagedClusterClaim{{Name: "version.openshift.io", Value: "4.16.3"}}
+
+	value := func(v string) *string { return &v }
+	change := func(field database.ClusterHistoryField, key string, oldValue, newValue *string,
+	) models.ManagedClusterHistory {
+		return models.ManagedClusterHistory{
+			ClusterID:   "cluster-id",
+			ClusterName: "cluster1",
+			LeafHubName: "hub1",
+			Field:       field,
+			Key:         key,
+			OldValue:    oldValue,
+			NewValue:    newValue,
+		}
+	}
+	assert.Equal(t, []models.ManagedClusterHistory{
+		change(database.ClusterHistoryLabel, "env", value("dev"), value("prod")),
+		change(database.ClusterHistoryLabel, "region", nil, value("apac")),
+		change(database.ClusterHistoryLabel, "vendor", value("OpenShift"), nil),
+		change(database.ClusterHistoryVersion, "", value("v1.28.6"), value("v1.29.5")),
+		change(database.ClusterHistoryCondition, clusterv1.ManagedClusterConditionAvailable,
+			value("True"), value("Unknown")),
+		change(database.ClusterHistoryClaim, "version.openshift.io", value("4.15.10"), value("4.16.3")),
+	}, clusterHistory("cluster-id", "hub1", previous, current))
+
+	assert.Empty(t, clusterHistory("cluster-id", "hub1", previous, previous.DeepCopy()))
+}
+
+func TestHubHistory(t *testing.T) {
+	hub1, hub2 := "hub1", "hub2"
+	cases := []struct {
+		name        string
+		previousHub string
+		currentHub  string
+		expected    models.ManagedClusterHistory
+	}{
+		{
+			name:       "the cluster is added",
+			currentHub: hub1,
+			expected:   models.ManagedClusterHistory{LeafHubName: hub1, NewValue: &hub1},
+		},
+		{
+			name:        "the cluster is moved to another hub",
+			previousHub: hub1,
+			currentHub:  hub2,
+			expected:    models.ManagedClusterHistory{LeafHubName: hub2, OldValue: &hub1, NewValue: &hub2},
+		},
+		{
+			name:        "the cluster is deleted",
+			previousHub: hub1,
+			expected:    models.ManagedClusterHistory{LeafHubName: hub1, OldValue: &hub1},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.expected.ClusterID, tc.expected.ClusterName = "cluster-id", "cluster1"
+			tc.expected.Field = database.ClusterHistoryHub
+			assert.Equal(t, tc.expected, hubHistory("cluster-id", "cluster1", tc.previousHub, tc.currentHub))
+		})
+	}
+}
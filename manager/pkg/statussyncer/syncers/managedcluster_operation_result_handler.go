@@ -0,0 +1,86 @@
+package dbsyncer
+
+import (
+	"context"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	"gorm.io/gorm"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+type managedClusterOperationResultHandler struct {
+	log           logr.Logger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+func NewManagedClusterOperationResultHandler() conflator.Handler {
+	eventType := string(enum.ManagedClusterOperationResultType)
+	logName := strings.Replace(eventType, enum.EventTypePrefix, "", -1)
+	return &managedClusterOperationResultHandler{
+		log:           ctrl.Log.WithName(logName),
+		eventType:     eventType,
+		eventSyncMode: enum.DeltaStateMode,
+		eventPriority: conflator.ManagedClusterOperationResultPriority,
+	}
+}
+
+func (h *managedClusterOperationResultHandler) RegisterHandler(conflationManager *conflator.ConflationManager) {
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+// handleEvent updates the per-cluster results of the bulk operations confirmed by the agent of the managed hub
+func (h *managedClusterOperationResultHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.V(2).Info(startMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+
+	data := spec.ManagedClusterOperationResultBundle{}
+	if err := evt.DataAs(&data); err != nil {
+		return err
+	}
+
+	db := database.GetGorm()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, result := range data {
+			state := database.OperationSucceeded
+			if !result.Succeeded {
+				state = database.OperationFailed
+			}
+			// the message of the succeeded result is reset by the select. Only the result which isn't completed is
+			// updated, so that the late result doesn't override the result failed by the timeout. The pending result
+			// is updated as well, since the result might be received before it's marked as sent.
+			err := tx.Model(&models.ManagedClusterOperationResult{}).
+				Where("operation_id = ? AND leaf_hub_name = ? AND cluster_name = ? AND state IN ?", result.OperationID,
+					leafHubName, result.ClusterName,
+					[]database.OperationState{database.OperationPending, database.OperationSent}).
+				Select("state", "message", "updated_at").
+				Updates(&models.ManagedClusterOperationResult{State: state, Message: result.Message}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	h.log.V(2).Info(finishMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+	return nil
+}
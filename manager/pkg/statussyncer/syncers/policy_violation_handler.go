@@ -0,0 +1,111 @@
+package dbsyncer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	"gorm.io/gorm"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/common"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+type policyViolationHandler struct {
+	log           logr.Logger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+func NewPolicyViolationHandler() conflator.Handler {
+	eventType := string(enum.PolicyViolationType)
+	logName := strings.Replace(eventType, enum.EventTypePrefix, "", -1)
+	return &policyViolationHandler{
+		log:           ctrl.Log.WithName(logName),
+		eventType:     eventType,
+		eventSyncMode: enum.DeltaStateMode,
+		eventPriority: conflator.PolicyViolationPriority,
+	}
+}
+
+func (h *policyViolationHandler) RegisterHandler(conflationManager *conflator.ConflationManager) {
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+// handleEvent replaces the violated templates of each (policy, cluster) pair in the bundle, the pair without
+// templates is compliant or deleted, so its violations are removed.
+func (h *policyViolationHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.V(2).Info(startMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+
+	data := grc.PolicyViolationBundle{}
+	if err := evt.DataAs(&data); err != nil {
+		return err
+	}
+
+	db := database.GetGorm()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, violation := range data {
+			err := tx.Where(&models.PolicyViolation{
+				PolicyID:    violation.PolicyID,
+				ClusterName: violation.ClusterName,
+				LeafHubName: leafHubName,
+			}).Delete(&models.PolicyViolation{}).Error
+			if err != nil {
+				return err
+			}
+
+			batchViolations := []models.PolicyViolation{}
+			for _, template := range violation.Templates {
+				relatedObjects, err := json.Marshal(template.RelatedObjects)
+				if err != nil {
+					return err
+				}
+				if template.RelatedObjects == nil {
+					relatedObjects = []byte("[]")
+				}
+				batchViolations = append(batchViolations, models.PolicyViolation{
+					PolicyID:           violation.PolicyID,
+					ClusterName:        violation.ClusterName,
+					LeafHubName:        leafHubName,
+					TemplateName:       template.Name,
+					ClusterID:          violation.ClusterID,
+					TemplateKind:       template.Kind,
+					TemplateAPIVersion: template.APIVersion,
+					Compliance:         common.GetDatabaseCompliance(template.Compliance),
+					Message:            template.Message,
+					RelatedObjects:     relatedObjects,
+				})
+			}
+			if len(batchViolations) == 0 {
+				continue
+			}
+			if err := tx.CreateInBatches(batchViolations, 100).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed handling leaf hub PolicyViolation event - %w", err)
+	}
+
+	h.log.V(2).Info(finishMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+	return nil
+}
@@ -0,0 +1,78 @@
+package dbsyncer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	"gorm.io/gorm/clause"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/event"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+type specDriftEventHandler struct {
+	log           logr.Logger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+func NewSpecDriftEventHandler() conflator.Handler {
+	eventType := string(enum.SpecDriftEventType)
+	logName := strings.Replace(eventType, enum.EventTypePrefix, "", -1)
+	return &specDriftEventHandler{
+		log:           ctrl.Log.WithName(logName),
+		eventType:     eventType,
+		eventSyncMode: enum.DeltaStateMode,
+		eventPriority: conflator.SpecDriftEventPriority,
+	}
+}
+
+func (h *specDriftEventHandler) RegisterHandler(conflationManager *conflator.ConflationManager) {
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+// handleEvent records the global resources drifted on the managed hub
+func (h *specDriftEventHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.V(2).Info(startMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+
+	driftEvents := event.SpecDriftEventBundle{}
+	if err := evt.DataAs(&driftEvents); err != nil {
+		return err
+	}
+
+	if len(driftEvents) == 0 {
+		h.log.Info("empty spec drift event payload", "event", evt)
+		return nil
+	}
+
+	for i := range driftEvents {
+		driftEvents[i].LeafHubName = leafHubName
+	}
+
+	db := database.GetGorm()
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "leaf_hub_name"}, {Name: "object_id"}, {Name: "created_at"}},
+		DoNothing: true,
+	}).CreateInBatches(driftEvents, 100).Error
+	if err != nil {
+		return fmt.Errorf("failed handling leaf hub spec drift event - %w", err)
+	}
+
+	h.log.V(2).Info(finishMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+	return nil
+}
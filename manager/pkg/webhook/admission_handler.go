@@ -5,19 +5,23 @@ package webhook
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"net/http"
+	"strings"
 
+	admissionv1 "k8s.io/api/admission/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/klog"
 	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 	placementrulesv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	actionv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/action/v1alpha1"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 )
 
-// NewAdmissionHandler is to handle the admission webhook for placementrule and placement
+// NewAdmissionHandler is to handle the admission webhook for placementrule, placement and globalclusteraction
 func NewAdmissionHandler(s *runtime.Scheme) admission.Handler {
 	return &admissionHandler{
 		decoder: admission.NewDecoder(s),
@@ -70,11 +74,45 @@ func (a *admissionHandler) Handle(ctx context.Context, req admission.Request) ad
 			return admission.PatchResponseFromRaw(req.Object.Raw, marshaledPlacementRule)
 		}
 		return admission.Allowed("")
+	case "GlobalClusterAction":
+		action := &actionv1alpha1.GlobalClusterAction{}
+		err := a.decoder.Decode(req, action)
+		if err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+
+		// the action is applied as the requester on the managed hub, the requester can't be changed after creation
+		userIdentity, userGroups := requesterIdentity(req)
+		if req.Operation == admissionv1.Update {
+			oldAction := &actionv1alpha1.GlobalClusterAction{}
+			if err := a.decoder.DecodeRaw(req.OldObject, oldAction); err != nil {
+				return admission.Errored(http.StatusBadRequest, err)
+			}
+			userIdentity = oldAction.Annotations[constants.UserIdentityAnnotation]
+			userGroups = oldAction.Annotations[constants.UserGroupsAnnotation]
+		}
+		if action.Annotations == nil {
+			action.Annotations = map[string]string{}
+		}
+		action.Annotations[constants.UserIdentityAnnotation] = userIdentity
+		action.Annotations[constants.UserGroupsAnnotation] = userGroups
+
+		marshaledAction, err := json.Marshal(action)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		return admission.PatchResponseFromRaw(req.Object.Raw, marshaledAction)
 	default:
 		return admission.Allowed("")
 	}
 }
 
+// requesterIdentity returns the base64 encoded user and comma separated groups of the admission request
+func requesterIdentity(req admission.Request) (string, string) {
+	return base64.StdEncoding.EncodeToString([]byte(req.UserInfo.Username)),
+		base64.StdEncoding.EncodeToString([]byte(strings.Join(req.UserInfo.Groups, ",")))
+}
+
 // AdmissionHandler implements admission.DecoderInjector.
 // A decoder will be automatically injected.
 
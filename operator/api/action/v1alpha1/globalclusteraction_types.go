@@ -0,0 +1,139 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.clusterName"
+// +kubebuilder:printcolumn:name="Action",type="string",JSONPath=".spec.action"
+// +kubebuilder:printcolumn:name="Hub",type="string",JSONPath=".status.leafHubName"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +operator-sdk:csv:customresourcedefinitions:resources={{Deployment,v1,multicluster-global-hub-manager}}
+// GlobalClusterAction is a global hub resource that dispatches a lifecycle action of a managed cluster to the managed
+// hub which owns the cluster
+type GlobalClusterAction struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec specifies the desired state of globalclusteraction
+	Spec GlobalClusterActionSpec `json:"spec,omitempty"`
+	// Status specifies the observed state of globalclusteraction
+	Status GlobalClusterActionStatus `json:"status,omitempty"`
+}
+
+// ClusterActionType is the lifecycle action applied to the managed cluster
+// +kubebuilder:validation:Enum=Detach;DenyClient;Upgrade;RotateBootstrap
+type ClusterActionType string
+
+const (
+	// ClusterActionDetach detaches the managed cluster from the managed hub
+	ClusterActionDetach ClusterActionType = "Detach"
+	// ClusterActionDenyClient sets the hubAcceptsClient of the managed cluster to false, so that the klusterlet can't
+	// connect to the managed hub anymore
+	ClusterActionDenyClient ClusterActionType = "DenyClient"
+	// ClusterActionUpgrade upgrades the OpenShift managed cluster by the ClusterVersion ManifestWork
+	ClusterActionUpgrade ClusterActionType = "Upgrade"
+	// ClusterActionRotateBootstrap rotates the bootstrap kubeconfig of the klusterlet
+	ClusterActionRotateBootstrap ClusterActionType = "RotateBootstrap"
+)
+
+// GlobalClusterActionSpec defines the desired state of globalclusteraction
+type GlobalClusterActionSpec struct {
+	// ClusterName is the name of the managed cluster
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	ClusterName string `json:"clusterName"`
+
+	// LeafHubName is the name of the managed hub which owns the cluster, it's resolved from the managed clusters
+	// reported by the managed hubs if it isn't specified
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	LeafHubName string `json:"leafHubName,omitempty"`
+
+	// Action is the lifecycle action applied to the managed cluster
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Action ClusterActionType `json:"action"`
+
+	// Upgrade specifies the desired OpenShift version, it's required by the Upgrade action
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Upgrade *ClusterUpgrade `json:"upgrade,omitempty"`
+}
+
+// ClusterUpgrade defines the desired update of the ClusterVersion on the managed cluster
+type ClusterUpgrade struct {
+	// Version is the desired OpenShift version, e.g. 4.16.3
+	// +kubebuilder:validation:Required
+	Version string `json:"version"`
+	// Image is the release image of the version, it's required if the version isn't in the available updates
+	// +optional
+	Image string `json:"image,omitempty"`
+	// Channel is the update channel of the cluster, e.g. stable-4.16
+	// +optional
+	Channel string `json:"channel,omitempty"`
+	// Force skips the verification and the precondition checks of the release
+	// +optional
+	Force bool `json:"force,omitempty"`
+}
+
+// ActionPhase is the phase of the action
+type ActionPhase string
+
+const (
+	ActionPhasePending    ActionPhase = "Pending"
+	ActionPhaseDispatched ActionPhase = "Dispatched"
+	ActionPhaseSucceeded  ActionPhase = "Succeeded"
+	ActionPhaseFailed     ActionPhase = "Failed"
+)
+
+// GlobalClusterActionStatus defines the observed state of globalclusteraction
+type GlobalClusterActionStatus struct {
+	// Phase is the current phase of the action
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Phase ActionPhase `json:"phase,omitempty"`
+
+	// Message is a human readable message indicating details about the phase
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LeafHubName is the name of the managed hub which the action is dispatched to
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	LeafHubName string `json:"leafHubName,omitempty"`
+
+	// CompletionTime is the time when the action succeeded or failed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// GlobalClusterActionList contains a list of globalclusteraction
+type GlobalClusterActionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GlobalClusterAction `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GlobalClusterAction{}, &GlobalClusterActionList{})
+}
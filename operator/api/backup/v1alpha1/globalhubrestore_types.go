@@ -0,0 +1,111 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Backup",type="string",JSONPath=".status.backupName"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +operator-sdk:csv:customresourcedefinitions:resources={{Deployment,v1,multicluster-global-hub-manager}}
+// GlobalHubRestore is a global hub resource that allows you to restore the global hub database from a backup
+type GlobalHubRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec specifies the desired state of globalhubrestore
+	Spec GlobalHubRestoreSpec `json:"spec,omitempty"`
+	// Status specifies the observed state of globalhubrestore
+	Status GlobalHubRestoreStatus `json:"status,omitempty"`
+}
+
+// GlobalHubRestoreSpec defines the desired state of globalhubrestore
+type GlobalHubRestoreSpec struct {
+	// BackupName is the name of the backup to restore, the latest backup is restored if it isn't specified
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	BackupName string `json:"backupName,omitempty"`
+
+	// SkipResync skips requesting the managed hubs to resync their resources after the restore
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	SkipResync bool `json:"skipResync,omitempty"`
+}
+
+// RestorePhase is the phase of the restore
+type RestorePhase string
+
+const (
+	RestorePhaseRestoring RestorePhase = "Restoring"
+	RestorePhaseVerifying RestorePhase = "Verifying"
+	RestorePhaseResyncing RestorePhase = "Resyncing"
+	RestorePhaseCompleted RestorePhase = "Completed"
+	RestorePhaseFailed    RestorePhase = "Failed"
+)
+
+// GlobalHubRestoreStatus defines the observed state of globalhubrestore
+type GlobalHubRestoreStatus struct {
+	// Phase is the current phase of the restore
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Phase RestorePhase `json:"phase,omitempty"`
+
+	// BackupName is the name of the restored backup
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	BackupName string `json:"backupName,omitempty"`
+
+	// Message is a human readable message indicating details about the phase
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Tables records the row counts of the restored tables, which are verified against the backup
+	// +optional
+	Tables []TableVerification `json:"tables,omitempty"`
+
+	// StartTime is the time when the restore started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time when the restore completed or failed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// TableVerification compares the row count of the table in the backup with the restored one
+type TableVerification struct {
+	// Name is the table name with the schema, e.g. "status.managed_clusters"
+	Name string `json:"name"`
+	// BackupRows is the row count of the table in the backup
+	BackupRows int64 `json:"backupRows"`
+	// RestoredRows is the row count of the table after the restore
+	RestoredRows int64 `json:"restoredRows"`
+}
+
+// +kubebuilder:object:root=true
+// GlobalHubRestoreList contains a list of globalhubrestore
+type GlobalHubRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GlobalHubRestore `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GlobalHubRestore{}, &GlobalHubRestoreList{})
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Active",type="integer",JSONPath=".status.activeAlertCount"
+// +kubebuilder:printcolumn:name="Last Evaluation",type="date",JSONPath=".status.lastEvaluationTime"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +operator-sdk:csv:customresourcedefinitions:resources={{Deployment,v1,multicluster-global-hub-manager}}
+// GlobalHubNotifier is a global hub resource that evaluates the built-in alert rules over the global hub database
+// and sends the alerts to the notification sinks
+type GlobalHubNotifier struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec specifies the desired state of globalhubnotifier
+	Spec GlobalHubNotifierSpec `json:"spec,omitempty"`
+	// Status specifies the observed state of globalhubnotifier
+	Status GlobalHubNotifierStatus `json:"status,omitempty"`
+}
+
+// GlobalHubNotifierSpec defines the desired state of globalhubnotifier
+type GlobalHubNotifierSpec struct {
+	// Rules specifies the built-in rules to evaluate, all the rules are evaluated with the default threshold if it
+	// isn't specified
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Rules []NotificationRule `json:"rules,omitempty"`
+
+	// Sinks specifies where to send the alerts
+	// +kubebuilder:validation:MinItems=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Sinks []NotificationSink `json:"sinks"`
+
+	// EvaluationInterval is the interval to evaluate the rules
+	// +kubebuilder:default:="1m"
+	// +optional
+	EvaluationInterval metav1.Duration `json:"evaluationInterval,omitempty"`
+
+	// RepeatInterval deduplicates the alerts, a firing alert isn't sent again until the interval elapses
+	// +kubebuilder:default:="4h"
+	// +optional
+	RepeatInterval metav1.Duration `json:"repeatInterval,omitempty"`
+
+	// Silences specifies the time windows in which the matched alerts aren't sent
+	// +optional
+	Silences []SilenceWindow `json:"silences,omitempty"`
+}
+
+// RuleType is the type of the built-in alert rule
+// +kubebuilder:validation:Enum=HubInactive;ComplianceSpike;StackRoxCriticalIncrease;MigrationFailed
+type RuleType string
+
+const (
+	// RuleHubInactive fires when the managed hub doesn't send the heartbeat
+	RuleHubInactive RuleType = "HubInactive"
+	// RuleComplianceSpike fires when the non-compliant clusters of the managed hub increase by the threshold
+	// between two evaluations
+	RuleComplianceSpike RuleType = "ComplianceSpike"
+	// RuleStackRoxCriticalIncrease fires when the critical StackRox alerts of the managed hub increase by the
+	// threshold between two evaluations
+	RuleStackRoxCriticalIncrease RuleType = "StackRoxCriticalIncrease"
+	// RuleMigrationFailed fires when a managed cluster migration fails
+	RuleMigrationFailed RuleType = "MigrationFailed"
+)
+
+// NotificationRule specifies a built-in rule
+type NotificationRule struct {
+	// Type is the type of the built-in rule
+	Type RuleType `json:"type"`
+
+	// Threshold is the minimum increase between two evaluations to fire the ComplianceSpike and
+	// StackRoxCriticalIncrease rules, it's ignored by the other rules
+	// +kubebuilder:default:=1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Threshold int32 `json:"threshold,omitempty"`
+}
+
+// NotificationSink specifies where to send the alerts, only one of the sinks can be specified
+type NotificationSink struct {
+	// Name is the name of the sink
+	Name string `json:"name"`
+
+	// Webhook posts the alerts as a JSON array to the URL
+	// +optional
+	Webhook *WebhookSink `json:"webhook,omitempty"`
+
+	// Slack posts the alerts as a message to the Slack-compatible incoming webhook
+	// +optional
+	Slack *WebhookSink `json:"slack,omitempty"`
+
+	// Email sends the alerts by the SMTP server
+	// +optional
+	Email *EmailSink `json:"email,omitempty"`
+
+	// Kafka sends the alerts as cloudevents to the topic of the global hub kafka
+	// +optional
+	Kafka *KafkaSink `json:"kafka,omitempty"`
+}
+
+// WebhookSink specifies the webhook, the URL is read from the secret since it usually contains the token
+type WebhookSink struct {
+	// URLSecretRef selects the key of the secret in the notifier namespace, which contains the webhook URL
+	URLSecretRef corev1.SecretKeySelector `json:"urlSecretRef"`
+}
+
+// EmailSink specifies the SMTP server and the recipients
+type EmailSink struct {
+	// Host is the address of the SMTP server
+	Host string `json:"host"`
+
+	// Port is the port of the SMTP server
+	// +kubebuilder:default:=587
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// From is the sender of the email
+	From string `json:"from"`
+
+	// To is the recipients of the email
+	// +kubebuilder:validation:MinItems=1
+	To []string `json:"to"`
+
+	// CredentialSecretName is the secret in the notifier namespace, which contains the "username" and "password"
+	// to authenticate with the SMTP server
+	// +optional
+	CredentialSecretName string `json:"credentialSecretName,omitempty"`
+}
+
+// KafkaSink specifies the topic of the global hub kafka
+type KafkaSink struct {
+	// Topic is the topic to send the alerts
+	Topic string `json:"topic"`
+}
+
+// SilenceWindow suppresses the matched alerts in the time window
+type SilenceWindow struct {
+	// Rules are the rules to silence, all the rules are silenced if it isn't specified
+	// +optional
+	Rules []RuleType `json:"rules,omitempty"`
+
+	// Hubs are the managed hubs to silence, all the hubs are silenced if it isn't specified
+	// +optional
+	Hubs []string `json:"hubs,omitempty"`
+
+	// StartTime is the start of the silence window
+	StartTime metav1.Time `json:"startTime"`
+
+	// EndTime is the end of the silence window
+	EndTime metav1.Time `json:"endTime"`
+}
+
+// GlobalHubNotifierStatus defines the observed state of globalhubnotifier
+type GlobalHubNotifierStatus struct {
+	// LastEvaluationTime is the time when the rules were evaluated
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	LastEvaluationTime *metav1.Time `json:"lastEvaluationTime,omitempty"`
+
+	// ActiveAlertCount is the number of the firing alerts
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	ActiveAlertCount int32 `json:"activeAlertCount,omitempty"`
+
+	// ActiveAlerts are the firing alerts, which are used to deduplicate the notifications
+	// +optional
+	ActiveAlerts []ActiveAlert `json:"activeAlerts,omitempty"`
+
+	// Conditions represents the latest available observations of the current state
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// ActiveAlert is a firing alert
+type ActiveAlert struct {
+	// Rule is the rule which fires the alert
+	Rule RuleType `json:"rule"`
+
+	// Hub is the managed hub of the alert
+	// +optional
+	Hub string `json:"hub,omitempty"`
+
+	// Subject is the object of the alert in the hub, e.g. the migration name
+	// +optional
+	Subject string `json:"subject,omitempty"`
+
+	// Message describes the alert
+	Message string `json:"message"`
+
+	// Since is the time when the alert started firing
+	Since metav1.Time `json:"since"`
+
+	// LastNotifiedTime is the time when the alert was sent to the sinks
+	// +optional
+	LastNotifiedTime *metav1.Time `json:"lastNotifiedTime,omitempty"`
+
+	// PendingSinks are the sinks which haven't received the alert since it's due, e.g. they failed to receive it.
+	// The alert is sent to them again in the next evaluation.
+	// +optional
+	PendingSinks []string `json:"pendingSinks,omitempty"`
+
+	// Silenced indicates the alert is suppressed by a silence window
+	// +optional
+	Silenced bool `json:"silenced,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// GlobalHubNotifierList contains a list of globalhubnotifier
+type GlobalHubNotifierList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GlobalHubNotifier `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GlobalHubNotifier{}, &GlobalHubNotifierList{})
+}
@@ -107,6 +107,96 @@ type MulticlusterGlobalHubSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
 	// +optional
 	EnableMetrics bool `json:"enableMetrics"`
+	// AgentRollout specifies how a new version of the agent is rolled out to the managed hubs.
+	// If it isn't set, the new agent is deployed to all the managed hubs at once.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	AgentRollout *AgentRolloutStrategy `json:"agentRollout,omitempty"`
+	// Tracing exports the spans of the status and spec bundles from the agents, through the transport, to the manager
+	// and its database writes, to the OpenTelemetry collector.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Tracing *TracingSpec `json:"tracing,omitempty"`
+	// Jobs overrides the schedules of the built-in jobs of the global hub manager, or disables them.
+	// The jobs which aren't listed keep their default schedules.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Jobs []ManagerJob `json:"jobs,omitempty"`
+}
+
+// ManagerJob defines the schedule of the built-in job of the global hub manager.
+type ManagerJob struct {
+	// Name is the name of the job
+	// +kubebuilder:validation:Enum=local-compliance-history;data-retention;database-backup;compliance-rollup;stale-heartbeat-cleanup;orphaned-spec-detection;vacuum-analyze
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// Schedule is the cron expression of the job, e.g. "0 0 * * *". The expression with 6 fields starts with the
+	// seconds. The default schedule of the job is used if it's empty.
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+	// Enabled specifies whether the job is scheduled. The database backup job is only scheduled when the backup of
+	// the postgres is configured.
+	// +kubebuilder:default=true
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// TracingSpec defines the OpenTelemetry collector to export the spans of the agents and the manager.
+type TracingSpec struct {
+	// Endpoint is the OTLP/HTTP endpoint of the collector, e.g. "otel-collector.observability.svc:4318"
+	// +kubebuilder:validation:Required
+	Endpoint string `json:"endpoint"`
+	// Insecure exports the spans to the collector without TLS
+	// +optional
+	Insecure bool `json:"insecure,omitempty"`
+	// SamplingPercentage is the percentage of the traces to be sampled. The bundles are sampled where they're built,
+	// the agents and the manager follow the decision once they're received.
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	SamplingPercentage int32 `json:"samplingPercentage"`
+}
+
+// AgentRolloutStrategy defines the staged rollout of the agent: the canary hubs are updated first, then the other
+// managed hubs are updated batch by batch. Each batch is observed for a pause before moving to the next one, and the
+// rollout halts when the updated hubs stop sending heartbeats, their addon is unavailable, or the manager fails to
+// process their bundles. The managed hubs without a deployed agent receive the new agent directly.
+type AgentRolloutStrategy struct {
+	// CanarySelector selects the managed hubs which are updated in the first batch
+	// +optional
+	CanarySelector *metav1.LabelSelector `json:"canarySelector,omitempty"`
+	// BatchSize is the number of managed hubs updated in each batch after the canary hubs
+	// +kubebuilder:default=5
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	BatchSize int32 `json:"batchSize,omitempty"`
+	// PauseBetweenBatches is the duration to observe the updated hubs before moving to the next batch
+	// +kubebuilder:default="10m"
+	// +optional
+	PauseBetweenBatches metav1.Duration `json:"pauseBetweenBatches,omitempty"`
+	// HeartbeatTimeout is the duration after which an updated hub without a heartbeat is considered failed
+	// +kubebuilder:default="5m"
+	// +optional
+	HeartbeatTimeout metav1.Duration `json:"heartbeatTimeout,omitempty"`
+	// MaxFailurePercentage is the percentage of failed hubs in a batch that the rollout tolerates before halting.
+	// The halted rollout is resumed by annotating the MulticlusterGlobalHub with
+	// "global-hub.open-cluster-management.io/resume-agent-rollout"
+	// +kubebuilder:default=0
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxFailurePercentage int32 `json:"maxFailurePercentage,omitempty"`
+	// MaxErrorPercentage is the percentage of the bundles from an updated hub that the manager fails to process
+	// during the observation, the hub is considered failed when the error rate exceeds it
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxErrorPercentage int32 `json:"maxErrorPercentage,omitempty"`
+	// Paused stops the rollout from moving to the next batch
+	// +optional
+	Paused bool `json:"paused,omitempty"`
 }
 
 type AdvancedSpec struct {
@@ -182,6 +272,93 @@ type PostgresSpec struct {
 	// StorageSize specifies the size for storage
 	// +optional
 	StorageSize string `json:"storageSize,omitempty"`
+
+	// Backup specifies the scheduled logical backup of the database. The backup is stored in a PVC by default,
+	// or in the S3-compatible object storage if the s3 is specified
+	// +optional
+	Backup *PostgresBackupSpec `json:"backup,omitempty"`
+
+	// TableRetentions overrides the retention of the tables, e.g. keep the "history.local_compliance" for "3y"
+	// and the "event.managed_clusters" for "3m". The other tables are kept for the retention
+	// +optional
+	TableRetentions []TableRetention `json:"tableRetentions,omitempty"`
+
+	// Archive exports the monthly partitions to the files before they are dropped by the data retention. The
+	// files are stored in a PVC by default, or in the S3-compatible object storage if the s3 is specified
+	// +optional
+	Archive *PostgresArchiveSpec `json:"archive,omitempty"`
+}
+
+// TableRetention defines the retention of a table
+type TableRetention struct {
+	// Table is the table name with the schema, e.g. "event.local_policies"
+	// +kubebuilder:validation:Enum=event.local_policies;event.local_root_policies;history.local_compliance;event.managed_clusters;event.spec_drifts;history.managed_clusters;status.managed_clusters;status.leaf_hubs;local_spec.policies
+	// +kubebuilder:validation:Required
+	Table string `json:"table"`
+
+	// Retention is a duration string in the same format as the retention of the postgres, e.g. "3m" or "1y6m"
+	// +kubebuilder:validation:Required
+	Retention string `json:"retention"`
+}
+
+// PostgresArchiveSpec defines the archival of the expired partitions
+type PostgresArchiveSpec struct {
+	// Format is the file format of the archived partitions, "json" for the gzip compressed JSON lines or "parquet"
+	// +kubebuilder:validation:Enum=json;parquet
+	// +kubebuilder:default:="json"
+	Format string `json:"format,omitempty"`
+
+	// StorageSize specifies the size of the PVC to store the archives
+	// +kubebuilder:default:="10Gi"
+	StorageSize string `json:"storageSize,omitempty"`
+
+	// S3 specifies the S3-compatible object storage to store the archives
+	// +optional
+	S3 *S3BackupStorage `json:"s3,omitempty"`
+}
+
+// PostgresBackupSpec defines the scheduled logical backup of the global hub database
+type PostgresBackupSpec struct {
+	// Schedule is a cron expression, defining when to run the backup, e.g. "0 2 * * *"
+	// +kubebuilder:default:="0 2 * * *"
+	Schedule string `json:"schedule,omitempty"`
+
+	// Retention is the number of the latest backups to keep
+	// +kubebuilder:default:=7
+	// +kubebuilder:validation:Minimum=1
+	Retention int32 `json:"retention,omitempty"`
+
+	// StorageSize specifies the size of the PVC to store the backups
+	// +kubebuilder:default:="10Gi"
+	StorageSize string `json:"storageSize,omitempty"`
+
+	// S3 specifies the S3-compatible object storage to store the backups
+	// +optional
+	S3 *S3BackupStorage `json:"s3,omitempty"`
+}
+
+// S3BackupStorage defines the S3-compatible object storage for the backups
+type S3BackupStorage struct {
+	// Endpoint is the URL of the S3-compatible service, e.g. "https://s3.us-east-1.amazonaws.com"
+	// +kubebuilder:validation:Required
+	Endpoint string `json:"endpoint"`
+
+	// Bucket is the bucket to store the backups
+	// +kubebuilder:validation:Required
+	Bucket string `json:"bucket"`
+
+	// Region is the region of the bucket
+	// +kubebuilder:default:="us-east-1"
+	Region string `json:"region,omitempty"`
+
+	// Prefix is the key prefix of the backups in the bucket
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// CredentialSecretName is the secret in the global hub namespace, which contains the "access-key-id" and
+	// "secret-access-key" of the bucket
+	// +kubebuilder:validation:Required
+	CredentialSecretName string `json:"credentialSecretName"`
 }
 
 // KafkaSpec defines the desired state of kafka
@@ -193,6 +370,30 @@ type KafkaSpec struct {
 	// StorageSize specifies the size for storage
 	// +optional
 	StorageSize string `json:"storageSize,omitempty"`
+
+	// Quotas specifies the default quotas applied to the kafkaUser of each managed hub. They can be overridden
+	// for an individual managed hub with the "global-hub.open-cluster-management.io/kafka-*" labels on the
+	// ManagedCluster, e.g. "global-hub.open-cluster-management.io/kafka-producer-byte-rate: 1048576"
+	// +optional
+	Quotas *KafkaQuotas `json:"quotas,omitempty"`
+}
+
+// KafkaQuotas defines the client quotas enforced by the kafka brokers for the managed hub kafkaUser
+type KafkaQuotas struct {
+	// ProducerByteRate is the maximum bytes per-second that the managed hub can publish to a broker
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ProducerByteRate *int32 `json:"producerByteRate,omitempty"`
+
+	// ConsumerByteRate is the maximum bytes per-second that the managed hub can fetch from a broker
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ConsumerByteRate *int32 `json:"consumerByteRate,omitempty"`
+
+	// RequestPercentage is the maximum CPU utilization of the managed hub as a percentage of network and I/O threads
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RequestPercentage *int32 `json:"requestPercentage,omitempty"`
 }
 
 // KafkaTopics is the transport topics for the manager and agent to communicate to one another
@@ -224,6 +425,43 @@ type MulticlusterGlobalHubStatus struct {
 	// +kubebuilder:default:="Progressing"
 	// +optional
 	Phase GlobalHubPhaseType `json:"phase"`
+
+	// AgentRollout reports the progress of the agent rollout to the managed hubs
+	// +optional
+	AgentRollout *AgentRolloutStatus `json:"agentRollout,omitempty"`
+}
+
+type AgentRolloutPhaseType string
+
+const (
+	AgentRolloutProgressing AgentRolloutPhaseType = "Progressing"
+	AgentRolloutPaused      AgentRolloutPhaseType = "Paused"
+	AgentRolloutHalted      AgentRolloutPhaseType = "Halted"
+	AgentRolloutCompleted   AgentRolloutPhaseType = "Completed"
+)
+
+// AgentRolloutStatus contains the progress of the agent rollout
+type AgentRolloutStatus struct {
+	// TargetImage is the agent image being rolled out
+	TargetImage string `json:"targetImage,omitempty"`
+	// Phase is the phase of the rollout: Progressing, Paused, Halted or Completed
+	Phase AgentRolloutPhaseType `json:"phase,omitempty"`
+	// UpdatedHubs is the number of managed hubs running the target agent
+	UpdatedHubs int32 `json:"updatedHubs"`
+	// TotalHubs is the number of managed hubs with the agent
+	TotalHubs int32 `json:"totalHubs"`
+	// CurrentBatch lists the managed hubs updated in the batch being observed
+	// +optional
+	CurrentBatch []string `json:"currentBatch,omitempty"`
+	// FailedHubs lists the managed hubs which halted the rollout
+	// +optional
+	FailedHubs []string `json:"failedHubs,omitempty"`
+	// LastBatchTime is the time when the current batch was updated
+	// +optional
+	LastBatchTime *metav1.Time `json:"lastBatchTime,omitempty"`
+	// Message is a human-readable message about the rollout
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 type GlobalHubPhaseType string
 
@@ -71,6 +71,57 @@ func (in *AdvancedSpec) DeepCopy() *AdvancedSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentRolloutStatus) DeepCopyInto(out *AgentRolloutStatus) {
+	*out = *in
+	if in.CurrentBatch != nil {
+		in, out := &in.CurrentBatch, &out.CurrentBatch
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FailedHubs != nil {
+		in, out := &in.FailedHubs, &out.FailedHubs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastBatchTime != nil {
+		in, out := &in.LastBatchTime, &out.LastBatchTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentRolloutStatus.
+func (in *AgentRolloutStatus) DeepCopy() *AgentRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentRolloutStrategy) DeepCopyInto(out *AgentRolloutStrategy) {
+	*out = *in
+	if in.CanarySelector != nil {
+		in, out := &in.CanarySelector, &out.CanarySelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	out.PauseBetweenBatches = in.PauseBetweenBatches
+	out.HeartbeatTimeout = in.HeartbeatTimeout
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentRolloutStrategy.
+func (in *AgentRolloutStrategy) DeepCopy() *AgentRolloutStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentRolloutStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommonSpec) DeepCopyInto(out *CommonSpec) {
 	*out = *in
@@ -94,8 +145,8 @@ func (in *CommonSpec) DeepCopy() *CommonSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataLayerSpec) DeepCopyInto(out *DataLayerSpec) {
 	*out = *in
-	out.Kafka = in.Kafka
-	out.Postgres = in.Postgres
+	in.Kafka.DeepCopyInto(&out.Kafka)
+	in.Postgres.DeepCopyInto(&out.Postgres)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataLayerSpec.
@@ -108,10 +159,45 @@ func (in *DataLayerSpec) DeepCopy() *DataLayerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaQuotas) DeepCopyInto(out *KafkaQuotas) {
+	*out = *in
+	if in.ProducerByteRate != nil {
+		in, out := &in.ProducerByteRate, &out.ProducerByteRate
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ConsumerByteRate != nil {
+		in, out := &in.ConsumerByteRate, &out.ConsumerByteRate
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RequestPercentage != nil {
+		in, out := &in.RequestPercentage, &out.RequestPercentage
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaQuotas.
+func (in *KafkaQuotas) DeepCopy() *KafkaQuotas {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaQuotas)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KafkaSpec) DeepCopyInto(out *KafkaSpec) {
 	*out = *in
 	out.KafkaTopics = in.KafkaTopics
+	if in.Quotas != nil {
+		in, out := &in.Quotas, &out.Quotas
+		*out = new(KafkaQuotas)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaSpec.
@@ -139,6 +225,26 @@ func (in *KafkaTopics) DeepCopy() *KafkaTopics {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagerJob) DeepCopyInto(out *ManagerJob) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagerJob.
+func (in *ManagerJob) DeepCopy() *ManagerJob {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagerJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MulticlusterGlobalHub) DeepCopyInto(out *MulticlusterGlobalHub) {
 	*out = *in
@@ -215,12 +321,29 @@ func (in *MulticlusterGlobalHubSpec) DeepCopyInto(out *MulticlusterGlobalHubSpec
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	out.DataLayerSpec = in.DataLayerSpec
+	in.DataLayerSpec.DeepCopyInto(&out.DataLayerSpec)
 	if in.AdvancedSpec != nil {
 		in, out := &in.AdvancedSpec, &out.AdvancedSpec
 		*out = new(AdvancedSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AgentRollout != nil {
+		in, out := &in.AgentRollout, &out.AgentRollout
+		*out = new(AgentRolloutStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tracing != nil {
+		in, out := &in.Tracing, &out.Tracing
+		*out = new(TracingSpec)
+		**out = **in
+	}
+	if in.Jobs != nil {
+		in, out := &in.Jobs, &out.Jobs
+		*out = make([]ManagerJob, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MulticlusterGlobalHubSpec.
@@ -250,6 +373,11 @@ func (in *MulticlusterGlobalHubStatus) DeepCopyInto(out *MulticlusterGlobalHubSt
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.AgentRollout != nil {
+		in, out := &in.AgentRollout, &out.AgentRollout
+		*out = new(AgentRolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MulticlusterGlobalHubStatus.
@@ -262,9 +390,64 @@ func (in *MulticlusterGlobalHubStatus) DeepCopy() *MulticlusterGlobalHubStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresArchiveSpec) DeepCopyInto(out *PostgresArchiveSpec) {
+	*out = *in
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3BackupStorage)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresArchiveSpec.
+func (in *PostgresArchiveSpec) DeepCopy() *PostgresArchiveSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresArchiveSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresBackupSpec) DeepCopyInto(out *PostgresBackupSpec) {
+	*out = *in
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3BackupStorage)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresBackupSpec.
+func (in *PostgresBackupSpec) DeepCopy() *PostgresBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresSpec) DeepCopyInto(out *PostgresSpec) {
 	*out = *in
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(PostgresBackupSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TableRetentions != nil {
+		in, out := &in.TableRetentions, &out.TableRetentions
+		*out = make([]TableRetention, len(*in))
+		copy(*out, *in)
+	}
+	if in.Archive != nil {
+		in, out := &in.Archive, &out.Archive
+		*out = new(PostgresArchiveSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresSpec.
@@ -306,6 +489,21 @@ func (in *ResourceRequirements) DeepCopy() *ResourceRequirements {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3BackupStorage) DeepCopyInto(out *S3BackupStorage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3BackupStorage.
+func (in *S3BackupStorage) DeepCopy() *S3BackupStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(S3BackupStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StatusCondition) DeepCopyInto(out *StatusCondition) {
 	*out = *in
@@ -321,3 +519,33 @@ func (in *StatusCondition) DeepCopy() *StatusCondition {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TableRetention) DeepCopyInto(out *TableRetention) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TableRetention.
+func (in *TableRetention) DeepCopy() *TableRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(TableRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TracingSpec) DeepCopyInto(out *TracingSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TracingSpec.
+func (in *TracingSpec) DeepCopy() *TracingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TracingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Kind",type="string",JSONPath=".spec.targetRef.kind"
+// +kubebuilder:printcolumn:name="Target",type="string",JSONPath=".spec.targetRef.name"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +operator-sdk:csv:customresourcedefinitions:resources={{Deployment,v1,multicluster-global-hub-manager}}
+// GlobalHubOverride is a global hub resource that customizes a global resource for the managed hubs before it's
+// sent to them
+type GlobalHubOverride struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec specifies the desired state of globalhuboverride
+	Spec GlobalHubOverrideSpec `json:"spec,omitempty"`
+	// Status specifies the observed state of globalhuboverride
+	Status GlobalHubOverrideStatus `json:"status,omitempty"`
+}
+
+// GlobalHubOverrideSpec defines the desired state of globalhuboverride
+type GlobalHubOverrideSpec struct {
+	// TargetRef is the global resource in the namespace of the override which is customized
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	TargetRef OverrideTargetReference `json:"targetRef"`
+
+	// Overrides are applied to the target in order for each managed hub they select
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Overrides []HubOverride `json:"overrides"`
+}
+
+// OverrideTargetReference is the reference of the global resource
+type OverrideTargetReference struct {
+	// Kind is the kind of the global resource
+	// +kubebuilder:validation:Enum=Policy;Placement
+	Kind string `json:"kind"`
+	// Name is the name of the global resource
+	Name string `json:"name"`
+}
+
+// HubOverride customizes the global resource for the managed hubs selected by the names or the labels. It selects
+// all the managed hubs if neither the names nor the labels are specified.
+type HubOverride struct {
+	// LeafHubNames are the names of the selected managed hubs
+	// +optional
+	LeafHubNames []string `json:"leafHubNames,omitempty"`
+
+	// HubSelector selects the managed hubs by the labels of their managed clusters on the global hub
+	// +optional
+	HubSelector *metav1.LabelSelector `json:"hubSelector,omitempty"`
+
+	// Parameters are referenced by the templates in the string values of the global resource, which are delimited by
+	// "{{globalhub" and "globalhub}}", e.g. "{{globalhub .Parameters.registry globalhub}}". The parameters of the
+	// later overrides take precedence
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+
+	// Patches are the JSON patches (RFC 6902) applied to the global resource
+	// +optional
+	Patches []JSONPatchOperation `json:"patches,omitempty"`
+}
+
+// JSONPatchOperation is an operation of the JSON patch
+type JSONPatchOperation struct {
+	// Op is the operation of the patch
+	// +kubebuilder:validation:Enum=add;remove;replace
+	Op string `json:"op"`
+	// Path is the JSON pointer of the patched field
+	Path string `json:"path"`
+	// Value is the value of the added or replaced field
+	// +optional
+	Value *apiextensionsv1.JSON `json:"value,omitempty"`
+}
+
+// GlobalHubOverrideStatus defines the observed state of globalhuboverride
+type GlobalHubOverrideStatus struct {
+	// ObservedGeneration is the generation of the override which the rendered resources are generated from
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// RenderedHubs summarize the global resources rendered for the managed hubs selected by the override, they are
+	// sent to the managed hubs instead of the global resource
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	RenderedHubs []RenderedHub `json:"renderedHubs,omitempty"`
+
+	// Message is a human readable message indicating why the target isn't rendered
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Conditions represents the latest available observations of the current state, the Rendered condition is false
+	// if the target fails to be rendered for any managed hub
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// RenderedHub summarizes the global resource rendered for a managed hub
+type RenderedHub struct {
+	// LeafHubName is the name of the managed hub
+	LeafHubName string `json:"leafHubName"`
+
+	// Hash is the sha256 hash of the rendered global resource, it changes once the resource sent to the managed hub
+	// is changed. The rendered resource is returned by the API /globalhuboverride/{namespace}/{name}/rendered/{hub}
+	// +optional
+	Hash string `json:"hash,omitempty"`
+
+	// Error is the reason why the global resource fails to be rendered, then it isn't sent to the managed hub
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// GlobalHubOverrideList contains a list of globalhuboverride
+type GlobalHubOverrideList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GlobalHubOverride `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GlobalHubOverride{}, &GlobalHubOverrideList{})
+}
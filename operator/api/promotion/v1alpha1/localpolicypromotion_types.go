@@ -0,0 +1,132 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Hub",type="string",JSONPath=".spec.leafHubName"
+// +kubebuilder:printcolumn:name="Policy",type="string",JSONPath=".spec.policyName"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +operator-sdk:csv:customresourcedefinitions:resources={{Deployment,v1,multicluster-global-hub-manager}}
+// LocalPolicyPromotion is a global hub resource that promotes a local policy of a managed hub to a global policy
+type LocalPolicyPromotion struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec specifies the desired state of localpolicypromotion
+	Spec LocalPolicyPromotionSpec `json:"spec,omitempty"`
+	// Status specifies the observed state of localpolicypromotion
+	Status LocalPolicyPromotionStatus `json:"status,omitempty"`
+}
+
+// LocalPolicyPromotionSpec defines the desired state of localpolicypromotion
+type LocalPolicyPromotionSpec struct {
+	// LeafHubName is the name of the managed hub which the local policy belongs to
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	LeafHubName string `json:"leafHubName"`
+
+	// PolicyNamespace is the namespace of the local policy on the managed hub
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	PolicyNamespace string `json:"policyNamespace"`
+
+	// PolicyName is the name of the local policy on the managed hub
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	PolicyName string `json:"policyName"`
+
+	// TargetNamespace is the namespace of the global policy on the global hub, it must be the namespace of the
+	// promotion, which is used if it isn't specified
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	TargetNamespace string `json:"targetNamespace,omitempty"`
+
+	// PlacementRef is the name of the Placement in the target namespace which the global policy is bound to
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	PlacementRef string `json:"placementRef"`
+
+	// IgnoreConflicts promotes the policy even if the other managed hubs have the local policies with the same
+	// namespace and name, these local policies are replaced by the global policy on the managed hubs
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	IgnoreConflicts bool `json:"ignoreConflicts,omitempty"`
+}
+
+// PromotionPhase is the phase of the promotion
+type PromotionPhase string
+
+const (
+	PromotionPhasePromoted PromotionPhase = "Promoted"
+	PromotionPhaseConflict PromotionPhase = "Conflict"
+	PromotionPhaseFailed   PromotionPhase = "Failed"
+)
+
+// LocalPolicyPromotionStatus defines the observed state of localpolicypromotion
+type LocalPolicyPromotionStatus struct {
+	// Phase is the current phase of the promotion
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Phase PromotionPhase `json:"phase,omitempty"`
+
+	// Message is a human readable message indicating details about the phase
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// PolicyID is the uid of the promoted local policy
+	// +optional
+	PolicyID string `json:"policyID,omitempty"`
+
+	// GlobalPolicy is the namespaced name of the created global policy
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	GlobalPolicy string `json:"globalPolicy,omitempty"`
+
+	// Conflicts are the local policies of the other managed hubs with the same namespace and name as the global
+	// policy
+	// +optional
+	Conflicts []PolicyConflict `json:"conflicts,omitempty"`
+
+	// CompletionTime is the time when the promotion completed or failed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// PolicyConflict is a local policy which has the same namespace and name as the global policy
+type PolicyConflict struct {
+	// LeafHubName is the name of the managed hub which the local policy belongs to
+	LeafHubName string `json:"leafHubName"`
+	// PolicyID is the uid of the local policy
+	PolicyID string `json:"policyID"`
+}
+
+// +kubebuilder:object:root=true
+// LocalPolicyPromotionList contains a list of localpolicypromotion
+type LocalPolicyPromotionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LocalPolicyPromotion `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&LocalPolicyPromotion{}, &LocalPolicyPromotionList{})
+}
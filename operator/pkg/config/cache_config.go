@@ -8,9 +8,12 @@ import (
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/rest"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	workv1 "open-cluster-management.io/api/work/v1"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	operatorconstants "github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/utils"
 )
@@ -93,6 +96,12 @@ func InitCache(config *rest.Config, cacheOpts cache.Options) (cache.Cache, error
 		&admissionregistrationv1.MutatingWebhookConfiguration{}: {
 			Label: labelSelector,
 		},
+		// addon rollout controller: the agent deployed by the addon
+		&workv1.ManifestWork{}: {
+			Label: labels.SelectorFromSet(labels.Set{
+				addonv1alpha1.AddonLabelKey: operatorconstants.GHManagedClusterAddonName,
+			}),
+		},
 	}
 	return cache.New(config, cacheOpts)
 }
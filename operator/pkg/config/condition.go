@@ -61,6 +61,14 @@ const (
 	CONDITION_MESSAGE_KAFKA_READY = "Kafka cluster is ready"
 )
 
+// NOTE: the status of KafkaUser ACL audit is False when any managed hub kafkaUser drifts from the expected ACLs
+const (
+	CONDITION_TYPE_KAFKA_USER_AUDIT     = "KafkaUserAudit"
+	CONDITION_REASON_KAFKA_USER_AUDITED = "KafkaUserACLsMatched"
+	CONDITION_REASON_KAFKA_USER_DRIFTED = "KafkaUserACLsDrifted"
+	CONDITION_MESSAGE_KAFKA_USER_AUDIT  = "The kafkaUser ACLs of all the managed hubs are as expected"
+)
+
 // NOTE: the status of Data Retention can be True or False
 const (
 	CONDITION_TYPE_DATABASE                  = "Database"
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -148,6 +149,21 @@ func WithStackroxIntegration(mgh *v1alpha4.MulticlusterGlobalHub) bool {
 	return ok
 }
 
+// WithPolicyViolations returns true if the agents send the details of the policy violations.
+func WithPolicyViolations(mgh *v1alpha4.MulticlusterGlobalHub) bool {
+	_, ok := mgh.GetAnnotations()[operatorconstants.AnnotationMGHWithPolicyViolations]
+	return ok
+}
+
+// GetTracingSamplingRatio returns the ratio of the traces sampled by the agents and the manager, which is passed to
+// their "--tracing-sampling-ratio" flag.
+func GetTracingSamplingRatio(tracing *v1alpha4.TracingSpec) string {
+	if tracing == nil {
+		return "0"
+	}
+	return strconv.FormatFloat(float64(tracing.SamplingPercentage)/100, 'f', -1, 64)
+}
+
 // GetStackroxPollInterval returns the StackRox API poll interval specified in the annotations of the given object. The
 // value should be a string that can be parsed with the time.ParseDuration function. If it isn't specified or the format
 // isn't valid, then it returns zero.
@@ -196,6 +212,23 @@ func GetLaunchJobNames(mgh *v1alpha4.MulticlusterGlobalHub) string {
 	return getAnnotation(mgh, operatorconstants.AnnotationLaunchJobNames)
 }
 
+// GetManagerJobs returns the schedules of the manager jobs, which are passed to the "--job-schedule" flags of the
+// manager, e.g. "data-retention=0 0 1 * *", and the disabled jobs concatenated using ","
+func GetManagerJobs(mgh *v1alpha4.MulticlusterGlobalHub) ([]string, string) {
+	schedules := []string{}
+	disabled := []string{}
+	for _, job := range mgh.Spec.Jobs {
+		if job.Enabled != nil && !*job.Enabled {
+			disabled = append(disabled, job.Name)
+			continue
+		}
+		if job.Schedule != "" {
+			schedules = append(schedules, fmt.Sprintf("%s=%s", job.Name, job.Schedule))
+		}
+	}
+	return schedules, strings.Join(disabled, ",")
+}
+
 // GetImageOverridesConfigmap returns the images override configmap annotation, or an empty string if not set
 func GetImageOverridesConfigmap(mgh *v1alpha4.MulticlusterGlobalHub) string {
 	return getAnnotation(mgh, operatorconstants.AnnotationImageOverridesCM)
@@ -27,6 +27,7 @@ import (
 	fakeimagev1client "github.com/openshift/client-go/image/clientset/versioned/typed/image/v1/fake"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
 	operatorconstants "github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
@@ -263,3 +264,48 @@ func TestStackRoxPoolNotPresent(t *testing.T) {
 		)
 	}
 }
+
+func TestGetTableRetentions(t *testing.T) {
+	retentions, err := GetTableRetentions(globalhubv1alpha4.PostgresSpec{
+		Retention: "18m",
+		TableRetentions: []globalhubv1alpha4.TableRetention{
+			{Table: "history.local_compliance", Retention: "3y"},
+			{Table: "event.managed_clusters", Retention: "0m"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to get the table retentions: %v", err)
+	}
+	if retentions != "history.local_compliance=36,event.managed_clusters=1" {
+		t.Fatalf("unexpected table retentions: %s", retentions)
+	}
+
+	_, err = GetTableRetentions(globalhubv1alpha4.PostgresSpec{
+		TableRetentions: []globalhubv1alpha4.TableRetention{
+			{Table: "event.local_policies", Retention: "3d"},
+		},
+	})
+	if err == nil {
+		t.Fatalf("expected the error of parsing the retention")
+	}
+}
+
+func TestGetManagerJobs(t *testing.T) {
+	mgh := &globalhubv1alpha4.MulticlusterGlobalHub{
+		Spec: globalhubv1alpha4.MulticlusterGlobalHubSpec{
+			Jobs: []globalhubv1alpha4.ManagerJob{
+				{Name: "data-retention", Schedule: "0 0 1 * *"},
+				{Name: "vacuum-analyze", Schedule: "0 3 * * 6", Enabled: ptr.To(false)},
+				{Name: "compliance-rollup", Enabled: ptr.To(true)},
+				{Name: "orphaned-spec-detection", Enabled: ptr.To(false)},
+			},
+		},
+	}
+	schedules, disabled := GetManagerJobs(mgh)
+	if !reflect.DeepEqual(schedules, []string{"data-retention=0 0 1 * *"}) {
+		t.Fatalf("unexpected job schedules: %v", schedules)
+	}
+	if disabled != "vacuum-analyze,orphaned-spec-detection" {
+		t.Fatalf("unexpected disabled jobs: %s", disabled)
+	}
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/utils"
 )
@@ -61,6 +63,24 @@ func SetPostgresType(ctx context.Context, runtimeClient client.Client, namespace
 	return nil
 }
 
+// GetTableRetentions returns the months to keep the data of the tables, which is passed to the "--table-retention"
+// flag of the manager, e.g. "event.managed_clusters=3,history.local_compliance=36"
+func GetTableRetentions(postgres v1alpha4.PostgresSpec) (string, error) {
+	retentions := make([]string, 0, len(postgres.TableRetentions))
+	for _, tableRetention := range postgres.TableRetentions {
+		months, err := utils.ParseRetentionMonth(tableRetention.Retention)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse the retention of the table %s: %w", tableRetention.Table, err)
+		}
+		// the retention should at least be 1 month, otherwise the current month partition is deleted
+		if months < 1 {
+			months = 1
+		}
+		retentions = append(retentions, fmt.Sprintf("%s=%d", tableRetention.Table, months))
+	}
+	return strings.Join(retentions, ","), nil
+}
+
 func IsBYOPostgres() bool {
 	return isBYOPostgres
 }
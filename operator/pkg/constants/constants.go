@@ -75,6 +75,10 @@ const (
 	// development environments, where is is convenient to reduce the poll interval. The value should be a string
 	// that can be parsed with the time.ParseDuration function.
 	AnnotationMGHWithStackroxPollInterval = "global-hub.open-cluster-management.io/with-stackrox-poll-interval"
+	// AnnotationMGHWithPolicyViolations enables the agents to send the details of the policy violations
+	AnnotationMGHWithPolicyViolations = "global-hub.open-cluster-management.io/with-policy-violations"
+	// AnnotationResumeAgentRollout resumes the halted agent rollout, it's removed once the rollout is resumed
+	AnnotationResumeAgentRollout = "global-hub.open-cluster-management.io/resume-agent-rollout"
 )
 
 // hub installation constants
@@ -90,6 +94,9 @@ const (
 const (
 	GHClusterManagementAddonName = "multicluster-global-hub-controller"
 	GHManagedClusterAddonName    = "multicluster-global-hub-controller"
+	// AnnotationAddonAgentImage sits in the ManagedClusterAddOn annotations to record the agent image rolled out to
+	// the managed hub, it's updated by the agent rollout controller batch by batch
+	AnnotationAddonAgentImage = "global-hub.open-cluster-management.io/agent-image"
 )
 
 // global hub names
@@ -124,6 +131,16 @@ const (
 	EnableKRaft = "global-hub.open-cluster-management.io/enable-kraft"
 )
 
+// kafka quota labels on the managed hub cluster, override the default quotas in the mgh dataLayer.kafka.quotas
+const (
+	// KafkaProducerByteRateLabelKey overrides the producerByteRate quota of the managed hub kafkaUser
+	KafkaProducerByteRateLabelKey = "global-hub.open-cluster-management.io/kafka-producer-byte-rate"
+	// KafkaConsumerByteRateLabelKey overrides the consumerByteRate quota of the managed hub kafkaUser
+	KafkaConsumerByteRateLabelKey = "global-hub.open-cluster-management.io/kafka-consumer-byte-rate"
+	// KafkaRequestPercentageLabelKey overrides the requestPercentage quota of the managed hub kafkaUser
+	KafkaRequestPercentageLabelKey = "global-hub.open-cluster-management.io/kafka-request-percentage"
+)
+
 // AggregationLevel specifies the level of aggregation leaf hubs should do before sending the information
 // Enum=full;minimal
 type AggregationLevel string
@@ -66,6 +66,7 @@ type ManifestsConfig struct {
 	Tolerations             []corev1.Toleration
 	AggregationLevel        string
 	EnableLocalPolicies     string
+	EnablePolicyViolations  bool
 	EnableGlobalResource    bool
 	AgentQPS                float32
 	AgentBurst              int
@@ -75,6 +76,9 @@ type ManifestsConfig struct {
 	Resources                 *Resources
 	EnableStackroxIntegration bool
 	StackroxPollInterval      time.Duration
+	TracingEndpoint           string
+	TracingInsecure           bool
+	TracingSamplingRatio      string
 }
 
 type Resources struct {
@@ -163,6 +167,11 @@ func (a *HohAgentAddon) GetValues(cluster *clusterv1.ManagedCluster,
 	if err != nil {
 		return nil, err
 	}
+	// keep the previous agent until the managed hub is updated by the agent rollout
+	image, err = rolloutImage(a.ctx, a.client, mgh, addon, image)
+	if err != nil {
+		return nil, err
+	}
 
 	imagePullPolicy := corev1.PullAlways
 	if mgh.Spec.ImagePullPolicy != "" {
@@ -223,6 +232,11 @@ func (a *HohAgentAddon) GetValues(cluster *clusterv1.ManagedCluster,
 		EnableStackroxIntegration: config.WithStackroxIntegration(mgh),
 		StackroxPollInterval:      config.GetStackroxPollInterval(mgh),
 	}
+	if mgh.Spec.Tracing != nil {
+		manifestsConfig.TracingEndpoint = mgh.Spec.Tracing.Endpoint
+		manifestsConfig.TracingInsecure = mgh.Spec.Tracing.Insecure
+		manifestsConfig.TracingSamplingRatio = config.GetTracingSamplingRatio(mgh.Spec.Tracing)
+	}
 
 	if config.EnableInventory() {
 		inventoryConn, err := getInventoryCredential(a.client)
@@ -260,6 +274,7 @@ func (a *HohAgentAddon) GetValues(cluster *clusterv1.ManagedCluster,
 
 	manifestsConfig.AggregationLevel = config.AggregationLevel
 	manifestsConfig.EnableLocalPolicies = config.EnableLocalPolicies
+	manifestsConfig.EnablePolicyViolations = config.WithPolicyViolations(mgh)
 
 	if a.installACMHub(cluster) {
 		manifestsConfig.InstallACMHub = true
@@ -310,6 +325,10 @@ func (a *HohAgentAddon) setImagePullSecret(mgh *globalhubv1alpha4.MulticlusterGl
 }
 
 func (a *HohAgentAddon) getOverrideImage(cluster *clusterv1.ManagedCluster) (string, error) {
+	return agentImage(cluster)
+}
+
+func agentImage(cluster *clusterv1.ManagedCluster) (string, error) {
 	// image registry override by operator environment variable and mgh annotation
 	configOverrideImage := config.GetImage(config.GlobalHubAgentImageKey)
 
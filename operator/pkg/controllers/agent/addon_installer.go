@@ -127,6 +127,14 @@ func (r *AddonInstaller) reconcileAddonAndResources(ctx context.Context, cluster
 			return r.removeResourcesAndAddon(ctx, cluster)
 		}
 
+		// the agent image annotation is maintained by the agent rollout controller
+		if image, ok := existingAddon.Annotations[operatorconstants.AnnotationAddonAgentImage]; ok {
+			if expectedAddon.Annotations == nil {
+				expectedAddon.Annotations = map[string]string{}
+			}
+			expectedAddon.Annotations[operatorconstants.AnnotationAddonAgentImage] = image
+		}
+
 		// update
 		if !reflect.DeepEqual(expectedAddon.Annotations, existingAddon.Annotations) ||
 			existingAddon.Spec.InstallNamespace != expectedAddon.Spec.InstallNamespace {
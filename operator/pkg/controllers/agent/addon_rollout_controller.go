@@ -0,0 +1,621 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"open-cluster-management.io/api/addon/v1alpha1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
+	operatorconstants "github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+const (
+	rolloutRequeuePeriod = 30 * time.Second
+	// the health of the managed hubs is read by a single query in each observation
+	healthPoolSize = 2
+)
+
+// HubHealth is the health of a managed hub observed by the global hub manager
+type HubHealth struct {
+	LastHeartbeat time.Time
+	// Received and Failures count the bundles received from the hub and the ones failed to be processed
+	Received int64
+	Failures int64
+}
+
+// HealthFunc returns the health of the managed hubs
+type HealthFunc func(ctx context.Context, hubs []string) (map[string]HubHealth, error)
+
+// AddonRolloutReconciler rolls out the agent to the managed hubs batch by batch. The agent image deployed to each hub
+// is recorded in the ManagedClusterAddOn annotation, which is used to render the addon manifests. The reconciler
+// moves the annotation to the target image for the canary hubs first, then for the other hubs in batches, and
+// observes the heartbeats and the error rates of the updated hubs before moving to the next batch.
+type AddonRolloutReconciler struct {
+	client.Client
+	log        logr.Logger
+	healthFunc HealthFunc
+	// baselines are the health of the current batch hubs when they're updated, the error rates are measured from them
+	baselines map[string]HubHealth
+}
+
+func NewAddonRolloutReconciler(c client.Client, healthFunc HealthFunc) *AddonRolloutReconciler {
+	if healthFunc == nil {
+		healthFunc = (&databaseHealth{}).health
+	}
+	return &AddonRolloutReconciler{
+		Client:     c,
+		log:        ctrl.Log.WithName("addon-rollout"),
+		healthFunc: healthFunc,
+		baselines:  map[string]HubHealth{},
+	}
+}
+
+// rolloutImage returns the agent image for the managed hub. When the staged rollout is enabled, the hub keeps the
+// image recorded in the addon annotation until the rollout reconciler updates it. The hub without the annotation
+// keeps the deployed agent until the rollout reconciler records it, and only the new hub receives the target image.
+func rolloutImage(ctx context.Context, c client.Reader, mgh *globalhubv1alpha4.MulticlusterGlobalHub,
+	addon *v1alpha1.ManagedClusterAddOn, targetImage string,
+) (string, error) {
+	if mgh.Spec.AgentRollout == nil || addon == nil {
+		return targetImage, nil
+	}
+	if image := addon.GetAnnotations()[operatorconstants.AnnotationAddonAgentImage]; image != "" {
+		return image, nil
+	}
+	image, err := deployedImage(ctx, c, addon.Namespace)
+	if err != nil {
+		return "", err
+	}
+	if image != "" {
+		return image, nil
+	}
+	return targetImage, nil
+}
+
+// deployedImage returns the agent image in the addon manifestworks of the managed hub, it's empty if the agent
+// isn't deployed. The manifestwork of the hosted mode is in the namespace of the hosting cluster.
+func deployedImage(ctx context.Context, c client.Reader, hubName string) (string, error) {
+	works := &workv1.ManifestWorkList{}
+	if err := c.List(ctx, works, client.MatchingLabels{
+		v1alpha1.AddonLabelKey: operatorconstants.GHManagedClusterAddonName,
+	}); err != nil {
+		return "", fmt.Errorf("failed to list the addon manifestworks: %w", err)
+	}
+	for _, work := range works.Items {
+		if work.Namespace != hubName && work.GetLabels()[v1alpha1.AddonNamespaceLabelKey] != hubName {
+			continue
+		}
+		for _, manifest := range work.Spec.Workload.Manifests {
+			raw := manifest.Raw
+			if len(raw) == 0 && manifest.Object != nil {
+				data, err := json.Marshal(manifest.Object)
+				if err != nil {
+					return "", err
+				}
+				raw = data
+			}
+			obj := &unstructured.Unstructured{}
+			if err := obj.UnmarshalJSON(raw); err != nil {
+				return "", fmt.Errorf("failed to decode the manifest of the manifestwork %s: %w", work.Name, err)
+			}
+			if obj.GetKind() != "Deployment" || obj.GetName() != constants.AgentDeploymentName {
+				continue
+			}
+			containers, _, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+			if err != nil {
+				return "", err
+			}
+			for _, container := range containers {
+				fields, ok := container.(map[string]interface{})
+				if !ok || fields["name"] != constants.AgentDeploymentName {
+					continue
+				}
+				if image, ok := fields["image"].(string); ok {
+					return image, nil
+				}
+			}
+		}
+	}
+	return "", nil
+}
+
+// rolloutHub is a managed hub with the agent addon
+type rolloutHub struct {
+	addon       *v1alpha1.ManagedClusterAddOn
+	cluster     *clusterv1.ManagedCluster
+	targetImage string
+}
+
+func (h *rolloutHub) name() string {
+	return h.addon.Namespace
+}
+
+func (h *rolloutHub) updated() bool {
+	return h.addon.GetAnnotations()[operatorconstants.AnnotationAddonAgentImage] == h.targetImage
+}
+
+func (r *AddonRolloutReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	mgh, err := config.GetMulticlusterGlobalHub(ctx, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if mgh == nil || mgh.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+	if config.IsPaused(mgh) {
+		return ctrl.Result{}, nil
+	}
+
+	hubs, err := r.listHubs(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// without the rollout strategy, all the hubs are updated at once
+	strategy := mgh.Spec.AgentRollout
+	if strategy == nil {
+		for _, hub := range hubs {
+			if err := r.updateHub(ctx, hub); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, r.updateRolloutStatus(ctx, mgh, nil)
+	}
+
+	status, err := r.rollout(ctx, mgh, strategy, hubs)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.updateRolloutStatus(ctx, mgh, status); err != nil {
+		return ctrl.Result{}, err
+	}
+	if status.Phase == globalhubv1alpha4.AgentRolloutCompleted {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: rolloutRequeuePeriod}, nil
+}
+
+// rollout moves the rollout forward, and returns the desired rollout status
+func (r *AddonRolloutReconciler) rollout(ctx context.Context, mgh *globalhubv1alpha4.MulticlusterGlobalHub,
+	strategy *globalhubv1alpha4.AgentRolloutStrategy, hubs []*rolloutHub,
+) (*globalhubv1alpha4.AgentRolloutStatus, error) {
+	targetImage := config.GetImage(config.GlobalHubAgentImageKey)
+
+	status := &globalhubv1alpha4.AgentRolloutStatus{}
+	if mgh.Status.AgentRollout != nil && mgh.Status.AgentRollout.TargetImage == targetImage {
+		status = mgh.Status.AgentRollout.DeepCopy()
+	}
+	status.TargetImage = targetImage
+	if status.Phase == "" {
+		status.Phase = globalhubv1alpha4.AgentRolloutProgressing
+	}
+
+	canarySelector := labels.Nothing()
+	if strategy.CanarySelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(strategy.CanarySelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid canarySelector: %w", err)
+		}
+		canarySelector = selector
+	}
+
+	var pendingCanaries, pendingHubs []*rolloutHub
+	for _, hub := range hubs {
+		// record the deployed agent before planning the batches, the hub without the deployed agent receives the
+		// target agent directly
+		if _, found := hub.addon.GetAnnotations()[operatorconstants.AnnotationAddonAgentImage]; !found {
+			image, err := deployedImage(ctx, r.Client, hub.name())
+			if err != nil {
+				return nil, err
+			}
+			if err := r.recordImage(ctx, hub, image); err != nil {
+				return nil, err
+			}
+		}
+		if hub.updated() {
+			continue
+		}
+		if canarySelector.Matches(labels.Set(hub.cluster.GetLabels())) {
+			pendingCanaries = append(pendingCanaries, hub)
+		} else {
+			pendingHubs = append(pendingHubs, hub)
+		}
+	}
+	status.TotalHubs = int32(len(hubs))
+	status.UpdatedHubs = int32(len(hubs) - len(pendingCanaries) - len(pendingHubs))
+
+	// resume the halted rollout by the annotation
+	if _, found := mgh.GetAnnotations()[operatorconstants.AnnotationResumeAgentRollout]; found {
+		if err := r.removeResumeAnnotation(ctx, mgh); err != nil {
+			return nil, err
+		}
+		if status.Phase == globalhubv1alpha4.AgentRolloutHalted {
+			r.log.Info("resume the halted agent rollout", "image", targetImage)
+			status.Phase = globalhubv1alpha4.AgentRolloutProgressing
+			status.CurrentBatch = nil
+			status.FailedHubs = nil
+		}
+	}
+
+	if status.Phase == globalhubv1alpha4.AgentRolloutHalted {
+		return status, nil
+	}
+	if strategy.Paused {
+		status.Phase = globalhubv1alpha4.AgentRolloutPaused
+		status.Message = "The agent rollout is paused"
+		return status, nil
+	}
+	status.Phase = globalhubv1alpha4.AgentRolloutProgressing
+
+	// observe the current batch before moving to the next one
+	if len(status.CurrentBatch) > 0 {
+		observed, err := r.observeBatch(ctx, strategy, status)
+		if err != nil {
+			return nil, err
+		}
+		if !observed {
+			return status, nil
+		}
+		status.CurrentBatch = nil
+	}
+
+	batch := pendingCanaries
+	if len(batch) == 0 {
+		sort.Slice(pendingHubs, func(i, j int) bool { return pendingHubs[i].name() < pendingHubs[j].name() })
+		batchSize := int(strategy.BatchSize)
+		if batchSize <= 0 {
+			batchSize = 1
+		}
+		batch = pendingHubs[:min(batchSize, len(pendingHubs))]
+	}
+	if len(batch) == 0 {
+		status.Phase = globalhubv1alpha4.AgentRolloutCompleted
+		status.LastBatchTime = nil
+		status.Message = fmt.Sprintf("The agent %s is rolled out to all the managed hubs", targetImage)
+		return status, nil
+	}
+
+	for _, hub := range batch {
+		if err := r.updateHub(ctx, hub); err != nil {
+			return nil, err
+		}
+		status.CurrentBatch = append(status.CurrentBatch, hub.name())
+	}
+	if err := r.recordBaselines(ctx, status.CurrentBatch); err != nil {
+		return nil, err
+	}
+	now := metav1.Now()
+	status.LastBatchTime = &now
+	status.UpdatedHubs += int32(len(batch))
+	status.Message = fmt.Sprintf("Rolling out the agent to the managed hubs: %s", strings.Join(status.CurrentBatch, ", "))
+	r.log.Info("roll out the agent", "image", targetImage, "hubs", status.CurrentBatch)
+	return status, nil
+}
+
+// observeBatch returns true when the current batch has been observed for the pause without exceeding the failures.
+// The rollout is halted if the failed hubs exceed the maxFailurePercentage
+func (r *AddonRolloutReconciler) observeBatch(ctx context.Context, strategy *globalhubv1alpha4.AgentRolloutStrategy,
+	status *globalhubv1alpha4.AgentRolloutStatus,
+) (bool, error) {
+	if status.LastBatchTime == nil {
+		return true, nil
+	}
+	// the baselines are lost when the operator restarts, observe the batch again from now
+	for _, hubName := range status.CurrentBatch {
+		if _, found := r.baselines[hubName]; found {
+			continue
+		}
+		if err := r.recordBaselines(ctx, status.CurrentBatch); err != nil {
+			return false, err
+		}
+		now := metav1.Now()
+		status.LastBatchTime = &now
+		return false, nil
+	}
+	if time.Since(status.LastBatchTime.Time) < strategy.PauseBetweenBatches.Duration {
+		return false, nil
+	}
+
+	healths, err := r.healthFunc(ctx, status.CurrentBatch)
+	if err != nil {
+		return false, fmt.Errorf("failed to get the health of the managed hubs: %w", err)
+	}
+
+	failedHubs := []string{}
+	for _, hubName := range status.CurrentBatch {
+		health := healths[hubName]
+		if !r.hubHealthy(ctx, hubName, health.LastHeartbeat, strategy.HeartbeatTimeout.Duration) ||
+			exceedErrorRate(r.baselines[hubName], health, strategy.MaxErrorPercentage) {
+			failedHubs = append(failedHubs, hubName)
+		}
+	}
+
+	if len(failedHubs)*100 > int(strategy.MaxFailurePercentage)*len(status.CurrentBatch) {
+		status.Phase = globalhubv1alpha4.AgentRolloutHalted
+		status.FailedHubs = failedHubs
+		status.Message = fmt.Sprintf("The agent rollout is halted, the managed hubs are unhealthy: %s",
+			strings.Join(failedHubs, ", "))
+		r.log.Info("halt the agent rollout", "image", status.TargetImage, "failedHubs", failedHubs)
+		return false, nil
+	}
+	return true, nil
+}
+
+// recordBaselines records the current health of the hubs, the error rates of the hubs are measured from it
+func (r *AddonRolloutReconciler) recordBaselines(ctx context.Context, hubs []string) error {
+	healths, err := r.healthFunc(ctx, hubs)
+	if err != nil {
+		return fmt.Errorf("failed to get the health of the managed hubs: %w", err)
+	}
+	r.baselines = map[string]HubHealth{}
+	for _, hubName := range hubs {
+		r.baselines[hubName] = healths[hubName]
+	}
+	return nil
+}
+
+// exceedErrorRate returns true if the bundles failed to be processed since the baseline exceed the maxErrorPercentage
+// of the received bundles. The counters are reset when the manager restarts, then they are counted from zero
+func exceedErrorRate(baseline, current HubHealth, maxErrorPercentage int32) bool {
+	if current.Received < baseline.Received || current.Failures < baseline.Failures {
+		baseline = HubHealth{}
+	}
+	received := current.Received - baseline.Received
+	failures := current.Failures - baseline.Failures
+	if received == 0 {
+		return false
+	}
+	return failures*100 > int64(maxErrorPercentage)*received
+}
+
+// hubHealthy checks the hub sends heartbeat within the timeout, and the addon is available and not degraded
+func (r *AddonRolloutReconciler) hubHealthy(ctx context.Context, hubName string, lastHeartbeat time.Time,
+	heartbeatTimeout time.Duration,
+) bool {
+	if lastHeartbeat.IsZero() || time.Since(lastHeartbeat) > heartbeatTimeout {
+		return false
+	}
+	addon := &v1alpha1.ManagedClusterAddOn{}
+	if err := r.Get(ctx, types.NamespacedName{
+		Namespace: hubName,
+		Name:      operatorconstants.GHManagedClusterAddonName,
+	}, addon); err != nil {
+		return false
+	}
+	if !meta.IsStatusConditionTrue(addon.Status.Conditions, v1alpha1.ManagedClusterAddOnConditionAvailable) {
+		return false
+	}
+	return !meta.IsStatusConditionTrue(addon.Status.Conditions, v1alpha1.ManagedClusterAddOnConditionDegraded)
+}
+
+func (r *AddonRolloutReconciler) listHubs(ctx context.Context) ([]*rolloutHub, error) {
+	addons := &v1alpha1.ManagedClusterAddOnList{}
+	if err := r.List(ctx, addons); err != nil {
+		return nil, err
+	}
+	hubs := []*rolloutHub{}
+	for i := range addons.Items {
+		addon := &addons.Items[i]
+		if addon.Name != operatorconstants.GHManagedClusterAddonName || !addon.DeletionTimestamp.IsZero() {
+			continue
+		}
+		cluster := &clusterv1.ManagedCluster{}
+		if err := r.Get(ctx, types.NamespacedName{Name: addon.Namespace}, cluster); err != nil {
+			if client.IgnoreNotFound(err) == nil {
+				continue
+			}
+			return nil, err
+		}
+		image, err := agentImage(cluster)
+		if err != nil {
+			return nil, err
+		}
+		hubs = append(hubs, &rolloutHub{addon: addon, cluster: cluster, targetImage: image})
+	}
+	return hubs, nil
+}
+
+// updateHub records the target image in the addon annotation, the addon manifests are rendered with the image
+func (r *AddonRolloutReconciler) updateHub(ctx context.Context, hub *rolloutHub) error {
+	if hub.updated() {
+		return nil
+	}
+	return r.recordImage(ctx, hub, hub.targetImage)
+}
+
+// recordImage records the image in the addon annotation, it's the target image if the image is empty
+func (r *AddonRolloutReconciler) recordImage(ctx context.Context, hub *rolloutHub, image string) error {
+	if image == "" {
+		image = hub.targetImage
+	}
+	annotations := hub.addon.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[operatorconstants.AnnotationAddonAgentImage] = image
+	hub.addon.SetAnnotations(annotations)
+	return r.Update(ctx, hub.addon)
+}
+
+func (r *AddonRolloutReconciler) removeResumeAnnotation(ctx context.Context,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub,
+) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		curmgh := &globalhubv1alpha4.MulticlusterGlobalHub{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(mgh), curmgh); err != nil {
+			return err
+		}
+		annotations := curmgh.GetAnnotations()
+		if _, found := annotations[operatorconstants.AnnotationResumeAgentRollout]; !found {
+			return nil
+		}
+		delete(annotations, operatorconstants.AnnotationResumeAgentRollout)
+		curmgh.SetAnnotations(annotations)
+		return r.Update(ctx, curmgh)
+	})
+}
+
+func (r *AddonRolloutReconciler) updateRolloutStatus(ctx context.Context,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub, status *globalhubv1alpha4.AgentRolloutStatus,
+) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		curmgh := &globalhubv1alpha4.MulticlusterGlobalHub{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(mgh), curmgh); err != nil {
+			return err
+		}
+		if equalRolloutStatus(curmgh.Status.AgentRollout, status) {
+			return nil
+		}
+		curmgh.Status.AgentRollout = status
+		return r.Status().Update(ctx, curmgh)
+	})
+}
+
+func equalRolloutStatus(a, b *globalhubv1alpha4.AgentRolloutStatus) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.TargetImage == b.TargetImage && a.Phase == b.Phase && a.Message == b.Message &&
+		a.UpdatedHubs == b.UpdatedHubs && a.TotalHubs == b.TotalHubs &&
+		strings.Join(a.CurrentBatch, ",") == strings.Join(b.CurrentBatch, ",") &&
+		strings.Join(a.FailedHubs, ",") == strings.Join(b.FailedHubs, ",") &&
+		a.LastBatchTime.Equal(b.LastBatchTime)
+}
+
+// databaseHealth reads the health of the managed hubs with the readonly user of the database, the connection pool is
+// reused by the observations and it's renewed once the storage connection is changed
+type databaseHealth struct {
+	mutex   sync.Mutex
+	pool    *pgxpool.Pool
+	poolURI string
+	poolCA  string
+}
+
+// health reads the last heartbeats of the managed hubs from the status.leaf_hub_heartbeats, and the bundle counters of
+// the managed hubs from the status.sync_health
+func (d *databaseHealth) health(ctx context.Context, hubs []string) (map[string]HubHealth, error) {
+	pool, err := d.getPool(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := pool.Query(ctx, `SELECT h.leaf_hub_name, h.last_timestamp, COALESCE(SUM(s.received), 0),
+		COALESCE(SUM(s.failures), 0) FROM status.leaf_hub_heartbeats h
+		LEFT JOIN status.sync_health s ON s.leaf_hub_name = h.leaf_hub_name
+		WHERE h.leaf_hub_name = ANY($1) GROUP BY h.leaf_hub_name, h.last_timestamp`, hubs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	healths := map[string]HubHealth{}
+	for rows.Next() {
+		var hubName string
+		health := HubHealth{}
+		if err := rows.Scan(&hubName, &health.LastHeartbeat, &health.Received, &health.Failures); err != nil {
+			return nil, err
+		}
+		healths[hubName] = health
+	}
+	return healths, rows.Err()
+}
+
+func (d *databaseHealth) getPool(ctx context.Context) (*pgxpool.Pool, error) {
+	storageConn := config.GetStorageConnection()
+	if storageConn == nil {
+		return nil, fmt.Errorf("storage connection is nil")
+	}
+	if storageConn.ReadonlyUserDatabaseURI == "" {
+		return nil, fmt.Errorf("the readonly user of the database isn't specified")
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.pool != nil && d.poolURI == storageConn.ReadonlyUserDatabaseURI && d.poolCA == string(storageConn.CACert) {
+		return d.pool, nil
+	}
+	if d.pool != nil {
+		d.pool.Close()
+		d.pool = nil
+	}
+
+	poolConfig, err := pgxpool.ParseConfig(storageConn.ReadonlyUserDatabaseURI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse database uri: %w", err)
+	}
+	connConfig, err := database.GetPostgresConfig(storageConn.ReadonlyUserDatabaseURI, storageConn.CACert)
+	if err != nil {
+		return nil, err
+	}
+	poolConfig.ConnConfig = connConfig
+	poolConfig.MaxConns = healthPoolSize
+	pool, err := pgxpool.ConnectConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	d.pool, d.poolURI, d.poolCA = pool, storageConn.ReadonlyUserDatabaseURI, string(storageConn.CACert)
+	return pool, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AddonRolloutReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	mghPred := predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return true
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			_, resume := e.ObjectNew.GetAnnotations()[operatorconstants.AnnotationResumeAgentRollout]
+			return resume || e.ObjectNew.GetGeneration() != e.ObjectOld.GetGeneration()
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+	}
+	addonPred := predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return e.Object.GetName() == operatorconstants.GHManagedClusterAddonName
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return false
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("addonRollout").
+		For(&globalhubv1alpha4.MulticlusterGlobalHub{}, builder.WithPredicates(mghPred)).
+		// the new addon is updated to the target image immediately
+		Watches(&v1alpha1.ManagedClusterAddOn{},
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+				return []reconcile.Request{{NamespacedName: config.GetMGHNamespacedName()}}
+			}), builder.WithPredicates(addonPred)).
+		Complete(r)
+}
@@ -0,0 +1,250 @@
+package agent_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"open-cluster-management.io/api/addon/v1alpha1"
+	workv1 "open-cluster-management.io/api/work/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
+	operatorconstants "github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
+	hubofhubsaddon "github.com/stolostron/multicluster-global-hub/operator/pkg/controllers/agent"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+func fakeRolloutAddon(cluster, image string) *v1alpha1.ManagedClusterAddOn {
+	addon := fakeHoHAddon(cluster, "", operatorconstants.GHAgentDeployModeDefault)
+	addon.SetAnnotations(map[string]string{operatorconstants.AnnotationAddonAgentImage: image})
+	addon.Status.Conditions = []metav1.Condition{{
+		Type:               v1alpha1.ManagedClusterAddOnConditionAvailable,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Available",
+		LastTransitionTime: metav1.Now(),
+	}}
+	return addon
+}
+
+// fakeAgentWork is the addon manifestwork deploying the agent with the image
+func fakeAgentWork(t *testing.T, cluster, image string) *workv1.ManifestWork {
+	deployment := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: constants.AgentDeploymentName, Namespace: constants.GHAgentNamespace},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: constants.AgentDeploymentName, Image: image}},
+				},
+			},
+		},
+	}
+	raw, err := json.Marshal(deployment)
+	assert.NoError(t, err)
+	return &workv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "addon-multicluster-global-hub-controller-deploy-0",
+			Namespace: cluster,
+			Labels:    map[string]string{v1alpha1.AddonLabelKey: operatorconstants.GHManagedClusterAddonName},
+		},
+		Spec: workv1.ManifestWorkSpec{
+			Workload: workv1.ManifestsTemplate{
+				Manifests: []workv1.Manifest{{RawExtension: runtime.RawExtension{Raw: raw}}},
+			},
+		},
+	}
+}
+
+// healthyHubs reports the heartbeats of the hubs, and the bundle counters returned by the counters func
+func healthyHubs(counters func() (int64, int64)) hubofhubsaddon.HealthFunc {
+	return func(ctx context.Context, hubs []string) (map[string]hubofhubsaddon.HubHealth, error) {
+		received, failures := counters()
+		healths := map[string]hubofhubsaddon.HubHealth{}
+		for _, hub := range hubs {
+			healths[hub] = hubofhubsaddon.HubHealth{
+				LastHeartbeat: time.Now(),
+				Received:      received,
+				Failures:      failures,
+			}
+		}
+		return healths, nil
+	}
+}
+
+func TestAddonRollout(t *testing.T) {
+	namespace := "multicluster-global-hub"
+	name := "test"
+	config.SetMGHNamespacedName(types.NamespacedName{Namespace: namespace, Name: name})
+	targetImage := config.GetImage(config.GlobalHubAgentImageKey)
+	oldImage := "quay.io/stolostron/multicluster-global-hub-agent:old"
+
+	newObjects := func() []client.Object {
+		mgh := fakeMGH(namespace, name)
+		mgh.Spec.AgentRollout = &operatorv1alpha4.AgentRolloutStrategy{
+			CanarySelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}},
+			BatchSize:          1,
+			HeartbeatTimeout:   metav1.Duration{Duration: 5 * time.Minute},
+			MaxErrorPercentage: 10,
+		}
+		canary := fakeCluster("hub3", "", operatorconstants.GHAgentDeployModeDefault)
+		canary.Labels["canary"] = "true"
+		// the hub deployed before the rollout is enabled
+		existingAddon := fakeRolloutAddon("hub5", "")
+		existingAddon.SetAnnotations(nil)
+		return []client.Object{
+			mgh,
+			fakeCluster("hub1", "", operatorconstants.GHAgentDeployModeDefault),
+			fakeCluster("hub2", "", operatorconstants.GHAgentDeployModeDefault),
+			canary,
+			fakeCluster("hub4", "", operatorconstants.GHAgentDeployModeDefault),
+			fakeCluster("hub5", "", operatorconstants.GHAgentDeployModeDefault),
+			fakeRolloutAddon("hub1", oldImage),
+			fakeRolloutAddon("hub2", oldImage),
+			fakeRolloutAddon("hub3", oldImage),
+			// the new hub without the deployed agent
+			fakeHoHAddon("hub4", "", operatorconstants.GHAgentDeployModeDefault),
+			existingAddon,
+			fakeAgentWork(t, "hub5", oldImage),
+		}
+	}
+
+	addonImage := func(c client.Client, cluster string) string {
+		addon := &v1alpha1.ManagedClusterAddOn{}
+		err := c.Get(context.TODO(), types.NamespacedName{
+			Namespace: cluster, Name: operatorconstants.GHManagedClusterAddonName,
+		}, addon)
+		assert.NoError(t, err)
+		return addon.GetAnnotations()[operatorconstants.AnnotationAddonAgentImage]
+	}
+
+	rolloutStatus := func(c client.Client) *operatorv1alpha4.AgentRolloutStatus {
+		mgh := &operatorv1alpha4.MulticlusterGlobalHub{}
+		err := c.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, mgh)
+		assert.NoError(t, err)
+		return mgh.Status.AgentRollout
+	}
+
+	t.Run("healthy batches", func(t *testing.T) {
+		c := fake.NewClientBuilder().WithScheme(config.GetRuntimeScheme()).WithObjects(newObjects()...).
+			WithStatusSubresource(&operatorv1alpha4.MulticlusterGlobalHub{}).Build()
+		r := hubofhubsaddon.NewAddonRolloutReconciler(c, healthyHubs(func() (int64, int64) { return 0, 0 }))
+
+		// the canary and the new hub are updated first, the existing hub keeps the deployed agent
+		_, err := r.Reconcile(context.TODO(), ctrl.Request{})
+		assert.NoError(t, err)
+		assert.Equal(t, targetImage, addonImage(c, "hub3"))
+		assert.Equal(t, targetImage, addonImage(c, "hub4"))
+		assert.Equal(t, oldImage, addonImage(c, "hub1"))
+		assert.Equal(t, oldImage, addonImage(c, "hub5"))
+		status := rolloutStatus(c)
+		assert.Equal(t, operatorv1alpha4.AgentRolloutProgressing, status.Phase)
+		assert.Equal(t, []string{"hub3"}, status.CurrentBatch)
+		assert.Equal(t, int32(2), status.UpdatedHubs)
+
+		_, err = r.Reconcile(context.TODO(), ctrl.Request{})
+		assert.NoError(t, err)
+		assert.Equal(t, targetImage, addonImage(c, "hub1"))
+		assert.Equal(t, oldImage, addonImage(c, "hub2"))
+
+		_, err = r.Reconcile(context.TODO(), ctrl.Request{})
+		assert.NoError(t, err)
+		assert.Equal(t, targetImage, addonImage(c, "hub2"))
+		assert.Equal(t, oldImage, addonImage(c, "hub5"))
+
+		_, err = r.Reconcile(context.TODO(), ctrl.Request{})
+		assert.NoError(t, err)
+		assert.Equal(t, targetImage, addonImage(c, "hub5"))
+
+		_, err = r.Reconcile(context.TODO(), ctrl.Request{})
+		assert.NoError(t, err)
+		status = rolloutStatus(c)
+		assert.Equal(t, operatorv1alpha4.AgentRolloutCompleted, status.Phase)
+		assert.Equal(t, int32(5), status.UpdatedHubs)
+	})
+
+	t.Run("halt on the error rate", func(t *testing.T) {
+		c := fake.NewClientBuilder().WithScheme(config.GetRuntimeScheme()).WithObjects(newObjects()...).
+			WithStatusSubresource(&operatorv1alpha4.MulticlusterGlobalHub{}).Build()
+		// the canary fails 5 of the 10 bundles received after it's updated
+		received, failures := int64(100), int64(1)
+		r := hubofhubsaddon.NewAddonRolloutReconciler(c, healthyHubs(func() (int64, int64) { return received, failures }))
+
+		_, err := r.Reconcile(context.TODO(), ctrl.Request{})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"hub3"}, rolloutStatus(c).CurrentBatch)
+
+		received, failures = 110, 6
+		_, err = r.Reconcile(context.TODO(), ctrl.Request{})
+		assert.NoError(t, err)
+		status := rolloutStatus(c)
+		assert.Equal(t, operatorv1alpha4.AgentRolloutHalted, status.Phase)
+		assert.Equal(t, []string{"hub3"}, status.FailedHubs)
+		assert.Equal(t, oldImage, addonImage(c, "hub1"))
+	})
+
+	t.Run("halt on the unhealthy canary", func(t *testing.T) {
+		c := fake.NewClientBuilder().WithScheme(config.GetRuntimeScheme()).WithObjects(newObjects()...).
+			WithStatusSubresource(&operatorv1alpha4.MulticlusterGlobalHub{}).Build()
+		r := hubofhubsaddon.NewAddonRolloutReconciler(c,
+			func(ctx context.Context, hubs []string) (map[string]hubofhubsaddon.HubHealth, error) {
+				return map[string]hubofhubsaddon.HubHealth{}, nil
+			})
+
+		_, err := r.Reconcile(context.TODO(), ctrl.Request{})
+		assert.NoError(t, err)
+		_, err = r.Reconcile(context.TODO(), ctrl.Request{})
+		assert.NoError(t, err)
+		status := rolloutStatus(c)
+		assert.Equal(t, operatorv1alpha4.AgentRolloutHalted, status.Phase)
+		assert.Equal(t, []string{"hub3"}, status.FailedHubs)
+		assert.Equal(t, oldImage, addonImage(c, "hub1"))
+
+		// resume the rollout
+		mgh := &operatorv1alpha4.MulticlusterGlobalHub{}
+		assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, mgh))
+		mgh.SetAnnotations(map[string]string{operatorconstants.AnnotationResumeAgentRollout: ""})
+		assert.NoError(t, c.Update(context.TODO(), mgh))
+
+		_, err = r.Reconcile(context.TODO(), ctrl.Request{})
+		assert.NoError(t, err)
+		status = rolloutStatus(c)
+		assert.Equal(t, operatorv1alpha4.AgentRolloutProgressing, status.Phase)
+		assert.Equal(t, targetImage, addonImage(c, "hub1"))
+		assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, mgh))
+		_, found := mgh.GetAnnotations()[operatorconstants.AnnotationResumeAgentRollout]
+		assert.False(t, found)
+	})
+}
+
+func TestRolloutWithoutStrategy(t *testing.T) {
+	namespace := "multicluster-global-hub"
+	name := "test"
+	config.SetMGHNamespacedName(types.NamespacedName{Namespace: namespace, Name: name})
+
+	c := fake.NewClientBuilder().WithScheme(config.GetRuntimeScheme()).WithObjects(
+		fakeMGH(namespace, name),
+		fakeCluster("hub1", "", operatorconstants.GHAgentDeployModeDefault),
+		fakeRolloutAddon("hub1", "quay.io/stolostron/multicluster-global-hub-agent:old"),
+	).WithStatusSubresource(&operatorv1alpha4.MulticlusterGlobalHub{}).Build()
+
+	_, err := hubofhubsaddon.NewAddonRolloutReconciler(c, nil).Reconcile(context.TODO(), ctrl.Request{})
+	assert.NoError(t, err)
+
+	addon := &v1alpha1.ManagedClusterAddOn{}
+	assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{
+		Namespace: "hub1", Name: operatorconstants.GHManagedClusterAddonName,
+	}, addon))
+	assert.Equal(t, config.GetImage(config.GlobalHubAgentImageKey),
+		addon.GetAnnotations()[operatorconstants.AnnotationAddonAgentImage])
+}
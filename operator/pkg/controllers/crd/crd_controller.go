@@ -68,6 +68,7 @@ type CrdController struct {
 	operatorConfig        *config.OperatorConfig
 	resources             map[string]bool
 	addonInstallerReady   bool
+	addonRolloutReady     bool
 	agentController       *agent.AddonController
 	globalHubController   runtimeController.Controller
 	backupControllerReady bool
@@ -117,6 +118,14 @@ func (r *CrdController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		r.addonInstallerReady = true
 	}
 
+	// start addon rollout controller
+	if !r.addonRolloutReady {
+		if err := agent.NewAddonRolloutReconciler(r.GetClient(), nil).SetupWithManager(r.Manager); err != nil {
+			return ctrl.Result{}, err
+		}
+		r.addonRolloutReady = true
+	}
+
 	// start addon controller
 	if r.agentController == nil {
 		agentController, err := agent.NewAddonController(r.Manager.GetConfig(), r.Manager.GetClient(), r.operatorConfig)
@@ -557,8 +557,8 @@ func watchMutatingWebhookConfigurationPredicate() predicate.TypedPredicate[*admi
 // +kubebuilder:rbac:groups=apps.open-cluster-management.io,resources=subscriptions,verbs=get;list;update;patch
 // +kubebuilder:rbac:groups=apps.open-cluster-management.io,resources=placementrules,verbs=get;list;update;patch
 // +kubebuilder:rbac:groups=apps.open-cluster-management.io,resources=channels,verbs=get;list;update;patch
-// +kubebuilder:rbac:groups=policy.open-cluster-management.io,resources=policies,verbs=get;list;patch;update
-// +kubebuilder:rbac:groups=policy.open-cluster-management.io,resources=placementbindings,verbs=get;list;patch;update
+// +kubebuilder:rbac:groups=policy.open-cluster-management.io,resources=policies,verbs=create;get;list;patch;update
+// +kubebuilder:rbac:groups=policy.open-cluster-management.io,resources=placementbindings,verbs=create;get;list;patch;update
 // +kubebuilder:rbac:groups=app.k8s.io,resources=applications,verbs=get;list;patch;update
 // +kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=placements,verbs=create;get;list;patch;update;delete
 // +kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=managedclustersetbindings,verbs=create;get;list;patch;update;delete
@@ -592,6 +592,11 @@ func watchMutatingWebhookConfigurationPredicate() predicate.TypedPredicate[*admi
 // +kubebuilder:rbac:groups=image.openshift.io,resources=imagestreams,verbs=get;list;watch
 // +kubebuilder:rbac:groups="authentication.open-cluster-management.io",resources=managedserviceaccounts,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups="global-hub.open-cluster-management.io",resources=managedclustermigrations,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups="global-hub.open-cluster-management.io",resources=globalhubrestores;globalhubrestores/status,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups="global-hub.open-cluster-management.io",resources=globalhubnotifiers;globalhubnotifiers/status,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups="global-hub.open-cluster-management.io",resources=localpolicypromotions;localpolicypromotions/status,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups="global-hub.open-cluster-management.io",resources=globalhuboverrides;globalhuboverrides/status,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups="global-hub.open-cluster-management.io",resources=globalclusteractions;globalclusteractions/status,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups="config.open-cluster-management.io",resources=klusterletconfigs,verbs=create;delete;get;list;patch;update;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -84,6 +84,11 @@ func (r *ManagerReconciler) Reconcile(ctx context.Context,
 	if months < 1 {
 		months = 1
 	}
+	tableRetentions, err := config.GetTableRetentions(mgh.Spec.DataLayerSpec.Postgres)
+	if err != nil {
+		return true, err
+	}
+	jobSchedules, disabledJobs := config.GetManagerJobs(mgh)
 
 	replicas := int32(1)
 	if mgh.Spec.AvailabilityConfig == v1alpha4.HAHigh {
@@ -118,7 +123,7 @@ func (r *ManagerReconciler) Reconcile(ctx context.Context,
 	}
 
 	managerObjects, err := hohRenderer.Render("manifests", "", func(profile string) (interface{}, error) {
-		return ManagerVariables{
+		managerVariables := ManagerVariables{
 			Image:              config.GetImage(config.GlobalHubManagerImageKey),
 			Replicas:           replicas,
 			ProxyImage:         config.GetImage(config.OauthProxyImageKey),
@@ -138,9 +143,12 @@ func (r *ManagerReconciler) Reconcile(ctx context.Context,
 			SchedulerInterval:     config.GetSchedulerInterval(mgh),
 			SkipAuth:              config.SkipAuth(mgh),
 			LaunchJobNames:        config.GetLaunchJobNames(mgh),
+			JobSchedules:          jobSchedules,
+			DisabledJobs:          disabledJobs,
 			NodeSelector:          mgh.Spec.NodeSelector,
 			Tolerations:           mgh.Spec.Tolerations,
 			RetentionMonth:        months,
+			TableRetentions:       tableRetentions,
 			StatisticLogInterval:  config.GetStatisticLogInterval(),
 			EnableGlobalResource:  r.operatorConfig.GlobalResourceEnabled,
 			ImportClusterInHosted: config.GetImportClusterInHosted(),
@@ -148,7 +156,25 @@ func (r *ManagerReconciler) Reconcile(ctx context.Context,
 			LogLevel:              r.operatorConfig.LogLevel,
 			Resources:             utils.GetResources(operatorconstants.Manager, mgh.Spec.AdvancedSpec),
 			WithACM:               config.IsACMResourceReady(),
-		}, nil
+			Tracing:               mgh.Spec.Tracing,
+			TracingSamplingRatio:  config.GetTracingSamplingRatio(mgh.Spec.Tracing),
+		}
+		if backup := mgh.Spec.DataLayerSpec.Postgres.Backup; backup != nil {
+			managerVariables.BackupEnabled = true
+			managerVariables.BackupSchedule = backup.Schedule
+			managerVariables.BackupRetention = backup.Retention
+			managerVariables.BackupStorageSize = backup.StorageSize
+			managerVariables.BackupStorageClass = mgh.Spec.DataLayerSpec.StorageClass
+			managerVariables.BackupS3 = backup.S3
+		}
+		if archive := mgh.Spec.DataLayerSpec.Postgres.Archive; archive != nil {
+			managerVariables.ArchiveEnabled = true
+			managerVariables.ArchiveFormat = archive.Format
+			managerVariables.ArchiveStorageSize = archive.StorageSize
+			managerVariables.ArchiveStorageClass = mgh.Spec.DataLayerSpec.StorageClass
+			managerVariables.ArchiveS3 = archive.S3
+		}
+		return managerVariables, nil
 	})
 	if err != nil {
 		return true, fmt.Errorf("failed to render manager objects: %v", err)
@@ -205,9 +231,12 @@ type ManagerVariables struct {
 	SchedulerInterval     string
 	SkipAuth              bool
 	LaunchJobNames        string
+	JobSchedules          []string
+	DisabledJobs          string
 	NodeSelector          map[string]string
 	Tolerations           []corev1.Toleration
 	RetentionMonth        int
+	TableRetentions       string
 	StatisticLogInterval  string
 	EnableGlobalResource  bool
 	ImportClusterInHosted bool
@@ -215,4 +244,17 @@ type ManagerVariables struct {
 	LogLevel              string
 	Resources             *corev1.ResourceRequirements
 	WithACM               bool
+	BackupEnabled         bool
+	BackupSchedule        string
+	BackupRetention       int32
+	BackupStorageSize     string
+	BackupStorageClass    string
+	BackupS3              *v1alpha4.S3BackupStorage
+	ArchiveEnabled        bool
+	ArchiveFormat         string
+	ArchiveStorageSize    string
+	ArchiveStorageClass   string
+	ArchiveS3             *v1alpha4.S3BackupStorage
+	Tracing               *v1alpha4.TracingSpec
+	TracingSamplingRatio  string
 }
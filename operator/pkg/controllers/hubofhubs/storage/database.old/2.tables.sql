@@ -112,6 +112,26 @@ CREATE TABLE IF NOT EXISTS spec.managed_clusters_labels (
     CONSTRAINT managed_clusters_labels_version_check CHECK ((version >= 0))
 );
 
+CREATE TABLE IF NOT EXISTS spec.managed_cluster_operations (
+    id uuid NOT NULL PRIMARY KEY,
+    payload jsonb NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS spec.managed_cluster_actions (
+    id uuid NOT NULL PRIMARY KEY,
+    namespace character varying(254) NOT NULL,
+    name character varying(254) NOT NULL,
+    leaf_hub_name character varying(254) NOT NULL,
+    cluster_name character varying(254) NOT NULL,
+    action character varying(64) NOT NULL,
+    payload jsonb NOT NULL,
+    state character varying(64) NOT NULL,
+    message text,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
 CREATE TABLE IF NOT EXISTS spec.managedclustersetbindings (
     id uuid PRIMARY KEY,
     payload jsonb NOT NULL,
@@ -185,6 +205,17 @@ CREATE TABLE IF NOT EXISTS status.compliance (
     cluster_id uuid
 );
 
+CREATE TABLE IF NOT EXISTS status.managed_cluster_operation_results (
+    operation_id uuid NOT NULL,
+    cluster_id uuid NOT NULL,
+    cluster_name character varying(254) NOT NULL,
+    leaf_hub_name character varying(254) NOT NULL,
+    state character varying(64) NOT NULL,
+    message text,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (operation_id, cluster_id)
+);
+
 CREATE TABLE IF NOT EXISTS status.placementdecisions (
     id uuid NOT NULL,
     leaf_hub_name character varying(254) NOT NULL,
@@ -217,6 +248,10 @@ CREATE TABLE IF NOT EXISTS status.subscription_statuses (
 
 CREATE UNIQUE INDEX IF NOT EXISTS managed_cluster_sets_tracking_cluster_set_name_and_leaf_hub_name_idx ON spec.managed_cluster_sets_tracking (cluster_set_name, leaf_hub_name);
 
+CREATE INDEX IF NOT EXISTS managed_cluster_actions_pending_idx ON spec.managed_cluster_actions (created_at) WHERE ((state)::text = 'pending'::text);
+
+CREATE INDEX IF NOT EXISTS managed_cluster_operation_results_pending_idx ON status.managed_cluster_operation_results (operation_id, leaf_hub_name) WHERE ((state)::text = 'pending'::text);
+
 CREATE INDEX IF NOT EXISTS compliance_leaf_hub_cluster_idx ON status.compliance (leaf_hub_name, cluster_name);
 
 CREATE INDEX IF NOT EXISTS compliance_leaf_hub_non_compliant_idx ON status.compliance (leaf_hub_name, compliance) WHERE (compliance <> 'compliant'::status.compliance_type);
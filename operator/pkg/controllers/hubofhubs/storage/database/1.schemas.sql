@@ -11,6 +11,8 @@ CREATE SCHEMA IF NOT EXISTS event;
 
 CREATE SCHEMA IF NOT EXISTS security;
 
+CREATE SCHEMA IF NOT EXISTS spec;
+
 CREATE EXTENSION IF NOT EXISTS pg_stat_statements;
 
 DO $$ BEGIN
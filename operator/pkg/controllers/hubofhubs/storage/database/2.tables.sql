@@ -51,6 +51,10 @@ CREATE TABLE IF NOT EXISTS status.leaf_hubs (
     payload jsonb NOT NULL,
     console_url text generated always as (payload ->> 'consoleURL') stored,
     grafana_url text generated always as (payload ->> 'grafanaURL') stored,
+    agent_version text generated always as (payload ->> 'agentVersion') stored,
+    openshift_version text generated always as (payload ->> 'openshiftVersion') stored,
+    acm_version text generated always as (payload ->> 'acmVersion') stored,
+    mce_version text generated always as (payload ->> 'mceVersion') stored,
     created_at timestamp without time zone DEFAULT now() NOT NULL,
     updated_at timestamp without time zone DEFAULT now() NOT NULL,
     deleted_at timestamp without time zone,
@@ -58,6 +62,22 @@ CREATE TABLE IF NOT EXISTS status.leaf_hubs (
 );
 CREATE INDEX IF NOT EXISTS leafhub_deleted_at_idx ON status.leaf_hubs (deleted_at);
 
+CREATE TABLE IF NOT EXISTS status.policy_violations (
+    policy_id uuid NOT NULL,
+    cluster_name character varying(254) NOT NULL,
+    leaf_hub_name character varying(254) NOT NULL,
+    template_name character varying(254) NOT NULL,
+    cluster_id uuid,
+    template_kind character varying(254) NOT NULL,
+    template_api_version character varying(254) NOT NULL,
+    compliance status.compliance_type NOT NULL,
+    message text,
+    related_objects jsonb NOT NULL DEFAULT '[]',
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (policy_id, cluster_name, leaf_hub_name, template_name)
+);
+CREATE INDEX IF NOT EXISTS policy_violations_leafhub_idx ON status.policy_violations (leaf_hub_name);
+
 -- Partition tables
 CREATE TABLE IF NOT EXISTS event.managed_clusters (
     event_namespace text NOT NULL,
@@ -104,6 +124,19 @@ CREATE TABLE IF NOT EXISTS event.local_root_policies (
     CONSTRAINT local_root_policies_unique_constraint UNIQUE (event_name, count, created_at)
 ) PARTITION BY RANGE (created_at);
 
+CREATE TABLE IF NOT EXISTS event.spec_drifts (
+    leaf_hub_name character varying(254) NOT NULL,
+    object_id uuid NOT NULL,
+    object_kind character varying(254) NOT NULL,
+    object_namespace text,
+    object_name text NOT NULL,
+    reason text NOT NULL,
+    message text,
+    reverted boolean NOT NULL DEFAULT false,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    CONSTRAINT spec_drifts_unique_constraint UNIQUE (leaf_hub_name, object_id, created_at)
+) PARTITION BY RANGE (created_at);
+
 -- log tables
 CREATE TABLE IF NOT EXISTS event.data_retention_job_log (
     table_name varchar(254) NOT NULL,
@@ -112,7 +145,11 @@ CREATE TABLE IF NOT EXISTS event.data_retention_job_log (
     min_partition varchar(254), -- minimum partition after the job
     max_partition varchar(254), -- maximum partition after the job
     min_deletion  timestamp, -- the oldest deleted record in the table after the job
-    error TEXT
+    error TEXT,
+    retention_month integer, -- the months to keep the data of the table
+    deleted_partitions text, -- the partitions deleted by the job, separated by comma
+    archives text, -- the archives of the deleted partitions, separated by comma
+    archived_rows bigint DEFAULT 0 -- the number of the archived rows
 );
 
 CREATE TABLE IF NOT EXISTS history.local_compliance (
@@ -125,6 +162,19 @@ CREATE TABLE IF NOT EXISTS history.local_compliance (
     CONSTRAINT local_policies_unique_constraint UNIQUE (leaf_hub_name, policy_id, cluster_id, compliance_date)
 ) PARTITION BY RANGE (compliance_date);
 
+-- the field level changes of the managed clusters: labels, version, conditions, claims and the owning hub
+CREATE TABLE IF NOT EXISTS history.managed_clusters (
+    cluster_id uuid NOT NULL,
+    cluster_name character varying(254) NOT NULL,
+    leaf_hub_name character varying(254) NOT NULL,
+    field character varying(64) NOT NULL,
+    key text NOT NULL DEFAULT '', -- the label key, the condition type or the claim name
+    old_value text,
+    new_value text,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+) PARTITION BY RANGE (created_at);
+CREATE INDEX IF NOT EXISTS managed_clusters_history_cluster_idx ON history.managed_clusters (cluster_id, created_at);
+
 CREATE TABLE IF NOT EXISTS history.local_compliance_job_log (
     name varchar(254) NOT NULL,
     start_at timestamp NOT NULL DEFAULT now(),
@@ -135,6 +185,35 @@ CREATE TABLE IF NOT EXISTS history.local_compliance_job_log (
     error TEXT
 );
 
+-- the daily compliance counts of the policies per managed hub, rolled up from the history.local_compliance
+CREATE TABLE IF NOT EXISTS history.local_compliance_rollup (
+    policy_id uuid NOT NULL,
+    leaf_hub_name character varying(254) NOT NULL,
+    compliance_date DATE NOT NULL,
+    compliant integer NOT NULL DEFAULT 0,
+    non_compliant integer NOT NULL DEFAULT 0,
+    pending integer NOT NULL DEFAULT 0,
+    unknown integer NOT NULL DEFAULT 0,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (policy_id, leaf_hub_name, compliance_date)
+);
+CREATE INDEX IF NOT EXISTS local_compliance_rollup_date_idx ON history.local_compliance_rollup (compliance_date);
+
+-- the runs of the manager jobs, which are triggered by the schedule, the launch or the API
+CREATE TABLE IF NOT EXISTS history.job_runs (
+    id uuid PRIMARY KEY,
+    job_name character varying(254) NOT NULL,
+    trigger character varying(64) NOT NULL,
+    state character varying(64) NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    start_at timestamp without time zone,
+    end_at timestamp without time zone,
+    duration_ms bigint DEFAULT 0 NOT NULL,
+    error text
+);
+CREATE INDEX IF NOT EXISTS job_runs_job_name_idx ON history.job_runs (job_name, created_at);
+CREATE INDEX IF NOT EXISTS job_runs_pending_idx ON history.job_runs (created_at) WHERE ((state)::text = 'pending'::text);
+
 CREATE TABLE IF NOT EXISTS status.transport (
     -- transport name, it is the topic name for the kafka transport
     name character varying(254) PRIMARY KEY,
@@ -143,6 +222,37 @@ CREATE TABLE IF NOT EXISTS status.transport (
     updated_at timestamp without time zone DEFAULT now() NOT NULL
 );
 
+-- the sync health of the event types per managed hub, the counters are reset when the manager restarts. The row of
+-- the "transport" event type keeps the consumer lag of the managed hub topic
+CREATE TABLE IF NOT EXISTS status.sync_health (
+    leaf_hub_name character varying(254) NOT NULL,
+    event_type character varying(254) NOT NULL,
+    last_received_at timestamp without time zone,
+    last_processed_at timestamp without time zone,
+    last_processed_version character varying(64) DEFAULT ''::character varying NOT NULL,
+    processing_latency_ms bigint DEFAULT 0 NOT NULL,
+    received bigint DEFAULT 0 NOT NULL,
+    processed bigint DEFAULT 0 NOT NULL,
+    failures bigint DEFAULT 0 NOT NULL,
+    last_error text DEFAULT ''::text NOT NULL,
+    topic character varying(254) DEFAULT ''::character varying NOT NULL,
+    consumer_lag bigint DEFAULT 0 NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (leaf_hub_name, event_type)
+);
+
+-- the saved searches aren't global resources, the table is created whether the global resources are enabled or not
+CREATE TABLE IF NOT EXISTS spec.saved_searches (
+    id uuid NOT NULL PRIMARY KEY,
+    name character varying(254) NOT NULL,
+    owner character varying(254) DEFAULT ''::character varying NOT NULL,
+    description text DEFAULT ''::text NOT NULL,
+    query text NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    UNIQUE (owner, name)
+);
+
 CREATE TABLE IF NOT EXISTS security.alert_counts (
     hub_name text NOT NULL,
     low integer NOT NULL,
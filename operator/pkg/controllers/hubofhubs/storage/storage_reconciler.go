@@ -41,6 +41,8 @@ type StorageReconciler struct {
 	upgrade                bool
 	databaseReconcileCount int
 	enableGlobalResource   bool
+	// the last database restore, the schema and migrations are re-applied after the database is restored
+	restoreVersion string
 }
 
 func NewStorageReconciler(mgr ctrl.Manager, enableGlobalResource bool) *StorageReconciler {
@@ -112,6 +114,12 @@ func (r *StorageReconciler) reconcileDatabase(ctx context.Context, mgh *v1alpha4
 		reconcileErr = fmt.Errorf("storage connection is nil")
 		return true, reconcileErr
 	}
+	if restoreVersion := mgh.Annotations[constants.DatabaseRestoreAnnotation]; restoreVersion != r.restoreVersion {
+		log.Info("the database is restored, reconcile the database again", "restore", restoreVersion)
+		r.restoreVersion = restoreVersion
+		r.databaseReconcileCount = 0
+		r.upgrade = false
+	}
 	// if the operator is restarted, reconcile the database again
 	if r.databaseReconcileCount > 0 {
 		return false, nil
@@ -17,3 +17,15 @@ ALTER TYPE local_status.compliance_type ADD VALUE IF NOT EXISTS 'pending';
 ALTER TABLE event.local_policies ADD COLUMN IF NOT EXISTS event_namespace text;
 ALTER TABLE event.local_policies ADD COLUMN IF NOT EXISTS cluster_name text;
 ALTER TABLE event.local_root_policies ADD COLUMN IF NOT EXISTS event_namespace text;
+
+---- Add the versions reported by the hub cluster info
+ALTER TABLE status.leaf_hubs ADD COLUMN IF NOT EXISTS agent_version text generated always as (payload ->> 'agentVersion') stored;
+ALTER TABLE status.leaf_hubs ADD COLUMN IF NOT EXISTS openshift_version text generated always as (payload ->> 'openshiftVersion') stored;
+ALTER TABLE status.leaf_hubs ADD COLUMN IF NOT EXISTS acm_version text generated always as (payload ->> 'acmVersion') stored;
+ALTER TABLE status.leaf_hubs ADD COLUMN IF NOT EXISTS mce_version text generated always as (payload ->> 'mceVersion') stored;
+
+---- Add the retention report to the data retention job log
+ALTER TABLE event.data_retention_job_log ADD COLUMN IF NOT EXISTS retention_month integer;
+ALTER TABLE event.data_retention_job_log ADD COLUMN IF NOT EXISTS deleted_partitions text;
+ALTER TABLE event.data_retention_job_log ADD COLUMN IF NOT EXISTS archives text;
+ALTER TABLE event.data_retention_job_log ADD COLUMN IF NOT EXISTS archived_rows bigint DEFAULT 0;
@@ -6,19 +6,25 @@ package protocol
 import (
 	"context"
 	"embed"
+	"fmt"
+	"strings"
 	"time"
 
 	kafkav1beta2 "github.com/RedHatInsights/strimzi-client-go/apis/kafka.strimzi.io/v1beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	"github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
+	operatorconstants "github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	"github.com/stolostron/multicluster-global-hub/pkg/utils"
 )
@@ -44,6 +50,8 @@ func (r *KafkaController) Reconcile(ctx context.Context, request ctrl.Request) (
 	if mgh.DeletionTimestamp != nil {
 		return ctrl.Result{}, nil
 	}
+	// the transporter is shared with the addons, refresh the mgh so that the kafkaUsers get the latest quotas
+	r.trans.mgh = mgh
 	var reconcileErr error
 	var kafkaReady bool
 
@@ -78,9 +86,46 @@ func (r *KafkaController) Reconcile(ctx context.Context, request ctrl.Request) (
 	}
 	config.SetTransporterConn(conn)
 
+	// the addons aren't reconciled once the quotas are changed, so apply them to the managed hub kafkaUsers here
+	if err := r.trans.EnsureUserQuotas(); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// audit the managed hub kafkaUsers, the drift is reported in the mgh condition and metrics
+	drifts, err := r.trans.AuditKafkaUsers()
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	updateKafkaUserAuditCondition(ctx, r.GetClient(), mgh, drifts)
+
 	return ctrl.Result{}, nil
 }
 
+// updateKafkaUserAuditCondition reports the drift without changing the phase, since the managed hubs are still able to
+// communicate with the global hub
+func updateKafkaUserAuditCondition(ctx context.Context, c client.Client, mgh *v1alpha4.MulticlusterGlobalHub,
+	drifts []KafkaUserDrift,
+) {
+	cond := metav1.Condition{
+		Type:    config.CONDITION_TYPE_KAFKA_USER_AUDIT,
+		Status:  config.CONDITION_STATUS_TRUE,
+		Reason:  config.CONDITION_REASON_KAFKA_USER_AUDITED,
+		Message: config.CONDITION_MESSAGE_KAFKA_USER_AUDIT,
+	}
+	if len(drifts) > 0 {
+		messages := make([]string, 0, len(drifts))
+		for _, drift := range drifts {
+			messages = append(messages, drift.String())
+		}
+		cond.Status = config.CONDITION_STATUS_FALSE
+		cond.Reason = config.CONDITION_REASON_KAFKA_USER_DRIFTED
+		cond.Message = fmt.Sprintf("The kafkaUser ACLs drift from the expected: %s", strings.Join(messages, "; "))
+	}
+	if err := config.UpdateCondition(ctx, c, client.ObjectKeyFromObject(mgh), cond, ""); err != nil {
+		klog.Errorf("failed to update the kafkaUser audit condition: %v", err)
+	}
+}
+
 var kafkaPred = predicate.Funcs{
 	CreateFunc: func(e event.CreateEvent) bool {
 		return false
@@ -93,6 +138,28 @@ var kafkaPred = predicate.Funcs{
 	},
 }
 
+// quotaLabelPred triggers the reconcile once the kafka quota labels of the managed hub are changed
+var quotaLabelPred = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return false
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		for _, key := range []string{
+			operatorconstants.KafkaProducerByteRateLabelKey,
+			operatorconstants.KafkaConsumerByteRateLabelKey,
+			operatorconstants.KafkaRequestPercentageLabelKey,
+		} {
+			if e.ObjectNew.GetLabels()[key] != e.ObjectOld.GetLabels()[key] {
+				return true
+			}
+		}
+		return false
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return false
+	},
+}
+
 var mghPred = predicate.Funcs{
 	CreateFunc: func(e event.CreateEvent) bool {
 		return true
@@ -123,6 +190,8 @@ func StartKafkaController(ctx context.Context, mgr ctrl.Manager, transporter tra
 			&handler.EnqueueRequestForObject{}, builder.WithPredicates(kafkaPred)).
 		Watches(&kafkav1beta2.KafkaTopic{},
 			&handler.EnqueueRequestForObject{}, builder.WithPredicates(kafkaPred)).
+		Watches(&clusterv1.ManagedCluster{},
+			&handler.EnqueueRequestForObject{}, builder.WithPredicates(quotaLabelPred)).
 		Complete(r)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,259 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package protocol
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	kafkav1beta2 "github.com/RedHatInsights/strimzi-client-go/apis/kafka.strimzi.io/v1beta2"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
+	operatorconstants "github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+var (
+	// KafkaUserQuotaGaugeVec reports the quotas applied to the kafkaUser of each managed hub, the throttling itself
+	// is exposed by the broker metrics "kafka_server_quota_*_throttle_time" and alerted by the kafka PrometheusRule
+	KafkaUserQuotaGaugeVec = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "multicluster_global_hub_kafka_user_quota",
+			Help: "The quota applied to the kafkaUser of the managed hub.",
+		},
+		[]string{"user", "quota"},
+	)
+	// KafkaUserACLDriftGaugeVec is 1 when the ACLs of the managed hub kafkaUser drift from the expected ACLs
+	KafkaUserACLDriftGaugeVec = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "multicluster_global_hub_kafka_user_acl_drift",
+			Help: "Whether the ACLs of the managed hub kafkaUser drift from the expected ACLs (1) or not (0).",
+		},
+		[]string{"user"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(KafkaUserQuotaGaugeVec, KafkaUserACLDriftGaugeVec)
+}
+
+// KafkaUserDrift records the difference between the actual and expected ACLs of a managed hub kafkaUser
+type KafkaUserDrift struct {
+	UserName string
+	// Missing ACLs are expected but not granted to the kafkaUser
+	Missing []string
+	// Unexpected ACLs are granted to the kafkaUser but not expected
+	Unexpected []string
+}
+
+func (d KafkaUserDrift) String() string {
+	return fmt.Sprintf("%s(missing: [%s], unexpected: [%s])", d.UserName,
+		strings.Join(d.Missing, ", "), strings.Join(d.Unexpected, ", "))
+}
+
+// getClusterQuotas returns the default quotas from the mgh overridden by the kafka quota labels of the managed hub
+func (k *strimziTransporter) getClusterQuotas(clusterName string) (*kafkav1beta2.KafkaUserSpecQuotas, error) {
+	quotas := &kafkav1beta2.KafkaUserSpecQuotas{}
+	if defaults := k.mgh.Spec.DataLayerSpec.Kafka.Quotas; defaults != nil {
+		quotas.ProducerByteRate = defaults.ProducerByteRate
+		quotas.ConsumerByteRate = defaults.ConsumerByteRate
+		quotas.RequestPercentage = defaults.RequestPercentage
+	}
+
+	cluster := &clusterv1.ManagedCluster{}
+	err := k.manager.GetClient().Get(k.ctx, types.NamespacedName{Name: clusterName}, cluster)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	k.overrideQuota(cluster.Labels, operatorconstants.KafkaProducerByteRateLabelKey, &quotas.ProducerByteRate)
+	k.overrideQuota(cluster.Labels, operatorconstants.KafkaConsumerByteRateLabelKey, &quotas.ConsumerByteRate)
+	k.overrideQuota(cluster.Labels, operatorconstants.KafkaRequestPercentageLabelKey, &quotas.RequestPercentage)
+
+	userName := config.GetKafkaUserName(clusterName)
+	setQuotaMetric(userName, "producerByteRate", quotas.ProducerByteRate)
+	setQuotaMetric(userName, "consumerByteRate", quotas.ConsumerByteRate)
+	setQuotaMetric(userName, "requestPercentage", quotas.RequestPercentage)
+
+	if quotas.ProducerByteRate == nil && quotas.ConsumerByteRate == nil && quotas.RequestPercentage == nil {
+		return nil, nil
+	}
+	return quotas, nil
+}
+
+// overrideQuota sets the quota with the label value, the invalid value is ignored so that a typo in the label
+// doesn't block the managed hub from connecting to the kafka
+func (k *strimziTransporter) overrideQuota(labels map[string]string, key string, quota **int32) {
+	val, ok := labels[key]
+	if !ok {
+		return
+	}
+	parsed, err := strconv.ParseInt(val, 10, 32)
+	if err != nil || parsed < 0 {
+		k.log.Info("ignore the invalid kafka quota label", "label", key, "value", val)
+		return
+	}
+	q := int32(parsed)
+	*quota = &q
+}
+
+func setQuotaMetric(userName, quotaName string, quota *int32) {
+	if quota == nil {
+		KafkaUserQuotaGaugeVec.DeleteLabelValues(userName, quotaName)
+		return
+	}
+	KafkaUserQuotaGaugeVec.WithLabelValues(userName, quotaName).Set(float64(*quota))
+}
+
+// hubKafkaUser is the kafkaUser of a managed hub
+type hubKafkaUser struct {
+	clusterName string
+	kafkaUser   *kafkav1beta2.KafkaUser
+}
+
+// listHubKafkaUsers returns the kafkaUsers of the managed hubs, the global hub kafkaUser is skipped since it's rendered
+// from the manifests
+func (k *strimziTransporter) listHubKafkaUsers() ([]hubKafkaUser, error) {
+	kafkaUsers := &kafkav1beta2.KafkaUserList{}
+	if err := k.manager.GetClient().List(k.ctx, kafkaUsers, client.InNamespace(k.kafkaClusterNamespace),
+		client.MatchingLabels{constants.GlobalHubOwnerLabelKey: constants.GlobalHubOwnerLabelVal},
+	); err != nil {
+		return nil, err
+	}
+
+	hubUsers := []hubKafkaUser{}
+	for i := range kafkaUsers.Items {
+		kafkaUser := &kafkaUsers.Items[i]
+		if kafkaUser.Name == DefaultGlobalHubKafkaUserName {
+			continue
+		}
+		clusterName, ok := strings.CutSuffix(kafkaUser.Name, config.GetKafkaUserName(""))
+		if !ok || clusterName == "" {
+			continue
+		}
+		hubUsers = append(hubUsers, hubKafkaUser{clusterName: clusterName, kafkaUser: kafkaUser})
+	}
+	return hubUsers, nil
+}
+
+// EnsureUserQuotas applies the quotas to the existing kafkaUsers of the managed hubs, so that the changes of the
+// default quotas in the mgh and the quota labels of the managed hubs don't wait for the addons to be reconciled
+func (k *strimziTransporter) EnsureUserQuotas() error {
+	hubUsers, err := k.listHubKafkaUsers()
+	if err != nil {
+		return err
+	}
+	for _, hubUser := range hubUsers {
+		quotas, err := k.getClusterQuotas(hubUser.clusterName)
+		if err != nil {
+			return err
+		}
+		kafkaUser := hubUser.kafkaUser
+		if kafkaUser.Spec == nil {
+			kafkaUser.Spec = &kafkav1beta2.KafkaUserSpec{}
+		}
+		if equality.Semantic.DeepEqual(kafkaUser.Spec.Quotas, quotas) {
+			continue
+		}
+		kafkaUser.Spec.Quotas = quotas
+		k.log.Info("update the quotas of the kafkaUser", "name", kafkaUser.Name)
+		if err := k.manager.GetClient().Update(k.ctx, kafkaUser); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AuditKafkaUsers compares the ACLs of the managed hub kafkaUsers with the expected ACLs, and returns the drifted users
+func (k *strimziTransporter) AuditKafkaUsers() ([]KafkaUserDrift, error) {
+	hubUsers, err := k.listHubKafkaUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	drifts := []KafkaUserDrift{}
+	for _, hubUser := range hubUsers {
+		kafkaUser, clusterName := hubUser.kafkaUser, hubUser.clusterName
+		var actual []kafkav1beta2.KafkaUserSpecAuthorizationAclsElem
+		if kafkaUser.Spec != nil && kafkaUser.Spec.Authorization != nil {
+			actual = kafkaUser.Spec.Authorization.Acls
+		}
+		drift := diffACLs(kafkaUser.Name, k.expectedUserACLs(clusterName), actual)
+		if drift == nil {
+			KafkaUserACLDriftGaugeVec.WithLabelValues(kafkaUser.Name).Set(0)
+			continue
+		}
+		KafkaUserACLDriftGaugeVec.WithLabelValues(kafkaUser.Name).Set(1)
+		k.log.Info("the kafkaUser ACLs drift from the expected", "drift", drift.String())
+		drifts = append(drifts, *drift)
+	}
+	return drifts, nil
+}
+
+// diffACLs returns nil if the actual ACLs are the same as the expected, the order of the ACLs is ignored
+func diffACLs(userName string, expected, actual []kafkav1beta2.KafkaUserSpecAuthorizationAclsElem,
+) *KafkaUserDrift {
+	drift := &KafkaUserDrift{UserName: userName}
+	for _, acl := range expected {
+		if !containsACL(actual, acl) {
+			drift.Missing = append(drift.Missing, aclString(acl))
+		}
+	}
+	for _, acl := range actual {
+		if !containsACL(expected, acl) {
+			drift.Unexpected = append(drift.Unexpected, aclString(acl))
+		}
+	}
+	if len(drift.Missing) == 0 && len(drift.Unexpected) == 0 {
+		return nil
+	}
+	return drift
+}
+
+func containsACL(acls []kafkav1beta2.KafkaUserSpecAuthorizationAclsElem,
+	acl kafkav1beta2.KafkaUserSpecAuthorizationAclsElem,
+) bool {
+	for _, a := range acls {
+		if aclString(a) == aclString(acl) {
+			return true
+		}
+	}
+	return false
+}
+
+// aclString returns a canonical representation of the ACL, e.g. "allow topic:gh-status.hub1(literal)[Write]@*"
+func aclString(acl kafkav1beta2.KafkaUserSpecAuthorizationAclsElem) string {
+	name, patternType, host, aclType := "", "literal", "*", "allow"
+	if acl.Resource.Name != nil {
+		name = *acl.Resource.Name
+	}
+	if acl.Resource.PatternType != nil {
+		patternType = string(*acl.Resource.PatternType)
+	}
+	if acl.Host != nil {
+		host = *acl.Host
+	}
+	if acl.Type != nil {
+		aclType = string(*acl.Type)
+	}
+	operations := []string{}
+	for _, op := range acl.Operations {
+		operations = append(operations, string(op))
+	}
+	// the deprecated single operation
+	if acl.Operation != nil {
+		operations = append(operations, string(*acl.Operation))
+	}
+	sort.Strings(operations)
+	return fmt.Sprintf("%s %s:%s(%s)[%s]@%s", aclType, acl.Resource.Type, name, patternType,
+		strings.Join(operations, ","), host)
+}
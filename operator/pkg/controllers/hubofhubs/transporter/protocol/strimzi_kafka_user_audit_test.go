@@ -0,0 +1,118 @@
+package protocol
+
+import (
+	"testing"
+
+	kafkav1beta2 "github.com/RedHatInsights/strimzi-client-go/apis/kafka.strimzi.io/v1beta2"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	operatorconstants "github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
+)
+
+func TestDiffACLs(t *testing.T) {
+	expected := []kafkav1beta2.KafkaUserSpecAuthorizationAclsElem{
+		ConsumeGroupReadACL(),
+		ReadTopicACL("gh-spec", false),
+		WriteTopicACL("gh-status.hub1"),
+	}
+
+	cases := []struct {
+		name           string
+		actual         []kafkav1beta2.KafkaUserSpecAuthorizationAclsElem
+		wantDrift      bool
+		wantMissing    int
+		wantUnexpected int
+	}{
+		{
+			name: "same acls in different order",
+			actual: []kafkav1beta2.KafkaUserSpecAuthorizationAclsElem{
+				WriteTopicACL("gh-status.hub1"),
+				ConsumeGroupReadACL(),
+				ReadTopicACL("gh-spec", false),
+			},
+			wantDrift: false,
+		},
+		{
+			name: "write another hub topic",
+			actual: []kafkav1beta2.KafkaUserSpecAuthorizationAclsElem{
+				ConsumeGroupReadACL(),
+				ReadTopicACL("gh-spec", false),
+				WriteTopicACL("gh-status.hub2"),
+			},
+			wantDrift:      true,
+			wantMissing:    1,
+			wantUnexpected: 1,
+		},
+		{
+			name: "read all the status topics",
+			actual: []kafkav1beta2.KafkaUserSpecAuthorizationAclsElem{
+				ConsumeGroupReadACL(),
+				ReadTopicACL("gh-spec", false),
+				WriteTopicACL("gh-status.hub1"),
+				ReadTopicACL("gh-status.", true),
+			},
+			wantDrift:      true,
+			wantUnexpected: 1,
+		},
+		{
+			name:        "no acls",
+			actual:      nil,
+			wantDrift:   true,
+			wantMissing: 3,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			drift := diffACLs("hub1-kafka-user", expected, c.actual)
+			if !c.wantDrift {
+				assert.Nil(t, drift)
+				return
+			}
+			assert.NotNil(t, drift)
+			assert.Len(t, drift.Missing, c.wantMissing)
+			assert.Len(t, drift.Unexpected, c.wantUnexpected)
+		})
+	}
+}
+
+func TestOverrideQuota(t *testing.T) {
+	trans := &strimziTransporter{}
+	labels := map[string]string{
+		operatorconstants.KafkaProducerByteRateLabelKey:  "1048576",
+		operatorconstants.KafkaConsumerByteRateLabelKey:  "invalid",
+		operatorconstants.KafkaRequestPercentageLabelKey: "-1",
+	}
+
+	producer := ptr.To[int32](1024)
+	trans.overrideQuota(labels, operatorconstants.KafkaProducerByteRateLabelKey, &producer)
+	assert.Equal(t, int32(1048576), *producer)
+
+	consumer := ptr.To[int32](2048)
+	trans.overrideQuota(labels, operatorconstants.KafkaConsumerByteRateLabelKey, &consumer)
+	assert.Equal(t, int32(2048), *consumer)
+
+	var request *int32
+	trans.overrideQuota(labels, operatorconstants.KafkaRequestPercentageLabelKey, &request)
+	assert.Nil(t, request)
+}
+
+func TestQuotaLabelPred(t *testing.T) {
+	cluster := func(labels map[string]string) *clusterv1.ManagedCluster {
+		return &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "hub1", Labels: labels}}
+	}
+	old := cluster(map[string]string{"vendor": "OpenShift"})
+
+	assert.False(t, quotaLabelPred.Update(event.UpdateEvent{
+		ObjectOld: old, ObjectNew: cluster(map[string]string{"vendor": "OpenShift", "env": "prod"}),
+	}))
+	assert.True(t, quotaLabelPred.Update(event.UpdateEvent{
+		ObjectOld: old, ObjectNew: cluster(map[string]string{
+			"vendor": "OpenShift", operatorconstants.KafkaProducerByteRateLabelKey: "1048576",
+		}),
+	}))
+}
@@ -291,19 +291,19 @@ func (k *strimziTransporter) renderKafkaResources(mgh *operatorv1alpha4.Multiclu
 // EnsureUser to reconcile the kafkaUser's setting(authn and authz)
 func (k *strimziTransporter) EnsureUser(clusterName string) (string, error) {
 	userName := config.GetKafkaUserName(clusterName)
-	clusterTopic := k.getClusterTopic(clusterName)
 
 	authnType := kafkav1beta2.KafkaUserSpecAuthenticationTypeTlsExternal
-	simpleACLs := []kafkav1beta2.KafkaUserSpecAuthorizationAclsElem{
-		ConsumeGroupReadACL(),
-		ReadTopicACL(clusterTopic.SpecTopic, false),
-		WriteTopicACL(clusterTopic.StatusTopic),
+	simpleACLs := k.expectedUserACLs(clusterName)
+
+	quotas, err := k.getClusterQuotas(clusterName)
+	if err != nil {
+		return "", err
 	}
 
-	desiredKafkaUser := k.newKafkaUser(userName, authnType, simpleACLs)
+	desiredKafkaUser := k.newKafkaUser(userName, authnType, simpleACLs, quotas)
 
 	kafkaUser := &kafkav1beta2.KafkaUser{}
-	err := k.manager.GetClient().Get(k.ctx, types.NamespacedName{
+	err = k.manager.GetClient().Get(k.ctx, types.NamespacedName{
 		Name:      userName,
 		Namespace: k.kafkaClusterNamespace,
 	}, kafkaUser)
@@ -319,8 +319,11 @@ func (k *strimziTransporter) EnsureUser(clusterName string) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	// the merge patch keeps the existing quotas, so override them to remove the quotas which are no longer set
+	updatedKafkaUser.Spec.Quotas = desiredKafkaUser.Spec.Quotas
 
-	if !equality.Semantic.DeepDerivative(updatedKafkaUser.Spec, kafkaUser.Spec) {
+	if !equality.Semantic.DeepDerivative(updatedKafkaUser.Spec, kafkaUser.Spec) ||
+		!equality.Semantic.DeepEqual(updatedKafkaUser.Spec.Quotas, kafkaUser.Spec.Quotas) {
 		klog.Infof("update the kafkaUser: %s", userName)
 		if err = k.manager.GetClient().Update(k.ctx, updatedKafkaUser); err != nil {
 			return "", err
@@ -518,10 +521,22 @@ func (k *strimziTransporter) newKafkaTopic(topicName string) *kafkav1beta2.Kafka
 	}
 }
 
+// expectedUserACLs returns the ACLs granted to the kafkaUser of the managed hub: read the spec topic and write the
+// status topic of the hub
+func (k *strimziTransporter) expectedUserACLs(clusterName string) []kafkav1beta2.KafkaUserSpecAuthorizationAclsElem {
+	clusterTopic := k.getClusterTopic(clusterName)
+	return []kafkav1beta2.KafkaUserSpecAuthorizationAclsElem{
+		ConsumeGroupReadACL(),
+		ReadTopicACL(clusterTopic.SpecTopic, false),
+		WriteTopicACL(clusterTopic.StatusTopic),
+	}
+}
+
 func (k *strimziTransporter) newKafkaUser(
 	userName string,
 	authnType kafkav1beta2.KafkaUserSpecAuthenticationType,
 	simpleACLs []kafkav1beta2.KafkaUserSpecAuthorizationAclsElem,
+	quotas *kafkav1beta2.KafkaUserSpecQuotas,
 ) *kafkav1beta2.KafkaUser {
 	return &kafkav1beta2.KafkaUser{
 		ObjectMeta: metav1.ObjectMeta{
@@ -541,6 +556,7 @@ func (k *strimziTransporter) newKafkaUser(
 				Type: kafkav1beta2.KafkaUserSpecAuthorizationTypeSimple,
 				Acls: simpleACLs,
 			},
+			Quotas: quotas,
 		},
 	}
 }
@@ -354,9 +354,14 @@ func updateReadyConditions(conds []metav1.Condition, phase v1alpha4.GlobalHubPha
 
 // dataRetention should at least be 1 month, otherwise it will deleted the current month partitions and records
 func updateRetentionConditions(mgh *v1alpha4.MulticlusterGlobalHub) (bool, []metav1.Condition) {
+	var tableRetentions string
 	months, err := utils.ParseRetentionMonth(mgh.Spec.DataLayerSpec.Postgres.Retention)
 	if err != nil {
 		err = fmt.Errorf("failed to parse the retention month, err:%v", err)
+	} else {
+		tableRetentions, err = config.GetTableRetentions(mgh.Spec.DataLayerSpec.Postgres)
+	}
+	if err != nil {
 		return config.NeedUpdateConditions(mgh.Status.Conditions, metav1.Condition{
 			Type:    config.CONDITION_TYPE_DATABASE,
 			Status:  config.CONDITION_STATUS_FALSE,
@@ -369,6 +374,9 @@ func updateRetentionConditions(mgh *v1alpha4.MulticlusterGlobalHub) (bool, []met
 		months = 1
 	}
 	msg := fmt.Sprintf("The data will be kept in the database for %d months.", months)
+	if tableRetentions != "" {
+		msg = fmt.Sprintf("%s The months of the tables are overridden: %s.", msg, tableRetentions)
+	}
 	return config.NeedUpdateConditions(mgh.Status.Conditions, metav1.Condition{
 		Type:    config.CONDITION_TYPE_DATABASE,
 		Status:  config.CONDITION_STATUS_TRUE,
@@ -4,6 +4,26 @@ type HubClusterInfo struct {
 	ConsoleURL string `json:"consoleURL"`
 	GrafanaURL string `json:"grafanaURL"`
 	ClusterId  string `json:"clusterId"`
+
+	// AgentVersion is the build version of the global hub agent running on the hub
+	AgentVersion     string `json:"agentVersion,omitempty"`
+	OpenShiftVersion string `json:"openshiftVersion,omitempty"`
+	ACMVersion       string `json:"acmVersion,omitempty"`
+	MCEVersion       string `json:"mceVersion,omitempty"`
+	// ManagedClusters is the number of the managed clusters by the available status: Available, Unavailable and
+	// Unknown
+	ManagedClusters map[string]int `json:"managedClusters,omitempty"`
+	// Addons are the ClusterManagementAddOns installed on the hub
+	Addons   []string     `json:"addons,omitempty"`
+	Capacity *HubCapacity `json:"capacity,omitempty"`
+}
+
+// HubCapacity is the total capacity of the nodes on the hub
+type HubCapacity struct {
+	Nodes  int    `json:"nodes"`
+	CPU    string `json:"cpu"`
+	Memory string `json:"memory"`
+	Pods   string `json:"pods"`
 }
 
 type HubClusterInfoBundle *HubClusterInfo
@@ -0,0 +1,5 @@
+package event
+
+import "github.com/stolostron/multicluster-global-hub/pkg/database/models"
+
+type SpecDriftEventBundle []models.SpecDriftEvent
@@ -0,0 +1,32 @@
+package grc
+
+// PolicyViolation is the details of the non-compliant templates of a policy on a cluster. The templates are empty if
+// the policy is compliant on the cluster or the replicated policy is deleted.
+type PolicyViolation struct {
+	PolicyID    string              `json:"policyId"`
+	ClusterID   string              `json:"clusterId"`
+	ClusterName string              `json:"clusterName"`
+	Templates   []TemplateViolation `json:"templates"`
+}
+
+// TemplateViolation is the ConfigurationPolicy/OperatorPolicy/Gatekeeper template which isn't compliant
+type TemplateViolation struct {
+	Name           string          `json:"name"`
+	Kind           string          `json:"kind"`
+	APIVersion     string          `json:"apiVersion"`
+	Compliance     string          `json:"compliance"`
+	Message        string          `json:"message"`
+	RelatedObjects []RelatedObject `json:"relatedObjects,omitempty"`
+}
+
+// RelatedObject is the object evaluated by the template and its compliance reason
+type RelatedObject struct {
+	Kind       string `json:"kind"`
+	APIVersion string `json:"apiVersion"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace,omitempty"`
+	Compliance string `json:"compliance"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+type PolicyViolationBundle []PolicyViolation
@@ -1,9 +1,38 @@
 package spec
 
-import "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// FullStateBundle carries all the objects of the spec table
+	FullStateBundle = "FullState"
+	// DeltaStateBundle carries only the objects created, updated or deleted since the base watermark
+	DeltaStateBundle = "DeltaState"
+)
+
+// BundleWatermark identifies the state of the spec table carried by the bundle. It's empty if the bundle is sent by
+// the manager which doesn't support the delta bundles, then the bundle is treated as a full state bundle.
+type BundleWatermark struct {
+	// Key identifies the sequence of the bundles, the agent tracks the applied watermark by it
+	Key        string `json:"key,omitempty"`
+	BundleType string `json:"bundleType,omitempty"`
+	// Watermark is the last update time of the spec table when the bundle is generated
+	Watermark *time.Time `json:"watermark,omitempty"`
+	// BaseWatermark is the watermark of the previous bundle with the same key, the delta bundle is generated since it
+	BaseWatermark *time.Time `json:"baseWatermark,omitempty"`
+}
+
+// IsDelta returns true if the bundle only carries the objects changed since the base watermark
+func (w *BundleWatermark) IsDelta() bool {
+	return w.BundleType == DeltaStateBundle
+}
 
 // Manger to Agent: GenericSpecBundle bundle received from transport containing Objects/DeletedObjects.
 type GenericSpecBundle struct {
+	BundleWatermark
 	Objects        []*unstructured.Unstructured `json:"objects"`
 	DeletedObjects []*unstructured.Unstructured `json:"deletedObjects"`
 }
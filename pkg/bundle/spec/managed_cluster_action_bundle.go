@@ -0,0 +1,31 @@
+package spec
+
+// ManagedClusterActionBundle is the lifecycle action dispatched to the managed hub which owns the cluster, the type
+// of the action is the type of the event.
+type ManagedClusterActionBundle struct {
+	ActionID    string `json:"actionId"`
+	ClusterName string `json:"clusterName"`
+	// Upgrade is the desired OpenShift version of the upgrade action
+	Upgrade *ClusterUpgrade `json:"upgrade,omitempty"`
+	// UserIdentity and UserGroups are the base64 encoded user and groups requesting the action, the agent impersonates
+	// them to apply the action on the managed hub
+	UserIdentity string `json:"userIdentity,omitempty"`
+	UserGroups   string `json:"userGroups,omitempty"`
+}
+
+// ClusterUpgrade is the desired update of the ClusterVersion on the managed cluster.
+type ClusterUpgrade struct {
+	Version string `json:"version"`
+	Image   string `json:"image,omitempty"`
+	Channel string `json:"channel,omitempty"`
+	Force   bool   `json:"force,omitempty"`
+}
+
+// ManagedClusterActionResult is the result of the lifecycle action, which is reported back by the agent of the
+// managed hub.
+type ManagedClusterActionResult struct {
+	ActionID    string `json:"actionId"`
+	ClusterName string `json:"clusterName"`
+	Succeeded   bool   `json:"succeeded"`
+	Message     string `json:"message,omitempty"`
+}
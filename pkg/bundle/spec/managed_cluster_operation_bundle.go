@@ -0,0 +1,57 @@
+package spec
+
+import clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+// ManagedClusterOperation is the bulk change applied to the managed clusters, the nil field isn't changed.
+type ManagedClusterOperation struct {
+	Labels      *MetadataChange `json:"labels,omitempty"`
+	Annotations *MetadataChange `json:"annotations,omitempty"`
+	// ClusterSet moves the clusters into the ManagedClusterSet, the empty value removes them from their set
+	ClusterSet       *string      `json:"clusterSet,omitempty"`
+	Taints           *TaintChange `json:"taints,omitempty"`
+	HubAcceptsClient *bool        `json:"hubAcceptsClient,omitempty"`
+}
+
+// IsEmpty returns true if the operation doesn't change anything
+func (o *ManagedClusterOperation) IsEmpty() bool {
+	return (o.Labels == nil || o.Labels.IsEmpty()) && (o.Annotations == nil || o.Annotations.IsEmpty()) &&
+		o.ClusterSet == nil && (o.Taints == nil || o.Taints.IsEmpty()) && o.HubAcceptsClient == nil
+}
+
+// MetadataChange holds the labels or annotations to be added/overwritten and the keys to be removed.
+type MetadataChange struct {
+	Add    map[string]string `json:"add,omitempty"`
+	Remove []string          `json:"remove,omitempty"`
+}
+
+func (c *MetadataChange) IsEmpty() bool {
+	return len(c.Add) == 0 && len(c.Remove) == 0
+}
+
+// TaintChange holds the taints to be added/overwritten and the taint keys to be removed.
+type TaintChange struct {
+	Add    []clusterv1.Taint `json:"add,omitempty"`
+	Remove []string          `json:"remove,omitempty"`
+}
+
+func (c *TaintChange) IsEmpty() bool {
+	return len(c.Add) == 0 && len(c.Remove) == 0
+}
+
+// ManagedClusterOperationBundle is the operation to be applied to the managed clusters of a managed hub.
+type ManagedClusterOperationBundle struct {
+	OperationID string                  `json:"operationId"`
+	Operation   ManagedClusterOperation `json:"operation"`
+	Clusters    []string                `json:"clusters"`
+}
+
+// ManagedClusterOperationResult is the result of applying the operation to a managed cluster, which is reported
+// back by the agent of the managed hub.
+type ManagedClusterOperationResult struct {
+	OperationID string `json:"operationId"`
+	ClusterName string `json:"clusterName"`
+	Succeeded   bool   `json:"succeeded"`
+	Message     string `json:"message,omitempty"`
+}
+
+type ManagedClusterOperationResultBundle []ManagedClusterOperationResult
@@ -109,6 +109,22 @@ const (
 	ManagedClusterManagedByAnnotation = "global-hub.open-cluster-management.io/managed-by"
 	// identify the resource is from the global hub cluster
 	OriginOwnerReferenceAnnotation = "global-hub.open-cluster-management.io/origin-ownerreference-uid"
+	// the database is restored by the GlobalHubRestore, the value is the uid of the restore. The operator re-applies
+	// the database schema and migrations once the value is changed
+	DatabaseRestoreAnnotation = "global-hub.open-cluster-management.io/database-restore"
+	// the global policy is promoted from the local policy by the LocalPolicyPromotion, the values are the managed hub,
+	// the namespaced name and the uid of the local policy, and the name of the promotion
+	PromotedFromHubAnnotation      = "global-hub.open-cluster-management.io/promoted-from-hub"
+	PromotedFromPolicyAnnotation   = "global-hub.open-cluster-management.io/promoted-from-policy"
+	PromotedFromPolicyIDAnnotation = "global-hub.open-cluster-management.io/promoted-from-policy-id"
+	PromotedByAnnotation           = "global-hub.open-cluster-management.io/promoted-by"
+	// the version of the global resource, it's the update time of the resource in the database. The agent skips
+	// applying the resource if the version on the managed hub isn't changed
+	SpecVersionAnnotation = "global-hub.open-cluster-management.io/spec-version"
+	// the base64 encoded user and comma separated groups requesting the resource, the agent impersonates them to
+	// apply the resource on the managed hub
+	UserIdentityAnnotation = "open-cluster-management.io/user-identity"
+	UserGroupsAnnotation   = "open-cluster-management.io/user-group"
 )
 
 // store all the finalizers
@@ -146,6 +162,15 @@ const (
 	// ManagedClustersLabelsMsgKey - managed clusters labels message key.
 	ManagedClustersLabelsMsgKey = "ManagedClustersLabels"
 
+	// ManagedClusterOperationMsgKey - the bulk operation on the managed clusters message key.
+	ManagedClusterOperationMsgKey = "ManagedClusterOperation"
+
+	// the lifecycle actions on the managed clusters message keys, each action is applied by its own syncer
+	ManagedClusterDetachMsgKey        = "ManagedClusterDetach"
+	ManagedClusterDenyClientMsgKey    = "ManagedClusterDenyClient"
+	ManagedClusterUpgradeMsgKey       = "ManagedClusterUpgrade"
+	KlusterletBootstrapRotationMsgKey = "KlusterletBootstrapRotation"
+
 	// GenericSpecMsgKey is the generic spec message key for the bundle
 	GenericSpecMsgKey = "Generic"
 )
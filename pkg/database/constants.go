@@ -73,6 +73,72 @@ const (
 	Pending ComplianceStatus = "pending"
 )
 
+// OperationState represents the state of a managed cluster in the bulk operation.
+type OperationState string
+
+// operation states.
+const (
+	// OperationPending the operation isn't sent to the managed hub yet.
+	OperationPending OperationState = "pending"
+	// OperationSent the operation is sent to the managed hub, waiting for the result from the agent.
+	OperationSent OperationState = "sent"
+	// OperationSucceeded the operation is applied to the managed cluster.
+	OperationSucceeded OperationState = "succeeded"
+	// OperationFailed the operation failed to be applied to the managed cluster.
+	OperationFailed OperationState = "failed"
+)
+
+// JobRunState represents the state of a run of the manager job.
+type JobRunState string
+
+// job run states.
+const (
+	// JobRunPending the run is triggered by the API, waiting for the scheduler to pick it up.
+	JobRunPending JobRunState = "pending"
+	// JobRunRunning the job is running.
+	JobRunRunning JobRunState = "running"
+	// JobRunSucceeded the job is finished successfully.
+	JobRunSucceeded JobRunState = "succeeded"
+	// JobRunFailed the job is finished with an error.
+	JobRunFailed JobRunState = "failed"
+	// JobRunSkipped the run is skipped since the previous run of the job is still running.
+	JobRunSkipped JobRunState = "skipped"
+)
+
+// JobRunTrigger represents how the run of the manager job is triggered.
+type JobRunTrigger string
+
+// job run triggers.
+const (
+	// JobRunScheduled the run is triggered by the cron schedule of the job.
+	JobRunScheduled JobRunTrigger = "schedule"
+	// JobRunLaunched the run is triggered by the launch jobs when the manager starts.
+	JobRunLaunched JobRunTrigger = "launch"
+	// JobRunManual the run is triggered by the API.
+	JobRunManual JobRunTrigger = "manual"
+)
+
+// ClusterHistoryField represents the field of the managed cluster recorded in the history.
+type ClusterHistoryField string
+
+// managed cluster history fields.
+const (
+	// ClusterHistoryLabel the label of the cluster, the key is the label key.
+	ClusterHistoryLabel ClusterHistoryField = "label"
+	// ClusterHistoryVersion the kubernetes version of the cluster.
+	ClusterHistoryVersion ClusterHistoryField = "version"
+	// ClusterHistoryCondition the status of the condition, the key is the condition type.
+	ClusterHistoryCondition ClusterHistoryField = "condition"
+	// ClusterHistoryClaim the cluster claim, the key is the claim name.
+	ClusterHistoryClaim ClusterHistoryField = "claim"
+	// ClusterHistoryHub the managed hub which owns the cluster, it's empty once the cluster is deleted.
+	ClusterHistoryHub ClusterHistoryField = "hub"
+)
+
+// SyncHealthTransportType is the event type of the sync health row which keeps the consumer lag of the managed hub
+// topic.
+const SyncHealthTransportType = "transport"
+
 // unique db types.
 const (
 	// UUID unique type.
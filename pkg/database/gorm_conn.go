@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"net/url"
 	"sync"
+	"time"
 
 	_ "github.com/lib/pq"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -21,8 +23,6 @@ import (
 const PostgresDialect = "postgres"
 
 var (
-	IsBackupEnabled bool
-
 	gormDB *gorm.DB
 	mutex  sync.Mutex
 	// Direct database connection.
@@ -33,8 +33,27 @@ var (
 	log      = ctrl.Log.WithName("database-controller")
 	lockConn *sql.Conn
 	ctx      = context.Background()
+
+	lockState = newAdvisoryLockState()
 )
 
+// advisoryLockState tracks the shared advisory lock holders of the connections and the backup quiesce
+type advisoryLockState struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	holders map[*sql.Conn]int
+	// pending is the connections which are acquiring or releasing the shared lock from the database
+	pending   map[*sql.Conn]bool
+	quiescing bool
+	quiesced  bool
+}
+
+func newAdvisoryLockState() *advisoryLockState {
+	state := &advisoryLockState{holders: map[*sql.Conn]int{}, pending: map[*sql.Conn]bool{}}
+	state.cond = sync.NewCond(&state.mu)
+	return state
+}
+
 type DatabaseConfig struct {
 	URL        string
 	Dialect    string
@@ -110,7 +129,8 @@ func GetSqlDb() *sql.DB {
 // The advisory lock should use a same connection in a connection pool.
 // Detail: https://engineering.qubecinema.com/2019/08/26/unlocking-advisory-locks.html
 func GetConn() *sql.Conn {
-	if !IsBackupEnabled {
+	if sqlDB == nil {
+		log.Error(nil, "sqlDb connection is not initialized")
 		return nil
 	}
 	var err error
@@ -124,22 +144,71 @@ func GetConn() *sql.Conn {
 	return lockConn
 }
 
+// Lock takes the shared advisory lock, so the status workers and cron tasks don't block each other, they are only
+// blocked by the exclusive lock of the backup or restore quiesce. The shared lock is always taken, since the database
+// can be quiesced by the volume backup, the scheduled backup or the restore. It's held once per connection and
+// released when the last holder of the connection unlocks it.
 func Lock(lockConn *sql.Conn) error {
-	if !IsBackupEnabled {
+	if lockConn == nil {
+		return fmt.Errorf("the connection of the advisory lock is not initialized")
+	}
+	lockState.mu.Lock()
+	// don't take the new shared lock when the backup is quiescing, otherwise the backup might starve. The other
+	// holders of the connection wait for the one which is acquiring or releasing the shared lock from the database.
+	for lockState.quiescing || lockState.pending[lockConn] {
+		lockState.cond.Wait()
+	}
+	if lockState.holders[lockConn] > 0 {
+		lockState.holders[lockConn]++
+		lockState.mu.Unlock()
 		return nil
 	}
-	log.V(2).Info("Add db lock")
-	defer log.V(2).Info("db locked")
-	_, err := lockConn.ExecContext(ctx, "select pg_advisory_lock($1)", constants.LockId)
-	return err
+	lockState.pending[lockConn] = true
+	lockState.mu.Unlock()
+
+	// the blocking call doesn't hold the mutex, so the quiesce of this process isn't blocked by it
+	log.V(2).Info("Add db shared lock")
+	_, err := lockConn.ExecContext(ctx, "select pg_advisory_lock_shared($1)", constants.LockId)
+
+	lockState.mu.Lock()
+	defer lockState.mu.Unlock()
+	delete(lockState.pending, lockConn)
+	lockState.cond.Broadcast()
+	if err != nil {
+		return err
+	}
+	lockState.holders[lockConn]++
+	return nil
 }
 
 func Unlock(lockConn *sql.Conn) {
-	if !IsBackupEnabled {
+	if lockConn == nil {
+		return
+	}
+	lockState.mu.Lock()
+	if lockState.holders[lockConn] == 0 {
+		lockState.mu.Unlock()
 		return
 	}
-	log.V(2).Info("unlock db")
-	defer log.V(2).Info("db unlocked")
+	lockState.holders[lockConn]--
+	if lockState.holders[lockConn] > 0 {
+		lockState.mu.Unlock()
+		return
+	}
+	delete(lockState.holders, lockConn)
+	lockState.pending[lockConn] = true
+	lockState.mu.Unlock()
+
+	// the database calls don't hold the mutex, the new holders of the connection and the quiesce wait until the
+	// shared lock is released
+	defer func() {
+		lockState.mu.Lock()
+		delete(lockState.pending, lockConn)
+		lockState.cond.Broadcast()
+		lockState.mu.Unlock()
+	}()
+
+	log.V(2).Info("unlock db shared lock")
 	err := retry.OnError(retry.DefaultRetry, func(err error) bool {
 		if err != nil {
 			klog.V(2).Infof("unlock failed, retry unlock. err: %s", err)
@@ -148,7 +217,7 @@ func Unlock(lockConn *sql.Conn) {
 		return false
 	},
 		func() error {
-			_, err := lockConn.ExecContext(ctx, "select pg_advisory_unlock($1)", constants.LockId)
+			_, err := lockConn.ExecContext(ctx, "select pg_advisory_unlock_shared($1)", constants.LockId)
 			return err
 		})
 	if err != nil {
@@ -156,6 +225,62 @@ func Unlock(lockConn *sql.Conn) {
 	}
 }
 
+// Quiesce takes the exclusive advisory lock for the backup. It stops granting the new shared locks in this process,
+// and waits at most the timeout for the existing shared locks, including the ones of the other processes, to be
+// released. The database is quiesced until Unquiesce is called.
+func Quiesce(ctx context.Context, lockConn *sql.Conn, timeout time.Duration) error {
+	lockState.mu.Lock()
+	lockState.quiescing = true
+	lockState.mu.Unlock()
+
+	err := wait.PollUntilContextTimeout(ctx, time.Second, timeout, true, func(ctx context.Context) (bool, error) {
+		lockState.mu.Lock()
+		defer lockState.mu.Unlock()
+		if len(lockState.holders) > 0 || len(lockState.pending) > 0 {
+			return false, nil
+		}
+		locked := false
+		if err := lockConn.QueryRowContext(ctx, "select pg_try_advisory_lock($1)",
+			constants.LockId).Scan(&locked); err != nil {
+			log.Error(err, "failed to try the db exclusive lock")
+			return false, nil
+		}
+		lockState.quiesced = locked
+		return locked, nil
+	})
+	if err != nil {
+		lockState.mu.Lock()
+		lockState.quiescing = false
+		lockState.cond.Broadcast()
+		lockState.mu.Unlock()
+		return fmt.Errorf("failed to quiesce the database in %s: %w", timeout, err)
+	}
+	log.Info("database is quiesced")
+	return nil
+}
+
+// Unquiesce releases the exclusive advisory lock of the backup, and resumes granting the shared locks.
+func Unquiesce(lockConn *sql.Conn) {
+	lockState.mu.Lock()
+	defer lockState.mu.Unlock()
+	if lockState.quiesced {
+		if _, err := lockConn.ExecContext(ctx, "select pg_advisory_unlock($1)", constants.LockId); err != nil {
+			log.Error(err, "failed to unlock the db exclusive lock")
+		}
+	}
+	lockState.quiescing = false
+	lockState.quiesced = false
+	lockState.cond.Broadcast()
+	log.Info("database is unquiesced")
+}
+
+// IsQuiesced returns whether the database is locked exclusively by the backup in this process
+func IsQuiesced() bool {
+	lockState.mu.Lock()
+	defer lockState.mu.Unlock()
+	return lockState.quiesced
+}
+
 // Close the sql.DB connection
 func CloseGorm(sqlConn *sql.DB) {
 	if sqlConn != nil {
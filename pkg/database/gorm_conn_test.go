@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	_ "github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
@@ -48,7 +49,6 @@ func TestConnectionPool(t *testing.T) {
 }
 
 func TestTwoInstanceCanGetLockWhenReleased(t *testing.T) {
-	database.IsBackupEnabled = true
 	err := database.InitGormInstance(databaseConfig)
 	assert.Nil(t, err)
 
@@ -68,5 +68,48 @@ func TestTwoInstanceCanGetLockWhenReleased(t *testing.T) {
 	err = database.Lock(newConn)
 	assert.Nil(t, err)
 	database.Unlock(newConn)
-	database.IsBackupEnabled = false
+}
+
+func TestSharedLockAndQuiesce(t *testing.T) {
+	ctx := context.Background()
+
+	_, sqlDb, err := database.NewGormConn(databaseConfig)
+	assert.Nil(t, err)
+	workerConn := database.GetConn()
+	otherConn, err := sqlDb.Conn(ctx)
+	assert.Nil(t, err)
+	backupConn, err := sqlDb.Conn(ctx)
+	assert.Nil(t, err)
+
+	// the shared locks don't block each other
+	assert.Nil(t, database.Lock(workerConn))
+	assert.Nil(t, database.Lock(workerConn))
+	assert.Nil(t, database.Lock(otherConn))
+
+	// the quiesce is bounded by the timeout when the shared locks aren't released
+	err = database.Quiesce(ctx, backupConn, 2*time.Second)
+	assert.NotNil(t, err)
+	assert.False(t, database.IsQuiesced())
+
+	database.Unlock(workerConn)
+	database.Unlock(workerConn)
+	database.Unlock(otherConn)
+	assert.Nil(t, database.Quiesce(ctx, backupConn, 5*time.Second))
+	assert.True(t, database.IsQuiesced())
+
+	// the new shared lock waits until the database is unquiesced
+	locked := make(chan struct{})
+	go func() {
+		assert.Nil(t, database.Lock(workerConn))
+		close(locked)
+	}()
+	select {
+	case <-locked:
+		t.Fatal("the shared lock shouldn't be granted when the database is quiesced")
+	case <-time.After(time.Second):
+	}
+	database.Unquiesce(backupConn)
+	<-locked
+	assert.False(t, database.IsQuiesced())
+	database.Unlock(workerConn)
 }
@@ -45,6 +45,11 @@ type DataRetentionJobLog struct {
 	MaxPartition string    `gorm:"column:max_partition"`
 	MinDeletion  time.Time `gorm:"column:min_deletion"`
 	Error        string    `gorm:"column:error"`
+	// the retention report of the table
+	RetentionMonth    int    `gorm:"column:retention_month"`
+	DeletedPartitions string `gorm:"column:deleted_partitions"`
+	Archives          string `gorm:"column:archives"`
+	ArchivedRows      int64  `gorm:"column:archived_rows"`
 }
 
 func (DataRetentionJobLog) TableName() string {
@@ -68,3 +73,20 @@ type ManagedClusterEvent struct {
 func (ManagedClusterEvent) TableName() string {
 	return "event.managed_clusters"
 }
+
+// SpecDriftEvent records the global resource which is changed or deleted on the managed hub by others than the agent
+type SpecDriftEvent struct {
+	LeafHubName     string    `gorm:"column:leaf_hub_name;type:varchar(254);not null" json:"leafHubName"`
+	ObjectID        string    `gorm:"column:object_id;type:uuid;not null" json:"objectId"`
+	ObjectKind      string    `gorm:"column:object_kind;type:varchar(254);not null" json:"objectKind"`
+	ObjectNamespace string    `gorm:"column:object_namespace;type:text" json:"objectNamespace"`
+	ObjectName      string    `gorm:"column:object_name;type:text;not null" json:"objectName"`
+	Reason          string    `gorm:"column:reason;type:text;not null" json:"reason"`
+	Message         string    `gorm:"column:message;type:text" json:"message"`
+	Reverted        bool      `gorm:"column:reverted;not null" json:"reverted"`
+	CreatedAt       time.Time `gorm:"column:created_at;default:now();not null" json:"createdAt"`
+}
+
+func (SpecDriftEvent) TableName() string {
+	return "event.spec_drifts"
+}
@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
 
 type LocalComplianceJobLog struct {
 	Name     string    `gorm:"column:name"`
@@ -29,3 +33,53 @@ type LocalComplianceHistory struct {
 func (LocalComplianceHistory) TableName() string {
 	return "history.local_compliance"
 }
+
+// LocalComplianceRollup is the daily compliance counts of the policy on the managed hub
+type LocalComplianceRollup struct {
+	PolicyID       string    `gorm:"column:policy_id;primaryKey"`
+	LeafHubName    string    `gorm:"column:leaf_hub_name;primaryKey"`
+	ComplianceDate time.Time `gorm:"type:date;column:compliance_date;primaryKey"`
+	Compliant      int       `gorm:"column:compliant"`
+	NonCompliant   int       `gorm:"column:non_compliant"`
+	Pending        int       `gorm:"column:pending"`
+	Unknown        int       `gorm:"column:unknown"`
+	UpdatedAt      time.Time `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (LocalComplianceRollup) TableName() string {
+	return "history.local_compliance_rollup"
+}
+
+// JobRun is a run of the manager job, the duration is in milliseconds
+type JobRun struct {
+	ID         string                 `gorm:"column:id;primaryKey"`
+	JobName    string                 `gorm:"column:job_name"`
+	Trigger    database.JobRunTrigger `gorm:"column:trigger"`
+	State      database.JobRunState   `gorm:"column:state"`
+	CreatedAt  time.Time              `gorm:"column:created_at;autoCreateTime:true"`
+	StartAt    *time.Time             `gorm:"column:start_at"`
+	EndAt      *time.Time             `gorm:"column:end_at"`
+	DurationMs int64                  `gorm:"column:duration_ms"`
+	Error      string                 `gorm:"column:error"`
+}
+
+func (JobRun) TableName() string {
+	return "history.job_runs"
+}
+
+// ManagedClusterHistory is a field level change of the managed cluster, the old value is nil if the field is added
+// and the new value is nil if the field is removed
+type ManagedClusterHistory struct {
+	ClusterID   string                       `gorm:"column:cluster_id" json:"clusterId"`
+	ClusterName string                       `gorm:"column:cluster_name" json:"clusterName"`
+	LeafHubName string                       `gorm:"column:leaf_hub_name" json:"leafHubName"`
+	Field       database.ClusterHistoryField `gorm:"column:field" json:"field"`
+	Key         string                       `gorm:"column:key" json:"key,omitempty"`
+	OldValue    *string                      `gorm:"column:old_value" json:"oldValue"`
+	NewValue    *string                      `gorm:"column:new_value" json:"newValue"`
+	CreatedAt   time.Time                    `gorm:"column:created_at;default:now()" json:"createdAt"`
+}
+
+func (ManagedClusterHistory) TableName() string {
+	return "history.managed_clusters"
+}
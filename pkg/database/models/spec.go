@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"gorm.io/datatypes"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
 )
 
 type ManagedClusterLabel struct {
@@ -22,6 +24,50 @@ func (ManagedClusterLabel) TableName() string {
 	return "spec.managed_clusters_labels"
 }
 
+type ManagedClusterOperation struct {
+	ID        string         `gorm:"column:id;primaryKey"`
+	Payload   datatypes.JSON `gorm:"column:payload;type:jsonb"`
+	CreatedAt time.Time      `gorm:"column:created_at;autoCreateTime:true"`
+}
+
+func (ManagedClusterOperation) TableName() string {
+	return "spec.managed_cluster_operations"
+}
+
+// ManagedClusterAction is the lifecycle action of the GlobalClusterAction, which is dispatched to the managed hub
+// owning the cluster. The ID is the uid of the GlobalClusterAction.
+type ManagedClusterAction struct {
+	ID          string                  `gorm:"column:id;primaryKey"`
+	Namespace   string                  `gorm:"column:namespace;not null"`
+	Name        string                  `gorm:"column:name;not null"`
+	LeafHubName string                  `gorm:"column:leaf_hub_name;not null"`
+	ClusterName string                  `gorm:"column:cluster_name;not null"`
+	Action      string                  `gorm:"column:action;not null"`
+	Payload     datatypes.JSON          `gorm:"column:payload;type:jsonb"`
+	State       database.OperationState `gorm:"column:state;not null"`
+	Message     string                  `gorm:"column:message"`
+	CreatedAt   time.Time               `gorm:"column:created_at;autoCreateTime:true"`
+	UpdatedAt   time.Time               `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (ManagedClusterAction) TableName() string {
+	return "spec.managed_cluster_actions"
+}
+
+type SavedSearch struct {
+	ID          string    `gorm:"column:id;primaryKey"`
+	Name        string    `gorm:"column:name"`
+	Owner       string    `gorm:"column:owner"`
+	Description string    `gorm:"column:description"`
+	Query       string    `gorm:"column:query"`
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime:true"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (SavedSearch) TableName() string {
+	return "spec.saved_searches"
+}
+
 // CREATE TABLE IF NOT EXISTS spec.policies (
 // 	id uuid PRIMARY KEY,
 // 	payload jsonb NOT NULL,
@@ -98,3 +98,55 @@ type SubscriptionReport struct {
 func (SubscriptionReport) TableName() string {
 	return "status.subscription_reports"
 }
+
+type PolicyViolation struct {
+	PolicyID           string                    `gorm:"column:policy_id;primaryKey"`
+	ClusterName        string                    `gorm:"column:cluster_name;primaryKey"`
+	LeafHubName        string                    `gorm:"column:leaf_hub_name;primaryKey"`
+	TemplateName       string                    `gorm:"column:template_name;primaryKey"`
+	ClusterID          string                    `gorm:"column:cluster_id"`
+	TemplateKind       string                    `gorm:"column:template_kind;not null"`
+	TemplateAPIVersion string                    `gorm:"column:template_api_version;not null"`
+	Compliance         database.ComplianceStatus `gorm:"column:compliance;not null"`
+	Message            string                    `gorm:"column:message"`
+	RelatedObjects     datatypes.JSON            `gorm:"column:related_objects;type:jsonb"`
+	UpdatedAt          time.Time                 `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (PolicyViolation) TableName() string {
+	return "status.policy_violations"
+}
+
+type ManagedClusterOperationResult struct {
+	OperationID string                  `gorm:"column:operation_id;primaryKey"`
+	ClusterID   string                  `gorm:"column:cluster_id;primaryKey"`
+	ClusterName string                  `gorm:"column:cluster_name;not null"`
+	LeafHubName string                  `gorm:"column:leaf_hub_name;not null"`
+	State       database.OperationState `gorm:"column:state;not null"`
+	Message     string                  `gorm:"column:message"`
+	UpdatedAt   time.Time               `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (ManagedClusterOperationResult) TableName() string {
+	return "status.managed_cluster_operation_results"
+}
+
+type SyncHealth struct {
+	LeafHubName          string     `gorm:"column:leaf_hub_name;primaryKey"`
+	EventType            string     `gorm:"column:event_type;primaryKey"`
+	LastReceivedAt       *time.Time `gorm:"column:last_received_at"`
+	LastProcessedAt      *time.Time `gorm:"column:last_processed_at"`
+	LastProcessedVersion string     `gorm:"column:last_processed_version"`
+	ProcessingLatencyMs  int64      `gorm:"column:processing_latency_ms"`
+	Received             int64      `gorm:"column:received"`
+	Processed            int64      `gorm:"column:processed"`
+	Failures             int64      `gorm:"column:failures"`
+	LastError            string     `gorm:"column:last_error"`
+	Topic                string     `gorm:"column:topic"`
+	ConsumerLag          int64      `gorm:"column:consumer_lag"`
+	UpdatedAt            time.Time  `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (SyncHealth) TableName() string {
+	return "status.sync_health"
+}
@@ -12,6 +12,13 @@ const (
 	SubscriptionReportType  EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.subscription.report"
 	SubscriptionStatusType  EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.subscription.status"
 
+	// used to report the results of the bulk managed cluster operations
+	//nolint: go:S103
+	ManagedClusterOperationResultType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.managedcluster.operationresult"
+	// used to report the results of the lifecycle actions on the managed clusters
+	//nolint: go:S103
+	ManagedClusterActionResultType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.managedcluster.actionresult"
+
 	// used by the local resources
 	//nolint: go:S103
 	LocalComplianceType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.policy.localcompliance"
@@ -26,6 +33,8 @@ const (
 
 	DeltaComplianceType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.policy.deltacompliance"
 	MiniComplianceType  EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.policy.minicompliance"
+	// used to send the details of the non-compliant templates of the replicated policies
+	PolicyViolationType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.policy.violation"
 
 	// used to send kube events
 	//nolint: go:S103
@@ -33,6 +42,8 @@ const (
 	//nolint: go:S103
 	LocalRootPolicyEventType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.event.localrootpolicy"
 	ManagedClusterEventType  EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.event.managedcluster"
+	// used to report the global resources which are drifted on the managed hubs
+	SpecDriftEventType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.event.specdrift"
 
 	PlacementDecisionType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.placementdecision"
 	//nolint: go:S103
@@ -42,4 +53,7 @@ const (
 
 	// Used to send security alerts:
 	SecurityAlertCountsType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.security.alertcounts"
+
+	// Used to send the alerts of the global hub notifier to the kafka sink
+	GlobalHubAlertType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.notifier.alert"
 )
@@ -0,0 +1,93 @@
+package statistics
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+)
+
+// HubSyncHealth is the sync health of an event type from a managed hub.
+type HubSyncHealth struct {
+	LeafHubName          string
+	EventType            string
+	LastReceivedAt       time.Time
+	LastProcessedAt      time.Time
+	LastProcessedVersion string
+	// ProcessingLatency is the duration from the cloudevent time to the database commit of the last processed event
+	ProcessingLatency time.Duration
+	Received          int64
+	Processed         int64
+	Failures          int64
+	LastError         string
+}
+
+// hubHealthTracker tracks the sync health per (managed hub, event type), it's updated by the transport dispatcher
+// and the database workers concurrently.
+type hubHealthTracker struct {
+	mutex  sync.Mutex
+	health map[string]*HubSyncHealth
+}
+
+func newHubHealthTracker() *hubHealthTracker {
+	return &hubHealthTracker{health: map[string]*HubSyncHealth{}}
+}
+
+func (t *hubHealthTracker) get(evt *cloudevents.Event) *HubSyncHealth {
+	key := fmt.Sprintf("%s/%s", evt.Source(), evt.Type())
+	health, ok := t.health[key]
+	if !ok {
+		health = &HubSyncHealth{LeafHubName: evt.Source(), EventType: evt.Type()}
+		t.health[key] = health
+	}
+	return health
+}
+
+func (t *hubHealthTracker) received(evt *cloudevents.Event) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	health := t.get(evt)
+	health.Received++
+	health.LastReceivedAt = time.Now()
+}
+
+func (t *hubHealthTracker) processed(evt *cloudevents.Event, err error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	health := t.get(evt)
+	if err != nil {
+		health.Failures++
+		health.LastError = err.Error()
+		return
+	}
+	now := time.Now()
+	health.Processed++
+	health.LastProcessedAt = now
+	if ver, ok := evt.Extensions()[version.ExtVersion].(string); ok {
+		health.LastProcessedVersion = ver
+	}
+	if !evt.Time().IsZero() {
+		health.ProcessingLatency = now.Sub(evt.Time())
+	}
+}
+
+// snapshot returns a copy of the sync health ordered by the hub and the event type
+func (t *hubHealthTracker) snapshot() []HubSyncHealth {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	healths := make([]HubSyncHealth, 0, len(t.health))
+	for _, health := range t.health {
+		healths = append(healths, *health)
+	}
+	sort.Slice(healths, func(i, j int) bool {
+		if healths[i].LeafHubName != healths[j].LeafHubName {
+			return healths[i].LeafHubName < healths[j].LeafHubName
+		}
+		return healths[i].EventType < healths[j].EventType
+	})
+	return healths
+}
@@ -0,0 +1,59 @@
+package statistics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+)
+
+func newEvent(source, eventType, ver string, eventTime time.Time) *cloudevents.Event {
+	evt := cloudevents.NewEvent()
+	evt.SetSource(source)
+	evt.SetType(eventType)
+	evt.SetTime(eventTime)
+	evt.SetExtension(version.ExtVersion, ver)
+	return &evt
+}
+
+func TestHubSyncHealth(t *testing.T) {
+	stats := NewStatistics(&StatisticsConfig{})
+	stats.Register("policy")
+
+	eventTime := time.Now().Add(-2 * time.Second)
+	stats.ReceivedEvent(newEvent("hub2", "policy", "0.1", eventTime))
+	stats.ReceivedEvent(newEvent("hub1", "policy", "0.1", eventTime))
+	stats.ReceivedEvent(newEvent("hub1", "policy", "0.2", eventTime))
+	// the unregistered event isn't tracked
+	stats.ReceivedEvent(newEvent("hub1", "cluster", "0.1", eventTime))
+
+	stats.AddDatabaseMetrics(newEvent("hub1", "policy", "0.2", eventTime), time.Millisecond, nil)
+	stats.AddDatabaseMetrics(newEvent("hub2", "policy", "0.1", eventTime), time.Millisecond,
+		errors.New("connection refused"))
+
+	healths := stats.HubSyncHealth()
+	assert.Len(t, healths, 2)
+
+	hub1 := healths[0]
+	assert.Equal(t, "hub1", hub1.LeafHubName)
+	assert.Equal(t, "policy", hub1.EventType)
+	assert.Equal(t, int64(2), hub1.Received)
+	assert.Equal(t, int64(1), hub1.Processed)
+	assert.Equal(t, int64(0), hub1.Failures)
+	assert.Equal(t, "0.2", hub1.LastProcessedVersion)
+	assert.False(t, hub1.LastReceivedAt.IsZero())
+	assert.False(t, hub1.LastProcessedAt.IsZero())
+	assert.GreaterOrEqual(t, hub1.ProcessingLatency, 2*time.Second)
+
+	hub2 := healths[1]
+	assert.Equal(t, "hub2", hub2.LeafHubName)
+	assert.Equal(t, int64(1), hub2.Received)
+	assert.Equal(t, int64(0), hub2.Processed)
+	assert.Equal(t, int64(1), hub2.Failures)
+	assert.Equal(t, "connection refused", hub2.LastError)
+	assert.True(t, hub2.LastProcessedAt.IsZero())
+}
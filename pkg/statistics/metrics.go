@@ -0,0 +1,90 @@
+package statistics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// the received, processed and failed events are exported per managed hub by the sync health collector, the metrics
+// here cover the remaining stages of the status pipeline
+var (
+	handlerDurationHistogram = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "multicluster_global_hub_status_handler_duration_seconds",
+			Help:    "The duration of the database worker handling the status event, including the retries.",
+			Buckets: prometheus.ExponentialBuckets(0.005, 2, 14),
+		},
+		[]string{"hub", "type"},
+	)
+
+	staleEventsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "multicluster_global_hub_status_stale_total",
+			Help: "The number of the status events skipped by the conflation since they aren't newer than the " +
+				"processed ones.",
+		},
+		[]string{"hub", "type"},
+	)
+
+	readyQueueGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "multicluster_global_hub_status_ready_queue_depth",
+			Help: "The number of the conflation units and the delta event jobs waiting for a database worker.",
+		},
+	)
+
+	conflationUnitsGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "multicluster_global_hub_status_conflation_units",
+			Help: "The number of the conflation units, one per managed hub.",
+		},
+	)
+
+	dbWorkersGaugeVec = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "multicluster_global_hub_status_db_workers",
+			Help: "The number of the database workers by the state. state = available | busy.",
+		},
+		[]string{"state"},
+	)
+
+	assembledMessageSizeHistogram = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "multicluster_global_hub_transport_assembled_message_size_bytes",
+			Help:    "The size of the transport messages assembled from the chunks.",
+			Buckets: prometheus.ExponentialBuckets(1024, 4, 10),
+		},
+	)
+
+	assembledMessageChunksHistogram = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "multicluster_global_hub_transport_assembled_message_chunks",
+			Help:    "The number of the chunks of the transport messages assembled from the chunks.",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 8),
+		},
+	)
+)
+
+// RegisterMetrics registers the status pipeline metrics with the controller-runtime registry
+func RegisterMetrics() error {
+	for _, collector := range []prometheus.Collector{
+		handlerDurationHistogram,
+		staleEventsCounter,
+		readyQueueGauge,
+		conflationUnitsGauge,
+		dbWorkersGaugeVec,
+		assembledMessageSizeHistogram,
+		assembledMessageChunksHistogram,
+	} {
+		if err := metrics.Registry.Register(collector); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AssembledMessage observes the size and the number of chunks of the message assembled by the transport consumer.
+func AssembledMessage(size int, chunks int) {
+	assembledMessageSizeHistogram.Observe(float64(size))
+	assembledMessageChunksHistogram.Observe(float64(chunks))
+}
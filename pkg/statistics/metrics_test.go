@@ -0,0 +1,41 @@
+package statistics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatisticsMetrics(t *testing.T) {
+	stats := NewStatistics(&StatisticsConfig{})
+	stats.Register("policy")
+
+	stats.StaleEvent(newEvent("hub1", "policy", "0.1", time.Now()))
+	stats.StaleEvent(newEvent("hub1", "policy", "0.1", time.Now()))
+	// the unregistered event isn't counted
+	stats.StaleEvent(newEvent("hub1", "cluster", "0.1", time.Now()))
+	assert.Equal(t, float64(2), testutil.ToFloat64(staleEventsCounter.WithLabelValues("hub1", "policy")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(staleEventsCounter.WithLabelValues("hub1", "cluster")))
+
+	// the metrics are shared by the tests in the package
+	series := testutil.CollectAndCount(handlerDurationHistogram)
+	stats.AddDatabaseMetrics(newEvent("hub3", "policy", "0.2", time.Now()), 20*time.Millisecond, nil)
+	assert.Equal(t, series+1, testutil.CollectAndCount(handlerDurationHistogram))
+
+	stats.SetNumberOfDBWorkers(5)
+	stats.SetNumberOfAvailableDBWorkers(3)
+	assert.Equal(t, float64(3), testutil.ToFloat64(dbWorkersGaugeVec.WithLabelValues("available")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(dbWorkersGaugeVec.WithLabelValues("busy")))
+
+	stats.SetConflationReadyQueueSize(7)
+	assert.Equal(t, float64(7), testutil.ToFloat64(readyQueueGauge))
+
+	stats.IncrementNumberOfConflations()
+	stats.IncrementNumberOfConflations()
+	assert.Equal(t, float64(2), testutil.ToFloat64(conflationUnitsGauge))
+
+	AssembledMessage(4096, 3)
+	assert.Equal(t, 1, testutil.CollectAndCount(assembledMessageSizeHistogram))
+}
@@ -21,6 +21,7 @@ func NewStatistics(statisticsConfig *StatisticsConfig) *Statistics {
 	return &Statistics{
 		log:          ctrl.Log.WithName("statistics"),
 		eventMetrics: make(map[string]*eventMetrics),
+		hubHealth:    newHubHealthTracker(),
 		logInterval:  statisticsConfig.LogInterval,
 	}
 }
@@ -28,10 +29,12 @@ func NewStatistics(statisticsConfig *StatisticsConfig) *Statistics {
 // Statistics aggregates different statistics.
 type Statistics struct {
 	log                      logr.Logger
+	numOfDBWorkers           int
 	numOfAvailableDBWorkers  int
 	conflationReadyQueueSize int
 	numOfConflationUnits     int
 	eventMetrics             map[string]*eventMetrics
+	hubHealth                *hubHealthTracker
 	logInterval              string
 	mutex                    sync.Mutex
 }
@@ -46,16 +49,44 @@ func (s *Statistics) ReceivedEvent(evt *cloudevents.Event) {
 		return
 	}
 	metrics.totalReceived++
+	s.hubHealth.received(evt)
+}
+
+// StaleEvent counts the event skipped by the conflation unit since it isn't newer than the processed one.
+func (s *Statistics) StaleEvent(evt *cloudevents.Event) {
+	if _, ok := s.eventMetrics[evt.Type()]; !ok {
+		return
+	}
+	staleEventsCounter.WithLabelValues(evt.Source(), evt.Type()).Inc()
+}
+
+// SetNumberOfDBWorkers sets number of db workers, the busy workers are the ones which aren't available.
+func (s *Statistics) SetNumberOfDBWorkers(numOf int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.numOfDBWorkers = numOf
+	s.setDBWorkersGauge()
 }
 
 // SetNumberOfAvailableDBWorkers sets number of available db workers.
 func (s *Statistics) SetNumberOfAvailableDBWorkers(numOf int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 	s.numOfAvailableDBWorkers = numOf
+	s.setDBWorkersGauge()
+}
+
+func (s *Statistics) setDBWorkersGauge() {
+	dbWorkersGaugeVec.WithLabelValues("available").Set(float64(s.numOfAvailableDBWorkers))
+	if busy := s.numOfDBWorkers - s.numOfAvailableDBWorkers; busy >= 0 {
+		dbWorkersGaugeVec.WithLabelValues("busy").Set(float64(busy))
+	}
 }
 
 // SetConflationReadyQueueSize sets conflation ready queue size.
 func (s *Statistics) SetConflationReadyQueueSize(size int) {
 	s.conflationReadyQueueSize = size
+	readyQueueGauge.Set(float64(size))
 }
 
 // StartConflationUnitMetrics starts conflation unit metrics of the specific event type.
@@ -81,6 +112,7 @@ func (s *Statistics) IncrementNumberOfConflations() {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 	s.numOfConflationUnits++
+	conflationUnitsGauge.Set(float64(s.numOfConflationUnits))
 }
 
 // AddDatabaseMetrics adds database metrics of the specific event type.
@@ -90,6 +122,13 @@ func (s *Statistics) AddDatabaseMetrics(evt *cloudevents.Event, duration time.Du
 		return
 	}
 	eventMetrics.database.add(duration, err)
+	s.hubHealth.processed(evt, err)
+	handlerDurationHistogram.WithLabelValues(evt.Source(), evt.Type()).Observe(duration.Seconds())
+}
+
+// HubSyncHealth returns the sync health of the registered event types per managed hub.
+func (s *Statistics) HubSyncHealth() []HubSyncHealth {
+	return s.hubHealth.snapshot()
 }
 
 // Start starts the statistics.
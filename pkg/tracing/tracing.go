@@ -0,0 +1,139 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/extensions"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+)
+
+const tracerName = "github.com/stolostron/multicluster-global-hub"
+
+var (
+	propagator = propagation.TraceContext{}
+
+	HubAttribute       = attribute.Key("globalhub.hub")
+	EventTypeAttribute = attribute.Key("globalhub.event.type")
+	VersionAttribute   = attribute.Key("globalhub.event.version")
+)
+
+// TracingConfig is the configuration of exporting the spans to the OTLP/HTTP collector, the tracing is disabled if
+// the endpoint is empty.
+type TracingConfig struct {
+	Endpoint      string
+	Insecure      bool
+	SamplingRatio float64
+}
+
+// InitTracing sets the global tracer provider, which exports the spans of the service to the collector. The spans are
+// sampled by the ratio unless their parent, e.g. the span on the other side of the transport, is sampled. It returns
+// the function to flush the remaining spans.
+func InitTracing(ctx context.Context, serviceName string, config *TracingConfig) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagator)
+	if config == nil || config.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	if config.SamplingRatio < 0 || config.SamplingRatio > 1 {
+		return nil, fmt.Errorf("the tracing sampling ratio %v must be between 0 and 1", config.SamplingRatio)
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(config.Endpoint)}
+	if config.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the tracing exporter: %w", err)
+	}
+	res, err := resource.Merge(resource.Default(),
+		resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SamplingRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	ctrl.Log.WithName("tracing").Info("exporting the spans", "endpoint", config.Endpoint,
+		"samplingRatio", config.SamplingRatio)
+	return provider.Shutdown, nil
+}
+
+// Start starts a span of the event. The span continues the one in the context, or the one propagated by the
+// "traceparent" extension of the event when the context doesn't carry a span.
+func Start(ctx context.Context, name string, evt *cloudevents.Event, attrs ...attribute.KeyValue,
+) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		ctx = Extract(ctx, evt)
+	}
+	attrs = append(attrs, EventAttributes(evt)...)
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EventAttributes returns the attributes identifying the event: the managed hub, the type and the version.
+func EventAttributes(evt *cloudevents.Event) []attribute.KeyValue {
+	if evt == nil {
+		return nil
+	}
+	attrs := []attribute.KeyValue{HubAttribute.String(evt.Source()), EventTypeAttribute.String(evt.Type())}
+	if ver, ok := evt.Extensions()[version.ExtVersion].(string); ok {
+		attrs = append(attrs, VersionAttribute.String(ver))
+	}
+	return attrs
+}
+
+// End records the error on the span and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Inject propagates the span in the context by the distributed tracing extension of the event, so that the span is
+// continued once the event is received from the transport.
+func Inject(ctx context.Context, evt *cloudevents.Event) {
+	if evt == nil || !trace.SpanContextFromContext(ctx).IsValid() {
+		return
+	}
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	extensions.DistributedTracingExtension{
+		TraceParent: carrier.Get(extensions.TraceParentExtension),
+		TraceState:  carrier.Get(extensions.TraceStateExtension),
+	}.AddTracingAttributes(evt)
+}
+
+// Extract returns the context with the span propagated by the distributed tracing extension of the event.
+func Extract(ctx context.Context, evt *cloudevents.Event) context.Context {
+	if evt == nil {
+		return ctx
+	}
+	ext, ok := extensions.GetDistributedTracingExtension(*evt)
+	if !ok {
+		return ctx
+	}
+	return propagator.Extract(ctx, propagation.MapCarrier{
+		extensions.TraceParentExtension: ext.TraceParent,
+		extensions.TraceStateExtension:  ext.TraceState,
+	})
+}
@@ -0,0 +1,75 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/extensions"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+)
+
+func newEvent() *cloudevents.Event {
+	evt := cloudevents.NewEvent()
+	evt.SetSource("hub1")
+	evt.SetType("io.open-cluster-management.operator.multiclusterglobalhubs.policy.localspec")
+	evt.SetExtension(version.ExtVersion, "1.2")
+	return &evt
+}
+
+func TestPropagation(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(sdktrace.NewTracerProvider())
+
+	// the event isn't changed without a span
+	evt := newEvent()
+	Inject(context.Background(), evt)
+	_, found := extensions.GetDistributedTracingExtension(*evt)
+	assert.False(t, found)
+
+	// the sender
+	ctx, sendSpan := Start(context.Background(), "transport.send", evt)
+	Inject(ctx, evt)
+	sendSpan.End()
+	ext, found := extensions.GetDistributedTracingExtension(*evt)
+	assert.True(t, found)
+	assert.Contains(t, ext.TraceParent, sendSpan.SpanContext().TraceID().String())
+
+	// the receiver continues the span by the event, the event is serialized by the transport
+	payload, err := evt.MarshalJSON()
+	assert.NoError(t, err)
+	received := cloudevents.NewEvent()
+	assert.NoError(t, received.UnmarshalJSON(payload))
+	_, handleSpan := Start(context.Background(), "status.handle", &received)
+	End(handleSpan, errors.New("failed to handle"))
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	handled := spans[1]
+	assert.Equal(t, "status.handle", handled.Name())
+	assert.Equal(t, sendSpan.SpanContext().TraceID(), handled.SpanContext().TraceID())
+	assert.Equal(t, sendSpan.SpanContext().SpanID(), handled.Parent().SpanID())
+	assert.True(t, handled.Parent().IsRemote())
+	assert.Equal(t, codes.Error, handled.Status().Code)
+	assert.Contains(t, handled.Attributes(), HubAttribute.String("hub1"))
+	assert.Contains(t, handled.Attributes(), VersionAttribute.String("1.2"))
+}
+
+func TestInitTracing(t *testing.T) {
+	// the tracing is disabled without the endpoint
+	shutdown, err := InitTracing(context.Background(), "test", &TracingConfig{})
+	assert.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	_, err = InitTracing(context.Background(), "test", &TracingConfig{Endpoint: "localhost:4318", SamplingRatio: 2})
+	assert.ErrorContains(t, err, "between 0 and 1")
+}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/config"
 )
@@ -157,14 +158,14 @@ func (c *GenericConsumer) Start(ctx context.Context) error {
 
 		chunk, isChunk := c.assembler.messageChunk(event)
 		if !isChunk {
-			c.eventChan <- &event
+			c.eventChan <- received(ctx, &event)
 			return ceprotocol.ResultACK
 		}
 		if payload := c.assembler.assemble(chunk); payload != nil {
 			if err := event.SetData(cloudevents.ApplicationJSON, payload); err != nil {
 				c.log.Error(err, "failed the set the assembled data to event")
 			} else {
-				c.eventChan <- &event
+				c.eventChan <- received(ctx, &event)
 			}
 		}
 		return ceprotocol.ResultACK
@@ -176,6 +177,14 @@ func (c *GenericConsumer) Start(ctx context.Context) error {
 	return nil
 }
 
+// received continues the span propagated by the sender, the event carries the receiving span to the handlers
+func received(ctx context.Context, evt *cloudevents.Event) *cloudevents.Event {
+	ctx, span := tracing.Start(ctx, "transport.receive", evt)
+	tracing.Inject(ctx, evt)
+	span.End()
+	return evt
+}
+
 func (c *GenericConsumer) EventChan() chan *cloudevents.Event {
 	return c.eventChan
 }
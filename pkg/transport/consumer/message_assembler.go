@@ -13,6 +13,7 @@ import (
 	"github.com/go-logr/logr"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
@@ -113,6 +114,7 @@ func (assembler *messageAssembler) assemble(chunk *messageChunk) []byte {
 		}
 		assembler.log.V(2).Info("assemble event data success!", "id", chunkCollection.id,
 			"size", chunkCollection.totalSize)
+		statistics.AssembledMessage(len(transportPayloadBytes), len(chunkCollection.orderedOffsets))
 		return transportPayloadBytes
 	}
 
@@ -16,6 +16,7 @@ import (
 	"github.com/go-logr/logr"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/config"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/inventory/client"
@@ -47,7 +48,16 @@ func NewGenericProducer(transportConfig *transport.TransportInternalConfig) (*Ge
 	return genericProducer, nil
 }
 
+// SendEvent sends the event in a span, which is propagated to the receiver by the "traceparent" extension.
 func (p *GenericProducer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+	ctx, span := tracing.Start(ctx, "transport.send", &evt)
+	tracing.Inject(ctx, &evt)
+	err := p.send(ctx, evt)
+	tracing.End(span, err)
+	return err
+}
+
+func (p *GenericProducer) send(ctx context.Context, evt cloudevents.Event) error {
 	// inventory client
 	if p.inventoryClient != nil {
 		return p.inventoryClient.Request(ctx, evt)
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -13,35 +14,110 @@ import (
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
 
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 )
 
 const (
-	controllerName      = "leaf-hub-agent-sync"
+	// UpdateFieldManager is the field manager of the server-side apply by UpdateObject
+	UpdateFieldManager  = "leaf-hub-agent-sync"
 	notFoundErrorSuffix = "not found"
 )
 
 // UpdateObject function updates a given k8s object.
 func UpdateObject(ctx context.Context, runtimeClient client.Client, obj *unstructured.Unstructured) error {
+	return ApplyObject(ctx, runtimeClient, obj, UpdateFieldManager, false)
+}
+
+// ApplyObject applies the object with the server-side apply by the field manager, the object is updated with the
+// response. The dry run returns the object which would be persisted without changing it.
+func ApplyObject(ctx context.Context, runtimeClient client.Client, obj *unstructured.Unstructured,
+	fieldManager string, dryRun bool,
+) error {
 	objectBytes, err := obj.MarshalJSON()
 	if err != nil {
 		return fmt.Errorf("failed to update object - %w", err)
 	}
 	forceChanges := true
-	if err := runtimeClient.Patch(ctx, obj, client.RawPatch(types.ApplyPatchType, objectBytes), &client.PatchOptions{
-		FieldManager: controllerName,
+	opts := &client.PatchOptions{
+		FieldManager: fieldManager,
 		Force:        &forceChanges,
 		Raw: &metav1.PatchOptions{
 			FieldValidation: metav1.FieldValidationIgnore,
 		},
-	}); err != nil {
+	}
+	if dryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+	if err := runtimeClient.Patch(ctx, obj, client.RawPatch(types.ApplyPatchType, objectBytes), opts); err != nil {
 		return fmt.Errorf("failed to update object - %w", err)
 	}
 
 	return nil
 }
 
+// HasFieldManager returns true if the fields of the object are applied by the field manager
+func HasFieldManager(obj client.Object, fieldManager string) bool {
+	return appliedFieldsIndex(obj.GetManagedFields(), fieldManager) >= 0
+}
+
+// UpgradeFieldManager transfers the fields applied by UpdateObject to the field manager, so that the fields dropped
+// from the object are removed by the next apply of the field manager. The object is the response of the apply by the
+// field manager, and it's updated with the transferred managed fields.
+func UpgradeFieldManager(ctx context.Context, runtimeClient client.Client, obj *unstructured.Unstructured,
+	fieldManager string,
+) error {
+	managedFields := obj.GetManagedFields()
+	updateIndex := appliedFieldsIndex(managedFields, UpdateFieldManager)
+	applyIndex := appliedFieldsIndex(managedFields, fieldManager)
+	if updateIndex < 0 || applyIndex < 0 {
+		return nil
+	}
+
+	fields := &fieldpath.Set{}
+	for _, entry := range []metav1.ManagedFieldsEntry{managedFields[updateIndex], managedFields[applyIndex]} {
+		if entry.FieldsV1 == nil {
+			continue
+		}
+		entryFields := &fieldpath.Set{}
+		if err := entryFields.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+			return fmt.Errorf("failed to decode the managed fields of %s - %w", entry.Manager, err)
+		}
+		fields = fields.Union(entryFields)
+	}
+	raw, err := fields.ToJSON()
+	if err != nil {
+		return fmt.Errorf("failed to encode the managed fields of %s - %w", fieldManager, err)
+	}
+	managedFields[applyIndex].FieldsV1 = &metav1.FieldsV1{Raw: raw}
+	managedFields = append(managedFields[:updateIndex], managedFields[updateIndex+1:]...)
+
+	// the managed fields are replaced only if the object isn't changed since the apply
+	patch, err := json.Marshal([]map[string]interface{}{
+		{"op": "test", "path": "/metadata/resourceVersion", "value": obj.GetResourceVersion()},
+		{"op": "replace", "path": "/metadata/managedFields", "value": managedFields},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upgrade the field manager - %w", err)
+	}
+	if err := runtimeClient.Patch(ctx, obj, client.RawPatch(types.JSONPatchType, patch)); err != nil {
+		return fmt.Errorf("failed to upgrade the field manager - %w", err)
+	}
+	return nil
+}
+
+// appliedFieldsIndex returns the index of the managed fields applied by the field manager, or -1 if it isn't found
+func appliedFieldsIndex(managedFields []metav1.ManagedFieldsEntry, fieldManager string) int {
+	for i, entry := range managedFields {
+		if entry.Manager == fieldManager && entry.Operation == metav1.ManagedFieldsOperationApply &&
+			entry.Subresource == "" {
+			return i
+		}
+	}
+	return -1
+}
+
 // DeleteObject tries to delete the given object from k8s. returns error and true/false if object was deleted or not.
 func DeleteObject(ctx context.Context, k8sClient client.Client, obj *unstructured.Unstructured) (bool, error) {
 	if err := k8sClient.Delete(ctx, obj); err != nil {
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestUpgradeFieldManager(t *testing.T) {
+	fieldManager := "multicluster-global-hub-agent-spec"
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cm1", Namespace: "default",
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager: UpdateFieldManager, Operation: metav1.ManagedFieldsOperationApply,
+					FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:a":{},"f:b":{}}}`)},
+				},
+				{
+					Manager: fieldManager, Operation: metav1.ManagedFieldsOperationApply,
+					FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:a":{},"f:c":{}}}`)},
+				},
+				{
+					Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate,
+					FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:d":{}}}`)},
+				},
+			},
+		},
+		Data: map[string]string{"a": "1", "b": "2", "c": "3", "d": "4"},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(configMap).Build()
+
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(configMap), obj))
+	assert.True(t, HasFieldManager(obj, UpdateFieldManager))
+	assert.NoError(t, UpgradeFieldManager(ctx, c, obj, fieldManager))
+
+	// the fields applied by the previous field manager are owned by the field manager
+	assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(configMap), obj))
+	assert.False(t, HasFieldManager(obj, UpdateFieldManager))
+	managedFields := obj.GetManagedFields()
+	assert.Len(t, managedFields, 2)
+	assert.Equal(t, fieldManager, managedFields[0].Manager)
+	assert.JSONEq(t, `{"f:data":{"f:a":{},"f:b":{},"f:c":{}}}`, string(managedFields[0].FieldsV1.Raw))
+	assert.Equal(t, "kubectl-edit", managedFields[1].Manager)
+
+	// the object without the previous field manager isn't changed
+	resourceVersion := obj.GetResourceVersion()
+	assert.NoError(t, UpgradeFieldManager(ctx, c, obj, fieldManager))
+	assert.Equal(t, resourceVersion, obj.GetResourceVersion())
+}
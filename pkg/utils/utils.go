@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"runtime"
+	"runtime/debug"
 	"strings"
 
 	"github.com/go-logr/logr"
@@ -22,7 +23,29 @@ import (
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 )
 
+// BuildVersion is the version of the binary, it's injected when building the image by the ldflags
+// "-X github.com/stolostron/multicluster-global-hub/pkg/utils.BuildVersion=<version>"
+var BuildVersion = ""
+
+// GetBuildVersion returns the BuildVersion, or the vcs revision from the build info if the version isn't injected
+func GetBuildVersion() string {
+	if BuildVersion != "" {
+		return BuildVersion
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			return setting.Value
+		}
+	}
+	return "unknown"
+}
+
 func PrintVersion(log logr.Logger) {
+	log.Info(fmt.Sprintf("Build Version: %s", GetBuildVersion()))
 	log.Info(fmt.Sprintf("Go Version: %s", runtime.Version()))
 	log.Info(fmt.Sprintf("Go OS/Arch: %s/%s", runtime.GOOS, runtime.GOARCH))
 }
@@ -0,0 +1,85 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+// go test ./test/integration/agent/spec -v -ginkgo.focus "ManagedClusterOperationBundle"
+var _ = Describe("ManagedClusterOperationBundle", func() {
+	It("sync managedclusteroperation bundle", func() {
+		managedClusterName := "operation-mc1"
+
+		By("Create ManagedCluster on the managed hub")
+		managedCluster := clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: managedClusterName,
+				Labels: map[string]string{
+					"vendor": "OpenShift",
+					"env":    "dev",
+				},
+			},
+			Spec: clusterv1.ManagedClusterSpec{
+				HubAcceptsClient: true,
+			},
+		}
+		Expect(runtimeClient.Create(ctx, &managedCluster)).NotTo(HaveOccurred())
+
+		By("Send ManagedClusterOperationBundle by transport")
+		hubAcceptsClient := false
+		operationBundle := &spec.ManagedClusterOperationBundle{
+			OperationID: "a7f1f6b2-0f5e-4b5e-9a55-4f1f1cbbd7b1",
+			Operation: spec.ManagedClusterOperation{
+				Labels: &spec.MetadataChange{
+					Add:    map[string]string{"test": "operation"},
+					Remove: []string{"env"},
+				},
+				Taints: &spec.TaintChange{
+					Add: []clusterv1.Taint{{Key: "maintenance", Effect: clusterv1.TaintEffectNoSelect}},
+				},
+				HubAcceptsClient: &hubAcceptsClient,
+			},
+			Clusters: []string{managedClusterName},
+		}
+		payloadBytes, err := json.Marshal(operationBundle)
+		Expect(err).NotTo(HaveOccurred())
+
+		evt := utils.ToCloudEvent(constants.ManagedClusterOperationMsgKey, constants.CloudEventSourceGlobalHub,
+			agentConfig.LeafHubName, payloadBytes)
+		err = genericProducer.SendEvent(ctx, evt)
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Check the operation is applied to the managed cluster")
+		Eventually(func() error {
+			mc := clusterv1.ManagedCluster{}
+			err = runtimeClient.Get(ctx, runtimeclient.ObjectKeyFromObject(&managedCluster), &mc)
+			if err != nil {
+				return err
+			}
+			if mc.Labels["test"] != "operation" {
+				return fmt.Errorf("not found label on cluster { %s : %s}", "test", "operation")
+			}
+			if _, found := mc.Labels["env"]; found {
+				return fmt.Errorf("the label env should be removed from the cluster")
+			}
+			if len(mc.Spec.Taints) != 1 || mc.Spec.Taints[0].Key != "maintenance" {
+				return fmt.Errorf("not found taint maintenance on cluster: %v", mc.Spec.Taints)
+			}
+			if mc.Spec.HubAcceptsClient {
+				return fmt.Errorf("the hubAcceptsClient should be false")
+			}
+			return nil
+		}, 5*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
+})
@@ -7,8 +7,10 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	configv1 "github.com/openshift/api/config/v1"
 	routev1 "github.com/openshift/api/route/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clustersv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
 
@@ -127,4 +129,53 @@ var _ = Describe("HubClusterInfo", Ordered, func() {
 			return nil
 		}, 50*time.Second, 1*time.Second).Should(Succeed())
 	})
+
+	It("should get the versions and capacity of the hub cluster", func() {
+		By("Create the ACM version clusterclaim and the openshift ClusterVersion")
+		Expect(runtimeClient.Create(ctx, &clustersv1alpha1.ClusterClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: constants.VersionClusterClaimName},
+			Spec:       clustersv1alpha1.ClusterClaimSpec{Value: "2.11.0"},
+		})).Should(Succeed())
+		clusterVersion := &configv1.ClusterVersion{
+			ObjectMeta: metav1.ObjectMeta{Name: "version"},
+			Spec:       configv1.ClusterVersionSpec{ClusterID: "00000000-0000-0000-0000-000000000001"},
+		}
+		Expect(runtimeClient.Create(ctx, clusterVersion)).Should(Succeed())
+		clusterVersion.Status = configv1.ClusterVersionStatus{
+			Desired:     configv1.Release{Version: "4.16.3"},
+			VersionHash: "hash",
+			History:     []configv1.UpdateHistory{},
+		}
+		Expect(runtimeClient.Status().Update(ctx, clusterVersion)).Should(Succeed())
+
+		By("Create the node in the hub cluster")
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "hub-node"}}
+		Expect(runtimeClient.Create(ctx, node)).Should(Succeed())
+		node.Status.Capacity = corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("8"),
+			corev1.ResourceMemory: resource.MustParse("32Gi"),
+			corev1.ResourcePods:   resource.MustParse("250"),
+		}
+		Expect(runtimeClient.Status().Update(ctx, node)).Should(Succeed())
+
+		By("Check the hub cluster info bundle contains the versions and capacity")
+		Eventually(func() error {
+			evt := <-hubInfoConsumer.EventChan()
+			clusterInfo := &cluster.HubClusterInfo{}
+			if err := evt.DataAs(clusterInfo); err != nil {
+				return err
+			}
+			if clusterInfo.AgentVersion == "" {
+				return fmt.Errorf("the agent version should not be empty")
+			}
+			if clusterInfo.ACMVersion != "2.11.0" || clusterInfo.OpenShiftVersion != "4.16.3" {
+				return fmt.Errorf("unexpected versions: acm %s, openshift %s", clusterInfo.ACMVersion,
+					clusterInfo.OpenShiftVersion)
+			}
+			if clusterInfo.Capacity == nil || clusterInfo.Capacity.CPU != "8" {
+				return fmt.Errorf("unexpected capacity: %v", clusterInfo.Capacity)
+			}
+			return nil
+		}, 50*time.Second, 1*time.Second).Should(Succeed())
+	})
 })
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -19,6 +20,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/managedclusters"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/util"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
@@ -68,7 +70,7 @@ var _ = Describe("Nonk8s API Server", Ordered, func() {
 		router, err = nonk8sapi.SetupRouter(&nonk8sapi.NonK8sAPIServerConfig{
 			ServerBasePath: "/global-hub-api/v1",
 			ClusterAPIURL:  testAuthServer.URL,
-		})
+		}, nil)
 		Expect(err).NotTo(HaveOccurred())
 	})
 
@@ -1442,6 +1444,456 @@ var _ = Describe("Nonk8s API Server", Ordered, func() {
 		Expect(w1.Body.String()).Should(MatchJSON(subscriptionReportStr))
 	})
 
+	It("Should be able to get compliance summary and history", func() {
+		hub := "compliance-hub"
+		plcID := uuid.New().String()
+		mc1ID := uuid.New().String()
+		mc2ID := uuid.New().String()
+
+		By("Insert testing local policy, managed clusters and compliances")
+		err := db.Exec(`INSERT INTO local_spec.policies (policy_id,leaf_hub_name,payload) VALUES (?,?,?)`,
+			plcID, hub, `{"metadata": {"name": "plc1", "annotations": {
+				"policy.open-cluster-management.io/standards": "NIST SP 800-53, PCI"}}}`).Error
+		Expect(err).ToNot(HaveOccurred())
+		for name, id := range map[string]string{"mc1": mc1ID, "mc2": mc2ID} {
+			env := map[string]string{"mc1": "prod", "mc2": "dev"}[name]
+			err = db.Exec(`INSERT INTO status.managed_clusters (cluster_id,leaf_hub_name,payload,error)
+				VALUES (?,?,?,'none')`, id, hub, fmt.Sprintf(`{"metadata": {"name": "%s", "labels": {
+					"cluster.open-cluster-management.io/clusterset": "set1", "env": "%s"}}}`, name, env)).Error
+			Expect(err).ToNot(HaveOccurred())
+		}
+		err = db.Exec(`INSERT INTO local_status.compliance (policy_id,cluster_name,cluster_id,leaf_hub_name,error,
+			compliance) VALUES (?,'mc1',?,?,'none','compliant'), (?,'mc2',?,?,'none','non_compliant')`,
+			plcID, mc1ID, hub, plcID, mc2ID, hub).Error
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Check the compliance summary is aggregated by policy standard")
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET",
+			"/global-hub-api/v1/compliance/summary?groupBy=standard&hub="+hub, nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(200))
+		Expect(w.Body.String()).Should(MatchJSON(`{"groupBy": "standard", "items": [
+			{"key": "NIST SP 800-53", "compliant": 1, "nonCompliant": 1, "pending": 0, "unknown": 0,
+				"total": 2, "complianceRate": 0.5},
+			{"key": "PCI", "compliant": 1, "nonCompliant": 1, "pending": 0, "unknown": 0,
+				"total": 2, "complianceRate": 0.5}]}`))
+
+		By("Check the compliance summary is aggregated by cluster set")
+		w = httptest.NewRecorder()
+		req, err = http.NewRequest("GET",
+			"/global-hub-api/v1/compliance/summary?groupBy=clusterset&hub="+hub, nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(200))
+		Expect(w.Body.String()).Should(MatchJSON(`{"groupBy": "clusterset", "items": [
+			{"key": "set1", "compliant": 1, "nonCompliant": 1, "pending": 0, "unknown": 0,
+				"total": 2, "complianceRate": 0.5}]}`))
+
+		By("Check the label key is required to group by label")
+		w = httptest.NewRecorder()
+		req, err = http.NewRequest("GET", "/global-hub-api/v1/compliance/summary?groupBy=label", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(400))
+
+		By("Insert testing compliance history")
+		today := time.Now().Format("2006-01-02")
+		yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+		err = db.Exec(`INSERT INTO history.local_compliance (policy_id,cluster_id,leaf_hub_name,compliance_date,
+			compliance) VALUES (?,?,?,?,'non_compliant'), (?,?,?,?,'non_compliant'), (?,?,?,?,'compliant')`,
+			plcID, mc1ID, hub, yesterday, plcID, mc2ID, hub, yesterday, plcID, mc1ID, hub, today).Error
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Check the daily compliance history")
+		w = httptest.NewRecorder()
+		req, err = http.NewRequest("GET", fmt.Sprintf(
+			"/global-hub-api/v1/compliance/history?hub=%s&start=%s&end=%s", hub, yesterday, today), nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(200))
+		Expect(w.Body.String()).Should(MatchJSON(fmt.Sprintf(`{"groupBy": "hub", "interval": "day",
+			"start": "%[1]s", "end": "%[2]s", "buckets": [
+			{"date": "%[1]s", "items": [{"key": "%[3]s", "compliant": 0, "nonCompliant": 2, "pending": 0,
+				"unknown": 0, "total": 2, "complianceRate": 0}]},
+			{"date": "%[2]s", "items": [{"key": "%[3]s", "compliant": 1, "nonCompliant": 0, "pending": 0,
+				"unknown": 0, "total": 1, "complianceRate": 1}]}]}`, yesterday, today, hub)))
+
+		By("Check the daily compliance history is aggregated by cluster set")
+		w = httptest.NewRecorder()
+		req, err = http.NewRequest("GET", fmt.Sprintf(
+			"/global-hub-api/v1/compliance/history?groupBy=clusterset&hub=%s&start=%s&end=%s", hub, yesterday,
+			today), nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(200))
+		Expect(w.Body.String()).Should(MatchJSON(fmt.Sprintf(`{"groupBy": "clusterset", "interval": "day",
+			"start": "%[1]s", "end": "%[2]s", "buckets": [
+			{"date": "%[1]s", "items": [{"key": "set1", "compliant": 0, "nonCompliant": 2, "pending": 0,
+				"unknown": 0, "total": 2, "complianceRate": 0}]},
+			{"date": "%[2]s", "items": [{"key": "set1", "compliant": 1, "nonCompliant": 0, "pending": 0,
+				"unknown": 0, "total": 1, "complianceRate": 1}]}]}`, yesterday, today)))
+
+		By("Check the daily compliance history is aggregated by cluster label")
+		w = httptest.NewRecorder()
+		req, err = http.NewRequest("GET", fmt.Sprintf(
+			"/global-hub-api/v1/compliance/history?groupBy=label&labelKey=env&hub=%s&start=%s&end=%s", hub,
+			yesterday, today), nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(200))
+		Expect(w.Body.String()).Should(MatchJSON(fmt.Sprintf(`{"groupBy": "label", "interval": "day",
+			"start": "%[1]s", "end": "%[2]s", "buckets": [
+			{"date": "%[1]s", "items": [
+				{"key": "dev", "compliant": 0, "nonCompliant": 1, "pending": 0, "unknown": 0, "total": 1,
+					"complianceRate": 0},
+				{"key": "prod", "compliant": 0, "nonCompliant": 1, "pending": 0, "unknown": 0, "total": 1,
+					"complianceRate": 0}]},
+			{"date": "%[2]s", "items": [{"key": "prod", "compliant": 1, "nonCompliant": 0, "pending": 0,
+				"unknown": 0, "total": 1, "complianceRate": 1}]}]}`, yesterday, today)))
+
+		By("Check the invalid date range is rejected")
+		w = httptest.NewRecorder()
+		req, err = http.NewRequest("GET", fmt.Sprintf(
+			"/global-hub-api/v1/compliance/history?start=%s&end=%s", today, yesterday), nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(400))
+	})
+
+	It("Should be able to create and get managed cluster operation", func() {
+		hub := "operation-hub"
+		mc1ID := uuid.New().String()
+		mc2ID := uuid.New().String()
+
+		By("Insert testing managed clusters")
+		for name, id := range map[string]string{"operation-mc1": mc1ID, "operation-mc2": mc2ID} {
+			err := db.Exec(`INSERT INTO status.managed_clusters (cluster_id,leaf_hub_name,payload,error)
+				VALUES (?,?,?,'none')`, id, hub, fmt.Sprintf(`{"metadata": {"name": "%s", "labels": {
+					"env": "operation"}}}`, name)).Error
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		By("Check the operation without the target clusters is rejected")
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("POST", "/global-hub-api/v1/managedclusters/operations",
+			bytes.NewBufferString(`{"operation": {"hubAcceptsClient": false}}`))
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(400))
+
+		By("Create the operation for the clusters selected by the label selector")
+		w = httptest.NewRecorder()
+		req, err = http.NewRequest("POST", "/global-hub-api/v1/managedclusters/operations",
+			bytes.NewBufferString(`{"labelSelector": "env=operation", "operation": {
+				"labels": {"add": {"foo": "bar"}, "remove": ["env"]},
+				"taints": {"add": [{"key": "maintenance", "effect": "NoSelect"}]}}}`))
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(202))
+		created := map[string]interface{}{}
+		Expect(json.Unmarshal(w.Body.Bytes(), &created)).To(Succeed())
+		Expect(created["clusters"]).To(BeEquivalentTo(2))
+		operationID, ok := created["operationID"].(string)
+		Expect(ok).To(BeTrue())
+
+		By("Check the operation is in progress with the pending clusters")
+		w = httptest.NewRecorder()
+		req, err = http.NewRequest("GET", "/global-hub-api/v1/managedclusters/operations/"+operationID, nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(200))
+		status := map[string]interface{}{}
+		Expect(json.Unmarshal(w.Body.Bytes(), &status)).To(Succeed())
+		Expect(status["state"]).To(Equal("InProgress"))
+		Expect(status["summary"]).To(HaveKeyWithValue("pending", BeEquivalentTo(2)))
+		Expect(status["clusters"]).To(HaveLen(2))
+
+		By("Check the unknown operation isn't found")
+		w = httptest.NewRecorder()
+		req, err = http.NewRequest("GET", "/global-hub-api/v1/managedclusters/operations/"+uuid.New().String(), nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(404))
+	})
+
+	It("Should be able to get the history of managed cluster", func() {
+		hub := "history-hub"
+		clusterID := uuid.New().String()
+
+		By("Insert testing managed cluster and its history")
+		err := db.Exec(`INSERT INTO status.managed_clusters (cluster_id,leaf_hub_name,payload,error)
+			VALUES (?,?,'{"metadata": {"name": "history-mc1"}}','none')`, clusterID, hub).Error
+		Expect(err).ToNot(HaveOccurred())
+		dev, prod, earlier := "dev", "prod", time.Now().UTC().Add(-2*time.Hour)
+		err = db.Create([]models.ManagedClusterHistory{
+			{
+				ClusterID: clusterID, ClusterName: "history-mc1", LeafHubName: hub,
+				Field: database.ClusterHistoryHub, NewValue: &hub, CreatedAt: earlier,
+			},
+			{
+				ClusterID: clusterID, ClusterName: "history-mc1", LeafHubName: hub,
+				Field: database.ClusterHistoryLabel, Key: "env", OldValue: &dev, NewValue: &prod,
+				CreatedAt: time.Now().UTC(),
+			},
+		}).Error
+		Expect(err).ToNot(HaveOccurred())
+
+		get := func(path string, code int) managedclusters.ClusterHistory {
+			w := httptest.NewRecorder()
+			req, err := http.NewRequest("GET", path, nil)
+			Expect(err).ToNot(HaveOccurred())
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(code), w.Body.String())
+			history := managedclusters.ClusterHistory{}
+			if code == 200 {
+				Expect(json.Unmarshal(w.Body.Bytes(), &history)).To(Succeed())
+			}
+			return history
+		}
+
+		By("Get the history of the managed cluster in time order")
+		history := get(fmt.Sprintf("/global-hub-api/v1/managedcluster/%s/history", clusterID), 200)
+		Expect(history.Items).To(HaveLen(2))
+		Expect(history.Items[0].Field).To(Equal(database.ClusterHistoryHub))
+		Expect(history.Items[1].Key).To(Equal("env"))
+		Expect(*history.Items[1].NewValue).To(Equal("prod"))
+
+		By("Get the history since the time and of the field")
+		since := url.QueryEscape(time.Now().UTC().Add(-time.Hour).Format(time.RFC3339))
+		history = get(fmt.Sprintf("/global-hub-api/v1/managedcluster/%s/history?since=%s", clusterID, since), 200)
+		Expect(history.Items).To(HaveLen(1))
+		history = get(fmt.Sprintf("/global-hub-api/v1/managedcluster/%s/history?field=hub", clusterID), 200)
+		Expect(history.Items).To(HaveLen(1))
+		Expect(*history.Items[0].NewValue).To(Equal(hub))
+
+		By("Get the history with the invalid parameters or of the unknown cluster")
+		get("/global-hub-api/v1/managedcluster/invalid/history", 400)
+		get(fmt.Sprintf("/global-hub-api/v1/managedcluster/%s/history?field=payload", clusterID), 400)
+		get(fmt.Sprintf("/global-hub-api/v1/managedcluster/%s/history?since=yesterday", clusterID), 400)
+		get(fmt.Sprintf("/global-hub-api/v1/managedcluster/%s/history", uuid.New().String()), 404)
+	})
+
+	It("Should be able to search resources and manage saved searches", func() {
+		hub := "search-hub"
+		plcID := uuid.New().String()
+		mc1ID := uuid.New().String()
+		mc2ID := uuid.New().String()
+
+		By("Insert testing local policy, managed clusters and compliances")
+		err := db.Exec(`INSERT INTO local_spec.policies (policy_id,leaf_hub_name,payload) VALUES (?,?,?)`,
+			plcID, hub, `{"metadata": {"name": "search-plc", "namespace": "default"}}`).Error
+		Expect(err).ToNot(HaveOccurred())
+		for name, id := range map[string]string{"search-mc1": mc1ID, "search-mc2": mc2ID} {
+			err = db.Exec(`INSERT INTO status.managed_clusters (cluster_id,leaf_hub_name,payload,error)
+				VALUES (?,?,?,'none')`, id, hub, fmt.Sprintf(`{"metadata": {"name": "%s", "labels": {
+					"vendor": "OpenShift"}}, "status": {"clusterClaims": [
+					{"name": "version.openshift.io", "value": "4.14"}]}}`, name)).Error
+			Expect(err).ToNot(HaveOccurred())
+		}
+		err = db.Exec(`INSERT INTO local_status.compliance (policy_id,cluster_name,cluster_id,leaf_hub_name,error,
+			compliance) VALUES (?,'search-mc1',?,?,'none','compliant'),
+			(?,'search-mc2',?,?,'none','non_compliant')`, plcID, mc1ID, hub, plcID, mc2ID, hub).Error
+		Expect(err).ToNot(HaveOccurred())
+
+		search := func(path string) map[string]interface{} {
+			w := httptest.NewRecorder()
+			req, err := http.NewRequest("GET", path, nil)
+			Expect(err).ToNot(HaveOccurred())
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(200), w.Body.String())
+			result := map[string]interface{}{}
+			Expect(json.Unmarshal(w.Body.Bytes(), &result)).To(Succeed())
+			return result
+		}
+		itemNames := func(result map[string]interface{}) []string {
+			names := []string{}
+			for _, item := range result["items"].([]interface{}) {
+				names = append(names, item.(map[string]interface{})["name"].(string))
+			}
+			return names
+		}
+
+		By("Search the non-compliant OpenShift 4.14 clusters of the policy")
+		result := search("/global-hub-api/v1/search?q=" + url.QueryEscape(fmt.Sprintf(
+			"kind:cluster hub:%s claim:version.openshift.io=4.14 compliance:search-plc=non_compliant", hub)))
+		Expect(itemNames(result)).To(Equal([]string{"search-mc2"}))
+
+		By("Search the clusters with paging")
+		query := url.QueryEscape(fmt.Sprintf("hub:%s label:vendor=OpenShift", hub))
+		result = search("/global-hub-api/v1/search?limit=1&q=" + query)
+		Expect(itemNames(result)).To(Equal([]string{"search-mc1"}))
+		Expect(result["continue"]).NotTo(BeEmpty())
+		result = search(fmt.Sprintf("/global-hub-api/v1/search?limit=1&q=%s&continue=%s", query,
+			result["continue"]))
+		Expect(itemNames(result)).To(Equal([]string{"search-mc2"}))
+		Expect(result).NotTo(HaveKey("continue"))
+
+		By("Search the policies with the compliance on the cluster")
+		result = search("/global-hub-api/v1/search?q=" + url.QueryEscape(
+			"kind:policy compliance:search-mc1=compliant name:search-*"))
+		Expect(itemNames(result)).To(Equal([]string{"search-plc"}))
+
+		By("Check the invalid query is rejected")
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/global-hub-api/v1/search?q="+url.QueryEscape("kind:hub label:a=b"), nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(400))
+
+		By("Create the saved search")
+		w = httptest.NewRecorder()
+		req, err = http.NewRequest("POST", "/global-hub-api/v1/searches", bytes.NewBufferString(fmt.Sprintf(
+			`{"name": "non-compliant", "query": "hub:%s compliance:non_compliant"}`, hub)))
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(201), w.Body.String())
+		saved := map[string]interface{}{}
+		Expect(json.Unmarshal(w.Body.Bytes(), &saved)).To(Succeed())
+		searchID := saved["id"].(string)
+
+		By("Check the saved search with the same name is conflicted")
+		w = httptest.NewRecorder()
+		req, err = http.NewRequest("POST", "/global-hub-api/v1/searches",
+			bytes.NewBufferString(`{"name": "non-compliant", "query": "kind:hub"}`))
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(409))
+
+		By("Run the saved search")
+		result = search("/global-hub-api/v1/searches/" + searchID + "/results")
+		Expect(itemNames(result)).To(Equal([]string{"search-mc2"}))
+
+		By("Update and list the saved search")
+		w = httptest.NewRecorder()
+		req, err = http.NewRequest("PUT", "/global-hub-api/v1/searches/"+searchID,
+			bytes.NewBufferString(`{"name": "hubs", "query": "kind:hub"}`))
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(200), w.Body.String())
+		result = search("/global-hub-api/v1/searches")
+		Expect(itemNames(result)).To(Equal([]string{"hubs"}))
+
+		By("Delete the saved search")
+		w = httptest.NewRecorder()
+		req, err = http.NewRequest("DELETE", "/global-hub-api/v1/searches/"+searchID, nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(204))
+		w = httptest.NewRecorder()
+		req, err = http.NewRequest("GET", "/global-hub-api/v1/searches/"+searchID, nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(404))
+	})
+
+	It("Should be able to list and get managed hubs", func() {
+		By("Insert the managed hubs with the heartbeat, managed clusters, alert counts and sync health")
+		now := time.Now()
+		for _, hub := range []string{"inventory-hub1", "inventory-hub2"} {
+			Expect(db.Create(&models.LeafHub{
+				LeafHubName: hub,
+				ClusterID:   uuid.New().String(),
+				Payload: []byte(fmt.Sprintf(`{"consoleURL": "https://console.%s", "grafanaURL": "",
+					"clusterId": "", "agentVersion": "1.3.0", "acmVersion": "2.11.0"}`, hub)),
+			}).Error).To(Succeed())
+		}
+		Expect(models.LeafHubHeartbeat{
+			Name: "inventory-hub1", Status: "active", LastUpdateAt: now,
+		}.UpInsertHeartBeat(db)).To(Succeed())
+		err := db.Exec(`INSERT INTO status.managed_clusters (cluster_id,leaf_hub_name,payload,error) VALUES
+			(?,'inventory-hub1',?,'none'), (?,'inventory-hub1',?,'none')`,
+			uuid.New().String(), `{"metadata": {"name": "inventory-mc1"}, "status": {"conditions": [
+				{"type": "ManagedClusterConditionAvailable", "status": "True"}]}}`,
+			uuid.New().String(), `{"metadata": {"name": "inventory-mc2"}, "status": {"conditions": [
+				{"type": "ManagedClusterConditionAvailable", "status": "Unknown"}]}}`).Error
+		Expect(err).ToNot(HaveOccurred())
+		err = db.Exec(`INSERT INTO security.alert_counts (hub_name,low,medium,high,critical,detail_url,source)
+			VALUES ('inventory-hub1',1,2,3,4,'https://central-a','a'), ('inventory-hub1',1,0,0,1,'https://central-b','b')`).
+			Error
+		Expect(err).ToNot(HaveOccurred())
+		Expect(db.Create([]models.SyncHealth{
+			{
+				LeafHubName: "inventory-hub1", EventType: "io.open-cluster-management.operator.multiclusterglobalhubs.policy",
+				LastReceivedAt: &now, LastProcessedAt: &now, LastProcessedVersion: "1.2", ProcessingLatencyMs: 20,
+				Received: 3, Processed: 2, Failures: 1, LastError: "connection refused",
+			},
+			{
+				LeafHubName: "inventory-hub1", EventType: database.SyncHealthTransportType,
+				Topic: "gh-status.inventory-hub1", ConsumerLag: 5,
+			},
+		}).Error).To(Succeed())
+
+		get := func(path string) map[string]interface{} {
+			w := httptest.NewRecorder()
+			req, err := http.NewRequest("GET", path, nil)
+			Expect(err).ToNot(HaveOccurred())
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(200), w.Body.String())
+			result := map[string]interface{}{}
+			Expect(json.Unmarshal(w.Body.Bytes(), &result)).To(Succeed())
+			return result
+		}
+
+		By("Get the managed hub")
+		hub := get("/global-hub-api/v1/hub/inventory-hub1")
+		Expect(hub).To(HaveKeyWithValue("status", "active"))
+		Expect(hub).To(HaveKeyWithValue("agentVersion", "1.3.0"))
+		Expect(hub).To(HaveKeyWithValue("acmVersion", "2.11.0"))
+		Expect(hub).To(HaveKeyWithValue("consoleURL", "https://console.inventory-hub1"))
+		Expect(hub).To(HaveKey("lastHeartbeat"))
+		Expect(hub["managedClusters"]).To(HaveKeyWithValue("total", BeEquivalentTo(2)))
+		Expect(hub["managedClusters"]).To(HaveKeyWithValue("available", BeEquivalentTo(1)))
+		Expect(hub["alertCounts"]).To(HaveKeyWithValue("low", BeEquivalentTo(2)))
+		Expect(hub["alertCounts"]).To(HaveKeyWithValue("critical", BeEquivalentTo(5)))
+		Expect(hub).To(HaveKeyWithValue("topic", "gh-status.inventory-hub1"))
+		Expect(hub).To(HaveKeyWithValue("consumerLag", BeEquivalentTo(5)))
+		syncHealth := hub["syncHealth"].([]interface{})
+		Expect(syncHealth).To(HaveLen(1))
+		Expect(syncHealth[0]).To(HaveKeyWithValue("failures", BeEquivalentTo(1)))
+		Expect(syncHealth[0]).To(HaveKeyWithValue("lastProcessedVersion", "1.2"))
+
+		hub = get("/global-hub-api/v1/hub/inventory-hub2")
+		Expect(hub).To(HaveKeyWithValue("status", "unknown"))
+		Expect(hub).NotTo(HaveKey("alertCounts"))
+
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/global-hub-api/v1/hub/inventory-hub3", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(404))
+
+		By("List the managed hubs with paging")
+		names := []string{}
+		path := "/global-hub-api/v1/hubs?limit=1"
+		for {
+			hubList := get(path)
+			items := hubList["items"].([]interface{})
+			Expect(len(items)).To(BeNumerically("<=", 1))
+			for _, item := range items {
+				names = append(names, item.(map[string]interface{})["name"].(string))
+			}
+			if _, ok := hubList["continue"]; !ok {
+				break
+			}
+			path = fmt.Sprintf("/global-hub-api/v1/hubs?limit=1&continue=%s", hubList["continue"])
+		}
+		Expect(names).To(ContainElements("inventory-hub1", "inventory-hub2"))
+
+		By("List the managed hubs as table")
+		w = httptest.NewRecorder()
+		req, err = http.NewRequest("GET", "/global-hub-api/v1/hubs", nil)
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("Accept", "application/json;as=Table;g=meta.k8s.io;v=v1")
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(200))
+		table := map[string]interface{}{}
+		Expect(json.Unmarshal(w.Body.Bytes(), &table)).To(Succeed())
+		Expect(table).To(HaveKeyWithValue("kind", "Table"))
+		Expect(len(table["rows"].([]interface{}))).To(Equal(len(names)))
+	})
+
 	AfterAll(func() {
 		database.CloseGorm(database.GetSqlDb())
 	})
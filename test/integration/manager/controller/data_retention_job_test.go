@@ -15,6 +15,7 @@ import (
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/config"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/cronjob/task"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
@@ -110,7 +111,8 @@ var _ = Describe("data retention job", Ordered, func() {
 	It("the data retention job should work", func() {
 		By("Create the data retention job")
 		s := gocron.NewScheduler(time.UTC)
-		_, err := s.Every(1).Week().DoWithJobDetails(task.DataRetention, ctx, retentionMonth)
+		_, err := s.Every(1).Week().DoWithJobDetails(task.DataRetention, ctx,
+			&config.DatabaseConfig{DataRetention: retentionMonth})
 		Expect(err).ToNot(HaveOccurred())
 		s.StartAsync()
 		defer s.Clear()
@@ -178,6 +180,8 @@ var _ = Describe("data retention job", Ordered, func() {
 				if log.Name == tableName {
 					Expect(log.MinPartition).To(ContainSubstring(minTime.Format(task.PartitionDateFormat)))
 					Expect(log.MaxPartition).To(ContainSubstring(maxTime.Format(task.PartitionDateFormat)))
+					Expect(log.RetentionMonth).To(Equal(retentionMonth))
+					Expect(log.DeletedPartitions).To(ContainSubstring(expirationTime.Format(task.PartitionDateFormat)))
 				}
 			}
 		}
@@ -1,15 +1,17 @@
 package controller
 
 import (
-	"time"
+	"fmt"
 
-	"github.com/go-co-op/gocron"
+	"github.com/google/uuid"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/config"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/cronjob"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/cronjob/task"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
 )
 
 var _ = Describe("scheduler", func() {
@@ -18,37 +20,56 @@ var _ = Describe("scheduler", func() {
 			DatabaseConfig: &config.DatabaseConfig{
 				DataRetention: 18,
 			},
+			JobConfig: &config.JobConfig{
+				Schedules:               map[string]string{task.VacuumAnalyzeTaskName: "0 3 * * 6"},
+				Disabled:                []string{task.OrphanedSpecTaskName},
+				StaleHeartbeatThreshold: task.DefaultStaleHeartbeatThreshold,
+			},
+		}
+		for _, interval := range []string{"month", "week", "day", "hour", "minute", "second"} {
+			managerConfig.SchedulerInterval = interval
+			Expect(cronjob.AddSchedulerToManager(ctx, mgr, managerConfig, false)).To(Succeed())
 		}
-		managerConfig.SchedulerInterval = "month"
-		Expect(cronjob.AddSchedulerToManager(ctx, mgr, managerConfig, false)).To(Succeed())
-
-		managerConfig.SchedulerInterval = "week"
-		Expect(cronjob.AddSchedulerToManager(ctx, mgr, managerConfig, false)).To(Succeed())
 
 		managerConfig.SchedulerInterval = "day"
-		Expect(cronjob.AddSchedulerToManager(ctx, mgr, managerConfig, false)).To(Succeed())
-
-		managerConfig.SchedulerInterval = "hour"
-		Expect(cronjob.AddSchedulerToManager(ctx, mgr, managerConfig, false)).To(Succeed())
-
-		managerConfig.SchedulerInterval = "minute"
-		Expect(cronjob.AddSchedulerToManager(ctx, mgr, managerConfig, false)).To(Succeed())
-
-		managerConfig.SchedulerInterval = "second"
-		Expect(cronjob.AddSchedulerToManager(ctx, mgr, managerConfig, false)).To(Succeed())
-
-		scheduler := gocron.NewScheduler(time.Local)
-		_, err := scheduler.Every(1).Day().At("00:00").Tag(task.LocalComplianceTaskName).DoWithJobDetails(
-			task.LocalComplianceHistory, ctx)
+		managerConfig.LaunchJobNames = fmt.Sprintf("%s,%s,unexpected_name", task.RetentionTaskName,
+			task.ComplianceRollupTaskName)
+		globalScheduler, err := cronjob.NewGlobalHubScheduler(ctx, managerConfig)
 		Expect(err).To(Succeed())
+		globalScheduler.ExecJobs(ctx)
 
-		_, err = scheduler.Every(1).Month(1, 15, 28).At("00:00").Tag(task.RetentionTaskName).
-			DoWithJobDetails(task.DataRetention, ctx, managerConfig.DatabaseConfig.DataRetention)
-		Expect(err).To(Succeed())
+		By("the launched jobs are recorded into the job history")
+		for _, name := range []string{task.RetentionTaskName, task.ComplianceRollupTaskName} {
+			Eventually(func() error {
+				run := models.JobRun{}
+				err := db.Where(&models.JobRun{JobName: name, Trigger: database.JobRunLaunched}).
+					Order("created_at DESC").First(&run).Error
+				if err != nil {
+					return err
+				}
+				if run.State != database.JobRunSucceeded {
+					return fmt.Errorf("the run of the job %s should be succeeded: %s %s", name, run.State, run.Error)
+				}
+				return nil
+			}, 30, 1).ShouldNot(HaveOccurred())
+		}
 
-		globalScheduler := cronjob.NewGlobalHubScheduler(scheduler,
-			[]string{task.RetentionTaskName, task.LocalComplianceTaskName, "unexpected_name"})
-		err = globalScheduler.ExecJobs()
-		Expect(err).To(Succeed())
+		By("the manual run of the unknown job is failed")
+		run := &models.JobRun{
+			ID:      uuid.New().String(),
+			JobName: "unexpected-name",
+			Trigger: database.JobRunManual,
+			State:   database.JobRunPending,
+		}
+		Expect(db.Create(run).Error).To(Succeed())
+		Eventually(func() error {
+			if err := db.Where(&models.JobRun{ID: run.ID}).First(run).Error; err != nil {
+				return err
+			}
+			if run.State != database.JobRunFailed {
+				return fmt.Errorf("the run %s should be failed: %s", run.ID, run.State)
+			}
+			return nil
+		}, 30, 1).ShouldNot(HaveOccurred())
 	})
 })
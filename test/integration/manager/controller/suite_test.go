@@ -70,7 +70,6 @@ var _ = BeforeSuite(func() {
 	Expect(cfg).NotTo(BeNil())
 
 	By("Create test postgres")
-	database.IsBackupEnabled = true
 	testPostgres, err = testpostgres.NewTestPostgres()
 	Expect(err).NotTo(HaveOccurred())
 	err = testpostgres.InitDatabase(testPostgres.URI)
@@ -65,4 +65,58 @@ var _ = Describe("ManagedClusterHandler", Ordered, func() {
 			return fmt.Errorf("not found expected resource on the table")
 		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
 	})
+
+	It("should be able to record the history of the managed cluster", func() {
+		leafHubName := "history-hub"
+		clusterID := "0d7b1a8e-5c43-4a5e-9b1e-6f7c2d3e4a51"
+		version := eventversion.NewVersion()
+		cluster := &clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "history-cluster",
+				ResourceVersion: "1",
+				Labels:          map[string]string{"env": "dev"},
+			},
+			Status: clusterv1.ManagedClusterStatus{
+				ClusterClaims: []clusterv1.ManagedClusterClaim{{Name: "id.k8s.io", Value: clusterID}},
+			},
+		}
+
+		By("Sync the new managed cluster")
+		version.Incr()
+		evt := ToCloudEvent(leafHubName, string(enum.ManagedClusterType), version, generic.GenericObjectBundle{cluster})
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+		Eventually(func() error {
+			history := []models.ManagedClusterHistory{}
+			err := database.GetGorm().Where(&models.ManagedClusterHistory{ClusterID: clusterID}).Find(&history).Error
+			if err != nil {
+				return err
+			}
+			if len(history) != 1 || history[0].Field != database.ClusterHistoryHub ||
+				*history[0].NewValue != leafHubName {
+				return fmt.Errorf("unexpected history of the new cluster: %v", history)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+
+		By("Sync the label changes of the managed cluster")
+		cluster.ResourceVersion = "2"
+		cluster.Labels = map[string]string{"env": "prod"}
+		version.Incr()
+		evt = ToCloudEvent(leafHubName, string(enum.ManagedClusterType), version, generic.GenericObjectBundle{cluster})
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+		Eventually(func() error {
+			history := []models.ManagedClusterHistory{}
+			err := database.GetGorm().Where(&models.ManagedClusterHistory{
+				ClusterID: clusterID, Field: database.ClusterHistoryLabel,
+			}).Find(&history).Error
+			if err != nil {
+				return err
+			}
+			if len(history) != 1 || history[0].Key != "env" || *history[0].OldValue != "dev" ||
+				*history[0].NewValue != "prod" {
+				return fmt.Errorf("unexpected label history of the cluster: %v", history)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
 })
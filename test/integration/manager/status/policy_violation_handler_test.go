@@ -0,0 +1,96 @@
+package status
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// go test /test/integration/manager/status -v -ginkgo.focus "PolicyViolationHandler"
+var _ = Describe("PolicyViolationHandler", Ordered, func() {
+	leafHubName := "hub1"
+	policyID := "5c2a9b1e-3f4d-4a8e-9b6c-7d1e2f3a4b5c"
+	clusterName := "cluster1"
+	version := eventversion.NewVersion()
+
+	listViolations := func() ([]models.PolicyViolation, error) {
+		violations := []models.PolicyViolation{}
+		err := database.GetGorm().Where(&models.PolicyViolation{
+			PolicyID: policyID, LeafHubName: leafHubName,
+		}).Find(&violations).Error
+		return violations, err
+	}
+
+	It("should store the violated templates", func() {
+		By("Create the policy violation event")
+		version.Incr()
+		data := grc.PolicyViolationBundle{{
+			PolicyID:    policyID,
+			ClusterID:   "0f6e1c2d-8a3b-4c5d-9e7f-1a2b3c4d5e6f",
+			ClusterName: clusterName,
+			Templates: []grc.TemplateViolation{{
+				Name:       "policy-namespace",
+				Kind:       "ConfigurationPolicy",
+				APIVersion: "policy.open-cluster-management.io/v1",
+				Compliance: "NonCompliant",
+				Message:    "NonCompliant; violation - namespaces [prod] not found",
+				RelatedObjects: []grc.RelatedObject{{
+					Kind: "Namespace", APIVersion: "v1", Name: "prod",
+					Compliance: "NonCompliant", Reason: "Resource not found but should exist",
+				}},
+			}},
+		}}
+		evt := ToCloudEvent(leafHubName, string(enum.PolicyViolationType), version, data)
+
+		By("Sync event with transport")
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+
+		By("Check the violation is created in database")
+		Eventually(func() error {
+			violations, err := listViolations()
+			if err != nil {
+				return err
+			}
+			if len(violations) != 1 {
+				return fmt.Errorf("expect 1 violation, but got %d", len(violations))
+			}
+			if violations[0].ClusterName != clusterName || violations[0].TemplateKind != "ConfigurationPolicy" ||
+				violations[0].Compliance != database.NonCompliant {
+				return fmt.Errorf("unexpected violation: %+v", violations[0])
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
+
+	It("should remove the violations when the policy is compliant", func() {
+		By("Create the policy violation event without templates")
+		version.Incr()
+		data := grc.PolicyViolationBundle{{
+			PolicyID:    policyID,
+			ClusterName: clusterName,
+			Templates:   []grc.TemplateViolation{},
+		}}
+		evt := ToCloudEvent(leafHubName, string(enum.PolicyViolationType), version, data)
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+
+		By("Check the violation is deleted from database")
+		Eventually(func() error {
+			violations, err := listViolations()
+			if err != nil {
+				return err
+			}
+			if len(violations) != 0 {
+				return fmt.Errorf("expect no violation, but got %d", len(violations))
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
+})
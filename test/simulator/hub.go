@@ -0,0 +1,316 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	policiesv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/event"
+	genericdata "github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+const policyNamespace = "default"
+
+// complianceProbe is a (policy, cluster) pair of the hub, whose compliance is flipped in a round only if the
+// previous flip is persisted, so the latency is measured from the first bundle carrying the change
+type complianceProbe struct {
+	policyID    string
+	clusterName string
+	expected    database.ComplianceStatus
+	sentAt      time.Time
+	pending     bool
+}
+
+// simulatedHub impersonates the agent of a managed hub, the bundles are mutated and sent by the hub goroutine, only
+// the probe and the counters are shared with the latency prober and the reporter
+type simulatedHub struct {
+	name     string
+	opts     *simulatorOptions
+	producer transport.Producer
+	random   *rand.Rand
+
+	clusters     []clusterv1.ManagedCluster
+	policies     []policiesv1.Policy
+	nonCompliant [][]bool // [policy][cluster]
+	events       event.ReplicatedPolicyEventBundle
+	versions     map[enum.EventType]*eventversion.Version
+
+	mutex sync.Mutex
+	probe complianceProbe
+
+	sentBundles   atomic.Int64
+	failedBundles atomic.Int64
+}
+
+func newSimulatedHub(name string, opts *simulatorOptions, producer transport.Producer) *simulatedHub {
+	h := &simulatedHub{
+		name:     name,
+		opts:     opts,
+		producer: producer,
+		random:   rand.New(rand.NewSource(time.Now().UnixNano())), // #nosec G404
+		versions: map[enum.EventType]*eventversion.Version{},
+	}
+
+	for i := 0; i < opts.clusters; i++ {
+		h.clusters = append(h.clusters, clusterv1.ManagedCluster{
+			TypeMeta: metav1.TypeMeta{Kind: "ManagedCluster", APIVersion: clusterv1.GroupVersion.String()},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            fmt.Sprintf("cluster-%d", i),
+				UID:             types.UID(uuid.New().String()),
+				ResourceVersion: "1",
+				Labels:          map[string]string{"vendor": "OpenShift", "simulator": "true"},
+			},
+			Spec: clusterv1.ManagedClusterSpec{HubAcceptsClient: true},
+			Status: clusterv1.ManagedClusterStatus{
+				ClusterClaims: []clusterv1.ManagedClusterClaim{{Name: "id.k8s.io", Value: uuid.New().String()}},
+				Conditions: []metav1.Condition{{
+					Type:               clusterv1.ManagedClusterConditionAvailable,
+					Status:             metav1.ConditionTrue,
+					Reason:             "ManagedClusterAvailable",
+					LastTransitionTime: metav1.Now(),
+				}},
+			},
+		})
+	}
+
+	for i := 0; i < opts.policies; i++ {
+		h.policies = append(h.policies, policiesv1.Policy{
+			TypeMeta: metav1.TypeMeta{Kind: policiesv1.Kind, APIVersion: policiesv1.GroupVersion.String()},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            fmt.Sprintf("policy-%d", i),
+				Namespace:       policyNamespace,
+				UID:             types.UID(uuid.New().String()),
+				ResourceVersion: "1",
+				Annotations: map[string]string{
+					"policy.open-cluster-management.io/standards":  "NIST SP 800-53",
+					"policy.open-cluster-management.io/categories": "CM Configuration Management",
+					"policy.open-cluster-management.io/controls":   "CM-2 Baseline Configuration",
+				},
+			},
+			Spec: policiesv1.PolicySpec{RemediationAction: policiesv1.Inform},
+		})
+		h.nonCompliant = append(h.nonCompliant, make([]bool, opts.clusters))
+	}
+
+	h.probe = complianceProbe{
+		policyID:    string(h.policies[0].UID),
+		clusterName: h.clusters[0].Name,
+		expected:    database.Compliant,
+	}
+	return h
+}
+
+func (h *simulatedHub) start(ctx context.Context) {
+	// the initial state: the compliance bundle creates the compliance rows which are updated by the complete bundles
+	h.sendHeartbeat(ctx)
+	h.sendManagedClusters(ctx)
+	h.send(ctx, enum.LocalPolicySpecType, h.policies, nil)
+	h.sendCompliance(ctx)
+
+	heartbeatTicker := time.NewTicker(h.opts.heartbeatInterval)
+	defer heartbeatTicker.Stop()
+	clusterTicker := time.NewTicker(h.opts.clusterInterval)
+	defer clusterTicker.Stop()
+	complianceTicker := time.NewTicker(h.opts.complianceInterval)
+	defer complianceTicker.Stop()
+	eventTicker := time.NewTicker(h.opts.eventInterval)
+	defer eventTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeatTicker.C:
+			h.sendHeartbeat(ctx)
+		case <-clusterTicker.C:
+			h.rotateManagedClusters()
+			h.sendManagedClusters(ctx)
+		case <-complianceTicker.C:
+			h.rotateCompliance(ctx)
+		case <-eventTicker.C:
+			if len(h.events) > 0 {
+				h.send(ctx, enum.LocalReplicatedPolicyEventType, h.events, nil)
+				h.events = event.ReplicatedPolicyEventBundle{}
+			}
+		}
+	}
+}
+
+func (h *simulatedHub) sendHeartbeat(ctx context.Context) {
+	h.send(ctx, enum.HubClusterHeartbeatType, genericdata.GenericObjectBundle{}, nil)
+}
+
+func (h *simulatedHub) sendManagedClusters(ctx context.Context) {
+	h.send(ctx, enum.ManagedClusterType, h.clusters, nil)
+}
+
+// rotateManagedClusters updates the resource version of the churned clusters, only these clusters are updated in
+// the database by the manager
+func (h *simulatedHub) rotateManagedClusters() {
+	for _, i := range h.pick(len(h.clusters)) {
+		cluster := &h.clusters[i]
+		resourceVersion, _ := strconv.Atoi(cluster.ResourceVersion)
+		cluster.ResourceVersion = strconv.Itoa(resourceVersion + 1)
+		cluster.Status.Conditions[0].LastTransitionTime = metav1.Now()
+	}
+}
+
+// sendCompliance sends the full compliance state of all the (policy, cluster) pairs
+func (h *simulatedHub) sendCompliance(ctx context.Context) {
+	bundle := grc.ComplianceBundle{}
+	for p, policy := range h.policies {
+		compliance := grc.Compliance{
+			PolicyID:                  string(policy.UID),
+			CompliantClusters:         []string{},
+			NonCompliantClusters:      []string{},
+			UnknownComplianceClusters: []string{},
+			PendingComplianceClusters: []string{},
+		}
+		for c, cluster := range h.clusters {
+			if h.nonCompliant[p][c] {
+				compliance.NonCompliantClusters = append(compliance.NonCompliantClusters, cluster.Name)
+			} else {
+				compliance.CompliantClusters = append(compliance.CompliantClusters, cluster.Name)
+			}
+		}
+		bundle = append(bundle, compliance)
+	}
+	h.send(ctx, enum.LocalComplianceType, bundle, nil)
+}
+
+// rotateCompliance flips the compliance of the churned pairs and the probe, then sends the complete compliance
+// bundle, which depends on the last compliance bundle as the agent does
+func (h *simulatedHub) rotateCompliance(ctx context.Context) {
+	for _, i := range h.pick(len(h.policies) * len(h.clusters)) {
+		if i == 0 {
+			continue // the first pair is the probe
+		}
+		h.flip(i/len(h.clusters), i%len(h.clusters))
+	}
+
+	h.mutex.Lock()
+	flipProbe := !h.probe.pending
+	h.mutex.Unlock()
+	if flipProbe {
+		h.flip(0, 0)
+	}
+
+	bundle := grc.CompleteComplianceBundle{}
+	for p, policy := range h.policies {
+		compliance := grc.CompleteCompliance{
+			PolicyID:                  string(policy.UID),
+			NonCompliantClusters:      []string{},
+			UnknownComplianceClusters: []string{},
+			PendingComplianceClusters: []string{},
+		}
+		for c, cluster := range h.clusters {
+			if h.nonCompliant[p][c] {
+				compliance.NonCompliantClusters = append(compliance.NonCompliantClusters, cluster.Name)
+			}
+		}
+		// the policy with all the clusters compliant isn't included in the bundle
+		if len(compliance.NonCompliantClusters) > 0 {
+			bundle = append(bundle, compliance)
+		}
+	}
+
+	sentAt := time.Now()
+	if !h.send(ctx, enum.LocalCompleteComplianceType, bundle, h.versions[enum.LocalComplianceType]) || !flipProbe {
+		return
+	}
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.probe.expected = database.Compliant
+	if h.nonCompliant[0][0] {
+		h.probe.expected = database.NonCompliant
+	}
+	h.probe.sentAt = sentAt
+	h.probe.pending = true
+}
+
+// flip changes the compliance of the (policy, cluster) pair and records the replicated policy event
+func (h *simulatedHub) flip(p, c int) {
+	h.nonCompliant[p][c] = !h.nonCompliant[p][c]
+	compliance, message := policiesv1.Compliant, "Compliant; notification - the resources are as expected"
+	if h.nonCompliant[p][c] {
+		compliance, message = policiesv1.NonCompliant, "NonCompliant; violation - the resources are not as expected"
+	}
+	policy, cluster := h.policies[p], h.clusters[c]
+	h.events = append(h.events, event.ReplicatedPolicyEvent{
+		BaseEvent: event.BaseEvent{
+			EventName:      fmt.Sprintf("%s.%s.%s", policy.Namespace, policy.Name, uuid.New().String()[:8]),
+			EventNamespace: cluster.Name,
+			Message:        message,
+			Reason:         "PolicyStatusSync",
+			Count:          1,
+			Source:         corev1.EventSource{Component: "policy-status-history-sync"},
+			CreatedAt:      metav1.Now(),
+		},
+		PolicyID:    string(policy.UID),
+		ClusterID:   cluster.Status.ClusterClaims[0].Value,
+		ClusterName: cluster.Name,
+		Compliance:  string(compliance),
+	})
+}
+
+// pick returns the random indexes of the churned items
+func (h *simulatedHub) pick(total int) []int {
+	count := int(float64(total) * h.opts.churn)
+	return h.random.Perm(total)[:count]
+}
+
+// send wraps the data into the cloudevent with the bundle version of the event type, the version is increased for
+// each bundle and the generation is moved to the next after sending
+func (h *simulatedHub) send(ctx context.Context, eventType enum.EventType, data interface{},
+	dependency *eventversion.Version,
+) bool {
+	version, found := h.versions[eventType]
+	if !found {
+		version = eventversion.NewVersion()
+		h.versions[eventType] = version
+	}
+	version.Incr()
+	defer version.Next()
+
+	evt := cloudevents.NewEvent()
+	evt.SetSource(h.name)
+	evt.SetType(string(eventType))
+	evt.SetExtension(eventversion.ExtVersion, version.String())
+	if dependency != nil {
+		evt.SetExtension(eventversion.ExtDependencyVersion, dependency.String())
+	}
+	if err := evt.SetData(cloudevents.ApplicationJSON, data); err != nil {
+		log.Error(err, "failed to set the event data", "hub", h.name, "type", eventType)
+		h.failedBundles.Add(1)
+		return false
+	}
+	if err := h.producer.SendEvent(ctx, evt); err != nil {
+		if ctx.Err() == nil {
+			log.Error(err, "failed to send the event", "hub", h.name, "type", eventType)
+		}
+		h.failedBundles.Add(1)
+		return false
+	}
+	h.sentBundles.Add(1)
+	return true
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+const probeInterval = 500 * time.Millisecond
+
+// latencyRecorder keeps the end-to-end latencies of the probes, the interval samples are reset by each report
+type latencyRecorder struct {
+	mutex    sync.Mutex
+	interval []time.Duration
+	total    []time.Duration
+	lost     int
+}
+
+func newLatencyRecorder() *latencyRecorder {
+	return &latencyRecorder{}
+}
+
+func (r *latencyRecorder) record(latency time.Duration) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.interval = append(r.interval, latency)
+	r.total = append(r.total, latency)
+}
+
+func (r *latencyRecorder) recordLost() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.lost++
+}
+
+// latencyProber polls the database for the pending probes of the hubs, the probe is observed once its compliance
+// is persisted by the manager
+type latencyProber struct {
+	hubs     []*simulatedHub
+	recorder *latencyRecorder
+	timeout  time.Duration
+}
+
+func newLatencyProber(hubs []*simulatedHub, recorder *latencyRecorder, timeout time.Duration) *latencyProber {
+	return &latencyProber{hubs: hubs, recorder: recorder, timeout: timeout}
+}
+
+func (p *latencyProber) start(ctx context.Context) {
+	ticker := time.NewTicker(probeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.poll(); err != nil {
+				log.Error(err, "failed to poll the probes from database")
+			}
+		}
+	}
+}
+
+type probeRow struct {
+	LeafHubName string
+	PolicyID    string
+	Compliance  string
+}
+
+func (p *latencyProber) poll() error {
+	policyIDs := []string{}
+	clusterName := ""
+	for _, hub := range p.hubs {
+		hub.mutex.Lock()
+		if hub.probe.pending {
+			policyIDs = append(policyIDs, hub.probe.policyID)
+			clusterName = hub.probe.clusterName
+		}
+		hub.mutex.Unlock()
+	}
+	if len(policyIDs) == 0 {
+		return nil
+	}
+
+	// all the probes are the first cluster of the hubs, which have the same name
+	rows := []probeRow{}
+	err := database.GetGorm().Raw(`SELECT leaf_hub_name, policy_id, compliance FROM local_status.compliance
+		WHERE cluster_name = ? AND policy_id IN ?`, clusterName, policyIDs).Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+	persisted := map[string]string{}
+	for _, row := range rows {
+		persisted[row.LeafHubName+"/"+row.PolicyID] = row.Compliance
+	}
+
+	now := time.Now()
+	for _, hub := range p.hubs {
+		hub.mutex.Lock()
+		probe := &hub.probe
+		if probe.pending {
+			if persisted[hub.name+"/"+probe.policyID] == string(probe.expected) {
+				p.recorder.record(now.Sub(probe.sentAt))
+				probe.pending = false
+			} else if now.Sub(probe.sentAt) > p.timeout {
+				p.recorder.recordLost()
+				probe.pending = false
+			}
+		}
+		hub.mutex.Unlock()
+	}
+	return nil
+}
+
+// percentile returns the nearest-rank percentile of the sorted latencies
+func percentile(sorted []time.Duration, percent float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(percent/100*float64(len(sorted))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+func summarize(latencies []time.Duration) string {
+	if len(latencies) == 0 {
+		return "samples=0"
+	}
+	sorted := make([]time.Duration, len(latencies))
+	copy(sorted, latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return fmt.Sprintf("samples=%d p50=%v p90=%v p99=%v max=%v", len(sorted),
+		percentile(sorted, 50).Round(time.Millisecond), percentile(sorted, 90).Round(time.Millisecond),
+		percentile(sorted, 99).Round(time.Millisecond), sorted[len(sorted)-1].Round(time.Millisecond))
+}
+
+// report prints the sent bundles and the latencies, the interval latencies are reset after reporting
+func report(hubs []*simulatedHub, recorder *latencyRecorder, name string) string {
+	var sent, failed int64
+	for _, hub := range hubs {
+		sent += hub.sentBundles.Load()
+		failed += hub.failedBundles.Load()
+	}
+
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "[%s] %s bundles: sent=%d failed=%d\n", time.Now().Format(time.RFC3339), name, sent,
+		failed)
+	fmt.Fprintf(&builder, "  latency(interval): %s\n", summarize(recorder.interval))
+	fmt.Fprintf(&builder, "  latency(total):    %s lost=%d", summarize(recorder.total), recorder.lost)
+	recorder.interval = nil
+	return builder.String()
+}
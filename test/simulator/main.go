@@ -0,0 +1,290 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+// The simulator impersonates the agents of thousands of managed hubs, it sends the status bundles to the manager
+// through the real transport and reports the latency until the status is persisted into the database. It's used to
+// benchmark the conflation pipeline of the manager reproducibly.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/pflag"
+	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	managerconfig "github.com/stolostron/multicluster-global-hub/manager/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	transportconfig "github.com/stolostron/multicluster-global-hub/pkg/transport/config"
+	genericconsumer "github.com/stolostron/multicluster-global-hub/pkg/transport/consumer"
+	genericproducer "github.com/stolostron/multicluster-global-hub/pkg/transport/producer"
+)
+
+var log = ctrl.Log.WithName("simulator")
+
+type simulatorOptions struct {
+	hubs               int
+	clusters           int
+	policies           int
+	hubPrefix          string
+	churn              float64
+	heartbeatInterval  time.Duration
+	clusterInterval    time.Duration
+	complianceInterval time.Duration
+	eventInterval      time.Duration
+	duration           time.Duration
+	reportInterval     time.Duration
+	probeTimeout       time.Duration
+	transportType      string
+	transportSecret    string
+	statusTopic        string
+	databaseURL        string
+	databaseCACert     string
+}
+
+func parseFlags() *simulatorOptions {
+	opts := &simulatorOptions{}
+	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
+	pflag.IntVar(&opts.hubs, "hubs", 10, "the number of the simulated managed hubs")
+	pflag.IntVar(&opts.clusters, "clusters", 100, "the number of the managed clusters of each managed hub")
+	pflag.IntVar(&opts.policies, "policies", 10, "the number of the policies of each managed hub")
+	pflag.StringVar(&opts.hubPrefix, "hub-prefix", "sim-hub", "the name prefix of the simulated managed hubs")
+	pflag.Float64Var(&opts.churn, "churn", 0.1,
+		"the ratio of the managed clusters/compliances that are changed in each round, between 0 and 1")
+	pflag.DurationVar(&opts.heartbeatInterval, "heartbeat-interval", time.Minute,
+		"the interval of sending the heartbeat of each managed hub")
+	pflag.DurationVar(&opts.clusterInterval, "cluster-interval", time.Minute,
+		"the interval of sending the managed clusters of each managed hub")
+	pflag.DurationVar(&opts.complianceInterval, "compliance-interval", 10*time.Second,
+		"the interval of rotating and sending the policy compliances of each managed hub")
+	pflag.DurationVar(&opts.eventInterval, "event-interval", 10*time.Second,
+		"the interval of sending the replicated policy events of each managed hub")
+	pflag.DurationVar(&opts.duration, "duration", 10*time.Minute, "how long the simulation runs")
+	pflag.DurationVar(&opts.reportInterval, "report-interval", 30*time.Second,
+		"the interval of printing the latency report")
+	pflag.DurationVar(&opts.probeTimeout, "probe-timeout", 2*time.Minute,
+		"the compliance change which isn't persisted within the timeout is counted as lost")
+	pflag.StringVar(&opts.transportType, "transport-type", string(transport.Chan),
+		"the transport type, 'chan' runs the status syncers of the manager in the simulator process, "+
+			"'kafka' sends the bundles to the kafka cluster consumed by the running manager")
+	pflag.StringVar(&opts.transportSecret, "transport-secret", "multicluster-global-hub/transport-config",
+		"the <namespace>/<name> of the secret with the kafka.yaml, only used by the kafka transport")
+	pflag.StringVar(&opts.statusTopic, "status-topic", "",
+		"the status topic to send the bundles, the default is the status topic of the transport secret")
+	pflag.StringVar(&opts.databaseURL, "database-url", "",
+		"the database url to measure the latency, it's required by the chan transport")
+	pflag.StringVar(&opts.databaseCACert, "database-ca-path", "", "the ca cert path of the database")
+	pflag.Parse()
+	return opts
+}
+
+func (o *simulatorOptions) validate() error {
+	if o.hubs < 1 || o.clusters < 1 || o.policies < 1 {
+		return fmt.Errorf("the hubs, clusters and policies must be greater than 0")
+	}
+	if o.churn < 0 || o.churn > 1 {
+		return fmt.Errorf("the churn must be between 0 and 1")
+	}
+	switch o.transportType {
+	case string(transport.Chan):
+		if o.databaseURL == "" {
+			return fmt.Errorf("the database-url is required by the chan transport")
+		}
+	case string(transport.Kafka):
+		if len(strings.Split(o.transportSecret, "/")) != 2 {
+			return fmt.Errorf("the transport-secret should be in the format <namespace>/<name>")
+		}
+	default:
+		return fmt.Errorf("unsupported transport type %s", o.transportType)
+	}
+	return nil
+}
+
+func main() {
+	ctrl.SetLogger(zap.New(zap.Level(zapcore.InfoLevel)))
+	opts := parseFlags()
+	if err := opts.validate(); err != nil {
+		log.Error(err, "invalid options")
+		os.Exit(1)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	ctx, cancelTimeout := context.WithTimeout(ctx, opts.duration)
+	defer cancelTimeout()
+
+	if err := run(ctx, opts); err != nil {
+		log.Error(err, "failed to run the simulator")
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, opts *simulatorOptions) error {
+	if opts.databaseURL != "" {
+		err := database.InitGormInstance(&database.DatabaseConfig{
+			URL:        opts.databaseURL,
+			Dialect:    database.PostgresDialect,
+			CaCertPath: opts.databaseCACert,
+			PoolSize:   5,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to connect to the database: %w", err)
+		}
+		defer database.CloseGorm(database.GetSqlDb())
+	}
+
+	transportConfig, err := transportConfig(opts)
+	if err != nil {
+		return err
+	}
+
+	if opts.transportType == string(transport.Chan) {
+		if err := startStatusSyncers(ctx, transportConfig); err != nil {
+			return err
+		}
+	}
+
+	// the producer sends the bundles to the status topic as the agent does
+	transportConfig.IsManager = false
+	producer, err := genericproducer.NewGenericProducer(transportConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create the producer: %w", err)
+	}
+
+	hubs := make([]*simulatedHub, 0, opts.hubs)
+	for i := 0; i < opts.hubs; i++ {
+		hubs = append(hubs, newSimulatedHub(fmt.Sprintf("%s-%d", opts.hubPrefix, i), opts, producer))
+	}
+
+	recorder := newLatencyRecorder()
+	if opts.databaseURL != "" {
+		go newLatencyProber(hubs, recorder, opts.probeTimeout).start(ctx)
+	} else {
+		log.Info("the database-url isn't specified, skip measuring the latency")
+	}
+
+	log.Info("start simulating", "hubs", opts.hubs, "clusters", opts.clusters, "policies", opts.policies,
+		"transport", opts.transportType)
+	var wg sync.WaitGroup
+	for _, hub := range hubs {
+		wg.Add(1)
+		go func(hub *simulatedHub) {
+			defer wg.Done()
+			hub.start(ctx)
+		}(hub)
+	}
+
+	ticker := time.NewTicker(opts.reportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			fmt.Println(report(hubs, recorder, "final"))
+			return nil
+		case <-ticker.C:
+			fmt.Println(report(hubs, recorder, "interval"))
+		}
+	}
+}
+
+func transportConfig(opts *simulatorOptions) (*transport.TransportInternalConfig, error) {
+	if opts.transportType == string(transport.Chan) {
+		return &transport.TransportInternalConfig{
+			TransportType: string(transport.Chan),
+			KafkaCredential: &transport.KafkaConfig{
+				SpecTopic:   "spec",
+				StatusTopic: "status",
+			},
+		}, nil
+	}
+
+	restConfig, err := ctrl.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the kubeconfig: %w", err)
+	}
+	c, err := client.New(restConfig, client.Options{Scheme: managerconfig.GetRuntimeScheme()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the runtime client: %w", err)
+	}
+	namespacedName := strings.Split(opts.transportSecret, "/")
+	secret := &corev1.Secret{}
+	err = c.Get(context.Background(), types.NamespacedName{Namespace: namespacedName[0], Name: namespacedName[1]},
+		secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the transport secret %s: %w", opts.transportSecret, err)
+	}
+	kafkaCredential, err := transportconfig.GetKafkaCredentailBySecret(secret, c)
+	if err != nil {
+		return nil, err
+	}
+	if opts.statusTopic != "" {
+		kafkaCredential.StatusTopic = opts.statusTopic
+	}
+	if kafkaCredential.StatusTopic == "" {
+		return nil, fmt.Errorf("the status topic isn't found in the transport secret %s", opts.transportSecret)
+	}
+	return &transport.TransportInternalConfig{
+		TransportType:   string(transport.Kafka),
+		KafkaCredential: kafkaCredential,
+	}, nil
+}
+
+// startStatusSyncers runs the status syncers of the manager in the current process, the consumer shares the go
+// channel with the producer of the simulated hubs
+func startStatusSyncers(ctx context.Context, transportConfig *transport.TransportInternalConfig) error {
+	restConfig, err := ctrl.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to get the kubeconfig for the runtime manager: %w", err)
+	}
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
+		Metrics: metricsserver.Options{
+			BindAddress: "0", // disable the metrics serving
+		},
+		Scheme: managerconfig.GetRuntimeScheme(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create the runtime manager: %w", err)
+	}
+
+	transportConfig.IsManager = true
+	consumer, err := genericconsumer.NewGenericConsumer(transportConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create the consumer: %w", err)
+	}
+	if err := mgr.Add(consumer); err != nil {
+		return err
+	}
+
+	err = statussyncer.AddStatusSyncers(mgr, consumer, &managerconfig.ManagerConfig{
+		TransportConfig:  transportConfig,
+		StatisticsConfig: &statistics.StatisticsConfig{LogInterval: "1m"},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add the status syncers: %w", err)
+	}
+
+	go func() {
+		if err := mgr.Start(ctx); err != nil {
+			log.Error(err, "failed to start the runtime manager")
+			os.Exit(1)
+		}
+	}()
+	return nil
+}